@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/edgecomet/engine/internal/cachedaemon"
 	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 	"github.com/edgecomet/engine/internal/common/logger"
 	"github.com/edgecomet/engine/internal/common/redis"
 )
@@ -40,12 +43,14 @@ func main() {
 		initialLogger.Fatal("Failed to load cache-daemon config", zap.Error(err))
 	}
 
-	// Resolve EG config path (relative paths are relative to daemon config directory)
+	// Resolve EG config and certificate paths (relative paths are relative to daemon config directory)
+	daemonDir := filepath.Dir(*configPath)
 	egConfigPath := daemonConfig.EgConfig
 	if !filepath.IsAbs(egConfigPath) {
-		daemonDir := filepath.Dir(*configPath)
 		egConfigPath = filepath.Join(daemonDir, egConfigPath)
 	}
+	daemonConfig.HTTPApi.TLS.ResolvePaths(daemonDir)
+	daemonConfig.EGClient.TLS.ResolvePaths(daemonDir)
 
 	initialLogger.Info("Loading EG config for hosts",
 		zap.String("eg_config_path", egConfigPath))
@@ -101,9 +106,23 @@ func main() {
 
 		listenAddr := daemonConfig.HTTPApi.Listen
 
+		apiTLS, err := internalauth.ServerTLSConfig(&daemonConfig.HTTPApi.TLS, "")
+		if err != nil {
+			zapLogger.Fatal("Failed to load HTTP API TLS config", zap.Error(err))
+		}
+		listener, err := net.Listen("tcp", listenAddr)
+		if err != nil {
+			zapLogger.Fatal("Failed to listen for HTTP API", zap.String("addr", listenAddr), zap.Error(err))
+		}
+		if apiTLS != nil {
+			listener = tls.NewListener(listener, apiTLS)
+		}
+
 		go func() {
-			zapLogger.Info("HTTP API server starting", zap.String("addr", listenAddr))
-			if err := httpServer.ListenAndServe(listenAddr); err != nil {
+			zapLogger.Info("HTTP API server starting",
+				zap.String("addr", listenAddr),
+				zap.Bool("tls", apiTLS != nil))
+			if err := httpServer.Serve(listener); err != nil {
 				zapLogger.Error("HTTP server error", zap.Error(err))
 			}
 		}()
@@ -18,6 +18,8 @@ import (
 
 	"github.com/edgecomet/engine/internal/cachedaemon"
 	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/common/eventbus"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 	"github.com/edgecomet/engine/internal/common/logger"
 	"github.com/edgecomet/engine/internal/common/metricsserver"
 	"github.com/edgecomet/engine/internal/common/redis"
@@ -28,11 +30,15 @@ import (
 	"github.com/edgecomet/engine/internal/edge/configtest"
 	"github.com/edgecomet/engine/internal/edge/device"
 	"github.com/edgecomet/engine/internal/edge/events"
+	"github.com/edgecomet/engine/internal/edge/hotcache"
 	"github.com/edgecomet/engine/internal/edge/internal_server"
 	"github.com/edgecomet/engine/internal/edge/metrics"
 	"github.com/edgecomet/engine/internal/edge/orchestrator"
+	"github.com/edgecomet/engine/internal/edge/popularity"
 	"github.com/edgecomet/engine/internal/edge/recache"
+	"github.com/edgecomet/engine/internal/edge/robots"
 	"github.com/edgecomet/engine/internal/edge/rsclient"
+	"github.com/edgecomet/engine/internal/edge/rshealth"
 	"github.com/edgecomet/engine/internal/edge/server"
 	"github.com/edgecomet/engine/internal/edge/sharding"
 	edgetls "github.com/edgecomet/engine/internal/edge/tls"
@@ -115,6 +121,17 @@ func main() {
 		egLogger.Fatal("Failed to start metrics server", zap.Error(err))
 	}
 
+	// Build internal TLS configs (relative paths resolve against the config directory)
+	configDir := filepath.Dir(*configPath)
+	internalServerTLS, err := internalauth.ServerTLSConfig(&cfg.Internal.TLS, configDir)
+	if err != nil {
+		egLogger.Fatal("Failed to load internal TLS config", zap.Error(err))
+	}
+	internalClientTLS, err := internalauth.ClientTLSConfig(&cfg.Internal.TLS, configDir)
+	if err != nil {
+		egLogger.Fatal("Failed to load internal TLS client config", zap.Error(err))
+	}
+
 	// Initialize EG ID
 	egID := cfg.EgID
 	if egID == "" {
@@ -131,7 +148,8 @@ func main() {
 		shardingManager, err = sharding.NewManager(
 			cfg.CacheSharding,
 			egID,
-			cfg.Internal.AuthKey,
+			cfg.Internal.GetClientKey(),
+			internalClientTLS,
 			redisClient,
 			cacheService,
 			cfg.Metrics.Namespace,
@@ -144,6 +162,7 @@ func main() {
 			&types.CacheShardingConfig{Enabled: ptrBool(false)},
 			egID,
 			"",
+			nil,
 			redisClient,
 			cacheService,
 			cfg.Metrics.Namespace,
@@ -154,6 +173,15 @@ func main() {
 		egLogger.Fatal("Failed to create sharding manager", zap.Error(err))
 	}
 
+	// Instance ID announced in cluster events and render notifications
+	eventInstance := cfg.EgID
+	if eventInstance == "" {
+		eventInstance, _ = os.Hostname()
+	}
+
+	// Create render notifier (coalesces concurrent renders of the same cache key)
+	renderNotifier := orchestrator.NewRenderNotifier(redisClient, eventInstance, egLogger)
+
 	// Create render orchestrator
 	renderOrchestrator := orchestrator.NewRenderOrchestrator(
 		metadataStore,
@@ -166,12 +194,55 @@ func main() {
 		redisClient,
 		configManager,
 		shardingManager,
+		renderNotifier,
 		egLogger,
 	)
 
+	// Initialize cluster events: this EG publishes its metadata changes and consumes
+	// invalidations to delete local files and drop hot cache entries
+	eventBus := eventbus.New(redisClient, eventbus.ComponentEdgeGateway, eventInstance, egLogger)
+	metadataStore.SetEventBus(eventBus)
+	if cfg.DuplicateDetection.IsEnabled() {
+		metadataStore.SetDuplicateIndex(cache.NewDuplicateIndex(redisClient, keyGenerator, egLogger))
+		egLogger.Info("Duplicate content detection enabled")
+	}
+	if cfg.SEOHistory.IsEnabled() {
+		metadataStore.SetSEOHistory(cache.NewSEOHistory(redisClient, keyGenerator,
+			cfg.SEOHistory.GetMaxVersions(), cfg.SEOHistory.GetRetention(), egLogger))
+		egLogger.Info("SEO history enabled",
+			zap.Int("max_versions", cfg.SEOHistory.GetMaxVersions()),
+			zap.Duration("retention", cfg.SEOHistory.GetRetention()))
+	}
+	if cfg.LinkGraph.IsEnabled() {
+		metadataStore.SetLinkGraph(cache.NewLinkGraph(redisClient, keyGenerator,
+			cfg.LinkGraph.GetRetention(), cfg.LinkGraph.DiscoverMaxDepth(), egLogger))
+		egLogger.Info("Link graph enabled",
+			zap.Duration("retention", cfg.LinkGraph.GetRetention()),
+			zap.Int("discover_max_depth", cfg.LinkGraph.DiscoverMaxDepth()))
+	}
+	eventHandlers := []eventbus.Handler{
+		cleanup.NewInvalidationHandler(cfg.Storage.BasePath, metadataStore.GetAbsoluteFilePath, egLogger),
+	}
+
+	// Initialize hot cache (serves only while the event subscriber is caught up)
+	var hotCache *hotcache.Cache
+	if cfg.HotCache.IsEnabled() {
+		hotCache = hotcache.New(cfg.HotCache, hotcache.NewMetrics(cfg.Metrics.Namespace))
+		metadataStore.SetOnChange(hotCache.OnMetadataChange)
+		renderOrchestrator.SetHotCache(hotCache)
+		eventHandlers = append(eventHandlers, hotCache)
+		egLogger.Info("Hot cache enabled",
+			zap.Int("max_entries", cfg.HotCache.GetMaxEntries()),
+			zap.Int64("max_size_bytes", cfg.HotCache.GetMaxSizeBytes()))
+	}
+	eventSubscriber := eventbus.NewSubscriber(redisClient, eventbus.ComponentEdgeGateway+"/"+eventInstance, egLogger, eventHandlers...)
+
 	// Initialize autorecache client
 	autorecacheClient := cachedaemon.NewAutorecacheClient(redisClient, egLogger)
 
+	// Initialize popularity tracker (used only when popularity is enabled)
+	popularityTracker := popularity.NewTracker(redisClient, keyGenerator)
+
 	// Initialize event emitter
 	var eventEmitter events.EventEmitter
 	if cfg.EventLogging != nil && cfg.EventLogging.File.Enabled {
@@ -186,10 +257,31 @@ func main() {
 
 	// Initialize recache service
 	cacheCoord := orchestrator.NewCacheCoordinator(metadataStore, fsCache, cacheService, shardingManager, metricsCollector, egLogger)
-	recacheService := recache.NewRecacheService(configManager, cacheCoord, bypassService, redisClient, rsClient, metadataStore, eventEmitter, cfg.EgID, egLogger)
+	if hotCache != nil {
+		cacheCoord.SetHotCache(hotCache)
+	}
+	recacheService := recache.NewRecacheService(configManager, cacheCoord, bypassService, redisClient, rsClient, metadataStore, popularityTracker, eventEmitter, cfg.EgID, egLogger)
+	recacheService.SetMetricsCollector(metricsCollector)
+
+	// robots.txt is fetched through the bypass service (SSRF protection) and checked per host
+	robotsFetcher := robots.NewFetcher(bypassService, egLogger)
+	recacheService.SetRobotsFetcher(robotsFetcher)
+
+	// Initialize render service health tracking (nil when disabled)
+	if cfg.Registry.Health.IsEnabled() {
+		healthTracker := rshealth.NewTracker(redisClient, cfg.Registry.Health, rshealth.NewMetrics(cfg.Metrics.Namespace), egLogger)
+		renderOrchestrator.SetHealthTracker(healthTracker)
+		recacheService.SetHealthTracker(healthTracker)
+		egLogger.Info("Render service health tracking enabled",
+			zap.String("selection_strategy", cfg.Registry.SelectionStrategy),
+			zap.Int("min_requests", cfg.Registry.Health.GetMinRequests()),
+			zap.Duration("open_duration", cfg.Registry.Health.GetOpenDuration()))
+	}
 
 	// Create internal server and register endpoints
 	internalSrv := internal_server.NewInternalServer(cfg.Internal.AuthKey, egLogger)
+	internalSrv.SetAuthenticator(internalauth.NewAuthenticator(cfg.Internal.AuthKey, cfg.Internal.Credentials))
+	internalSrv.SetTLSConfig(internalServerTLS)
 
 	// Register sharding endpoints
 	if shardingManager != nil && shardingManager.IsEnabled() {
@@ -231,9 +323,11 @@ func main() {
 		shardingManager,
 		metadataStore,
 		autorecacheClient,
+		popularityTracker,
 		eventEmitter,
 		cfg.EgID,
 	)
+	srv.SetRobotsFetcher(robotsFetcher)
 
 	// Start internal server (before cluster registration)
 	ctx := context.Background()
@@ -252,6 +346,13 @@ func main() {
 		egLogger.Info("Sharding manager started successfully")
 	}
 
+	// Start cluster event subscriber
+	eventSubscriber.Start()
+	eventBus.PublishConfigReloaded(ctx)
+
+	// Start render notifier
+	renderNotifier.Start()
+
 	// Start cleanup worker
 	if cleanupWorker != nil {
 		cleanupWorker.Start()
@@ -261,7 +362,6 @@ func main() {
 	// Create TLS listener before starting public servers to fail fast
 	var tlsListener net.Listener
 	if cfg.Server.TLS.Enabled {
-		configDir := filepath.Dir(*configPath)
 		certPath := cfg.Server.TLS.CertFile
 		keyPath := cfg.Server.TLS.KeyFile
 		if !filepath.IsAbs(certPath) {
@@ -345,6 +445,12 @@ func main() {
 		cleanupWorker.Shutdown()
 	}
 
+	// Shutdown cluster event subscriber
+	eventSubscriber.Shutdown()
+
+	// Shutdown render notifier
+	renderNotifier.Shutdown()
+
 	// Shutdown metrics server
 	if metricsServer != nil {
 		egLogger.Info("Shutting down metrics server")
@@ -13,6 +13,7 @@ import (
 
 	"github.com/edgecomet/engine/internal/common/config"
 	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/eventbus"
 	logutil "github.com/edgecomet/engine/internal/common/logger"
 	"github.com/edgecomet/engine/internal/common/metricsserver"
 	"github.com/edgecomet/engine/internal/common/redis"
@@ -98,6 +99,22 @@ func main() {
 		logger.Fatal("Failed to start metrics server", zap.Error(err))
 	}
 
+	// Create the subresource cache shared by all Chrome instances (before pool creation)
+	if resourceCache := cfg.Chrome.ResourceCache; resourceCache.IsEnabled() {
+		chromeConfig.ResourceCache = chrome.NewResourceCache(
+			resourceCache.GetMaxSize(),
+			resourceCache.GetMaxEntrySize(),
+			resourceCache.AllowedDomains,
+			resourceCache.GetResourceTypes(),
+			metricsCollector,
+			logger,
+		)
+		logger.Info("Resource cache enabled",
+			zap.Int64("max_size_bytes", resourceCache.GetMaxSize()),
+			zap.Strings("allowed_domains", resourceCache.AllowedDomains),
+			zap.Strings("resource_types", resourceCache.GetResourceTypes()))
+	}
+
 	// Initialize RS registry (before pool creation)
 	rsRegistry := registry.NewServiceRegistry(redisClient, logger)
 
@@ -195,6 +212,9 @@ func main() {
 		zap.String("listen", cfg.Server.Listen),
 		zap.Int("chrome_instances", poolSize))
 
+	// Announce the loaded configuration to the cluster
+	eventbus.New(redisClient, eventbus.ComponentRenderService, cfg.Server.ID, logger).PublishConfigReloaded(context.Background())
+
 	// Switch to configured log level after startup is complete
 	dynamicLogger.SwitchToConfiguredLevel()
 
@@ -11,30 +11,43 @@ import (
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 
+	"github.com/edgecomet/engine/internal/common/eventbus"
 	"github.com/edgecomet/engine/internal/common/httputil"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/internal/edge/seoaudit"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
+// defaultDuplicateSimilarity is the MinHash similarity treated as near-duplicate content
+// when the request does not set min_similarity
+const defaultDuplicateSimilarity = 0.9
+
 // ServeHTTP is the main HTTP request handler for the cache daemon API
 func (d *CacheDaemon) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	path := string(ctx.Path())
 	method := string(ctx.Method())
 
-	// Auth middleware - validate X-Internal-Auth header for all endpoints
-	authKey := string(ctx.Request.Header.Peek("X-Internal-Auth"))
-	if authKey == "" {
-		d.logger.Warn("Missing X-Internal-Auth header",
-			zap.String("path", path),
-			zap.String("remote_addr", ctx.RemoteAddr().String()))
-		httputil.JSONError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
+	// Origin webhooks authenticate with an HMAC signature instead of X-Internal-Auth
+	if method == "POST" && path == webhookPath {
+		d.handleWebhook(ctx)
 		return
 	}
-	if authKey != d.internalAuthKey {
-		d.logger.Warn("Invalid X-Internal-Auth header",
+
+	// Auth middleware - identify the caller and check the scope the route needs
+	identity, status, err := d.authorizeAPI(ctx, apiScope(path))
+	if err != nil {
+		fields := []zap.Field{
 			zap.String("path", path),
-			zap.String("remote_addr", ctx.RemoteAddr().String()))
-		httputil.JSONError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
+			zap.String("remote_addr", ctx.RemoteAddr().String()),
+			zap.Error(err),
+		}
+		if identity != nil {
+			fields = append(fields, zap.String("caller", identity.Name))
+		}
+		d.logger.Warn("Daemon API request rejected", fields...)
+		httputil.JSONError(ctx, strings.ToLower(fasthttp.StatusMessage(status)), status)
 		return
 	}
 
@@ -56,6 +69,18 @@ func (d *CacheDaemon) ServeHTTP(ctx *fasthttp.RequestCtx) {
 		d.handleCacheURLsAPI(ctx)
 	case method == "GET" && path == "/internal/cache/summary":
 		d.handleCacheSummaryAPI(ctx)
+	case method == "GET" && path == "/internal/cache/duplicates":
+		d.handleDuplicatesAPI(ctx)
+	case method == "GET" && path == "/internal/cache/seo":
+		d.handleSEOReportAPI(ctx)
+	case method == "GET" && path == "/internal/cache/seo/history":
+		d.handleSEOHistoryAPI(ctx)
+	case method == "GET" && path == "/internal/cache/links/inlinks":
+		d.handleInlinksAPI(ctx)
+	case method == "GET" && path == "/internal/cache/links/orphans":
+		d.handleOrphansAPI(ctx)
+	case method == "GET" && path == "/internal/cache/links/broken":
+		d.handleBrokenLinksAPI(ctx)
 	case method == "GET" && path == "/internal/cache/queue":
 		d.handleCacheQueueAPI(ctx)
 	case method == "GET" && path == "/internal/cache/queue/summary":
@@ -65,6 +90,28 @@ func (d *CacheDaemon) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	}
 }
 
+// apiScope returns the scope a caller needs for a daemon API path
+func apiScope(path string) string {
+	switch {
+	case path == "/internal/cache/recache":
+		return internalauth.ScopeRecache
+	case strings.HasPrefix(path, "/internal/cache/invalidate"):
+		return internalauth.ScopeInvalidate
+	case strings.HasPrefix(path, "/internal/scheduler/"):
+		return internalauth.ScopeScheduler
+	default:
+		return internalauth.ScopeRead
+	}
+}
+
+// authorizeAPI authenticates a daemon API caller against the configured credentials
+func (d *CacheDaemon) authorizeAPI(ctx *fasthttp.RequestCtx, scope string) (*internalauth.Identity, int, error) {
+	if d.apiAuth == nil {
+		return nil, fasthttp.StatusUnauthorized, fmt.Errorf("no API credentials configured")
+	}
+	return d.apiAuth.Authorize(ctx, scope)
+}
+
 // resolveDimensionIDs builds the list of non-block dimension IDs for a host and validates any
 // explicitly requested IDs against it. Block dimensions never produce cache entries.
 // Returns all non-block dimensions if requestedIDs is empty.
@@ -226,6 +273,7 @@ func (d *CacheDaemon) handleInvalidateAPI(ctx *fasthttp.RequestCtx) {
 	// Invalidate cache entries
 	entriesInvalidated := 0
 	reqCtx := context.Background()
+	var invalidated []eventbus.Entry
 
 	for _, url := range req.URLs {
 		// Normalize URL
@@ -244,6 +292,9 @@ func (d *CacheDaemon) handleInvalidateAPI(ctx *fasthttp.RequestCtx) {
 			cacheKey := d.keyGenerator.GenerateCacheKey(req.HostID, dimensionID, urlHash)
 			metadataKey := d.keyGenerator.GenerateMetadataKey(cacheKey)
 
+			// Best effort: without the path EGs keep the file until their cleanup worker removes it
+			filePath, _ := d.redis.HGet(reqCtx, metadataKey, "file_path")
+
 			deleted, err := d.redis.DelCount(reqCtx, metadataKey)
 			if err != nil {
 				d.logger.Error("Failed to delete cache metadata",
@@ -252,6 +303,9 @@ func (d *CacheDaemon) handleInvalidateAPI(ctx *fasthttp.RequestCtx) {
 				continue
 			}
 			urlDeleted += int(deleted)
+			if deleted > 0 {
+				invalidated = append(invalidated, eventbus.Entry{Key: cacheKey.String(), FilePath: filePath})
+			}
 		}
 
 		if urlDeleted == 0 {
@@ -262,6 +316,7 @@ func (d *CacheDaemon) handleInvalidateAPI(ctx *fasthttp.RequestCtx) {
 		}
 		entriesInvalidated += urlDeleted
 	}
+	d.events.PublishEntries(reqCtx, eventbus.EntryInvalidated, invalidated...)
 
 	// Return response
 	data := types.InvalidateAPIData{
@@ -422,6 +477,8 @@ func (d *CacheDaemon) handleInvalidateAllAPI(ctx *fasthttp.RequestCtx) {
 		args[1] = nextCursor
 	}
 
+	d.events.PublishHostPurged(reqCtx, req.HostID, req.DimensionIDs)
+
 	data := types.InvalidateAllAPIData{
 		HostID:             req.HostID,
 		DimensionIDsCount:  len(dimensionIDs),
@@ -651,6 +708,21 @@ func (d *CacheDaemon) handleCacheURLsAPI(ctx *fasthttp.RequestCtx) {
 		indexStatusFilter = strings.Join(parsed, ",")
 	}
 
+	seoIssueFilter := queryParamString(ctx, "seo_issue")
+	if seoIssueFilter != "" {
+		rules := strings.Split(seoIssueFilter, ",")
+		trimmedRules := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			rule = strings.TrimSpace(rule)
+			if !seoaudit.IsPageRule(rule) {
+				httputil.JSONError(ctx, fmt.Sprintf("invalid seo_issue filter: %s (must be a page-level rule)", rule), fasthttp.StatusBadRequest)
+				return
+			}
+			trimmedRules = append(trimmedRules, rule)
+		}
+		seoIssueFilter = strings.Join(trimmedRules, ",")
+	}
+
 	staleTTL := d.getStaleTTL(host)
 
 	params := CacheListParams{
@@ -667,6 +739,7 @@ func (d *CacheDaemon) handleCacheURLsAPI(ctx *fasthttp.RequestCtx) {
 		StatusCodeFilter:  statusCodeFilter,
 		SourceFilter:      sourceFilter,
 		IndexStatusFilter: indexStatusFilter,
+		SEOIssueFilter:    seoIssueFilter,
 		StaleTTL:          staleTTL,
 	}
 
@@ -705,6 +778,336 @@ func (d *CacheDaemon) handleCacheSummaryAPI(ctx *fasthttp.RequestCtx) {
 		zap.Int("total_urls", result.TotalUrls))
 }
 
+// handleDuplicatesAPI returns clusters of duplicate and near-duplicate pages of a host,
+// built from the LSH index of page MinHash signatures
+func (d *CacheDaemon) handleDuplicatesAPI(ctx *fasthttp.RequestCtx) {
+	host, hostID, ok := d.resolveHost(ctx)
+	if !ok {
+		return
+	}
+
+	minSimilarity := defaultDuplicateSimilarity
+	if raw := queryParamString(ctx, "min_similarity"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < cache.MinDuplicateSimilarity || parsed > 1 {
+			httputil.JSONError(ctx, fmt.Sprintf("min_similarity must be a number between %g and 1", cache.MinDuplicateSimilarity), fasthttp.StatusBadRequest)
+			return
+		}
+		minSimilarity = parsed
+	}
+
+	limit, err := queryParamInt(ctx, "limit", defaultLimit)
+	if err != nil {
+		httputil.JSONError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if limit < 1 || limit > maxLimit {
+		httputil.JSONError(ctx, fmt.Sprintf("limit must be between 1 and %d", maxLimit), fasthttp.StatusBadRequest)
+		return
+	}
+
+	dimensionFilter := queryParamString(ctx, "dimension")
+	if dimensionFilter != "" {
+		if _, exists := host.Dimensions[dimensionFilter]; !exists {
+			httputil.JSONError(ctx, fmt.Sprintf("dimension '%s' not configured for host", dimensionFilter), fasthttp.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := d.duplicateIndex.FindClusters(context.Background(), hostID, minSimilarity)
+	if handleRedisError(ctx, err, d.logger) {
+		return
+	}
+
+	clusters := report.Clusters
+	if dimensionFilter != "" {
+		filtered := make([]cache.DuplicateCluster, 0, len(clusters))
+		for _, cluster := range clusters {
+			if cluster.Dimension == dimensionFilter {
+				filtered = append(filtered, cluster)
+			}
+		}
+		clusters = filtered
+	}
+
+	response := DuplicatesResponse{
+		HostID:           hostID,
+		MinSimilarity:    minSimilarity,
+		TotalClusters:    len(clusters),
+		Clusters:         clusters[:min(limit, len(clusters))],
+		IndexedPages:     report.IndexedPages,
+		ComparedPairs:    report.ComparedPairs,
+		TruncatedBuckets: report.TruncatedBuckets,
+	}
+	httputil.JSONData(ctx, response, fasthttp.StatusOK)
+
+	d.logger.Debug("Duplicates request served",
+		zap.Int("host_id", hostID),
+		zap.Float64("min_similarity", minSimilarity),
+		zap.Int("total_clusters", response.TotalClusters),
+		zap.Int("compared_pairs", report.ComparedPairs))
+}
+
+// handleSEOReportAPI returns the SEO findings of a host's cached pages, aggregated per rule
+func (d *CacheDaemon) handleSEOReportAPI(ctx *fasthttp.RequestCtx) {
+	host, hostID, ok := d.resolveHost(ctx)
+	if !ok {
+		return
+	}
+
+	limit, err := queryParamInt(ctx, "limit", defaultLimit)
+	if err != nil {
+		httputil.JSONError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if limit < 1 || limit > maxLimit {
+		httputil.JSONError(ctx, fmt.Sprintf("limit must be between 1 and %d", maxLimit), fasthttp.StatusBadRequest)
+		return
+	}
+
+	dimension := queryParamString(ctx, "dimension")
+	if dimension != "" {
+		if _, exists := host.Dimensions[dimension]; !exists {
+			httputil.JSONError(ctx, fmt.Sprintf("dimension '%s' not configured for host", dimension), fasthttp.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := d.seoAuditor.HostReport(context.Background(), hostID, dimension, limit)
+	if handleRedisError(ctx, err, d.logger) {
+		return
+	}
+
+	httputil.JSONData(ctx, SEOReportResponse{HostID: hostID, Dimension: dimension, Report: report}, fasthttp.StatusOK)
+
+	d.logger.Debug("SEO report request served",
+		zap.Int("host_id", hostID),
+		zap.Int("pages_audited", report.PagesAudited),
+		zap.Int("pages_with_issues", report.PagesWithIssues))
+}
+
+// handleSEOHistoryAPI returns the stored PageSEO versions of one URL and dimension
+// with the tracked fields that changed between consecutive renders
+func (d *CacheDaemon) handleSEOHistoryAPI(ctx *fasthttp.RequestCtx) {
+	host, hostID, ok := d.resolveHost(ctx)
+	if !ok {
+		return
+	}
+
+	rawURL := queryParamString(ctx, "url")
+	if rawURL == "" {
+		httputil.JSONError(ctx, "url is required", fasthttp.StatusBadRequest)
+		return
+	}
+
+	dimensionName := queryParamString(ctx, "dimension")
+	if dimensionName == "" {
+		httputil.JSONError(ctx, "dimension is required", fasthttp.StatusBadRequest)
+		return
+	}
+	dimension, exists := host.Dimensions[dimensionName]
+	if !exists {
+		httputil.JSONError(ctx, fmt.Sprintf("dimension '%s' not configured for host", dimensionName), fasthttp.StatusBadRequest)
+		return
+	}
+
+	normalizedResult, err := d.normalizer.Normalize(rawURL, nil)
+	if err != nil {
+		httputil.JSONError(ctx, fmt.Sprintf("invalid url: %s", err.Error()), fasthttp.StatusBadRequest)
+		return
+	}
+
+	urlHash := d.normalizer.Hash(normalizedResult.NormalizedURL)
+	cacheKey := d.keyGenerator.GenerateCacheKey(hostID, dimension.ID, urlHash)
+
+	versions, err := d.seoHistory.Versions(context.Background(), cacheKey)
+	if handleRedisError(ctx, err, d.logger) {
+		return
+	}
+
+	changes := make([]SEOHistoryChange, 0, len(versions))
+	for i := 0; i+1 < len(versions); i++ {
+		changes = append(changes, SEOHistoryChange{
+			From:   versions[i+1].RenderedAt,
+			To:     versions[i].RenderedAt,
+			Fields: cache.DiffPageSEO(versions[i+1].SEO, versions[i].SEO),
+		})
+	}
+
+	response := SEOHistoryResponse{
+		HostID:    hostID,
+		URL:       normalizedResult.NormalizedURL,
+		Dimension: dimensionName,
+		CacheKey:  cacheKey.String(),
+		Versions:  versions,
+		Changes:   changes,
+	}
+	httputil.JSONData(ctx, response, fasthttp.StatusOK)
+
+	d.logger.Debug("SEO history request served",
+		zap.Int("host_id", hostID),
+		zap.String("cache_key", cacheKey.String()),
+		zap.Int("versions", len(versions)))
+}
+
+// resolveLinkReportParams validates the required dimension and the limit of a link graph report
+func (d *CacheDaemon) resolveLinkReportParams(ctx *fasthttp.RequestCtx, host *types.Host) (string, types.Dimension, int, bool) {
+	limit, err := queryParamInt(ctx, "limit", defaultLimit)
+	if err != nil {
+		httputil.JSONError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return "", types.Dimension{}, 0, false
+	}
+	if limit < 1 || limit > maxLimit {
+		httputil.JSONError(ctx, fmt.Sprintf("limit must be between 1 and %d", maxLimit), fasthttp.StatusBadRequest)
+		return "", types.Dimension{}, 0, false
+	}
+
+	dimensionName := queryParamString(ctx, "dimension")
+	if dimensionName == "" {
+		httputil.JSONError(ctx, "dimension is required", fasthttp.StatusBadRequest)
+		return "", types.Dimension{}, 0, false
+	}
+	dimension, exists := host.Dimensions[dimensionName]
+	if !exists {
+		httputil.JSONError(ctx, fmt.Sprintf("dimension '%s' not configured for host", dimensionName), fasthttp.StatusBadRequest)
+		return "", types.Dimension{}, 0, false
+	}
+	return dimensionName, dimension, limit, true
+}
+
+// handleInlinksAPI returns the most linked URLs of a host dimension, or the pages
+// linking to one URL when url is set
+func (d *CacheDaemon) handleInlinksAPI(ctx *fasthttp.RequestCtx) {
+	host, hostID, ok := d.resolveHost(ctx)
+	if !ok {
+		return
+	}
+	dimensionName, dimension, limit, ok := d.resolveLinkReportParams(ctx, host)
+	if !ok {
+		return
+	}
+
+	rawURL := queryParamString(ctx, "url")
+	if rawURL == "" {
+		report, err := d.linkGraph.TopLinked(context.Background(), hostID, dimension.ID, limit)
+		if handleRedisError(ctx, err, d.logger) {
+			return
+		}
+		httputil.JSONData(ctx, LinkReportResponse{HostID: hostID, Dimension: dimensionName, LinkedPages: report}, fasthttp.StatusOK)
+
+		d.logger.Debug("Inlinks request served",
+			zap.Int("host_id", hostID),
+			zap.String("dimension", dimensionName),
+			zap.Int("total", report.Total))
+		return
+	}
+
+	normalizedResult, err := d.normalizer.Normalize(rawURL, d.getStripPatterns(host, rawURL))
+	if err != nil {
+		httputil.JSONError(ctx, fmt.Sprintf("invalid url: %s", err.Error()), fasthttp.StatusBadRequest)
+		return
+	}
+	cacheKey := d.keyGenerator.GenerateCacheKey(hostID, dimension.ID, d.normalizer.Hash(normalizedResult.NormalizedURL))
+
+	sources, err := d.linkGraph.PageInlinks(context.Background(), cacheKey)
+	if handleRedisError(ctx, err, d.logger) {
+		return
+	}
+
+	response := PageInlinksResponse{
+		HostID:     hostID,
+		Dimension:  dimensionName,
+		URL:        normalizedResult.NormalizedURL,
+		Inlinks:    len(sources),
+		LinkedFrom: sources[:min(limit, len(sources))],
+	}
+	httputil.JSONData(ctx, response, fasthttp.StatusOK)
+
+	d.logger.Debug("Page inlinks request served",
+		zap.Int("host_id", hostID),
+		zap.String("cache_key", cacheKey.String()),
+		zap.Int("inlinks", response.Inlinks))
+}
+
+// handleBrokenLinksAPI returns the link targets of a host dimension that are not cached
+// or not cached as a 200 response
+func (d *CacheDaemon) handleBrokenLinksAPI(ctx *fasthttp.RequestCtx) {
+	host, hostID, ok := d.resolveHost(ctx)
+	if !ok {
+		return
+	}
+	dimensionName, dimension, limit, ok := d.resolveLinkReportParams(ctx, host)
+	if !ok {
+		return
+	}
+
+	report, err := d.linkGraph.BrokenLinks(context.Background(), hostID, dimension.ID, limit)
+	if handleRedisError(ctx, err, d.logger) {
+		return
+	}
+	httputil.JSONData(ctx, LinkReportResponse{HostID: hostID, Dimension: dimensionName, LinkedPages: report}, fasthttp.StatusOK)
+
+	d.logger.Debug("Broken links request served",
+		zap.Int("host_id", hostID),
+		zap.String("dimension", dimensionName),
+		zap.Int("total", report.Total))
+}
+
+// handleOrphansAPI returns the cached 200 pages of a host dimension that no rendered
+// page links to
+func (d *CacheDaemon) handleOrphansAPI(ctx *fasthttp.RequestCtx) {
+	host, hostID, ok := d.resolveHost(ctx)
+	if !ok {
+		return
+	}
+	dimensionName, dimension, limit, ok := d.resolveLinkReportParams(ctx, host)
+	if !ok {
+		return
+	}
+
+	inlinks, err := d.linkGraph.InlinkCounts(context.Background(), hostID, dimension.ID)
+	if handleRedisError(ctx, err, d.logger) {
+		return
+	}
+
+	response := OrphansResponse{HostID: hostID, Dimension: dimensionName, Pages: []OrphanPage{}}
+	cursor := "0"
+	for {
+		page, err := d.cacheReader.ListURLs(CacheListParams{
+			HostID:           hostID,
+			Cursor:           cursor,
+			Limit:            scheduledJobPageSize,
+			DimensionFilter:  dimensionName,
+			StatusCodeFilter: "200",
+		})
+		if handleRedisError(ctx, err, d.logger) {
+			return
+		}
+
+		for _, item := range page.Items {
+			cacheKey, err := d.keyGenerator.ParseCacheKey(item.CacheKey)
+			if err != nil || inlinks[cacheKey.URLHash] > 0 {
+				continue
+			}
+			response.Total++
+			if len(response.Pages) < limit {
+				response.Pages = append(response.Pages, OrphanPage{URL: item.URL, CacheKey: item.CacheKey, Source: item.Source})
+			}
+		}
+
+		if !page.HasMore {
+			break
+		}
+		cursor = page.Cursor
+	}
+	httputil.JSONData(ctx, response, fasthttp.StatusOK)
+
+	d.logger.Debug("Orphans request served",
+		zap.Int("host_id", hostID),
+		zap.String("dimension", dimensionName),
+		zap.Int("total", response.Total))
+}
+
 func (d *CacheDaemon) handleCacheQueueAPI(ctx *fasthttp.RequestCtx) {
 	host, _, ok := d.resolveHost(ctx)
 	if !ok {
@@ -1,8 +1,13 @@
 package cachedaemon
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
@@ -11,8 +16,12 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
 	"github.com/edgecomet/engine/internal/edge/hash"
+	"github.com/edgecomet/engine/internal/edge/seoaudit"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -84,9 +93,14 @@ func setupTestDaemon(t *testing.T) (*CacheDaemon, *miniredis.Miniredis) {
 		normalizer:      hash.NewURLNormalizer(),
 		internalQueue:   iq,
 		internalAuthKey: "test-auth-key",
+		apiAuth:         internalauth.NewAuthenticator("test-auth-key", nil),
 		configManager:   configMgr,
 		cacheReader:     NewCacheReader(redisClient, keyGen, logger),
 		queueReader:     NewQueueReader(redisClient, keyGen, iq, logger),
+		duplicateIndex:  cache.NewDuplicateIndex(redisClient, keyGen, logger),
+		seoAuditor:      seoaudit.NewAuditor(redisClient, keyGen, logger),
+		seoHistory:      cache.NewSEOHistory(redisClient, keyGen, 0, 0, logger),
+		linkGraph:       cache.NewLinkGraph(redisClient, keyGen, 0, 0, logger),
 	}
 
 	return daemon, mr
@@ -276,7 +290,7 @@ func TestHandlerValidation(t *testing.T) {
 
 	t.Run("empty auth key rejects request without header", func(t *testing.T) {
 		daemon, _ := setupTestDaemon(t)
-		daemon.internalAuthKey = ""
+		daemon.apiAuth = internalauth.NewAuthenticator("", nil)
 		ctx := &fasthttp.RequestCtx{}
 		ctx.Request.Header.SetMethod("GET")
 		ctx.Request.SetRequestURI("/status")
@@ -286,7 +300,7 @@ func TestHandlerValidation(t *testing.T) {
 
 	t.Run("empty auth key rejects request with empty header", func(t *testing.T) {
 		daemon, _ := setupTestDaemon(t)
-		daemon.internalAuthKey = ""
+		daemon.apiAuth = internalauth.NewAuthenticator("", nil)
 		ctx := &fasthttp.RequestCtx{}
 		ctx.Request.Header.SetMethod("GET")
 		ctx.Request.SetRequestURI("/status")
@@ -295,3 +309,297 @@ func TestHandlerValidation(t *testing.T) {
 		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
 	})
 }
+
+// storeDuplicatePage stores render metadata for a page and adds its signature to the duplicate index
+func storeDuplicatePage(t *testing.T, daemon *CacheDaemon, dimension string, dimensionID int, urlHash, url string, words []string) {
+	t.Helper()
+	ctx := context.Background()
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: dimensionID, URLHash: urlHash}
+	now := time.Now().UTC()
+	metadata := &cache.CacheMetadata{
+		Key:        cacheKey.String(),
+		URL:        url,
+		HostID:     1,
+		Dimension:  dimension,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(time.Hour),
+		LastAccess: now,
+		Source:     cache.SourceRender,
+		StatusCode: 200,
+		MinHash:    htmlprocessor.ComputeMinHash(words),
+	}
+	require.NoError(t, daemon.redis.HSet(ctx, daemon.keyGenerator.GenerateMetadataKey(cacheKey), metadata.ToHash()))
+	require.NoError(t, daemon.duplicateIndex.Ingest(ctx, cacheKey, metadata.MinHash, time.Hour))
+}
+
+func TestDuplicatesAPI(t *testing.T) {
+	words := make([]string, 200)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i)
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		for _, query := range []string{
+			"",
+			"?host_id=1&min_similarity=0.3",
+			"?host_id=1&min_similarity=1.5",
+			"?host_id=1&min_similarity=abc",
+			"?host_id=1&limit=0",
+			"?host_id=1&limit=101",
+			"?host_id=1&dimension=tablet",
+		} {
+			ctx := makeTestRequest(daemon, "GET", "/internal/cache/duplicates"+query)
+			assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode(), query)
+		}
+	})
+
+	t.Run("returns clusters", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		storeDuplicatePage(t, daemon, "mobile", 1, "a", "https://example.com/a", words)
+		storeDuplicatePage(t, daemon, "mobile", 1, "b", "https://example.com/a?utm_source=x", words)
+		storeDuplicatePage(t, daemon, "desktop", 2, "a", "https://example.com/a", words)
+		storeDuplicatePage(t, daemon, "desktop", 2, "b", "https://example.com/a?utm_source=x", words)
+
+		ctx := makeTestRequest(daemon, "GET", "/internal/cache/duplicates?host_id=1")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+		var resp struct {
+			Data DuplicatesResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &resp))
+		assert.Equal(t, 0.9, resp.Data.MinSimilarity)
+		assert.Equal(t, 2, resp.Data.TotalClusters)
+		assert.Equal(t, 4, resp.Data.IndexedPages)
+
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/duplicates?host_id=1&dimension=desktop&min_similarity=0.95")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &resp))
+		require.Len(t, resp.Data.Clusters, 1)
+		assert.Equal(t, "desktop", resp.Data.Clusters[0].Dimension)
+		assert.Equal(t, "https://example.com/a", resp.Data.Clusters[0].Pages[0].URL)
+
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/duplicates?host_id=1&limit=1")
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &resp))
+		assert.Equal(t, 2, resp.Data.TotalClusters)
+		assert.Len(t, resp.Data.Clusters, 1)
+	})
+
+	t.Run("report is read-only, prune removes expired pages", func(t *testing.T) {
+		daemon, mr := setupTestDaemon(t)
+		storeDuplicatePage(t, daemon, "mobile", 1, "a", "https://example.com/a", words)
+		storeDuplicatePage(t, daemon, "mobile", 1, "b", "https://example.com/b", words)
+		expired := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "b"}
+		mr.Del(daemon.keyGenerator.GenerateMetadataKey(expired))
+		before := mr.Dump()
+
+		ctx := makeTestRequest(daemon, "GET", "/internal/cache/duplicates?host_id=1")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Contains(t, string(ctx.Response.Body()), `"total_clusters":0`)
+		assert.Equal(t, before, mr.Dump(), "GET does not modify the index")
+
+		daemon.PruneDuplicateIndex(context.Background())
+		for _, key := range mr.Keys() {
+			if isMember, _ := mr.SIsMember(key, expired.String()); isMember {
+				t.Errorf("expired member still in bucket %s", key)
+			}
+		}
+	})
+}
+
+func TestSEOReportAPI(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		for _, path := range []string{
+			"/internal/cache/seo",
+			"/internal/cache/seo?host_id=1&limit=0",
+			"/internal/cache/seo?host_id=1&dimension=tablet",
+			"/internal/cache/urls?host_id=1&seo_issue=duplicate_title",
+		} {
+			ctx := makeTestRequest(daemon, "GET", path)
+			assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode(), path)
+		}
+	})
+
+	t.Run("returns findings", func(t *testing.T) {
+		daemon, mr := setupTestDaemon(t)
+		now := time.Now().Unix()
+		for _, hash := range []string{"a", "b"} {
+			populateMetadataHash(mr, 1, 1, hash, map[string]string{
+				"host_id":     "1",
+				"url":         "https://example.com/" + hash,
+				"dimension":   "mobile",
+				"size":        "500",
+				"created_at":  fmt.Sprintf("%d", now-100),
+				"expires_at":  fmt.Sprintf("%d", now+3600),
+				"last_access": fmt.Sprintf("%d", now-100),
+				"source":      "render",
+				"status_code": "200",
+				"title":       "Same title",
+				"seo_issues":  "missing_h1",
+			})
+		}
+
+		ctx := makeTestRequest(daemon, "GET", "/internal/cache/seo?host_id=1&dimension=mobile&limit=1")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+
+		var resp struct {
+			Data SEOReportResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &resp))
+		assert.Equal(t, "mobile", resp.Data.Dimension)
+		assert.Equal(t, 2, resp.Data.PagesAudited)
+		require.Len(t, resp.Data.Findings, 2)
+		assert.Equal(t, seoaudit.RuleDuplicateTitle, resp.Data.Findings[0].Rule)
+		assert.Equal(t, 2, resp.Data.Findings[0].Pages)
+		assert.Len(t, resp.Data.Findings[0].URLs, 1)
+		assert.Equal(t, seoaudit.RuleMissingH1, resp.Data.Findings[1].Rule)
+
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/urls?host_id=1&seo_issue=missing_h1")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Contains(t, string(ctx.Response.Body()), `"seo_issues":["missing_h1"]`)
+	})
+}
+
+func TestSEOHistoryAPI(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		for _, path := range []string{
+			"/internal/cache/seo/history?host_id=1&dimension=mobile",
+			"/internal/cache/seo/history?host_id=1&url=https://example.com/",
+			"/internal/cache/seo/history?host_id=1&url=https://example.com/&dimension=tablet",
+			"/internal/cache/seo/history?host_id=1&url=not-a-url&dimension=mobile",
+		} {
+			ctx := makeTestRequest(daemon, "GET", path)
+			assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode(), path)
+		}
+	})
+
+	t.Run("returns versions and changes", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		recorder := cache.NewSEOHistory(daemon.redis, daemon.keyGenerator, 10, time.Hour, zap.NewNop())
+
+		normalized, err := daemon.normalizer.Normalize("https://example.com/shoes", nil)
+		require.NoError(t, err)
+		cacheKey := daemon.keyGenerator.GenerateCacheKey(1, 1, daemon.normalizer.Hash(normalized.NormalizedURL))
+		start := time.Unix(1700000000, 0).UTC()
+		ctx := context.Background()
+		require.NoError(t, recorder.Record(ctx, cacheKey, &types.PageSEO{Title: "Shoes"}, start))
+		require.NoError(t, recorder.Record(ctx, cacheKey, &types.PageSEO{Title: "Shoes | Shop", H1s: []string{"Shoes"}}, start.Add(time.Hour)))
+
+		reqCtx := makeTestRequest(daemon, "GET", "/internal/cache/seo/history?host_id=1&url=https://example.com/shoes&dimension=mobile")
+		require.Equal(t, fasthttp.StatusOK, reqCtx.Response.StatusCode())
+
+		var resp struct {
+			Data SEOHistoryResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(reqCtx.Response.Body(), &resp))
+		assert.Equal(t, cacheKey.String(), resp.Data.CacheKey)
+		require.Len(t, resp.Data.Versions, 2)
+		assert.Equal(t, "Shoes | Shop", resp.Data.Versions[0].SEO.Title)
+		require.Len(t, resp.Data.Changes, 1)
+		assert.Equal(t, start, resp.Data.Changes[0].From)
+		assert.Equal(t, start.Add(time.Hour), resp.Data.Changes[0].To)
+		require.Len(t, resp.Data.Changes[0].Fields, 2)
+		assert.Equal(t, "title", resp.Data.Changes[0].Fields[0].Field)
+		assert.Equal(t, "h1s", resp.Data.Changes[0].Fields[1].Field)
+
+		reqCtx = makeTestRequest(daemon, "GET", "/internal/cache/seo/history?host_id=1&url=https://example.com/other&dimension=mobile")
+		require.Equal(t, fasthttp.StatusOK, reqCtx.Response.StatusCode())
+		assert.Contains(t, string(reqCtx.Response.Body()), `"versions":[]`)
+	})
+}
+
+// storeLinkPage stores render metadata for a desktop page and its outlinks
+func storeLinkPage(t *testing.T, daemon *CacheDaemon, rawURL string, statusCode int, links []string) {
+	t.Helper()
+	ctx := context.Background()
+	normalized, err := daemon.normalizer.Normalize(rawURL, nil)
+	require.NoError(t, err)
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: 2, URLHash: daemon.normalizer.Hash(normalized.NormalizedURL)}
+	now := time.Now().UTC()
+	metadata := &cache.CacheMetadata{
+		Key:        cacheKey.String(),
+		URL:        normalized.NormalizedURL,
+		HostID:     1,
+		Dimension:  "desktop",
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(time.Hour),
+		LastAccess: now,
+		Source:     cache.SourceRender,
+		StatusCode: statusCode,
+	}
+	require.NoError(t, daemon.redis.HSet(ctx, daemon.keyGenerator.GenerateMetadataKey(cacheKey), metadata.ToHash()))
+
+	recorder := cache.NewLinkGraph(daemon.redis, daemon.keyGenerator, time.Hour, 0, zap.NewNop())
+	_, err = recorder.Ingest(ctx, cacheKey, links, []string{"example.com"}, nil)
+	require.NoError(t, err)
+}
+
+func TestLinkGraphAPI(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		for _, path := range []string{
+			"/internal/cache/links/inlinks?host_id=1",
+			"/internal/cache/links/orphans?host_id=1&dimension=tablet",
+			"/internal/cache/links/broken?host_id=1&dimension=desktop&limit=0",
+			"/internal/cache/links/inlinks?host_id=1&dimension=desktop&url=nohost",
+		} {
+			ctx := makeTestRequest(daemon, "GET", path)
+			assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode(), path)
+		}
+	})
+
+	t.Run("reports", func(t *testing.T) {
+		daemon, _ := setupTestDaemon(t)
+		storeLinkPage(t, daemon, "https://example.com/", 200, []string{"https://example.com/a", "https://example.com/missing"})
+		storeLinkPage(t, daemon, "https://example.com/a", 200, []string{"https://example.com/missing"})
+		storeLinkPage(t, daemon, "https://example.com/orphan", 200, nil)
+
+		var inlinks struct {
+			Data LinkReportResponse `json:"data"`
+		}
+		ctx := makeTestRequest(daemon, "GET", "/internal/cache/links/inlinks?host_id=1&dimension=desktop")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &inlinks))
+		assert.Equal(t, 2, inlinks.Data.Total)
+		require.Len(t, inlinks.Data.Pages, 2)
+		assert.Equal(t, "https://example.com/missing", inlinks.Data.Pages[0].URL)
+		assert.Equal(t, 2, inlinks.Data.Pages[0].Inlinks)
+
+		var page struct {
+			Data PageInlinksResponse `json:"data"`
+		}
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/links/inlinks?host_id=1&dimension=desktop&url=https://example.com/missing")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &page))
+		assert.Equal(t, []string{"https://example.com/", "https://example.com/a"}, page.Data.LinkedFrom)
+
+		// Tracking parameters are stripped the same way as when the links were ingested
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/links/inlinks?host_id=1&dimension=desktop&url="+
+			url.QueryEscape("https://example.com/missing?utm_source=newsletter"))
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &page))
+		assert.Equal(t, "https://example.com/missing", page.Data.URL)
+		assert.Equal(t, 2, page.Data.Inlinks)
+
+		var broken struct {
+			Data LinkReportResponse `json:"data"`
+		}
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/links/broken?host_id=1&dimension=desktop")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &broken))
+		assert.Equal(t, 1, broken.Data.Total)
+		assert.False(t, broken.Data.Pages[0].Cached)
+
+		var orphans struct {
+			Data OrphansResponse `json:"data"`
+		}
+		ctx = makeTestRequest(daemon, "GET", "/internal/cache/links/orphans?host_id=1&dimension=desktop")
+		require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		require.NoError(t, json.Unmarshal(ctx.Response.Body(), &orphans))
+		assert.Equal(t, 2, orphans.Data.Total)
+		urls := []string{orphans.Data.Pages[0].URL, orphans.Data.Pages[1].URL}
+		assert.ElementsMatch(t, []string{"https://example.com/", "https://example.com/orphan"}, urls)
+	})
+}
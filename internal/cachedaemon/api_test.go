@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -23,6 +25,40 @@ func makePostRequest(daemon *CacheDaemon, path string, body []byte) *fasthttp.Re
 	return ctx
 }
 
+func TestServeHTTP_CredentialScopes(t *testing.T) {
+	daemon, _ := setupTestDaemon(t)
+	daemon.apiAuth = internalauth.NewAuthenticator("test-auth-key", []configtypes.InternalCredential{
+		{Name: "dashboard", Key: "dashboard-key-0123456789", Scopes: []string{internalauth.ScopeRead}},
+		{Name: "cms", Key: "cms-key-0123456789", Scopes: []string{internalauth.ScopeInvalidate}},
+	})
+
+	tests := []struct {
+		name     string
+		key      string
+		method   string
+		path     string
+		expected int
+	}{
+		{"read scope lists cache", "dashboard-key-0123456789", "GET", "/internal/cache/summary?host_id=1", fasthttp.StatusOK},
+		{"read scope cannot invalidate", "dashboard-key-0123456789", "POST", "/internal/cache/invalidate-all", fasthttp.StatusForbidden},
+		{"invalidate scope cannot read", "cms-key-0123456789", "GET", "/status", fasthttp.StatusForbidden},
+		{"invalidate scope cannot recache", "cms-key-0123456789", "POST", "/internal/cache/recache", fasthttp.StatusForbidden},
+		{"shared key keeps full access", "test-auth-key", "GET", "/internal/cache/summary?host_id=1", fasthttp.StatusOK},
+		{"unknown key", "unknown-key", "GET", "/status", fasthttp.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(tt.method)
+			ctx.Request.SetRequestURI(tt.path)
+			ctx.Request.Header.Set("X-Internal-Auth", tt.key)
+			daemon.ServeHTTP(ctx)
+			assert.Equal(t, tt.expected, ctx.Response.StatusCode())
+		})
+	}
+}
+
 func TestResolveDimensionIDs(t *testing.T) {
 	host := &types.Host{
 		Domain: "example.com",
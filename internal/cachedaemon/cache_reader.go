@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
 )
 
 const (
@@ -34,6 +36,7 @@ local cache_age_max = tonumber(ARGV[12])
 local status_code_filter = ARGV[13]
 local source_filter = ARGV[14]
 local index_status_filter = ARGV[15]
+local seo_issue_filter = ARGV[16]
 
 local max_scan_iterations = 200
 local scan_iterations = 0
@@ -114,6 +117,20 @@ repeat
             end
         end
 
+        if pass and seo_issue_filter ~= "" then
+            local issues = "," .. (hash["seo_issues"] or "") .. ","
+            local matched = false
+            for rule in string.gmatch(seo_issue_filter, "[^,]+") do
+                if string.find(issues, "," .. rule .. ",", 1, true) then
+                    matched = true
+                    break
+                end
+            end
+            if not matched then
+                pass = false
+            end
+        end
+
         if pass then
             hash["_status"] = status
             hash["_age"] = tostring(now - tonumber(hash["created_at"] or "0"))
@@ -199,21 +216,27 @@ func NewCacheReader(redisClient *redis.Client, keyGenerator *redis.KeyGenerator,
 }
 
 type CacheURLItem struct {
-	URL         string `json:"url"`
-	Title       string `json:"title"`
-	Dimension   string `json:"dimension"`
-	Status      string `json:"status"`
-	CacheAge    int64  `json:"cache_age"`
-	Size        int64  `json:"size"`
-	DiskSize    int64  `json:"disk_size"`
-	LastAccess  int64  `json:"last_access"`
-	CacheKey    string `json:"cache_key"`
-	CreatedAt   int64  `json:"created_at"`
-	ExpiresAt   int64  `json:"expires_at"`
-	StatusCode  int    `json:"status_code"`
-	Source      string `json:"source"`
-	IndexStatus int    `json:"index_status"`
-	LastBotHit  *int64 `json:"last_bot_hit,omitempty"`
+	URL         string   `json:"url"`
+	Title       string   `json:"title"`
+	Dimension   string   `json:"dimension"`
+	Status      string   `json:"status"`
+	CacheAge    int64    `json:"cache_age"`
+	Size        int64    `json:"size"`
+	DiskSize    int64    `json:"disk_size"`
+	LastAccess  int64    `json:"last_access"`
+	CacheKey    string   `json:"cache_key"`
+	CreatedAt   int64    `json:"created_at"`
+	ExpiresAt   int64    `json:"expires_at"`
+	StatusCode  int      `json:"status_code"`
+	Source      string   `json:"source"`
+	IndexStatus int      `json:"index_status"`
+	LastBotHit  *int64   `json:"last_bot_hit,omitempty"`
+	SEOIssues   []string `json:"seo_issues,omitempty"`
+
+	// From the stored PageSEO snapshot (empty for entries cached without one)
+	CanonicalURL string   `json:"canonical_url,omitempty"`
+	MetaRobots   []string `json:"meta_robots,omitempty"`
+	WordCount    int      `json:"word_count,omitempty"`
 }
 
 type CacheURLsResponse struct {
@@ -246,6 +269,7 @@ type CacheListParams struct {
 	StatusCodeFilter  string
 	SourceFilter      string
 	IndexStatusFilter string
+	SEOIssueFilter    string
 	StaleTTL          int64
 }
 
@@ -269,6 +293,7 @@ func (cr *CacheReader) ListURLs(params CacheListParams) (*CacheURLsResponse, err
 		params.StatusCodeFilter,
 		params.SourceFilter,
 		params.IndexStatusFilter,
+		params.SEOIssueFilter,
 	)
 	if err != nil {
 		return nil, err
@@ -315,6 +340,18 @@ func (cr *CacheReader) ListURLs(params CacheListParams) (*CacheURLsResponse, err
 			item.LastBotHit = &lbh
 		}
 
+		if seoIssues := stringFromMap(raw, "seo_issues"); seoIssues != "" {
+			item.SEOIssues = strings.Split(seoIssues, ",")
+		}
+
+		item.CanonicalURL = stringFromMap(raw, "canonical_url")
+		if snapshot := stringFromMap(raw, "seo"); snapshot != "" {
+			if seo, err := cache.DecodePageSEO(snapshot); err == nil {
+				item.MetaRobots = seo.MetaRobots
+				item.WordCount = seo.WordCount
+			}
+		}
+
 		items = append(items, item)
 	}
 
@@ -346,6 +346,82 @@ func TestCacheReader_ListURLs(t *testing.T) {
 		assert.Equal(t, 1, result.Items[0].IndexStatus)
 	})
 
+	t.Run("seo_issue filter", func(t *testing.T) {
+		cr, mr := setupTestCacheReader(t)
+
+		populateMetadataHash(mr, 1, 1, "seo1", map[string]string{
+			"url":        "https://example.com/thin",
+			"dimension":  "mobile",
+			"size":       "500",
+			"created_at": fmt.Sprintf("%d", now-100),
+			"expires_at": fmt.Sprintf("%d", now+3600),
+			"source":     "render",
+			"seo_issues": "missing_h1,low_word_count",
+		})
+		populateMetadataHash(mr, 1, 1, "seo2", map[string]string{
+			"url":        "https://example.com/multiple",
+			"dimension":  "mobile",
+			"size":       "500",
+			"created_at": fmt.Sprintf("%d", now-100),
+			"expires_at": fmt.Sprintf("%d", now+3600),
+			"source":     "render",
+			"seo_issues": "multiple_h1",
+		})
+		populateMetadataHash(mr, 1, 1, "seo3", map[string]string{
+			"url":        "https://example.com/clean",
+			"dimension":  "mobile",
+			"size":       "500",
+			"created_at": fmt.Sprintf("%d", now-100),
+			"expires_at": fmt.Sprintf("%d", now+3600),
+			"source":     "render",
+		})
+
+		result, err := cr.ListURLs(CacheListParams{
+			HostID:         1,
+			Cursor:         "0",
+			Limit:          100,
+			SEOIssueFilter: "low_word_count,noindex",
+			StaleTTL:       600,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, "https://example.com/thin", result.Items[0].URL)
+		assert.Equal(t, []string{"missing_h1", "low_word_count"}, result.Items[0].SEOIssues)
+
+		// "h1" must not match "missing_h1" or "multiple_h1" as a substring
+		result, err = cr.ListURLs(CacheListParams{
+			HostID:         1,
+			Cursor:         "0",
+			Limit:          100,
+			SEOIssueFilter: "h1",
+			StaleTTL:       600,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result.Items)
+	})
+
+	t.Run("SEO snapshot fields", func(t *testing.T) {
+		cr, mr := setupTestCacheReader(t)
+
+		populateMetadataHash(mr, 1, 1, "snap1", map[string]string{
+			"url":           "https://example.com/snapshot",
+			"dimension":     "mobile",
+			"size":          "500",
+			"created_at":    fmt.Sprintf("%d", now-100),
+			"expires_at":    fmt.Sprintf("%d", now+3600),
+			"source":        "render",
+			"canonical_url": "https://example.com/canonical",
+			"seo":           `{"title":"Snapshot","meta_robots":["noindex","follow"],"word_count":420}`,
+		})
+
+		result, err := cr.ListURLs(CacheListParams{HostID: 1, Cursor: "0", Limit: 100, StaleTTL: 600})
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, "https://example.com/canonical", result.Items[0].CanonicalURL)
+		assert.Equal(t, []string{"noindex", "follow"}, result.Items[0].MetaRobots)
+		assert.Equal(t, 420, result.Items[0].WordCount)
+	})
+
 	t.Run("combined filters", func(t *testing.T) {
 		cr, mr := setupTestCacheReader(t)
 
@@ -10,36 +10,54 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/edgecomet/engine/internal/cachedaemon/metrics"
+	"github.com/edgecomet/engine/internal/common/config"
 	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/eventbus"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 	"github.com/edgecomet/engine/internal/common/metricsserver"
 	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
 	"github.com/edgecomet/engine/internal/edge/hash"
+	"github.com/edgecomet/engine/internal/edge/seoaudit"
 	"github.com/edgecomet/engine/internal/edge/sharding"
 	"github.com/edgecomet/engine/internal/render/registry"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
+// duplicatePruneInterval is how often expired members are removed from the duplicate index
+const duplicatePruneInterval = time.Hour
+
 // CacheDaemon is the main cache daemon service
 type CacheDaemon struct {
 	daemonConfig    *configtypes.CacheDaemonConfig
 	configManager   configtypes.EGConfigManager
 	redis           *redis.Client
 	logger          *zap.Logger
-	internalAuthKey string // Internal auth key from EG config (cache_sharding.internal_auth_key)
+	internalAuthKey string                      // Key sent to EGs (eg_client.auth_key, default EG internal.auth_key)
+	apiAuth         *internalauth.Authenticator // Authenticates daemon API callers
+	egScheme        string                      // "http" or "https" for EG internal APIs
 	internalQueue   *InternalQueue
 	rsRegistry      *registry.ServiceRegistry
 	egRegistry      sharding.Registry
 	normalizer      *hash.URLNormalizer
 	keyGenerator    *redis.KeyGenerator
+	events          *eventbus.Bus // Publishes cache changes to the cluster
 	httpClient      *fasthttp.Client
-	retryBaseDelay  time.Duration // Override for testing (0 = use default from distributor.go)
+	retryBaseDelay  time.Duration       // Override for testing (0 = use default from distributor.go)
+	fairShare       *FairShareScheduler // nil when scheduler.fair_share is disabled
+	schedules       *ScheduleManager    // nil when no schedules are configured
+	webhookTags     map[string][]webhookTag
 	startTime       time.Time
 	lastTickMu      sync.RWMutex
 	lastTickTime    time.Time
 
 	// Readers
-	cacheReader *CacheReader
-	queueReader *QueueReader
+	cacheReader    *CacheReader
+	queueReader    *QueueReader
+	duplicateIndex *cache.DuplicateIndex
+	seoAuditor     *seoaudit.Auditor
+	seoHistory     *cache.SEOHistory
+	linkGraph      *cache.LinkGraph
 
 	// Metrics
 	metricsCollector *metrics.MetricsCollector
@@ -72,12 +90,26 @@ func NewCacheDaemon(
 		return nil, fmt.Errorf("logger is required")
 	}
 
-	// Get internal auth key from EG config (internal.auth_key)
+	// API callers authenticate with the EG internal.auth_key (all scopes) or http_api.credentials
 	egConfig := configManager.GetConfig()
-	if egConfig.Internal.AuthKey == "" {
-		return nil, fmt.Errorf("internal.auth_key in EG config is required for daemon API authentication")
+	if egConfig.Internal.AuthKey == "" && len(daemonCfg.HTTPApi.Credentials) == 0 {
+		return nil, fmt.Errorf("internal.auth_key in EG config or http_api.credentials is required for daemon API authentication")
+	}
+	apiAuth := internalauth.NewAuthenticator(egConfig.Internal.AuthKey, daemonCfg.HTTPApi.Credentials)
+
+	// Recache calls to EGs use eg_client.auth_key, falling back to the shared key
+	internalAuthKey := daemonCfg.EGClient.AuthKey
+	if internalAuthKey == "" {
+		internalAuthKey = egConfig.Internal.AuthKey
+	}
+	if internalAuthKey == "" && daemonCfg.EGClient.TLS.CertFile == "" {
+		return nil, fmt.Errorf("eg_client.auth_key or eg_client.tls client certificate is required when EG internal.auth_key is not set")
+	}
+
+	egTLS, err := internalauth.ClientTLSConfig(&daemonCfg.EGClient.TLS, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load eg_client TLS config: %w", err)
 	}
-	internalAuthKey := egConfig.Internal.AuthKey
 
 	// Initialize internal queue
 	internalQueue := NewInternalQueue(daemonCfg.InternalQueue.MaxSize)
@@ -99,6 +131,7 @@ func NewCacheDaemon(
 		ReadTimeout:         time.Duration(daemonCfg.Recache.TimeoutPerURL),
 		WriteTimeout:        time.Duration(daemonCfg.Recache.TimeoutPerURL),
 		MaxIdleConnDuration: 500 * time.Millisecond,
+		TLSConfig:           egTLS,
 	}
 
 	// Get retry base delay from config (default: 5s)
@@ -128,24 +161,64 @@ func NewCacheDaemon(
 		return nil, fmt.Errorf("failed to start metrics server: %w", err)
 	}
 
+	// Initialize fair-share scheduling across hosts
+	var fairShare *FairShareScheduler
+	if daemonCfg.Scheduler.FairShare.Enabled {
+		fairShare = NewFairShareScheduler(&daemonCfg.Scheduler.FairShare)
+		logger.Info("Fair-share scheduling enabled",
+			zap.Int("quantum", daemonCfg.Scheduler.FairShare.Quantum),
+			zap.Int("default_weight", daemonCfg.Scheduler.FairShare.DefaultWeight),
+			zap.Int("default_max_concurrent", daemonCfg.Scheduler.FairShare.DefaultMaxConcurrent),
+			zap.Int("host_overrides", len(daemonCfg.Scheduler.FairShare.Hosts)))
+	}
+
+	// Initialize time windows and cron jobs
+	var schedules *ScheduleManager
+	if len(daemonCfg.Schedules.Hosts) > 0 || len(daemonCfg.Schedules.Jobs) > 0 {
+		schedules, err = NewScheduleManager(&daemonCfg.Schedules, time.Now().UTC())
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize schedules: %w", err)
+		}
+		logger.Info("Recache schedules enabled",
+			zap.String("timezone", daemonCfg.Schedules.Location().String()),
+			zap.Int("host_windows", len(daemonCfg.Schedules.Hosts)),
+			zap.Int("jobs", len(daemonCfg.Schedules.Jobs)))
+	}
+
+	if daemonCfg.Webhooks.Enabled {
+		logger.Info("Origin webhooks enabled",
+			zap.String("path", webhookPath),
+			zap.Int("tags", len(daemonCfg.Webhooks.Tags)))
+	}
+
 	daemon := &CacheDaemon{
 		daemonConfig:     daemonCfg,
 		configManager:    configManager,
 		redis:            redisClient,
 		logger:           logger,
 		internalAuthKey:  internalAuthKey,
+		apiAuth:          apiAuth,
+		egScheme:         internalauth.Scheme(&daemonCfg.EGClient.TLS),
 		internalQueue:    internalQueue,
 		rsRegistry:       rsRegistry,
 		egRegistry:       egRegistry,
 		normalizer:       normalizer,
 		keyGenerator:     keyGenerator,
+		events:           eventbus.New(redisClient, eventbus.ComponentCacheDaemon, daemonCfg.DaemonID, logger),
 		httpClient:       httpClient,
 		retryBaseDelay:   retryBaseDelay,
+		fairShare:        fairShare,
+		schedules:        schedules,
+		webhookTags:      compileWebhookTags(&daemonCfg.Webhooks),
 		startTime:        time.Now().UTC(),
 		metricsCollector: metricsCollector,
 		metricsServer:    metricsServer,
 		cacheReader:      NewCacheReader(redisClient, keyGenerator, logger),
 		queueReader:      NewQueueReader(redisClient, keyGenerator, internalQueue, logger),
+		duplicateIndex:   cache.NewDuplicateIndex(redisClient, keyGenerator, logger),
+		seoAuditor:       seoaudit.NewAuditor(redisClient, keyGenerator, logger),
+		seoHistory:       cache.NewSEOHistory(redisClient, keyGenerator, 0, 0, logger), // Read-only, EGs record versions
+		linkGraph:        cache.NewLinkGraph(redisClient, keyGenerator, 0, 0, logger),  // Read-only, EGs record links
 	}
 
 	return daemon, nil
@@ -161,10 +234,49 @@ func (d *CacheDaemon) Start(ctx context.Context) error {
 	// Start scheduler in separate goroutine
 	go d.Run(d.schedulerCtx)
 
+	// Prune expired entries from the duplicate index outside of the read-only report API
+	go d.RunDuplicateIndexPrune(d.schedulerCtx)
+
+	d.events.PublishConfigReloaded(ctx)
+
 	d.logger.Info("Cache daemon components started")
 	return nil
 }
 
+// RunDuplicateIndexPrune periodically removes duplicate index members whose cache
+// metadata expired without a delete. It returns when ctx is cancelled.
+func (d *CacheDaemon) RunDuplicateIndexPrune(ctx context.Context) {
+	ticker := time.NewTicker(duplicatePruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.PruneDuplicateIndex(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// PruneDuplicateIndex removes expired duplicate index members of every configured host
+func (d *CacheDaemon) PruneDuplicateIndex(ctx context.Context) {
+	for _, hostID := range d.GetConfiguredHosts() {
+		removed, err := d.duplicateIndex.Prune(ctx, hostID)
+		if err != nil {
+			d.logger.Warn("Failed to prune duplicate index",
+				zap.Int("host_id", hostID),
+				zap.Error(err))
+			continue
+		}
+		if removed > 0 {
+			d.logger.Debug("Pruned duplicate index",
+				zap.Int("host_id", hostID),
+				zap.Int("removed", removed))
+		}
+	}
+}
+
 // Shutdown gracefully shuts down the cache daemon
 func (d *CacheDaemon) Shutdown() error {
 	d.logger.Info("Shutting down cache daemon")
@@ -285,3 +397,23 @@ func (d *CacheDaemon) getStaleTTL(host *types.Host) int64 {
 	}
 	return 0
 }
+
+// getStripPatterns resolves the tracking parameters stripped from a URL of the host,
+// matching the normalization applied when the URL was cached and linked
+func (d *CacheDaemon) getStripPatterns(host *types.Host, url string) []config.CompiledStripPattern {
+	egConfig := d.configManager.GetConfig()
+	resolver := config.NewConfigResolver(
+		&egConfig.Render,
+		&egConfig.Bypass,
+		egConfig.TrackingParams,
+		egConfig.CacheSharding,
+		egConfig.BothitRecache,
+		egConfig.Headers,
+		egConfig.Storage.Compression,
+		host,
+	)
+	if tp := resolver.ResolveForURL(url).TrackingParams; tp != nil && tp.Enabled {
+		return tp.CompiledPatterns
+	}
+	return nil
+}
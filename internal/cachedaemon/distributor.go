@@ -30,20 +30,14 @@ func (d *CacheDaemon) DistributeToEGs(batch []InternalQueueEntry) {
 		d.logger.Error("Failed to query EG registry",
 			zap.Error(err),
 			zap.Int("batch_size", len(batch)))
-		// Re-enqueue entire batch
-		for _, entry := range batch {
-			d.internalQueue.Enqueue(entry)
-		}
+		d.requeueBatch(batch)
 		return
 	}
 
 	if len(egs) == 0 {
 		d.logger.Warn("No healthy EGs available, re-enqueueing batch",
 			zap.Int("batch_size", len(batch)))
-		// Re-enqueue entire batch
-		for _, entry := range batch {
-			d.internalQueue.Enqueue(entry)
-		}
+		d.requeueBatch(batch)
 		return
 	}
 
@@ -86,6 +80,16 @@ func (d *CacheDaemon) DistributeToEGs(batch []InternalQueueEntry) {
 	d.HandleRecacheResults(resultsChan)
 }
 
+// requeueBatch returns a batch that could not be dispatched to the internal queue
+func (d *CacheDaemon) requeueBatch(batch []InternalQueueEntry) {
+	for _, entry := range batch {
+		d.internalQueue.Enqueue(entry)
+		if d.fairShare != nil {
+			d.fairShare.FinishDispatch(entry.HostID)
+		}
+	}
+}
+
 // SendBatchToEG sends a batch of recache requests to a single EG concurrently
 func (d *CacheDaemon) SendBatchToEG(egAddress string, batch []InternalQueueEntry, results chan<- RecacheResult, wg *sync.WaitGroup) {
 	defer wg.Done()
@@ -116,9 +120,17 @@ func (d *CacheDaemon) SendBatchToEG(egAddress string, batch []InternalQueueEntry
 	batchWG.Wait()
 }
 
+// scheme returns the URL scheme for EG internal APIs (tests build daemons without it)
+func (d *CacheDaemon) scheme() string {
+	if d.egScheme == "" {
+		return "http"
+	}
+	return d.egScheme
+}
+
 // SendRecacheRequest sends a single recache request to an EG
 func (d *CacheDaemon) SendRecacheRequest(egAddress string, entry InternalQueueEntry) error {
-	url := fmt.Sprintf("http://%s/internal/cache/recache", egAddress)
+	url := fmt.Sprintf("%s://%s/internal/cache/recache", d.scheme(), egAddress)
 
 	// Build request body
 	body := recache.RecacheRequest{
@@ -174,6 +186,7 @@ func (d *CacheDaemon) HandleRecacheResults(resultsChan chan RecacheResult) {
 	for result := range resultsChan {
 		if result.Success {
 			successCount++
+			d.recordHostResult(result.Entry.HostID, "success")
 		} else {
 			// Increment retry count
 			result.Entry.RetryCount++
@@ -187,6 +200,7 @@ func (d *CacheDaemon) HandleRecacheResults(resultsChan chan RecacheResult) {
 				// Re-enqueue for retry
 				failedEntries = append(failedEntries, result.Entry)
 				retryCount++
+				d.recordHostResult(result.Entry.HostID, "retry")
 
 				d.logger.Debug("Recache failed, will retry with backoff",
 					zap.Int("host_id", result.Entry.HostID),
@@ -198,6 +212,7 @@ func (d *CacheDaemon) HandleRecacheResults(resultsChan chan RecacheResult) {
 			} else {
 				// Discard after max retries
 				discardCount++
+				d.recordHostResult(result.Entry.HostID, "discard")
 
 				d.logger.Error("Recache failed after max retries, discarding",
 					zap.Int("host_id", result.Entry.HostID),
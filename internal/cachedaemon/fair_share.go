@@ -0,0 +1,366 @@
+package cachedaemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// fairShareDispatchQueue keys DRR state for the internal queue -> EG dispatch stage.
+// Redis priorities (high, normal, autorecache) are used as keys for the pull stage.
+const fairShareDispatchQueue = "dispatch"
+
+// FairShareScheduler splits a shared budget across hosts using deficit round robin (DRR).
+// Each round a host with pending work is credited quantum*weight entries; unused credit
+// carries over while the host still has demand and is reset once its queue drains.
+// State is kept per queue so high, normal, autorecache and dispatch are balanced independently.
+// The scheduler also counts recaches in flight per host to enforce max_concurrent.
+type FairShareScheduler struct {
+	mu       sync.Mutex
+	cfg      *configtypes.CacheDaemonFairShare
+	deficits map[string]map[int]int
+	cursors  map[string]int // Last host served per queue, next round starts after it
+	inFlight map[int]int    // Recaches dispatched to EGs per host and not yet finished
+}
+
+// NewFairShareScheduler creates a DRR scheduler for the given configuration
+func NewFairShareScheduler(cfg *configtypes.CacheDaemonFairShare) *FairShareScheduler {
+	return &FairShareScheduler{
+		cfg:      cfg,
+		deficits: make(map[string]map[int]int),
+		cursors:  make(map[string]int),
+		inFlight: make(map[int]int),
+	}
+}
+
+// Allocate distributes up to budget entries across hosts with pending demand.
+// Returns the number of entries each host may take; hosts with zero allocation are omitted.
+func (s *FairShareScheduler) Allocate(queue string, demand map[int]int, budget int) map[int]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	allocation := make(map[int]int)
+
+	deficits, ok := s.deficits[queue]
+	if !ok {
+		deficits = make(map[int]int)
+		s.deficits[queue] = deficits
+	}
+
+	// Hosts without demand lose accumulated credit (standard DRR)
+	for hostID := range deficits {
+		if demand[hostID] <= 0 {
+			delete(deficits, hostID)
+		}
+	}
+
+	active := s.rotatedActiveHosts(queue, demand)
+	if len(active) == 0 || budget <= 0 {
+		return allocation
+	}
+
+	remaining := make(map[int]int, len(active))
+	for _, hostID := range active {
+		remaining[hostID] = demand[hostID]
+	}
+
+	quantum := s.cfg.Quantum
+	if quantum <= 0 {
+		quantum = configtypes.DefaultFairShareQuantum
+	}
+
+	for budget > 0 && len(active) > 0 {
+		stillActive := active[:0]
+		for _, hostID := range active {
+			if budget == 0 {
+				stillActive = append(stillActive, hostID)
+				continue
+			}
+
+			deficits[hostID] += quantum * s.cfg.WeightFor(hostID)
+
+			n := min(deficits[hostID], remaining[hostID], budget)
+			allocation[hostID] += n
+			deficits[hostID] -= n
+			remaining[hostID] -= n
+			budget -= n
+			s.cursors[queue] = hostID
+
+			if remaining[hostID] == 0 {
+				delete(deficits, hostID)
+				continue
+			}
+			stillActive = append(stillActive, hostID)
+		}
+		active = stillActive
+	}
+
+	for hostID, n := range allocation {
+		if n == 0 {
+			delete(allocation, hostID)
+		}
+	}
+
+	return allocation
+}
+
+// Deficit returns the current carried-over credit for a host in the given queue
+func (s *FairShareScheduler) Deficit(queue string, hostID int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deficits[queue][hostID]
+}
+
+// StartDispatch records n recaches of a host dispatched to EGs
+func (s *FairShareScheduler) StartDispatch(hostID, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight[hostID] += n
+}
+
+// FinishDispatch records a dispatched recache of a host as finished (or never sent)
+func (s *FairShareScheduler) FinishDispatch(hostID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inFlight[hostID] <= 1 {
+		delete(s.inFlight, hostID)
+		return
+	}
+	s.inFlight[hostID]--
+}
+
+// InFlight returns the number of dispatched recaches of a host that have not finished
+func (s *FairShareScheduler) InFlight(hostID int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight[hostID]
+}
+
+// rotatedActiveHosts returns hosts with demand sorted by ID, starting after the queue cursor
+func (s *FairShareScheduler) rotatedActiveHosts(queue string, demand map[int]int) []int {
+	active := make([]int, 0, len(demand))
+	for hostID, n := range demand {
+		if n > 0 {
+			active = append(active, hostID)
+		}
+	}
+	sort.Ints(active)
+
+	cursor, ok := s.cursors[queue]
+	if !ok {
+		return active
+	}
+
+	start := sort.SearchInts(active, cursor+1)
+	if start == 0 || start == len(active) {
+		return active
+	}
+	rotated := make([]int, 0, len(active))
+	rotated = append(rotated, active[start:]...)
+	return append(rotated, active[:start]...)
+}
+
+// pullFairShare moves entries from a Redis priority queue into the internal queue using DRR.
+// dueOnly restricts the pull to entries with score <= now (autorecache).
+func (d *CacheDaemon) pullFairShare(priority string, dueOnly bool) {
+	ctx := context.Background()
+
+	internalQueueSpace := d.daemonConfig.InternalQueue.MaxSize - d.internalQueue.Size()
+	if internalQueueSpace <= 0 {
+		d.logger.Debug("Internal queue full, skipping fair-share queue processing",
+			zap.String("priority", priority))
+		return
+	}
+
+	hosts := d.schedulableHosts(priority)
+	now := time.Now().UTC().Unix()
+	nowStr := fmt.Sprintf("%d", now)
+
+	demand := make(map[int]int, len(hosts))
+	for _, hostID := range hosts {
+		zsetKey := d.keyGenerator.RecacheQueueKey(hostID, priority)
+
+		var count int64
+		var err error
+		if dueOnly {
+			count, err = d.redis.ZCount(ctx, zsetKey, "-inf", nowStr)
+		} else {
+			count, err = d.redis.ZCard(ctx, zsetKey)
+		}
+		if err != nil {
+			d.logger.Error("Failed to read queue depth",
+				zap.Int("host_id", hostID),
+				zap.String("key", zsetKey),
+				zap.Error(err))
+			continue
+		}
+		if count > 0 {
+			demand[hostID] = int(count)
+		}
+	}
+
+	allocation := d.fairShare.Allocate(priority, demand, internalQueueSpace)
+	if len(allocation) == 0 {
+		return
+	}
+
+	pulledCount := 0
+	for _, hostID := range sortedHostIDs(allocation) {
+		pulledCount += d.popIntoInternalQueue(ctx, hostID, priority, allocation[hostID], dueOnly, now)
+	}
+
+	if pulledCount > 0 {
+		d.logger.Info("Processed queues with fair-share scheduling",
+			zap.String("priority", priority),
+			zap.Int("entries_pulled", pulledCount),
+			zap.Int("hosts_served", len(allocation)))
+	}
+}
+
+// popIntoInternalQueue pops up to count entries from a host's ZSET into the internal queue.
+// Entries that cannot be enqueued (or are not yet due) are re-added with their original score.
+func (d *CacheDaemon) popIntoInternalQueue(ctx context.Context, hostID int, priority string, count int, dueOnly bool, now int64) int {
+	zsetKey := d.keyGenerator.RecacheQueueKey(hostID, priority)
+
+	result, err := d.redis.ZPopMin(ctx, zsetKey, int64(count))
+	if err != nil {
+		d.logger.Error("Failed to pop from queue",
+			zap.Int("host_id", hostID),
+			zap.String("key", zsetKey),
+			zap.Error(err))
+		return 0
+	}
+
+	pulled := 0
+	for _, z := range result {
+		memberJSON, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+
+		// Not due yet (autorecache entry scheduled after the count was taken)
+		if dueOnly && int64(z.Score) > now {
+			d.restoreQueueMember(ctx, zsetKey, hostID, z.Score, memberJSON)
+			continue
+		}
+
+		var member types.RecacheMember
+		if err := json.Unmarshal([]byte(memberJSON), &member); err != nil {
+			d.logger.Error("Failed to unmarshal RecacheMember",
+				zap.Int("host_id", hostID),
+				zap.String("member_json", memberJSON),
+				zap.Error(err))
+			continue
+		}
+
+		entry := InternalQueueEntry{
+			HostID:      hostID,
+			URL:         member.URL,
+			DimensionID: member.DimensionID,
+			RetryCount:  0,
+			QueuedAt:    time.Now().UTC(),
+		}
+
+		if !d.internalQueue.Enqueue(entry) {
+			d.logger.Warn("Failed to enqueue entry (queue full)",
+				zap.Int("host_id", hostID),
+				zap.String("url", member.URL))
+			d.restoreQueueMember(ctx, zsetKey, hostID, z.Score, memberJSON)
+			continue
+		}
+
+		pulled++
+		d.logger.Debug("Pulled from queue",
+			zap.String("priority", priority),
+			zap.Int("host_id", hostID),
+			zap.String("url", member.URL),
+			zap.Int("dimension_id", member.DimensionID))
+	}
+
+	if pulled > 0 {
+		d.recordHostPulled(hostID, priority, pulled)
+	}
+
+	return pulled
+}
+
+// restoreQueueMember re-adds a popped member to its ZSET with the original score
+func (d *CacheDaemon) restoreQueueMember(ctx context.Context, zsetKey string, hostID int, score float64, memberJSON string) {
+	if err := d.redis.ZAdd(ctx, zsetKey, score, memberJSON); err != nil {
+		d.logger.Error("CRITICAL: Failed to re-add dropped entry to ZSET",
+			zap.Int("host_id", hostID),
+			zap.String("key", zsetKey),
+			zap.Error(err))
+	}
+}
+
+// dequeueFairShare selects up to capacity ready entries from the internal queue,
+// balancing hosts by weight and honoring per-host max_concurrent limits. Recaches still
+// in flight from earlier dispatches count against the limit.
+func (d *CacheDaemon) dequeueFairShare(capacity int) []InternalQueueEntry {
+	now := time.Now().UTC()
+	demand := d.internalQueue.CountReadyByHostID(now)
+
+	fairShareCfg := &d.daemonConfig.Scheduler.FairShare
+	for hostID, n := range demand {
+		limit := fairShareCfg.MaxConcurrentFor(hostID)
+		if limit <= 0 {
+			continue
+		}
+		available := limit - d.fairShare.InFlight(hostID)
+		if available <= 0 {
+			delete(demand, hostID)
+		} else if n > available {
+			demand[hostID] = available
+		}
+	}
+
+	allocation := d.fairShare.Allocate(fairShareDispatchQueue, demand, capacity)
+	if len(allocation) == 0 {
+		return nil
+	}
+
+	batch := d.internalQueue.DequeueReadyByHostID(now, allocation)
+	for _, entry := range batch {
+		d.fairShare.StartDispatch(entry.HostID, 1)
+	}
+	return batch
+}
+
+// recordHostResult records per-host recache throughput and ends the recache's in-flight slot
+func (d *CacheDaemon) recordHostResult(hostID int, status string) {
+	if d.fairShare != nil {
+		d.fairShare.FinishDispatch(hostID)
+	}
+	if d.metricsCollector == nil {
+		return
+	}
+	d.metricsCollector.RecordHostRecache(strconv.Itoa(hostID), status)
+}
+
+// recordHostPulled records entries moved from a host's Redis queue into the internal queue
+func (d *CacheDaemon) recordHostPulled(hostID int, priority string, count int) {
+	if d.metricsCollector == nil {
+		return
+	}
+	d.metricsCollector.RecordHostPulled(strconv.Itoa(hostID), priority, count)
+}
+
+// sortedHostIDs returns map keys in ascending order
+func sortedHostIDs(m map[int]int) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,259 @@
+package cachedaemon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestFairShareScheduler_Allocate(t *testing.T) {
+	t.Run("equal weights split budget evenly", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		alloc := s.Allocate("high", map[int]int{1: 500000, 2: 10, 3: 10}, 30)
+		assert.Equal(t, map[int]int{1: 10, 2: 10, 3: 10}, alloc)
+	})
+
+	t.Run("small host is not starved by large backlog", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		alloc := s.Allocate("normal", map[int]int{1: 500000, 2: 3}, 100)
+		assert.Equal(t, 3, alloc[2])
+		assert.Equal(t, 97, alloc[1])
+	})
+
+	t.Run("weights are respected", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{
+			Quantum: 1,
+			Hosts: []configtypes.CacheDaemonHostSchedulePolicy{
+				{HostID: 1, Weight: 3},
+			},
+		})
+
+		alloc := s.Allocate("high", map[int]int{1: 1000, 2: 1000}, 40)
+		assert.Equal(t, 30, alloc[1])
+		assert.Equal(t, 10, alloc[2])
+	})
+
+	t.Run("hosts without demand are omitted", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		alloc := s.Allocate("high", map[int]int{1: 0, 2: 5}, 10)
+		assert.Equal(t, map[int]int{2: 5}, alloc)
+	})
+
+	t.Run("zero budget allocates nothing", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		alloc := s.Allocate("high", map[int]int{1: 5}, 0)
+		assert.Empty(t, alloc)
+	})
+
+	t.Run("round robin resumes after last served host across calls", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 1})
+		demand := map[int]int{1: 100, 2: 100, 3: 100}
+
+		served := make(map[int]int)
+		for i := 0; i < 9; i++ {
+			alloc := s.Allocate("high", demand, 1)
+			require.Len(t, alloc, 1)
+			for hostID, n := range alloc {
+				served[hostID] += n
+			}
+		}
+		assert.Equal(t, map[int]int{1: 3, 2: 3, 3: 3}, served)
+	})
+
+	t.Run("deficit carries over while host has demand", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 5})
+
+		alloc := s.Allocate("high", map[int]int{1: 100}, 2)
+		assert.Equal(t, 2, alloc[1])
+		assert.Equal(t, 3, s.Deficit("high", 1))
+	})
+
+	t.Run("deficit resets when host queue drains", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 5})
+
+		s.Allocate("high", map[int]int{1: 100}, 2)
+		require.Equal(t, 3, s.Deficit("high", 1))
+
+		s.Allocate("high", map[int]int{2: 10}, 2)
+		assert.Equal(t, 0, s.Deficit("high", 1))
+	})
+
+	t.Run("queues keep independent state", func(t *testing.T) {
+		s := NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 5})
+
+		s.Allocate("high", map[int]int{1: 100}, 2)
+		assert.Equal(t, 3, s.Deficit("high", 1))
+		assert.Equal(t, 0, s.Deficit("normal", 1))
+	})
+}
+
+func TestCacheDaemonFairShare_Overrides(t *testing.T) {
+	cfg := &configtypes.CacheDaemonFairShare{
+		DefaultWeight:        2,
+		DefaultMaxConcurrent: 10,
+		Hosts: []configtypes.CacheDaemonHostSchedulePolicy{
+			{HostID: 1, Weight: 5, MaxConcurrent: intPtr(0)},
+			{HostID: 2, MaxConcurrent: intPtr(3)},
+		},
+	}
+
+	assert.Equal(t, 5, cfg.WeightFor(1))
+	assert.Equal(t, 2, cfg.WeightFor(2))
+	assert.Equal(t, 2, cfg.WeightFor(99))
+
+	assert.Equal(t, 0, cfg.MaxConcurrentFor(1), "explicit 0 means unlimited")
+	assert.Equal(t, 3, cfg.MaxConcurrentFor(2))
+	assert.Equal(t, 10, cfg.MaxConcurrentFor(99))
+}
+
+func setupFairShareDaemon(t *testing.T, fairShare configtypes.CacheDaemonFairShare) (*CacheDaemon, *miniredis.Miniredis) {
+	daemon, mr := setupTestDaemon(t)
+	daemon.daemonConfig = &configtypes.CacheDaemonConfig{
+		Scheduler:     configtypes.CacheDaemonScheduler{FairShare: fairShare},
+		InternalQueue: configtypes.CacheDaemonInternalQueue{MaxSize: 100, MaxRetries: 3},
+	}
+	daemon.fairShare = NewFairShareScheduler(&daemon.daemonConfig.Scheduler.FairShare)
+	return daemon, mr
+}
+
+func TestCacheDaemon_PullFairShare(t *testing.T) {
+	t.Run("pulls from every host with pending entries", func(t *testing.T) {
+		daemon, mr := setupTestDaemon(t)
+		daemon.daemonConfig = &configtypes.CacheDaemonConfig{
+			InternalQueue: configtypes.CacheDaemonInternalQueue{MaxSize: 10, MaxRetries: 3},
+		}
+		daemon.internalQueue = NewInternalQueue(10)
+		daemon.fairShare = NewFairShareScheduler(&configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		for i := 0; i < 50; i++ {
+			addQueueMember(mr, 1, redis.PriorityHigh, fmt.Sprintf("https://example.com/%d", i), 1, float64(i))
+		}
+		addQueueMember(mr, 2, redis.PriorityHigh, "https://nocache.com/a", 1, 1)
+		addQueueMember(mr, 2, redis.PriorityHigh, "https://nocache.com/b", 1, 2)
+
+		daemon.ProcessHighPriorityQueues(0)
+
+		assert.Equal(t, 10, daemon.internalQueue.Size())
+		assert.Equal(t, 8, daemon.internalQueue.CountByHostID(1))
+		assert.Equal(t, 2, daemon.internalQueue.CountByHostID(2))
+
+		remaining, err := mr.ZMembers(daemon.keyGenerator.RecacheQueueKey(1, redis.PriorityHigh))
+		require.NoError(t, err)
+		assert.Len(t, remaining, 42)
+	})
+
+	t.Run("autorecache pulls only due entries", func(t *testing.T) {
+		daemon, mr := setupFairShareDaemon(t, configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		now := time.Now().UTC().Unix()
+		addQueueMember(mr, 1, redis.PriorityAutorecache, "https://example.com/due", 1, float64(now-10))
+		addQueueMember(mr, 1, redis.PriorityAutorecache, "https://example.com/future", 1, float64(now+3600))
+
+		daemon.ProcessAutoRecacheQueues(0)
+
+		require.Equal(t, 1, daemon.internalQueue.Size())
+		entries := daemon.internalQueue.Dequeue(1)
+		assert.Equal(t, "https://example.com/due", entries[0].URL)
+
+		remaining, err := mr.ZMembers(daemon.keyGenerator.RecacheQueueKey(1, redis.PriorityAutorecache))
+		require.NoError(t, err)
+		assert.Len(t, remaining, 1)
+	})
+}
+
+func TestCacheDaemon_DequeueFairShare(t *testing.T) {
+	t.Run("honors per-host max_concurrent", func(t *testing.T) {
+		daemon, _ := setupFairShareDaemon(t, configtypes.CacheDaemonFairShare{
+			Quantum: 1,
+			Hosts: []configtypes.CacheDaemonHostSchedulePolicy{
+				{HostID: 1, MaxConcurrent: intPtr(2)},
+			},
+		})
+
+		for i := 0; i < 10; i++ {
+			daemon.internalQueue.Enqueue(InternalQueueEntry{HostID: 1, URL: fmt.Sprintf("https://example.com/%d", i)})
+		}
+		daemon.internalQueue.Enqueue(InternalQueueEntry{HostID: 2, URL: "https://nocache.com/a"})
+
+		batch := daemon.dequeueFairShare(20)
+
+		counts := map[int]int{}
+		for _, e := range batch {
+			counts[e.HostID]++
+		}
+		assert.Equal(t, map[int]int{1: 2, 2: 1}, counts)
+		assert.Equal(t, 8, daemon.internalQueue.Size())
+	})
+
+	t.Run("counts recaches in flight from earlier dispatches", func(t *testing.T) {
+		daemon, _ := setupFairShareDaemon(t, configtypes.CacheDaemonFairShare{
+			Quantum: 1,
+			Hosts: []configtypes.CacheDaemonHostSchedulePolicy{
+				{HostID: 1, MaxConcurrent: intPtr(2)},
+			},
+		})
+
+		for i := 0; i < 10; i++ {
+			daemon.internalQueue.Enqueue(InternalQueueEntry{HostID: 1, URL: fmt.Sprintf("https://example.com/%d", i)})
+		}
+
+		require.Len(t, daemon.dequeueFairShare(20), 2)
+		assert.Equal(t, 2, daemon.fairShare.InFlight(1))
+		assert.Empty(t, daemon.dequeueFairShare(20), "limit reached while earlier recaches are in flight")
+
+		daemon.recordHostResult(1, "success")
+		assert.Len(t, daemon.dequeueFairShare(20), 1, "a finished recache frees one slot")
+
+		// Batches that could not be dispatched free their slots too
+		daemon.requeueBatch([]InternalQueueEntry{{HostID: 1, URL: "https://example.com/requeued"}})
+		assert.Equal(t, 1, daemon.fairShare.InFlight(1))
+	})
+
+	t.Run("interleaves hosts when capacity is scarce", func(t *testing.T) {
+		daemon, _ := setupFairShareDaemon(t, configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		for i := 0; i < 20; i++ {
+			daemon.internalQueue.Enqueue(InternalQueueEntry{HostID: 1, URL: fmt.Sprintf("https://example.com/%d", i)})
+		}
+		for i := 0; i < 20; i++ {
+			daemon.internalQueue.Enqueue(InternalQueueEntry{HostID: 2, URL: fmt.Sprintf("https://nocache.com/%d", i)})
+		}
+
+		batch := daemon.dequeueFairShare(4)
+
+		counts := map[int]int{}
+		for _, e := range batch {
+			counts[e.HostID]++
+		}
+		assert.Equal(t, map[int]int{1: 2, 2: 2}, counts)
+	})
+
+	t.Run("skips entries waiting for retry backoff", func(t *testing.T) {
+		daemon, _ := setupFairShareDaemon(t, configtypes.CacheDaemonFairShare{Quantum: 1})
+
+		daemon.internalQueue.Enqueue(InternalQueueEntry{
+			HostID:         1,
+			URL:            "https://example.com/later",
+			NextRetryAfter: time.Now().UTC().Add(time.Hour),
+		})
+		daemon.internalQueue.Enqueue(InternalQueueEntry{HostID: 1, URL: "https://example.com/now"})
+
+		batch := daemon.dequeueFairShare(10)
+		require.Len(t, batch, 1)
+		assert.Equal(t, "https://example.com/now", batch[0].URL)
+		assert.Equal(t, 1, daemon.internalQueue.Size())
+	})
+}
@@ -16,6 +16,11 @@ type InternalQueueEntry struct {
 	NextRetryAfter time.Time
 }
 
+// IsReady reports whether the entry's retry backoff has elapsed
+func (e InternalQueueEntry) IsReady(now time.Time) bool {
+	return e.NextRetryAfter.IsZero() || !now.Before(e.NextRetryAfter)
+}
+
 // InternalQueue is a thread-safe in-memory queue for recache tasks
 type InternalQueue struct {
 	mu      sync.RWMutex
@@ -86,3 +91,45 @@ func (q *InternalQueue) CountByHostID(hostID int) int {
 	}
 	return count
 }
+
+// CountReadyByHostID returns the number of ready entries (backoff elapsed) per host
+func (q *InternalQueue) CountReadyByHostID(now time.Time) map[int]int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	counts := make(map[int]int)
+	for _, entry := range q.entries {
+		if entry.IsReady(now) {
+			counts[entry.HostID]++
+		}
+	}
+	return counts
+}
+
+// DequeueReadyByHostID removes up to limits[hostID] ready entries for each host.
+// Remaining entries keep their relative order.
+func (q *InternalQueue) DequeueReadyByHostID(now time.Time, limits map[int]int) []InternalQueueEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	taken := make(map[int]int, len(limits))
+	result := make([]InternalQueueEntry, 0)
+	kept := q.entries[:0]
+
+	for _, entry := range q.entries {
+		if taken[entry.HostID] < limits[entry.HostID] && entry.IsReady(now) {
+			taken[entry.HostID]++
+			result = append(result, entry)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	// Clear tail so dropped entries can be garbage collected
+	for i := len(kept); i < len(q.entries); i++ {
+		q.entries[i] = InternalQueueEntry{}
+	}
+	q.entries = kept
+
+	return result
+}
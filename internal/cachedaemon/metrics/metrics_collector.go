@@ -58,6 +58,23 @@ func (mc *MetricsCollector) RecordEGRequest(egID, status string) {
 		zap.String("status", status))
 }
 
+func (mc *MetricsCollector) RecordHostRecache(hostID, status string) {
+	mc.prometheus.RecordHostRecache(hostID, status)
+
+	mc.logger.Debug("Recorded host recache metric",
+		zap.String("host_id", hostID),
+		zap.String("status", status))
+}
+
+func (mc *MetricsCollector) RecordHostPulled(hostID, queueType string, count int) {
+	mc.prometheus.RecordHostPulled(hostID, queueType, count)
+
+	mc.logger.Debug("Recorded host pulled metric",
+		zap.String("host_id", hostID),
+		zap.String("queue_type", queueType),
+		zap.Int("count", count))
+}
+
 func (mc *MetricsCollector) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	mc.prometheus.ServeHTTP(ctx)
 }
@@ -17,6 +17,8 @@ type PrometheusMetrics struct {
 	recacheDuration      prometheus.Histogram
 	redisOperationsTotal *prometheus.CounterVec
 	egRequestsTotal      *prometheus.CounterVec
+	hostRecachesTotal    *prometheus.CounterVec
+	hostPulledTotal      *prometheus.CounterVec
 }
 
 func NewPrometheusMetrics(namespace string, logger *zap.Logger) *PrometheusMetrics {
@@ -78,12 +80,34 @@ func NewPrometheusMetrics(namespace string, logger *zap.Logger) *PrometheusMetri
 		[]string{"eg_id", "status"},
 	)
 
+	pm.hostRecachesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cd",
+			Name:      "host_recaches_total",
+			Help:      "Total number of recache results per host",
+		},
+		[]string{"host_id", "status"},
+	)
+
+	pm.hostPulledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cd",
+			Name:      "host_pulled_total",
+			Help:      "Total number of entries pulled from recache queues per host",
+		},
+		[]string{"host_id", "queue_type"},
+	)
+
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(pm.recacheRequestsTotal)
 	registry.MustRegister(pm.queueDepth)
 	registry.MustRegister(pm.recacheDuration)
 	registry.MustRegister(pm.redisOperationsTotal)
 	registry.MustRegister(pm.egRequestsTotal)
+	registry.MustRegister(pm.hostRecachesTotal)
+	registry.MustRegister(pm.hostPulledTotal)
 
 	gatherer := prometheus.Gatherer(registry)
 	handler := promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
@@ -118,6 +142,14 @@ func (pm *PrometheusMetrics) RecordEGRequest(egID, status string) {
 	pm.egRequestsTotal.WithLabelValues(egID, status).Inc()
 }
 
+func (pm *PrometheusMetrics) RecordHostRecache(hostID, status string) {
+	pm.hostRecachesTotal.WithLabelValues(hostID, status).Inc()
+}
+
+func (pm *PrometheusMetrics) RecordHostPulled(hostID, queueType string, count int) {
+	pm.hostPulledTotal.WithLabelValues(hostID, queueType).Add(float64(count))
+}
+
 func (pm *PrometheusMetrics) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	pm.httpHandler(ctx)
 }
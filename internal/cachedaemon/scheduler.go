@@ -52,6 +52,9 @@ func (d *CacheDaemon) Run(ctx context.Context) {
 				continue
 			}
 
+			// Enqueue URL sets for cron jobs that are due
+			d.RunDueScheduledJobs(now)
+
 			// Calculate available RS capacity
 			availableCapacity := d.CalculateAvailableCapacity()
 
@@ -132,6 +135,11 @@ func (d *CacheDaemon) CalculateAvailableCapacity() int {
 
 // ProcessHighPriorityQueues pulls entries from high priority ZSETs and enqueues them
 func (d *CacheDaemon) ProcessHighPriorityQueues(availableCapacity int) {
+	if d.fairShare != nil {
+		d.pullFairShare(redis.PriorityHigh, false)
+		return
+	}
+
 	ctx := context.Background()
 
 	// Check internal queue space
@@ -141,7 +149,7 @@ func (d *CacheDaemon) ProcessHighPriorityQueues(availableCapacity int) {
 		return
 	}
 
-	hosts := d.GetConfiguredHosts()
+	hosts := d.schedulableHosts(redis.PriorityHigh)
 	pulledCount := 0
 
 	for _, hostID := range hosts {
@@ -190,6 +198,7 @@ func (d *CacheDaemon) ProcessHighPriorityQueues(availableCapacity int) {
 		// Enqueue
 		if d.internalQueue.Enqueue(entry) {
 			pulledCount++
+			d.recordHostPulled(hostID, redis.PriorityHigh, 1)
 			d.logger.Debug("Pulled from high priority queue",
 				zap.Int("host_id", hostID),
 				zap.String("url", member.URL),
@@ -223,6 +232,11 @@ func (d *CacheDaemon) ProcessHighPriorityQueues(availableCapacity int) {
 
 // ProcessNormalPriorityQueues pulls entries from normal priority ZSETs and enqueues them
 func (d *CacheDaemon) ProcessNormalPriorityQueues(availableCapacity int) {
+	if d.fairShare != nil {
+		d.pullFairShare(redis.PriorityNormal, false)
+		return
+	}
+
 	ctx := context.Background()
 
 	// Check internal queue space
@@ -232,7 +246,7 @@ func (d *CacheDaemon) ProcessNormalPriorityQueues(availableCapacity int) {
 		return
 	}
 
-	hosts := d.GetConfiguredHosts()
+	hosts := d.schedulableHosts(redis.PriorityNormal)
 	pulledCount := 0
 
 	for _, hostID := range hosts {
@@ -281,6 +295,7 @@ func (d *CacheDaemon) ProcessNormalPriorityQueues(availableCapacity int) {
 		// Enqueue
 		if d.internalQueue.Enqueue(entry) {
 			pulledCount++
+			d.recordHostPulled(hostID, redis.PriorityNormal, 1)
 			d.logger.Debug("Pulled from normal priority queue",
 				zap.Int("host_id", hostID),
 				zap.String("url", member.URL),
@@ -314,6 +329,11 @@ func (d *CacheDaemon) ProcessNormalPriorityQueues(availableCapacity int) {
 
 // ProcessAutoRecacheQueues pulls entries from autorecache ZSETs (only due entries)
 func (d *CacheDaemon) ProcessAutoRecacheQueues(availableCapacity int) {
+	if d.fairShare != nil {
+		d.pullFairShare(redis.PriorityAutorecache, true)
+		return
+	}
+
 	ctx := context.Background()
 
 	// Check internal queue space
@@ -323,7 +343,7 @@ func (d *CacheDaemon) ProcessAutoRecacheQueues(availableCapacity int) {
 		return
 	}
 
-	hosts := d.GetConfiguredHosts()
+	hosts := d.schedulableHosts(redis.PriorityAutorecache)
 	pulledCount := 0
 	now := time.Now().UTC().Unix()
 	nowStr := fmt.Sprintf("%d", now)
@@ -403,6 +423,7 @@ func (d *CacheDaemon) ProcessAutoRecacheQueues(availableCapacity int) {
 		// Enqueue
 		if d.internalQueue.Enqueue(entry) {
 			pulledCount++
+			d.recordHostPulled(hostID, redis.PriorityAutorecache, 1)
 			d.logger.Debug("Pulled from autorecache queue",
 				zap.Int("host_id", hostID),
 				zap.String("url", member.URL),
@@ -444,6 +465,20 @@ func (d *CacheDaemon) ProcessInternalQueue(availableCapacity int) {
 		return
 	}
 
+	if d.fairShare != nil {
+		readyBatch := d.dequeueFairShare(availableCapacity)
+		if len(readyBatch) == 0 {
+			return
+		}
+
+		d.logger.Info("Processing internal queue batch (fair-share)",
+			zap.Int("batch_size", len(readyBatch)),
+			zap.Int("available_capacity", availableCapacity))
+
+		d.DistributeToEGs(readyBatch)
+		return
+	}
+
 	// Dequeue batch (up to availableCapacity)
 	batchSize := availableCapacity
 	if batchSize > d.internalQueue.Size() {
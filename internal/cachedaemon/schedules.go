@@ -0,0 +1,331 @@
+package cachedaemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/common/schedule"
+	"github.com/edgecomet/engine/pkg/pattern"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+const (
+	// scheduledJobPageSize is the number of metadata entries read per Lua scan call
+	scheduledJobPageSize = 500
+
+	// scheduledJobLockTTL keeps the per-run lock long enough for every daemon to see it
+	scheduledJobLockTTL = 24 * time.Hour
+)
+
+// hostWindows is the compiled form of CacheDaemonHostWindows
+type hostWindows struct {
+	allowed  []schedule.Window
+	blackout []schedule.Window
+	queues   map[string]bool
+}
+
+// scheduledJob is the compiled form of CacheDaemonScheduledJob
+type scheduledJob struct {
+	cfg      configtypes.CacheDaemonScheduledJob
+	cron     *schedule.CronExpr
+	patterns []*pattern.Pattern
+	nextRun  time.Time
+	running  bool
+}
+
+// ScheduleManager gates queue processing by time of day and tracks cron job due times
+type ScheduleManager struct {
+	mu       sync.Mutex
+	location *time.Location
+	windows  map[int]*hostWindows
+	jobs     []*scheduledJob
+}
+
+// NewScheduleManager compiles schedule configuration. now seeds the first run of each job,
+// so jobs fire at their next cron time rather than immediately on startup.
+func NewScheduleManager(cfg *configtypes.CacheDaemonSchedules, now time.Time) (*ScheduleManager, error) {
+	m := &ScheduleManager{
+		location: cfg.Location(),
+		windows:  make(map[int]*hostWindows, len(cfg.Hosts)),
+		jobs:     make([]*scheduledJob, 0, len(cfg.Jobs)),
+	}
+
+	for _, h := range cfg.Hosts {
+		allowed, err := schedule.ParseWindows(h.AllowedWindows)
+		if err != nil {
+			return nil, fmt.Errorf("host %d: %w", h.HostID, err)
+		}
+		blackout, err := schedule.ParseWindows(h.BlackoutWindows)
+		if err != nil {
+			return nil, fmt.Errorf("host %d: %w", h.HostID, err)
+		}
+
+		queues := h.Queues
+		if len(queues) == 0 {
+			queues = configtypes.DefaultScheduleGatedQueues
+		}
+		queueSet := make(map[string]bool, len(queues))
+		for _, q := range queues {
+			queueSet[q] = true
+		}
+
+		m.windows[h.HostID] = &hostWindows{allowed: allowed, blackout: blackout, queues: queueSet}
+	}
+
+	localNow := now.In(m.location)
+	for _, j := range cfg.Jobs {
+		cron, err := schedule.ParseCron(j.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("job '%s': %w", j.Name, err)
+		}
+
+		patterns := make([]*pattern.Pattern, 0, len(j.URLPatterns))
+		for _, p := range j.URLPatterns {
+			compiled, err := pattern.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("job '%s': %w", j.Name, err)
+			}
+			patterns = append(patterns, compiled)
+		}
+
+		m.jobs = append(m.jobs, &scheduledJob{
+			cfg:      j,
+			cron:     cron,
+			patterns: patterns,
+			nextRun:  cron.Next(localNow),
+		})
+	}
+
+	return m, nil
+}
+
+// QueueOpen reports whether the host's queue may be processed at the given time.
+// A gated queue is closed inside any blackout window, and outside the allowed windows when any are set.
+func (m *ScheduleManager) QueueOpen(hostID int, priority string, now time.Time) bool {
+	w, ok := m.windows[hostID]
+	if !ok || !w.queues[priority] {
+		return true
+	}
+
+	local := now.In(m.location)
+	if schedule.AnyContains(w.blackout, local) {
+		return false
+	}
+	if len(w.allowed) > 0 && !schedule.AnyContains(w.allowed, local) {
+		return false
+	}
+	return true
+}
+
+// DueJobs returns jobs whose next run time has passed and are not still running,
+// advancing each returned job to its following run. The returned fire times identify
+// the run for cross-daemon deduplication.
+func (m *ScheduleManager) DueJobs(now time.Time) ([]*scheduledJob, []time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	local := now.In(m.location)
+	var due []*scheduledJob
+	var fireTimes []time.Time
+
+	for _, job := range m.jobs {
+		if job.nextRun.IsZero() || local.Before(job.nextRun) {
+			continue
+		}
+
+		fireTime := job.nextRun
+		job.nextRun = job.cron.Next(local)
+
+		if job.running {
+			continue
+		}
+		job.running = true
+		due = append(due, job)
+		fireTimes = append(fireTimes, fireTime)
+	}
+
+	return due, fireTimes
+}
+
+// finish marks a job as no longer running
+func (m *ScheduleManager) finish(job *scheduledJob) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.running = false
+}
+
+// matchesURL reports whether a cached URL path matches the job patterns (no patterns = match all)
+func (j *scheduledJob) matchesURL(rawURL string) bool {
+	if len(j.patterns) == 0 {
+		return true
+	}
+	return matchURLPatterns(j.patterns, rawURL)
+}
+
+// matchURLPatterns reports whether the path (and query) of rawURL matches any pattern
+func matchURLPatterns(patterns []*pattern.Pattern, rawURL string) bool {
+	path := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		path = parsed.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		if parsed.RawQuery != "" {
+			path += "?" + parsed.RawQuery
+		}
+	}
+
+	for _, p := range patterns {
+		if p.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulableHosts returns configured hosts whose queue of the given priority is open now
+func (d *CacheDaemon) schedulableHosts(priority string) []int {
+	hosts := d.GetConfiguredHosts()
+	if d.schedules == nil {
+		return hosts
+	}
+
+	now := time.Now().UTC()
+	open := hosts[:0]
+	for _, hostID := range hosts {
+		if d.schedules.QueueOpen(hostID, priority, now) {
+			open = append(open, hostID)
+		} else {
+			d.logger.Debug("Queue closed by schedule window",
+				zap.Int("host_id", hostID),
+				zap.String("priority", priority))
+		}
+	}
+	return open
+}
+
+// RunDueScheduledJobs starts every cron job whose run time has passed.
+// Each run is claimed with a Redis lock so only one daemon executes it.
+func (d *CacheDaemon) RunDueScheduledJobs(now time.Time) {
+	if d.schedules == nil {
+		return
+	}
+
+	jobs, fireTimes := d.schedules.DueJobs(now)
+	for i, job := range jobs {
+		lockKey := d.keyGenerator.ScheduledJobRunKey(job.cfg.Name, fireTimes[i].Unix())
+		acquired, err := d.redis.SetNX(context.Background(), lockKey, d.daemonConfig.DaemonID, scheduledJobLockTTL)
+		if err != nil {
+			d.logger.Error("Failed to claim scheduled job run",
+				zap.String("job", job.cfg.Name),
+				zap.Error(err))
+			d.schedules.finish(job)
+			continue
+		}
+		if !acquired {
+			d.logger.Debug("Scheduled job run already claimed by another daemon",
+				zap.String("job", job.cfg.Name),
+				zap.Time("fire_time", fireTimes[i]))
+			d.schedules.finish(job)
+			continue
+		}
+
+		go func(job *scheduledJob) {
+			defer d.schedules.finish(job)
+			if _, err := d.ExecuteScheduledJob(job); err != nil {
+				d.logger.Error("Scheduled job failed",
+					zap.String("job", job.cfg.Name),
+					zap.Int("host_id", job.cfg.HostID),
+					zap.Error(err))
+			}
+		}(job)
+	}
+}
+
+// ExecuteScheduledJob scans cache metadata for the job's host and enqueues matching URLs.
+// Returns the number of queue entries added.
+func (d *CacheDaemon) ExecuteScheduledJob(job *scheduledJob) (int, error) {
+	host := d.GetHost(job.cfg.HostID)
+	if host == nil {
+		return 0, fmt.Errorf("host_id %d not found", job.cfg.HostID)
+	}
+
+	dimensionIDs, err := resolveDimensionIDs(host, job.cfg.DimensionIDs)
+	if err != nil {
+		return 0, err
+	}
+	allowedDimensions := make(map[int]bool, len(dimensionIDs))
+	for _, id := range dimensionIDs {
+		allowedDimensions[id] = true
+	}
+
+	priority := job.cfg.Priority
+	if priority == "" {
+		priority = redis.PriorityNormal
+	}
+
+	ctx := context.Background()
+	queueKey := d.keyGenerator.RecacheQueueKey(host.ID, priority)
+	score := float64(time.Now().UTC().Unix())
+	cursor := "0"
+	scanned := 0
+	enqueued := 0
+
+	for {
+		page, err := d.cacheReader.ListURLs(CacheListParams{
+			HostID: host.ID,
+			Cursor: cursor,
+			Limit:  scheduledJobPageSize,
+		})
+		if err != nil {
+			return enqueued, fmt.Errorf("failed to scan cache metadata: %w", err)
+		}
+
+		for _, item := range page.Items {
+			scanned++
+			if !job.matchesURL(item.URL) {
+				continue
+			}
+
+			cacheKey, err := d.keyGenerator.ParseCacheKey(item.CacheKey)
+			if err != nil || !allowedDimensions[cacheKey.DimensionID] {
+				continue
+			}
+
+			memberJSON, _ := json.Marshal(types.RecacheMember{
+				URL:         item.URL,
+				DimensionID: cacheKey.DimensionID,
+			})
+			if err := d.redis.ZAdd(ctx, queueKey, score, string(memberJSON)); err != nil {
+				d.logger.Error("Failed to add scheduled entry to ZSET",
+					zap.String("queue", queueKey),
+					zap.String("url", item.URL),
+					zap.Error(err))
+				continue
+			}
+			enqueued++
+		}
+
+		if !page.HasMore {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	d.logger.Info("Scheduled job completed",
+		zap.String("job", job.cfg.Name),
+		zap.Int("host_id", host.ID),
+		zap.String("priority", priority),
+		zap.Int("entries_scanned", scanned),
+		zap.Int("entries_enqueued", enqueued))
+
+	return enqueued, nil
+}
@@ -0,0 +1,196 @@
+package cachedaemon
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02 15:04", s)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestScheduleManager_QueueOpen(t *testing.T) {
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Hosts: []configtypes.CacheDaemonHostWindows{
+			{HostID: 1, AllowedWindows: []string{"01:00-06:00"}, BlackoutWindows: []string{"03:00-03:30"}},
+			{HostID: 2, BlackoutWindows: []string{"12:00-13:00"}, Queues: []string{"high"}},
+		},
+	}, mustTime(t, "2026-03-10 00:00"))
+	require.NoError(t, err)
+
+	t.Run("inside allowed window", func(t *testing.T) {
+		assert.True(t, m.QueueOpen(1, redis.PriorityNormal, mustTime(t, "2026-03-10 02:00")))
+	})
+
+	t.Run("outside allowed window", func(t *testing.T) {
+		assert.False(t, m.QueueOpen(1, redis.PriorityNormal, mustTime(t, "2026-03-10 12:00")))
+		assert.False(t, m.QueueOpen(1, redis.PriorityAutorecache, mustTime(t, "2026-03-10 12:00")))
+	})
+
+	t.Run("blackout overrides allowed window", func(t *testing.T) {
+		assert.False(t, m.QueueOpen(1, redis.PriorityNormal, mustTime(t, "2026-03-10 03:15")))
+	})
+
+	t.Run("high priority not gated by default", func(t *testing.T) {
+		assert.True(t, m.QueueOpen(1, redis.PriorityHigh, mustTime(t, "2026-03-10 12:00")))
+	})
+
+	t.Run("explicit queue list", func(t *testing.T) {
+		assert.False(t, m.QueueOpen(2, redis.PriorityHigh, mustTime(t, "2026-03-10 12:30")))
+		assert.True(t, m.QueueOpen(2, redis.PriorityNormal, mustTime(t, "2026-03-10 12:30")))
+	})
+
+	t.Run("hosts without windows are always open", func(t *testing.T) {
+		assert.True(t, m.QueueOpen(99, redis.PriorityNormal, mustTime(t, "2026-03-10 12:00")))
+	})
+}
+
+func TestScheduleManager_QueueOpenTimezone(t *testing.T) {
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Timezone: "America/New_York",
+		Hosts: []configtypes.CacheDaemonHostWindows{
+			{HostID: 1, AllowedWindows: []string{"01:00-06:00"}},
+		},
+	}, mustTime(t, "2026-01-10 00:00"))
+	require.NoError(t, err)
+
+	// 07:00 UTC is 02:00 in New York (EST)
+	assert.True(t, m.QueueOpen(1, redis.PriorityNormal, mustTime(t, "2026-01-10 07:00")))
+	assert.False(t, m.QueueOpen(1, redis.PriorityNormal, mustTime(t, "2026-01-10 02:00")))
+}
+
+func TestScheduleManager_DueJobs(t *testing.T) {
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Jobs: []configtypes.CacheDaemonScheduledJob{
+			{Name: "nightly", HostID: 1, Cron: "0 3 * * *"},
+		},
+	}, mustTime(t, "2026-03-10 01:00"))
+	require.NoError(t, err)
+
+	jobs, _ := m.DueJobs(mustTime(t, "2026-03-10 02:59"))
+	assert.Empty(t, jobs, "not due before fire time")
+
+	jobs, fireTimes := m.DueJobs(mustTime(t, "2026-03-10 03:00"))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, mustTime(t, "2026-03-10 03:00"), fireTimes[0].UTC())
+
+	jobs, _ = m.DueJobs(mustTime(t, "2026-03-11 03:05"))
+	assert.Empty(t, jobs, "previous run still in progress")
+
+	m.finish(m.jobs[0])
+	jobs, fireTimes = m.DueJobs(mustTime(t, "2026-03-12 03:00"))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, mustTime(t, "2026-03-12 03:00"), fireTimes[0].UTC())
+}
+
+func TestScheduledJob_MatchesURL(t *testing.T) {
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Jobs: []configtypes.CacheDaemonScheduledJob{
+			{Name: "categories", HostID: 1, Cron: "@daily", URLPatterns: []string{"/category/*", "~^/c/[0-9]+$"}},
+			{Name: "all", HostID: 1, Cron: "@daily"},
+		},
+	}, time.Now())
+	require.NoError(t, err)
+
+	categories := m.jobs[0]
+	assert.True(t, categories.matchesURL("https://example.com/category/shoes"))
+	assert.True(t, categories.matchesURL("https://example.com/c/42"))
+	assert.False(t, categories.matchesURL("https://example.com/product/1"))
+
+	all := m.jobs[1]
+	assert.True(t, all.matchesURL("https://example.com/anything"))
+}
+
+func TestCacheDaemon_ExecuteScheduledJob(t *testing.T) {
+	daemon, mr := setupTestDaemon(t)
+	daemon.daemonConfig = &configtypes.CacheDaemonConfig{DaemonID: "daemon-1"}
+
+	populateMetadataHash(mr, 1, 1, "h1", map[string]string{
+		"key": "cache:1:1:h1", "url": "https://example.com/category/a", "dimension": "mobile",
+		"created_at": "1000000", "expires_at": "9999999999",
+	})
+	populateMetadataHash(mr, 1, 2, "h2", map[string]string{
+		"key": "cache:1:2:h2", "url": "https://example.com/category/a", "dimension": "desktop",
+		"created_at": "1000000", "expires_at": "9999999999",
+	})
+	populateMetadataHash(mr, 1, 1, "h3", map[string]string{
+		"key": "cache:1:1:h3", "url": "https://example.com/product/b", "dimension": "mobile",
+		"created_at": "1000000", "expires_at": "9999999999",
+	})
+
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Jobs: []configtypes.CacheDaemonScheduledJob{
+			{Name: "categories", HostID: 1, Cron: "@daily", URLPatterns: []string{"/category/*"}, DimensionIDs: []int{1}},
+		},
+	}, time.Now())
+	require.NoError(t, err)
+
+	enqueued, err := daemon.ExecuteScheduledJob(m.jobs[0])
+	require.NoError(t, err)
+	assert.Equal(t, 1, enqueued)
+
+	members, err := mr.ZMembers(daemon.keyGenerator.RecacheQueueKey(1, redis.PriorityNormal))
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	var member types.RecacheMember
+	require.NoError(t, json.Unmarshal([]byte(members[0]), &member))
+	assert.Equal(t, "https://example.com/category/a", member.URL)
+	assert.Equal(t, 1, member.DimensionID)
+}
+
+func TestCacheDaemon_RunDueScheduledJobsDeduplicates(t *testing.T) {
+	daemon, mr := setupTestDaemon(t)
+	daemon.daemonConfig = &configtypes.CacheDaemonConfig{DaemonID: "daemon-1"}
+
+	start := mustTime(t, "2026-03-10 02:00")
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Jobs: []configtypes.CacheDaemonScheduledJob{{Name: "nightly", HostID: 1, Cron: "0 3 * * *"}},
+	}, start)
+	require.NoError(t, err)
+	daemon.schedules = m
+
+	fireTime := mustTime(t, "2026-03-10 03:00")
+	lockKey := daemon.keyGenerator.ScheduledJobRunKey("nightly", fireTime.Unix())
+	require.NoError(t, mr.Set(lockKey, "daemon-2"))
+
+	daemon.RunDueScheduledJobs(fireTime)
+
+	value, err := mr.Get(lockKey)
+	require.NoError(t, err)
+	assert.Equal(t, "daemon-2", value, "run claimed by another daemon must not be taken over")
+	assert.False(t, m.jobs[0].running)
+}
+
+func TestCacheDaemon_SchedulableHosts(t *testing.T) {
+	daemon, _ := setupTestDaemon(t)
+
+	// Host 2 is closed all day for normal queue
+	m, err := NewScheduleManager(&configtypes.CacheDaemonSchedules{
+		Hosts: []configtypes.CacheDaemonHostWindows{
+			{HostID: 2, BlackoutWindows: []string{"00:00-24:00"}},
+		},
+	}, time.Now())
+	require.NoError(t, err)
+	daemon.schedules = m
+
+	hosts := daemon.schedulableHosts(redis.PriorityNormal)
+	sort.Ints(hosts)
+	assert.Equal(t, []int{1}, hosts)
+
+	hosts = daemon.schedulableHosts(redis.PriorityHigh)
+	sort.Ints(hosts)
+	assert.Equal(t, []int{1, 2}, hosts)
+}
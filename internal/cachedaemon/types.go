@@ -1,5 +1,12 @@
 package cachedaemon
 
+import (
+	"time"
+
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/internal/edge/seoaudit"
+)
+
 // StatusResponse is the response for GET /status endpoint
 type StatusResponse struct {
 	Daemon        DaemonStatus             `json:"daemon"`
@@ -42,3 +49,70 @@ type QueueStatus struct {
 	Total  int `json:"total"`   // Total entries in ZSET
 	DueNow int `json:"due_now"` // Entries with score <= now
 }
+
+// DuplicatesResponse is the response for GET /internal/cache/duplicates endpoint
+type DuplicatesResponse struct {
+	HostID           int                      `json:"host_id"`
+	MinSimilarity    float64                  `json:"min_similarity"`
+	TotalClusters    int                      `json:"total_clusters"`
+	Clusters         []cache.DuplicateCluster `json:"clusters"`
+	IndexedPages     int                      `json:"indexed_pages"`
+	ComparedPairs    int                      `json:"compared_pairs"`
+	TruncatedBuckets int                      `json:"truncated_buckets"`
+}
+
+// SEOReportResponse is the response for GET /internal/cache/seo endpoint
+type SEOReportResponse struct {
+	HostID    int    `json:"host_id"`
+	Dimension string `json:"dimension,omitempty"`
+	*seoaudit.Report
+}
+
+// SEOHistoryResponse is the response for GET /internal/cache/seo/history endpoint
+type SEOHistoryResponse struct {
+	HostID    int                `json:"host_id"`
+	URL       string             `json:"url"` // Normalized URL
+	Dimension string             `json:"dimension"`
+	CacheKey  string             `json:"cache_key"`
+	Versions  []cache.SEOVersion `json:"versions"` // Newest first
+	Changes   []SEOHistoryChange `json:"changes"`  // Between consecutive versions, newest first
+}
+
+// SEOHistoryChange lists the tracked PageSEO fields that changed between two renders
+type SEOHistoryChange struct {
+	From   time.Time              `json:"from"`
+	To     time.Time              `json:"to"`
+	Fields []cache.SEOFieldChange `json:"fields"`
+}
+
+// LinkReportResponse is the response for GET /internal/cache/links/inlinks (without url)
+// and GET /internal/cache/links/broken endpoints
+type LinkReportResponse struct {
+	HostID    int    `json:"host_id"`
+	Dimension string `json:"dimension"`
+	*cache.LinkedPages
+}
+
+// PageInlinksResponse is the response for GET /internal/cache/links/inlinks with url
+type PageInlinksResponse struct {
+	HostID     int      `json:"host_id"`
+	Dimension  string   `json:"dimension"`
+	URL        string   `json:"url"` // Normalized URL
+	Inlinks    int      `json:"inlinks"`
+	LinkedFrom []string `json:"linked_from"` // URLs of linking pages, sorted
+}
+
+// OrphansResponse is the response for GET /internal/cache/links/orphans endpoint
+type OrphansResponse struct {
+	HostID    int          `json:"host_id"`
+	Dimension string       `json:"dimension"`
+	Total     int          `json:"total"`
+	Pages     []OrphanPage `json:"pages"`
+}
+
+// OrphanPage is a cached 200 page that no rendered page links to
+type OrphanPage struct {
+	URL      string `json:"url"`
+	CacheKey string `json:"cache_key"`
+	Source   string `json:"source"`
+}
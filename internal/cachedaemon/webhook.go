@@ -0,0 +1,427 @@
+package cachedaemon
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/eventbus"
+	"github.com/edgecomet/engine/internal/common/httputil"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/pattern"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+const (
+	webhookPath            = "/webhooks/origin"
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignaturePrefix = "sha256="
+
+	// maxWebhookURLs bounds URLs per webhook, including URLs expanded from tags
+	maxWebhookURLs = 10000
+)
+
+// webhookTag is the compiled form of CacheDaemonWebhookTag
+type webhookTag struct {
+	hostID   int
+	patterns []*pattern.Pattern
+}
+
+// webhookTarget is a single cache entry affected by a webhook
+type webhookTarget struct {
+	host        *types.Host
+	url         string // Normalized URL
+	urlHash     string
+	dimensionID int
+}
+
+// compileWebhookTags compiles tag URL patterns, grouped by tag name.
+// Patterns are validated with the daemon config, so invalid ones are skipped.
+func compileWebhookTags(cfg *configtypes.CacheDaemonWebhooks) map[string][]webhookTag {
+	tags := make(map[string][]webhookTag, len(cfg.Tags))
+	for _, t := range cfg.Tags {
+		compiled := webhookTag{hostID: t.HostID}
+		for _, p := range t.URLPatterns {
+			if cp, err := pattern.Compile(p); err == nil {
+				compiled.patterns = append(compiled.patterns, cp)
+			}
+		}
+		tags[t.Name] = append(tags[t.Name], compiled)
+	}
+	return tags
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "{timestamp}.{body}"
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyWebhookSignature checks the timestamp window and the signature against every secret
+func verifyWebhookSignature(cfg *configtypes.CacheDaemonWebhooks, timestamp, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("missing %s or %s header", webhookTimestampHeader, webhookSignatureHeader)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", webhookTimestampHeader)
+	}
+	skew := time.Duration(math.Abs(float64(now.Unix()-ts))) * time.Second
+	if skew > cfg.GetTimestampTolerance() {
+		return fmt.Errorf("timestamp outside tolerance (%v)", skew)
+	}
+
+	provided, err := hex.DecodeString(strings.TrimPrefix(signature, webhookSignaturePrefix))
+	if err != nil {
+		return fmt.Errorf("invalid %s header", webhookSignatureHeader)
+	}
+
+	for _, secret := range cfg.Secrets {
+		expected, _ := hex.DecodeString(signWebhook(secret, timestamp, body))
+		if hmac.Equal(provided, expected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature mismatch")
+}
+
+// claimWebhookDelivery records a verified delivery by its signature with SET NX, so a captured
+// request cannot be replayed while its timestamp is still within tolerance. The claim lasts
+// until the timestamp leaves the tolerance window. Returns false for a duplicate delivery.
+func (d *CacheDaemon) claimWebhookDelivery(timestamp, signature string, tolerance time.Duration, now time.Time) (bool, error) {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s header", webhookTimestampHeader)
+	}
+	ttl := time.Unix(ts, 0).Add(tolerance).Sub(now)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	key := d.keyGenerator.WebhookDeliveryKey(strings.ToLower(strings.TrimPrefix(signature, webhookSignaturePrefix)))
+	return d.redis.SetNX(context.Background(), key, timestamp, ttl)
+}
+
+// handleWebhook handles POST /webhooks/origin.
+// Authenticated by HMAC signature rather than X-Internal-Auth so CMSes never hold the internal key.
+func (d *CacheDaemon) handleWebhook(ctx *fasthttp.RequestCtx) {
+	cfg := &d.daemonConfig.Webhooks
+	if !cfg.Enabled {
+		httputil.JSONError(ctx, "not found", fasthttp.StatusNotFound)
+		return
+	}
+
+	body := ctx.Request.Body()
+	timestamp := string(ctx.Request.Header.Peek(webhookTimestampHeader))
+	signature := string(ctx.Request.Header.Peek(webhookSignatureHeader))
+	now := time.Now().UTC()
+	if err := verifyWebhookSignature(cfg, timestamp, signature, body, now); err != nil {
+		d.logger.Warn("Rejected origin webhook",
+			zap.String("remote_addr", ctx.RemoteAddr().String()),
+			zap.Error(err))
+		httputil.JSONError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
+		return
+	}
+
+	claimed, err := d.claimWebhookDelivery(timestamp, signature, cfg.GetTimestampTolerance(), now)
+	if err != nil {
+		d.logger.Error("Failed to record webhook delivery", zap.Error(err))
+		httputil.JSONError(ctx, "failed to record delivery", fasthttp.StatusInternalServerError)
+		return
+	}
+	if !claimed {
+		d.logger.Warn("Rejected replayed origin webhook",
+			zap.String("remote_addr", ctx.RemoteAddr().String()),
+			zap.String("timestamp", timestamp))
+		httputil.JSONError(ctx, "duplicate delivery", fasthttp.StatusConflict)
+		return
+	}
+
+	var req types.WebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		httputil.JSONError(ctx, fmt.Sprintf("invalid json: %s", err.Error()), fasthttp.StatusBadRequest)
+		return
+	}
+
+	var deletedStatus int
+	switch req.Event {
+	case types.WebhookEventPublish, types.WebhookEventUpdate:
+	case types.WebhookEventUnpublish:
+		deletedStatus = fasthttp.StatusNotFound
+	case types.WebhookEventDelete:
+		deletedStatus = fasthttp.StatusGone
+	default:
+		httputil.JSONError(ctx, "event must be one of: publish, update, unpublish, delete", fasthttp.StatusBadRequest)
+		return
+	}
+
+	if len(req.URLs) == 0 && len(req.Tags) == 0 {
+		httputil.JSONError(ctx, "urls or tags must be specified", fasthttp.StatusBadRequest)
+		return
+	}
+	if len(req.URLs) > maxWebhookURLs {
+		httputil.JSONError(ctx, fmt.Sprintf("urls array cannot exceed %d entries", maxWebhookURLs), fasthttp.StatusBadRequest)
+		return
+	}
+
+	targets, unknownURLs := d.resolveWebhookURLs(req.URLs)
+	tagTargets, unknownTags, err := d.expandWebhookTags(req.Tags)
+	if err != nil {
+		d.logger.Error("Failed to expand webhook tags", zap.Error(err))
+		httputil.JSONError(ctx, "failed to expand tags", fasthttp.StatusInternalServerError)
+		return
+	}
+	targets = append(targets, tagTargets...)
+
+	data := types.WebhookAPIData{
+		Event:       req.Event,
+		URLsCount:   countWebhookURLs(targets),
+		UnknownURLs: unknownURLs,
+		UnknownTags: unknownTags,
+	}
+
+	if deletedStatus != 0 {
+		data.EntriesDeleted = d.markWebhookTargetsDeleted(targets, deletedStatus, cfg.GetDeletedTTL())
+	} else {
+		data.EntriesEnqueued = d.enqueueWebhookTargets(targets)
+	}
+
+	httputil.JSONData(ctx, data, fasthttp.StatusOK)
+
+	d.logger.Info("Origin webhook processed",
+		zap.String("event", req.Event),
+		zap.Int("urls_count", data.URLsCount),
+		zap.Int("entries_enqueued", data.EntriesEnqueued),
+		zap.Int("entries_deleted", data.EntriesDeleted),
+		zap.Strings("unknown_urls", unknownURLs),
+		zap.Strings("unknown_tags", unknownTags))
+}
+
+// resolveWebhookURLs maps URLs to hosts by domain and expands them to all non-block dimensions
+func (d *CacheDaemon) resolveWebhookURLs(urls []string) ([]webhookTarget, []string) {
+	var targets []webhookTarget
+	var unknown []string
+
+	for _, rawURL := range urls {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || parsed.Hostname() == "" {
+			unknown = append(unknown, rawURL)
+			continue
+		}
+		host := d.configManager.GetHostByDomain(strings.ToLower(parsed.Hostname()))
+		if host == nil {
+			unknown = append(unknown, rawURL)
+			continue
+		}
+
+		normalized, err := d.normalizer.Normalize(rawURL, nil)
+		if err != nil {
+			unknown = append(unknown, rawURL)
+			continue
+		}
+
+		urlHash := d.normalizer.Hash(normalized.NormalizedURL)
+		dimensionIDs, _ := resolveDimensionIDs(host, nil)
+		for _, dimensionID := range dimensionIDs {
+			targets = append(targets, webhookTarget{host: host, url: normalized.NormalizedURL, urlHash: urlHash, dimensionID: dimensionID})
+		}
+	}
+
+	return targets, unknown
+}
+
+// expandWebhookTags scans cache metadata of each tag's host for URLs matching its patterns
+func (d *CacheDaemon) expandWebhookTags(tags []string) ([]webhookTarget, []string, error) {
+	var targets []webhookTarget
+	var unknown []string
+
+	for _, name := range tags {
+		mappings, ok := d.webhookTags[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		for _, mapping := range mappings {
+			host := d.GetHost(mapping.hostID)
+			if host == nil {
+				continue
+			}
+
+			cursor := "0"
+			for {
+				page, err := d.cacheReader.ListURLs(CacheListParams{
+					HostID: host.ID,
+					Cursor: cursor,
+					Limit:  scheduledJobPageSize,
+				})
+				if err != nil {
+					return nil, nil, fmt.Errorf("tag %q: %w", name, err)
+				}
+
+				for _, item := range page.Items {
+					if !matchURLPatterns(mapping.patterns, item.URL) {
+						continue
+					}
+					cacheKey, err := d.keyGenerator.ParseCacheKey(item.CacheKey)
+					if err != nil {
+						continue
+					}
+					targets = append(targets, webhookTarget{host: host, url: item.URL, urlHash: cacheKey.URLHash, dimensionID: cacheKey.DimensionID})
+					if len(targets) > maxWebhookURLs {
+						return nil, nil, fmt.Errorf("tag %q matches more than %d cache entries", name, maxWebhookURLs)
+					}
+				}
+
+				if !page.HasMore {
+					break
+				}
+				cursor = page.Cursor
+			}
+		}
+	}
+
+	return targets, unknown, nil
+}
+
+// enqueueWebhookTargets clears deleted-content markers and adds targets to the high priority queue
+func (d *CacheDaemon) enqueueWebhookTargets(targets []webhookTarget) int {
+	ctx := context.Background()
+	score := float64(time.Now().UTC().Unix())
+	enqueued := 0
+	var cleared []eventbus.Entry
+
+	for _, target := range targets {
+		cacheKey := d.keyGenerator.GenerateCacheKey(target.host.ID, target.dimensionID, target.urlHash)
+		metadataKey := d.keyGenerator.GenerateMetadataKey(cacheKey)
+
+		// Republished content must not keep serving 404/410 until the recache finishes
+		source, err := d.redis.HGet(ctx, metadataKey, "source")
+		if err == nil && source == cache.SourceDeleted {
+			if err := d.redis.Del(ctx, metadataKey); err != nil {
+				d.logger.Error("Failed to clear deleted-content marker",
+					zap.String("metadata_key", metadataKey),
+					zap.Error(err))
+			} else {
+				cleared = append(cleared, eventbus.Entry{Key: cacheKey.String()})
+			}
+		}
+
+		memberJSON, _ := json.Marshal(types.RecacheMember{URL: target.url, DimensionID: target.dimensionID})
+		queueKey := d.keyGenerator.RecacheQueueKey(target.host.ID, redis.PriorityHigh)
+		if err := d.redis.ZAdd(ctx, queueKey, score, string(memberJSON)); err != nil {
+			d.logger.Error("Failed to add webhook entry to ZSET",
+				zap.String("queue", queueKey),
+				zap.String("url", target.url),
+				zap.Error(err))
+			continue
+		}
+		enqueued++
+	}
+
+	d.events.PublishEntries(ctx, eventbus.EntryInvalidated, cleared...)
+	return enqueued
+}
+
+// markWebhookTargetsDeleted replaces cache metadata with metadata-only deleted-content markers
+// that EGs serve as a bodyless statusCode response, and drops pending recaches for the targets
+func (d *CacheDaemon) markWebhookTargetsDeleted(targets []webhookTarget, statusCode int, ttl time.Duration) int {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	marked := 0
+	var invalidated []eventbus.Entry
+
+	for _, target := range targets {
+		cacheKey := d.keyGenerator.GenerateCacheKey(target.host.ID, target.dimensionID, target.urlHash)
+		metadataKey := d.keyGenerator.GenerateMetadataKey(cacheKey)
+
+		marker := &cache.CacheMetadata{
+			Key:         cacheKey.String(),
+			URL:         target.url,
+			HostID:      target.host.ID,
+			Dimension:   dimensionName(target.host, target.dimensionID),
+			RequestID:   "webhook",
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+			LastAccess:  now,
+			Source:      cache.SourceDeleted,
+			StatusCode:  statusCode,
+			IndexStatus: int(types.IndexStatusNon200),
+		}
+
+		var values []interface{}
+		for k, v := range marker.ToHash() {
+			values = append(values, k, v)
+		}
+
+		// EGs delete the file of the previous entry
+		filePath, _ := d.redis.HGet(ctx, metadataKey, "file_path")
+
+		// Delete first so fields of the previous entry (file_path, eg_ids) do not survive
+		if err := d.redis.Del(ctx, metadataKey); err != nil {
+			d.logger.Error("Failed to delete cache metadata for deleted content",
+				zap.String("metadata_key", metadataKey),
+				zap.Error(err))
+			continue
+		}
+		invalidated = append(invalidated, eventbus.Entry{Key: cacheKey.String(), FilePath: filePath})
+		if err := d.redis.HSetWithExpire(ctx, metadataKey, ttl, values...); err != nil {
+			d.logger.Error("Failed to store deleted-content marker",
+				zap.String("metadata_key", metadataKey),
+				zap.Error(err))
+			continue
+		}
+
+		memberJSON, _ := json.Marshal(types.RecacheMember{URL: target.url, DimensionID: target.dimensionID})
+		for _, priority := range []string{redis.PriorityHigh, redis.PriorityNormal, redis.PriorityAutorecache} {
+			queueKey := d.keyGenerator.RecacheQueueKey(target.host.ID, priority)
+			if err := d.redis.ZRem(ctx, queueKey, string(memberJSON)); err != nil {
+				d.logger.Warn("Failed to remove pending recache for deleted content",
+					zap.String("queue", queueKey),
+					zap.String("url", target.url),
+					zap.Error(err))
+			}
+		}
+		marked++
+	}
+
+	d.events.PublishEntries(ctx, eventbus.EntryInvalidated, invalidated...)
+	return marked
+}
+
+// dimensionName returns the name of a host dimension by ID
+func dimensionName(host *types.Host, dimensionID int) string {
+	for name, dim := range host.Dimensions {
+		if dim.ID == dimensionID {
+			return name
+		}
+	}
+	return ""
+}
+
+// countWebhookURLs returns the number of distinct URLs across targets
+func countWebhookURLs(targets []webhookTarget) int {
+	seen := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		seen[fmt.Sprintf("%d:%s", t.host.ID, t.url)] = true
+	}
+	return len(seen)
+}
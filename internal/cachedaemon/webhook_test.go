@@ -0,0 +1,236 @@
+package cachedaemon
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+const testWebhookSecret = "webhook-secret-0123456789"
+
+func setupWebhookDaemon(t *testing.T, tags ...configtypes.CacheDaemonWebhookTag) (*CacheDaemon, func(body []byte) *fasthttp.RequestCtx) {
+	daemon, _ := setupTestDaemon(t)
+	daemon.daemonConfig = &configtypes.CacheDaemonConfig{
+		Webhooks: configtypes.CacheDaemonWebhooks{
+			Enabled: true,
+			Secrets: []string{"old-secret-0123456789", testWebhookSecret},
+			Tags:    tags,
+		},
+	}
+	daemon.webhookTags = compileWebhookTags(&daemon.daemonConfig.Webhooks)
+
+	send := func(body []byte) *fasthttp.RequestCtx {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod("POST")
+		ctx.Request.SetRequestURI(webhookPath)
+		ctx.Request.Header.Set(webhookTimestampHeader, timestamp)
+		ctx.Request.Header.Set(webhookSignatureHeader, webhookSignaturePrefix+signWebhook(testWebhookSecret, timestamp, body))
+		ctx.Request.SetBody(body)
+		daemon.ServeHTTP(ctx)
+		return ctx
+	}
+	return daemon, send
+}
+
+func webhookData(t *testing.T, ctx *fasthttp.RequestCtx) types.WebhookAPIData {
+	t.Helper()
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode(), string(ctx.Response.Body()))
+	var resp struct {
+		Data types.WebhookAPIData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(ctx.Response.Body(), &resp))
+	return resp.Data
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	cfg := &configtypes.CacheDaemonWebhooks{Secrets: []string{testWebhookSecret}}
+	body := []byte(`{"event":"update"}`)
+	now := time.Unix(1_700_000_000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := webhookSignaturePrefix + signWebhook(testWebhookSecret, timestamp, body)
+
+	t.Run("valid signature", func(t *testing.T) {
+		assert.NoError(t, verifyWebhookSignature(cfg, timestamp, signature, body, now))
+	})
+
+	t.Run("signature without prefix", func(t *testing.T) {
+		assert.NoError(t, verifyWebhookSignature(cfg, timestamp, signWebhook(testWebhookSecret, timestamp, body), body, now))
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		assert.Error(t, verifyWebhookSignature(cfg, timestamp, signature, []byte(`{"event":"delete"}`), now))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		bad := webhookSignaturePrefix + signWebhook("another-secret-0123456", timestamp, body)
+		assert.Error(t, verifyWebhookSignature(cfg, timestamp, bad, body, now))
+	})
+
+	t.Run("timestamp outside tolerance", func(t *testing.T) {
+		assert.Error(t, verifyWebhookSignature(cfg, timestamp, signature, body, now.Add(10*time.Minute)))
+	})
+
+	t.Run("missing headers", func(t *testing.T) {
+		assert.Error(t, verifyWebhookSignature(cfg, "", signature, body, now))
+		assert.Error(t, verifyWebhookSignature(cfg, timestamp, "", body, now))
+	})
+}
+
+func TestHandleWebhook_Authentication(t *testing.T) {
+	daemon, _ := setupWebhookDaemon(t)
+
+	t.Run("replayed delivery is rejected", func(t *testing.T) {
+		body := []byte(`{"event":"update","urls":["https://example.com/replay"]}`)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		signature := webhookSignaturePrefix + signWebhook(testWebhookSecret, timestamp, body)
+		deliver := func() *fasthttp.RequestCtx {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod("POST")
+			ctx.Request.SetRequestURI(webhookPath)
+			ctx.Request.Header.Set(webhookTimestampHeader, timestamp)
+			ctx.Request.Header.Set(webhookSignatureHeader, signature)
+			ctx.Request.SetBody(body)
+			daemon.ServeHTTP(ctx)
+			return ctx
+		}
+
+		webhookData(t, deliver())
+		assert.Equal(t, fasthttp.StatusConflict, deliver().Response.StatusCode())
+
+		ttl, err := daemon.redis.TTL(t.Context(), daemon.keyGenerator.WebhookDeliveryKey(signWebhook(testWebhookSecret, timestamp, body)))
+		require.NoError(t, err)
+		assert.Greater(t, ttl, time.Duration(0))
+		assert.LessOrEqual(t, ttl, daemon.daemonConfig.Webhooks.GetTimestampTolerance())
+	})
+
+	t.Run("internal auth key is not accepted", func(t *testing.T) {
+		ctx := makePostRequest(daemon, webhookPath, []byte(`{"event":"update","urls":["https://example.com/a"]}`))
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+	})
+
+	t.Run("disabled webhooks are not found", func(t *testing.T) {
+		daemon.daemonConfig.Webhooks.Enabled = false
+		defer func() { daemon.daemonConfig.Webhooks.Enabled = true }()
+
+		ctx := makePostRequest(daemon, webhookPath, []byte(`{}`))
+		assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+	})
+}
+
+func TestHandleWebhook_Update(t *testing.T) {
+	daemon, send := setupWebhookDaemon(t)
+	mr := daemon.redis
+
+	// Existing deleted-content marker for mobile must be cleared on republish
+	normalized, err := daemon.normalizer.Normalize("https://example.com/a", nil)
+	require.NoError(t, err)
+	markerKey := daemon.keyGenerator.GenerateMetadataKey(daemon.keyGenerator.GenerateCacheKey(1, 1, daemon.normalizer.Hash(normalized.NormalizedURL)))
+	require.NoError(t, mr.HSet(t.Context(), markerKey, "source", cache.SourceDeleted, "status_code", 410))
+
+	data := webhookData(t, send([]byte(`{"event":"update","urls":["https://example.com/a","https://unknown.com/b"]}`)))
+	assert.Equal(t, 1, data.URLsCount)
+	assert.Equal(t, 3, data.EntriesEnqueued, "all non-block dimensions")
+	assert.Equal(t, []string{"https://unknown.com/b"}, data.UnknownURLs)
+
+	count, err := mr.ZCard(t.Context(), daemon.keyGenerator.RecacheQueueKey(1, redis.PriorityHigh))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	exists, err := mr.Exists(t.Context(), markerKey)
+	require.NoError(t, err)
+	assert.False(t, exists, "deleted-content marker must be cleared")
+}
+
+func TestHandleWebhook_Delete(t *testing.T) {
+	daemon, send := setupWebhookDaemon(t)
+
+	normalized, err := daemon.normalizer.Normalize("https://example.com/gone", nil)
+	require.NoError(t, err)
+	urlHash := daemon.normalizer.Hash(normalized.NormalizedURL)
+	cacheKey := daemon.keyGenerator.GenerateCacheKey(1, 1, urlHash)
+	metadataKey := daemon.keyGenerator.GenerateMetadataKey(cacheKey)
+	require.NoError(t, daemon.redis.HSet(t.Context(), metadataKey, "source", "render", "file_path", "1/2026/01/01/00/00/x.html", "eg_ids", "eg-1"))
+
+	member, _ := json.Marshal(types.RecacheMember{URL: normalized.NormalizedURL, DimensionID: 1})
+	autorecacheKey := daemon.keyGenerator.RecacheQueueKey(1, redis.PriorityAutorecache)
+	require.NoError(t, daemon.redis.ZAdd(t.Context(), autorecacheKey, 1, string(member)))
+
+	data := webhookData(t, send([]byte(`{"event":"delete","urls":["https://example.com/gone"]}`)))
+	assert.Equal(t, 3, data.EntriesDeleted)
+	assert.Zero(t, data.EntriesEnqueued)
+
+	fields, err := daemon.redis.HGetAll(t.Context(), metadataKey)
+	require.NoError(t, err)
+	marker := &cache.CacheMetadata{}
+	require.NoError(t, marker.FromHash(fields))
+	assert.True(t, marker.IsDeleted())
+	assert.Equal(t, fasthttp.StatusGone, marker.StatusCode)
+	assert.Equal(t, "mobile", marker.Dimension)
+	assert.Empty(t, marker.FilePath, "fields of the previous entry must not survive")
+	assert.Empty(t, marker.EgIDs)
+
+	ttl, err := daemon.redis.TTL(t.Context(), metadataKey)
+	require.NoError(t, err)
+	assert.InDelta(t, configtypes.DefaultWebhookDeletedTTL.Seconds(), ttl.Seconds(), 5)
+
+	count, err := daemon.redis.ZCard(t.Context(), autorecacheKey)
+	require.NoError(t, err)
+	assert.Zero(t, count, "pending recache must be dropped")
+}
+
+func TestHandleWebhook_UnpublishTags(t *testing.T) {
+	daemon, send := setupWebhookDaemon(t, configtypes.CacheDaemonWebhookTag{
+		Name: "shoes", HostID: 1, URLPatterns: []string{"/shoes/*"},
+	})
+	mr := daemon.redis
+
+	for hash, u := range map[string]string{"h1": "https://example.com/shoes/red", "h2": "https://example.com/hats/blue"} {
+		require.NoError(t, mr.HSet(t.Context(), "meta:cache:1:2:"+hash,
+			"key", "cache:1:2:"+hash, "url", u, "dimension", "desktop",
+			"created_at", "1000000", "expires_at", "9999999999"))
+	}
+
+	data := webhookData(t, send([]byte(`{"event":"unpublish","tags":["shoes","missing"]}`)))
+	assert.Equal(t, 1, data.URLsCount)
+	assert.Equal(t, 1, data.EntriesDeleted)
+	assert.Equal(t, []string{"missing"}, data.UnknownTags)
+
+	status, err := mr.HGet(t.Context(), "meta:cache:1:2:h1", "status_code")
+	require.NoError(t, err)
+	assert.Equal(t, "404", status)
+
+	source, err := mr.HGet(t.Context(), "meta:cache:1:2:h2", "source")
+	require.NoError(t, err)
+	assert.Empty(t, source, "non-matching URL untouched")
+}
+
+func TestHandleWebhook_Validation(t *testing.T) {
+	_, send := setupWebhookDaemon(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{`},
+		{name: "unknown event", body: `{"event":"archive","urls":["https://example.com/a"]}`},
+		{name: "no urls or tags", body: `{"event":"update"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := send([]byte(tt.body))
+			assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+		})
+	}
+}
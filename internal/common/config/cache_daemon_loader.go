@@ -26,6 +26,14 @@ func applyDaemonDefaults(config *configtypes.CacheDaemonConfig) {
 	if config.Logging.File.Format == "" {
 		config.Logging.File.Format = configtypes.LogFormatText
 	}
+
+	// Fair-share scheduling defaults
+	if config.Scheduler.FairShare.Quantum == 0 {
+		config.Scheduler.FairShare.Quantum = configtypes.DefaultFairShareQuantum
+	}
+	if config.Scheduler.FairShare.DefaultWeight == 0 {
+		config.Scheduler.FairShare.DefaultWeight = configtypes.DefaultFairShareWeight
+	}
 }
 
 // LoadCacheDaemonConfig loads cache-daemon configuration from YAML file
@@ -45,9 +45,18 @@ type ResolvedRenderConfig struct {
 	Timeout              time.Duration
 	Dimension            string // empty = use detected
 	Events               types.RenderEvents
-	BlockedPatterns      []string // Merged global → host → pattern
-	BlockedResourceTypes []string // Merged global → host → pattern
-	StripScripts         bool     // Whether to strip executable scripts from rendered HTML
+	BlockedPatterns      []string              // Merged global → host → pattern
+	BlockedResourceTypes []string              // Merged global → host → pattern
+	StripScripts         bool                  // Whether to strip executable scripts from rendered HTML
+	Transforms           []types.HTMLTransform // HTML post-processing, global → host → pattern (replaced, not merged)
+	Markdown             ResolvedMarkdownConfig
+	Quality              *types.RenderQualityConfig // Quality gates, host → pattern (replaced, not merged), nil = none
+}
+
+// ResolvedMarkdownConfig contains resolved Markdown variant configuration
+type ResolvedMarkdownConfig struct {
+	AcceptHeader  bool // Serve Markdown when the client prefers text/markdown
+	JSONLDSummary bool // Append a summary of JSON-LD structured data
 }
 
 // ResolvedBypassConfig contains resolved bypass configuration
@@ -275,6 +284,10 @@ func (r *ConfigResolver) resolveRenderConfig(resolved *ResolvedConfig, matchedRu
 	resolved.Render.Events = r.host.Render.Events
 	resolved.Render.Dimension = "" // Empty means use detected dimension
 
+	if len(r.globalRender.Transforms) > 0 {
+		resolved.Render.Transforms = r.globalRender.Transforms
+	}
+
 	// Host render config overrides blocked fields (replaces global)
 	if len(r.host.Render.BlockedResourceTypes) > 0 {
 		resolved.Render.BlockedResourceTypes = r.host.Render.BlockedResourceTypes
@@ -282,6 +295,10 @@ func (r *ConfigResolver) resolveRenderConfig(resolved *ResolvedConfig, matchedRu
 	if len(r.host.Render.BlockedPatterns) > 0 {
 		resolved.Render.BlockedPatterns = r.host.Render.BlockedPatterns
 	}
+	if len(r.host.Render.Transforms) > 0 {
+		resolved.Render.Transforms = r.host.Render.Transforms
+	}
+	resolved.Render.Quality = r.host.Render.Quality
 
 	// Apply pattern-level overrides
 	if matchedRule != nil && matchedRule.Render != nil {
@@ -301,6 +318,12 @@ func (r *ConfigResolver) resolveRenderConfig(resolved *ResolvedConfig, matchedRu
 		if len(matchedRule.Render.BlockedPatterns) > 0 {
 			resolved.Render.BlockedPatterns = matchedRule.Render.BlockedPatterns
 		}
+		if len(matchedRule.Render.Transforms) > 0 {
+			resolved.Render.Transforms = matchedRule.Render.Transforms
+		}
+		if matchedRule.Render.Quality != nil {
+			resolved.Render.Quality = matchedRule.Render.Quality
+		}
 	}
 
 	// Resolve StripScripts (default: true - scripts stripped by default)
@@ -315,6 +338,29 @@ func (r *ConfigResolver) resolveRenderConfig(resolved *ResolvedConfig, matchedRu
 		stripScripts = *matchedRule.Render.StripScripts
 	}
 	resolved.Render.StripScripts = stripScripts
+
+	// Resolve Markdown variant field by field (defaults: no Accept negotiation, JSON-LD summary on)
+	resolved.Render.Markdown = ResolvedMarkdownConfig{JSONLDSummary: true}
+	if r.globalRender != nil {
+		mergeMarkdownConfig(&resolved.Render.Markdown, r.globalRender.Markdown)
+	}
+	mergeMarkdownConfig(&resolved.Render.Markdown, r.host.Render.Markdown)
+	if matchedRule != nil && matchedRule.Render != nil {
+		mergeMarkdownConfig(&resolved.Render.Markdown, matchedRule.Render.Markdown)
+	}
+}
+
+// mergeMarkdownConfig applies the fields set in override
+func mergeMarkdownConfig(base *ResolvedMarkdownConfig, override *types.MarkdownConfig) {
+	if override == nil {
+		return
+	}
+	if override.AcceptHeader != nil {
+		base.AcceptHeader = *override.AcceptHeader
+	}
+	if override.JSONLDSummary != nil {
+		base.JSONLDSummary = *override.JSONLDSummary
+	}
 }
 
 // mergeRenderEvents performs deep merge of render events configuration
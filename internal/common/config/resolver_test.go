@@ -1357,3 +1357,80 @@ func TestResolver_BypassCacheExpiredMerge(t *testing.T) {
 		assert.Nil(t, resolved.Bypass.Cache.Expired.StaleTTL)
 	})
 }
+
+func TestResolver_TransformsResolution(t *testing.T) {
+	globalBypass := buildTestGlobalBypass()
+	minify := []types.HTMLTransform{{Type: types.HTMLTransformMinify}}
+	removeBanner := []types.HTMLTransform{{Type: types.HTMLTransformRemove, Selector: "#cookie-banner"}}
+	removeChat := []types.HTMLTransform{{Type: types.HTMLTransformRemove, Selector: ".chat-widget"}}
+
+	globalRender := buildTestGlobalRender()
+	globalRender.Transforms = minify
+	host := buildTestHost()
+
+	resolver := NewConfigResolver(globalRender, globalBypass, nil, nil, nil, nil, types.CompressionSnappy, host)
+	assert.Equal(t, minify, resolver.ResolveForURL("https://example.com/page").Render.Transforms, "global transforms apply by default")
+
+	host.Render.Transforms = removeBanner
+	host.URLRules = []types.URLRule{
+		{
+			Match:  "/support/*",
+			Action: types.ActionRender,
+			Render: &types.RenderRuleConfig{Transforms: removeChat},
+		},
+	}
+
+	resolver = NewConfigResolver(globalRender, globalBypass, nil, nil, nil, nil, types.CompressionSnappy, host)
+	assert.Equal(t, removeBanner, resolver.ResolveForURL("https://example.com/page").Render.Transforms, "host replaces global")
+	assert.Equal(t, removeChat, resolver.ResolveForURL("https://example.com/support/faq").Render.Transforms, "pattern replaces host")
+}
+
+func TestResolver_QualityResolution(t *testing.T) {
+	globalBypass := buildTestGlobalBypass()
+	globalRender := buildTestGlobalRender()
+	host := buildTestHost()
+
+	resolver := NewConfigResolver(globalRender, globalBypass, nil, nil, nil, nil, types.CompressionSnappy, host)
+	assert.Nil(t, resolver.ResolveForURL("https://example.com/page").Render.Quality, "no gates by default")
+
+	hostQuality := &types.RenderQualityConfig{MinWordCount: 50, RequiredSelectors: []string{"#app"}}
+	checkoutQuality := &types.RenderQualityConfig{ForbiddenText: []string{"Something went wrong"}}
+	host.Render.Quality = hostQuality
+	host.URLRules = []types.URLRule{
+		{
+			Match:  "/checkout/*",
+			Action: types.ActionRender,
+			Render: &types.RenderRuleConfig{Quality: checkoutQuality},
+		},
+	}
+
+	resolver = NewConfigResolver(globalRender, globalBypass, nil, nil, nil, nil, types.CompressionSnappy, host)
+	assert.Same(t, hostQuality, resolver.ResolveForURL("https://example.com/page").Render.Quality)
+	assert.Same(t, checkoutQuality, resolver.ResolveForURL("https://example.com/checkout/cart").Render.Quality, "pattern replaces host")
+}
+
+func TestResolver_MarkdownResolution(t *testing.T) {
+	globalBypass := buildTestGlobalBypass()
+	globalRender := buildTestGlobalRender()
+	host := buildTestHost()
+
+	resolver := NewConfigResolver(globalRender, globalBypass, nil, nil, nil, nil, types.CompressionSnappy, host)
+	assert.Equal(t, ResolvedMarkdownConfig{AcceptHeader: false, JSONLDSummary: true},
+		resolver.ResolveForURL("https://example.com/page").Render.Markdown, "defaults")
+
+	globalRender.Markdown = &types.MarkdownConfig{AcceptHeader: ptrBool(true)}
+	host.Render.Markdown = &types.MarkdownConfig{JSONLDSummary: ptrBool(false)}
+	host.URLRules = []types.URLRule{
+		{
+			Match:  "/checkout/*",
+			Action: types.ActionRender,
+			Render: &types.RenderRuleConfig{Markdown: &types.MarkdownConfig{AcceptHeader: ptrBool(false)}},
+		},
+	}
+
+	resolver = NewConfigResolver(globalRender, globalBypass, nil, nil, nil, nil, types.CompressionSnappy, host)
+	assert.Equal(t, ResolvedMarkdownConfig{AcceptHeader: true, JSONLDSummary: false},
+		resolver.ResolveForURL("https://example.com/page").Render.Markdown, "fields merge from global and host")
+	assert.Equal(t, ResolvedMarkdownConfig{AcceptHeader: false, JSONLDSummary: false},
+		resolver.ResolveForURL("https://example.com/checkout/cart").Render.Markdown, "pattern overrides a single field")
+}
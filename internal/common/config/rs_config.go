@@ -33,10 +33,11 @@ type RSServerConfig struct {
 
 // ChromeYAMLConfig represents Chrome configuration for YAML
 type ChromeYAMLConfig struct {
-	PoolSize string         `yaml:"pool_size"`
-	Warmup   WarmupConfig   `yaml:"warmup"`
-	Restart  RestartConfig  `yaml:"restart"`
-	Render   RSRenderConfig `yaml:"render"`
+	PoolSize      string               `yaml:"pool_size"`
+	Warmup        WarmupConfig         `yaml:"warmup"`
+	Restart       RestartConfig        `yaml:"restart"`
+	Render        RSRenderConfig       `yaml:"render"`
+	ResourceCache *ResourceCacheConfig `yaml:"resource_cache,omitempty"` // Subresource cache shared across renders (optional)
 }
 
 // WarmupConfig represents Chrome warmup configuration
@@ -60,6 +61,53 @@ const (
 	defaultRestartAfterTime  = 60 * time.Minute
 )
 
+// Resource cache defaults
+const (
+	DefaultResourceCacheMaxSizeMB      = 256
+	DefaultResourceCacheMaxEntrySizeMB = 5
+)
+
+// DefaultResourceCacheTypes are the Chrome resource types cached by default
+var DefaultResourceCacheTypes = []string{types.ResourceTypeScript, types.ResourceTypeStylesheet, types.ResourceTypeFont}
+
+// ResourceCacheConfig controls the subresource cache shared by all Chrome instances
+type ResourceCacheConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	MaxSizeMB      int      `yaml:"max_size_mb,omitempty"`       // Total cached body size (default: 256)
+	MaxEntrySizeMB int      `yaml:"max_entry_size_mb,omitempty"` // Largest cached response body (default: 5)
+	AllowedDomains []string `yaml:"allowed_domains,omitempty"`   // Domains cached besides the rendered page's origin (subdomains included)
+	ResourceTypes  []string `yaml:"resource_types,omitempty"`    // Chrome resource types to cache (default: Script, Stylesheet, Font)
+}
+
+// IsEnabled returns true if the resource cache is enabled (nil-safe)
+func (c *ResourceCacheConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetMaxSize returns the total cached body size in bytes
+func (c *ResourceCacheConfig) GetMaxSize() int64 {
+	if c == nil || c.MaxSizeMB <= 0 {
+		return DefaultResourceCacheMaxSizeMB << 20
+	}
+	return int64(c.MaxSizeMB) << 20
+}
+
+// GetMaxEntrySize returns the largest cached response body in bytes
+func (c *ResourceCacheConfig) GetMaxEntrySize() int64 {
+	if c == nil || c.MaxEntrySizeMB <= 0 {
+		return DefaultResourceCacheMaxEntrySizeMB << 20
+	}
+	return int64(c.MaxEntrySizeMB) << 20
+}
+
+// GetResourceTypes returns the Chrome resource types to cache
+func (c *ResourceCacheConfig) GetResourceTypes() []string {
+	if c == nil || len(c.ResourceTypes) == 0 {
+		return DefaultResourceCacheTypes
+	}
+	return c.ResourceTypes
+}
+
 // RSRenderConfig represents rendering timeout configuration for Render Service
 type RSRenderConfig struct {
 	MaxTimeout types.Duration `yaml:"max_timeout"` // maximum render timeout - cancels stuck renders
@@ -213,6 +261,11 @@ func (cfg *RSConfig) Validate() error {
 		return fmt.Errorf("chrome.render.max_timeout must be positive")
 	}
 
+	// Resource cache validation
+	if err := cfg.Chrome.ResourceCache.validate(); err != nil {
+		return err
+	}
+
 	// Log validation
 	validLogLevels := map[string]bool{
 		configtypes.LogLevelDebug:  true,
@@ -293,6 +346,47 @@ func (cfg *RSConfig) Validate() error {
 	return nil
 }
 
+// cacheableResourceTypes are the Chrome resource types the resource cache can store
+var cacheableResourceTypes = map[string]bool{
+	types.ResourceTypeStylesheet: true,
+	types.ResourceTypeImage:      true,
+	types.ResourceTypeMedia:      true,
+	types.ResourceTypeFont:       true,
+	types.ResourceTypeScript:     true,
+	types.ResourceTypeTextTrack:  true,
+	types.ResourceTypeXHR:        true,
+	types.ResourceTypeFetch:      true,
+	types.ResourceTypeManifest:   true,
+	types.ResourceTypeOther:      true,
+}
+
+// validate checks resource cache settings (nil-safe)
+func (c *ResourceCacheConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxSizeMB < 0 {
+		return fmt.Errorf("chrome.resource_cache.max_size_mb must be >= 0, got %d", c.MaxSizeMB)
+	}
+	if c.MaxEntrySizeMB < 0 {
+		return fmt.Errorf("chrome.resource_cache.max_entry_size_mb must be >= 0, got %d", c.MaxEntrySizeMB)
+	}
+	if c.GetMaxEntrySize() > c.GetMaxSize() {
+		return fmt.Errorf("chrome.resource_cache.max_entry_size_mb must not exceed max_size_mb")
+	}
+	for _, domain := range c.AllowedDomains {
+		if domain == "" || strings.ContainsAny(domain, "/:* ") {
+			return fmt.Errorf("invalid chrome.resource_cache.allowed_domains entry '%s' (must be a domain name)", domain)
+		}
+	}
+	for _, resourceType := range c.ResourceTypes {
+		if !cacheableResourceTypes[resourceType] {
+			return fmt.Errorf("invalid chrome.resource_cache.resource_types entry '%s'", resourceType)
+		}
+	}
+	return nil
+}
+
 // LoadRSConfig loads RS configuration from a file
 func LoadRSConfig(configPath string) (*RSConfig, error) {
 	data, err := os.ReadFile(configPath)
@@ -420,3 +420,39 @@ func TestGetConfigPath(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "config file does not exist")
 }
+
+func TestResourceCacheConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var cfg *ResourceCacheConfig
+		assert.False(t, cfg.IsEnabled())
+		assert.Equal(t, int64(256<<20), cfg.GetMaxSize())
+		assert.Equal(t, int64(5<<20), cfg.GetMaxEntrySize())
+		assert.Equal(t, DefaultResourceCacheTypes, cfg.GetResourceTypes())
+		assert.NoError(t, cfg.validate())
+	})
+
+	tests := []struct {
+		name     string
+		config   ResourceCacheConfig
+		errorMsg string
+	}{
+		{"valid", ResourceCacheConfig{Enabled: true, MaxSizeMB: 64, MaxEntrySizeMB: 2, AllowedDomains: []string{"cdn.example.com"}, ResourceTypes: []string{"Script", "Image"}}, ""},
+		{"negative max size", ResourceCacheConfig{MaxSizeMB: -1}, "max_size_mb must be >= 0"},
+		{"entry larger than cache", ResourceCacheConfig{MaxSizeMB: 4, MaxEntrySizeMB: 8}, "must not exceed max_size_mb"},
+		{"domain with scheme", ResourceCacheConfig{AllowedDomains: []string{"https://cdn.example.com"}}, "invalid chrome.resource_cache.allowed_domains"},
+		{"wildcard domain", ResourceCacheConfig{AllowedDomains: []string{"*.example.com"}}, "invalid chrome.resource_cache.allowed_domains"},
+		{"document type", ResourceCacheConfig{ResourceTypes: []string{"Document"}}, "invalid chrome.resource_cache.resource_types"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/edgecomet/engine/internal/common/schedule"
+	"github.com/edgecomet/engine/pkg/pattern"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -18,12 +20,141 @@ type CacheDaemonConfig struct {
 	HTTPApi       CacheDaemonHTTPApi       `yaml:"http_api"`       // HTTP API configuration
 	Logging       CacheDaemonLogging       `yaml:"logging"`        // Logging configuration
 	Metrics       MetricsConfig            `yaml:"metrics"`        // Metrics configuration
+	Schedules     CacheDaemonSchedules     `yaml:"schedules"`      // Time windows and cron-triggered recache jobs
+	Webhooks      CacheDaemonWebhooks      `yaml:"webhooks"`       // HMAC-authenticated origin change webhooks
+	EGClient      CacheDaemonEGClient      `yaml:"eg_client"`      // Credentials for recache calls to EG internal APIs
 }
 
 // CacheDaemonScheduler defines scheduler timing configuration
 type CacheDaemonScheduler struct {
-	TickInterval        types.Duration `yaml:"tick_interval"`         // How often scheduler runs (min: 100ms, e.g., 1s)
-	NormalCheckInterval types.Duration `yaml:"normal_check_interval"` // How often to check normal/autorecache queues (e.g., 60s)
+	TickInterval        types.Duration       `yaml:"tick_interval"`         // How often scheduler runs (min: 100ms, e.g., 1s)
+	NormalCheckInterval types.Duration       `yaml:"normal_check_interval"` // How often to check normal/autorecache queues (e.g., 60s)
+	FairShare           CacheDaemonFairShare `yaml:"fair_share"`            // Fair-share scheduling across hosts
+}
+
+// CacheDaemonFairShare defines deficit-round-robin scheduling across hosts.
+// When disabled, queues are drained in host iteration order.
+type CacheDaemonFairShare struct {
+	Enabled              bool                            `yaml:"enabled"`                // Enable fair-share scheduling
+	Quantum              int                             `yaml:"quantum"`                // Entries credited per round per unit of weight (default: 1)
+	DefaultWeight        int                             `yaml:"default_weight"`         // Weight for hosts without override (default: 1)
+	DefaultMaxConcurrent int                             `yaml:"default_max_concurrent"` // Max recaches per host in flight (0 = unlimited)
+	Hosts                []CacheDaemonHostSchedulePolicy `yaml:"hosts"`                  // Per-host overrides
+}
+
+// CacheDaemonHostSchedulePolicy overrides fair-share settings for a single host
+type CacheDaemonHostSchedulePolicy struct {
+	HostID        int  `yaml:"host_id"`        // Host ID from EG hosts configuration
+	Weight        int  `yaml:"weight"`         // Relative share of recache capacity (0 = use default_weight)
+	MaxConcurrent *int `yaml:"max_concurrent"` // Max recaches in flight (nil = use default_max_concurrent, 0 = unlimited)
+}
+
+// Default values for fair-share scheduling
+const (
+	DefaultFairShareQuantum = 1
+	DefaultFairShareWeight  = 1
+)
+
+// WeightFor returns the effective weight for a host
+func (f *CacheDaemonFairShare) WeightFor(hostID int) int {
+	for _, h := range f.Hosts {
+		if h.HostID == hostID && h.Weight > 0 {
+			return h.Weight
+		}
+	}
+	if f.DefaultWeight > 0 {
+		return f.DefaultWeight
+	}
+	return DefaultFairShareWeight
+}
+
+// MaxConcurrentFor returns the effective in-flight recache limit for a host (0 = unlimited)
+func (f *CacheDaemonFairShare) MaxConcurrentFor(hostID int) int {
+	for _, h := range f.Hosts {
+		if h.HostID == hostID && h.MaxConcurrent != nil {
+			return *h.MaxConcurrent
+		}
+	}
+	return f.DefaultMaxConcurrent
+}
+
+// CacheDaemonSchedules defines per-host processing windows and periodic recache jobs
+type CacheDaemonSchedules struct {
+	Timezone string                    `yaml:"timezone"` // IANA timezone for windows and cron (default: UTC)
+	Hosts    []CacheDaemonHostWindows  `yaml:"hosts"`    // Per-host allowed and blackout windows
+	Jobs     []CacheDaemonScheduledJob `yaml:"jobs"`     // Cron-triggered recache jobs
+}
+
+// CacheDaemonHostWindows gates queue processing for a host by time of day
+type CacheDaemonHostWindows struct {
+	HostID          int      `yaml:"host_id"`          // Host ID from EG hosts configuration
+	AllowedWindows  []string `yaml:"allowed_windows"`  // Queues processed only inside these windows (empty = always)
+	BlackoutWindows []string `yaml:"blackout_windows"` // Queues never processed inside these windows
+	Queues          []string `yaml:"queues"`           // Gated queues: high, normal, autorecache (default: normal, autorecache)
+}
+
+// CacheDaemonScheduledJob enqueues cached URLs matching patterns on a cron schedule
+type CacheDaemonScheduledJob struct {
+	Name         string   `yaml:"name"`          // Unique job name (used for logging and run deduplication)
+	HostID       int      `yaml:"host_id"`       // Host ID from EG hosts configuration
+	Cron         string   `yaml:"cron"`          // Five-field cron expression or macro (e.g., "0 3 * * *", "@daily")
+	URLPatterns  []string `yaml:"url_patterns"`  // URL path patterns (exact, wildcard, ~regexp); empty = all cached URLs
+	DimensionIDs []int    `yaml:"dimension_ids"` // Restrict to these dimensions (empty = all)
+	Priority     string   `yaml:"priority"`      // Target queue: "normal" (default) or "high"
+}
+
+// Default gated queues when CacheDaemonHostWindows.Queues is empty
+var DefaultScheduleGatedQueues = []string{"normal", "autorecache"}
+
+// Location returns the configured timezone (UTC when unset or invalid)
+func (s *CacheDaemonSchedules) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// CacheDaemonWebhooks configures the origin webhook receiver (POST /webhooks/origin).
+// Requests are authenticated with an HMAC-SHA256 signature instead of X-Internal-Auth.
+type CacheDaemonWebhooks struct {
+	Enabled            bool                    `yaml:"enabled"`             // Enable the webhook endpoint
+	Secrets            []string                `yaml:"secrets"`             // Shared HMAC secrets (any match is accepted, for rotation)
+	TimestampTolerance types.Duration          `yaml:"timestamp_tolerance"` // Max age/skew of X-Webhook-Timestamp (default: 5m)
+	DeletedTTL         types.Duration          `yaml:"deleted_ttl"`         // How long deleted URLs are served as 404/410 (default: 24h)
+	Tags               []CacheDaemonWebhookTag `yaml:"tags"`                // Tag to URL pattern mapping
+}
+
+// CacheDaemonWebhookTag maps a webhook tag to cached URLs of a host
+type CacheDaemonWebhookTag struct {
+	Name        string   `yaml:"name"`         // Tag name sent in webhook payloads (may repeat for several hosts)
+	HostID      int      `yaml:"host_id"`      // Host ID from EG hosts configuration
+	URLPatterns []string `yaml:"url_patterns"` // URL path patterns (exact, wildcard, ~regexp)
+}
+
+// Default values for webhooks
+const (
+	DefaultWebhookTimestampTolerance = 5 * time.Minute
+	DefaultWebhookDeletedTTL         = 24 * time.Hour
+)
+
+// GetTimestampTolerance returns the configured timestamp tolerance or the default
+func (w *CacheDaemonWebhooks) GetTimestampTolerance() time.Duration {
+	if w.TimestampTolerance > 0 {
+		return time.Duration(w.TimestampTolerance)
+	}
+	return DefaultWebhookTimestampTolerance
+}
+
+// GetDeletedTTL returns the configured deleted-content TTL or the default
+func (w *CacheDaemonWebhooks) GetDeletedTTL() time.Duration {
+	if w.DeletedTTL > 0 {
+		return time.Duration(w.DeletedTTL)
+	}
+	return DefaultWebhookDeletedTTL
 }
 
 // CacheDaemonInternalQueue defines internal queue configuration
@@ -41,10 +172,36 @@ type CacheDaemonRecache struct {
 
 // CacheDaemonHTTPApi defines HTTP API configuration
 type CacheDaemonHTTPApi struct {
-	Enabled             bool           `yaml:"enabled"`               // Enable/disable HTTP API
-	Listen              string         `yaml:"listen"`                // Listen address (e.g., ":10090")
-	RequestTimeout      types.Duration `yaml:"request_timeout"`       // Timeout for incoming API requests (e.g., 30s)
-	SchedulerControlAPI bool           `yaml:"scheduler_control_api"` // Enable scheduler pause/resume API (for testing)
+	Enabled             bool                 `yaml:"enabled"`               // Enable/disable HTTP API
+	Listen              string               `yaml:"listen"`                // Listen address (e.g., ":10090")
+	RequestTimeout      types.Duration       `yaml:"request_timeout"`       // Timeout for incoming API requests (e.g., 30s)
+	SchedulerControlAPI bool                 `yaml:"scheduler_control_api"` // Enable scheduler pause/resume API (for testing)
+	TLS                 InternalTLSConfig    `yaml:"tls"`                   // TLS/mTLS for the API listener
+	Credentials         []InternalCredential `yaml:"credentials"`           // Scoped API callers (EG internal.auth_key keeps full access)
+}
+
+// CacheDaemonEGClient configures how the daemon authenticates to EG internal APIs
+type CacheDaemonEGClient struct {
+	AuthKey string            `yaml:"auth_key"` // X-Internal-Auth sent to EGs (default: EG internal.auth_key)
+	TLS     InternalTLSConfig `yaml:"tls"`      // Client TLS for EG internal APIs (cert_file/key_file present a client certificate)
+}
+
+// validate checks EG client credentials
+func (c *CacheDaemonEGClient) validate() error {
+	if c.AuthKey != "" && len(c.AuthKey) < 16 {
+		return fmt.Errorf("eg_client.auth_key must be at least 16 characters")
+	}
+	if !c.TLS.Enabled {
+		return nil
+	}
+	// Client side: the certificate is optional, ca_file verifies EG server certificates
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("eg_client.tls.cert_file and eg_client.tls.key_file must be set together")
+	}
+	if c.TLS.ClientAuth != "" {
+		return fmt.Errorf("eg_client.tls.client_auth is only valid for listeners")
+	}
+	return nil
 }
 
 // CacheDaemonLogging defines logging configuration (type alias for consistency)
@@ -86,6 +243,18 @@ func (c *CacheDaemonConfig) Validate() error {
 		return fmt.Errorf("scheduler.normal_check_interval (%v) must be a multiple of tick_interval (%v)", normalCheckInterval, tickInterval)
 	}
 
+	if err := c.Scheduler.FairShare.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Schedules.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Webhooks.validate(); err != nil {
+		return err
+	}
+
 	// Validate max_size > 0
 	if c.InternalQueue.MaxSize <= 0 {
 		return fmt.Errorf("internal_queue.max_size must be > 0, got %d", c.InternalQueue.MaxSize)
@@ -124,6 +293,17 @@ func (c *CacheDaemonConfig) Validate() error {
 		if time.Duration(c.HTTPApi.RequestTimeout) <= 0 {
 			return fmt.Errorf("http_api.request_timeout must be > 0 when http_api is enabled")
 		}
+
+		if err := c.HTTPApi.TLS.Validate("http_api.tls"); err != nil {
+			return err
+		}
+		if err := ValidateInternalCredentials("http_api.credentials", c.HTTPApi.Credentials, &c.HTTPApi.TLS); err != nil {
+			return err
+		}
+	}
+
+	if err := c.EGClient.validate(); err != nil {
+		return err
 	}
 
 	// Validate metrics configuration
@@ -195,3 +375,141 @@ func (c *CacheDaemonConfig) Validate() error {
 
 	return nil
 }
+
+// validate checks fair-share weights, limits and host overrides
+func (f *CacheDaemonFairShare) validate() error {
+	if f.Quantum < 0 {
+		return fmt.Errorf("scheduler.fair_share.quantum must be >= 0, got %d", f.Quantum)
+	}
+	if f.DefaultWeight < 0 {
+		return fmt.Errorf("scheduler.fair_share.default_weight must be >= 0, got %d", f.DefaultWeight)
+	}
+	if f.DefaultMaxConcurrent < 0 {
+		return fmt.Errorf("scheduler.fair_share.default_max_concurrent must be >= 0, got %d", f.DefaultMaxConcurrent)
+	}
+
+	seen := make(map[int]bool, len(f.Hosts))
+	for i, h := range f.Hosts {
+		if h.HostID <= 0 {
+			return fmt.Errorf("scheduler.fair_share.hosts[%d].host_id must be > 0, got %d", i, h.HostID)
+		}
+		if seen[h.HostID] {
+			return fmt.Errorf("scheduler.fair_share.hosts[%d]: duplicate host_id %d", i, h.HostID)
+		}
+		seen[h.HostID] = true
+		if h.Weight < 0 {
+			return fmt.Errorf("scheduler.fair_share.hosts[%d].weight must be >= 0, got %d", i, h.Weight)
+		}
+		if h.MaxConcurrent != nil && *h.MaxConcurrent < 0 {
+			return fmt.Errorf("scheduler.fair_share.hosts[%d].max_concurrent must be >= 0, got %d", i, *h.MaxConcurrent)
+		}
+	}
+
+	return nil
+}
+
+// validate checks timezone, windows, cron expressions and patterns
+func (s *CacheDaemonSchedules) validate() error {
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("schedules.timezone: invalid timezone '%s': %w", s.Timezone, err)
+		}
+	}
+
+	validQueues := map[string]bool{"high": true, "normal": true, "autorecache": true}
+
+	seenHosts := make(map[int]bool, len(s.Hosts))
+	for i, h := range s.Hosts {
+		if h.HostID <= 0 {
+			return fmt.Errorf("schedules.hosts[%d].host_id must be > 0, got %d", i, h.HostID)
+		}
+		if seenHosts[h.HostID] {
+			return fmt.Errorf("schedules.hosts[%d]: duplicate host_id %d", i, h.HostID)
+		}
+		seenHosts[h.HostID] = true
+
+		if len(h.AllowedWindows) == 0 && len(h.BlackoutWindows) == 0 {
+			return fmt.Errorf("schedules.hosts[%d]: allowed_windows or blackout_windows must be specified", i)
+		}
+		if _, err := schedule.ParseWindows(h.AllowedWindows); err != nil {
+			return fmt.Errorf("schedules.hosts[%d].allowed_windows: %w", i, err)
+		}
+		if _, err := schedule.ParseWindows(h.BlackoutWindows); err != nil {
+			return fmt.Errorf("schedules.hosts[%d].blackout_windows: %w", i, err)
+		}
+		for _, q := range h.Queues {
+			if !validQueues[q] {
+				return fmt.Errorf("schedules.hosts[%d].queues: invalid queue '%s' (must be high, normal or autorecache)", i, q)
+			}
+		}
+	}
+
+	seenJobs := make(map[string]bool, len(s.Jobs))
+	for i, j := range s.Jobs {
+		if j.Name == "" {
+			return fmt.Errorf("schedules.jobs[%d].name must be specified", i)
+		}
+		if seenJobs[j.Name] {
+			return fmt.Errorf("schedules.jobs[%d]: duplicate name '%s'", i, j.Name)
+		}
+		seenJobs[j.Name] = true
+
+		if j.HostID <= 0 {
+			return fmt.Errorf("schedules.jobs[%d].host_id must be > 0, got %d", i, j.HostID)
+		}
+		if _, err := schedule.ParseCron(j.Cron); err != nil {
+			return fmt.Errorf("schedules.jobs[%d].cron: %w", i, err)
+		}
+		for _, p := range j.URLPatterns {
+			if _, err := pattern.Compile(p); err != nil {
+				return fmt.Errorf("schedules.jobs[%d].url_patterns: %w", i, err)
+			}
+		}
+		if j.Priority != "" && j.Priority != "normal" && j.Priority != "high" {
+			return fmt.Errorf("schedules.jobs[%d].priority must be 'normal' or 'high', got '%s'", i, j.Priority)
+		}
+	}
+
+	return nil
+}
+
+// validate checks secrets, durations and tag mappings
+func (w *CacheDaemonWebhooks) validate() error {
+	if !w.Enabled {
+		return nil
+	}
+
+	if len(w.Secrets) == 0 {
+		return fmt.Errorf("webhooks.secrets must be specified when webhooks are enabled")
+	}
+	for i, secret := range w.Secrets {
+		if len(secret) < 16 {
+			return fmt.Errorf("webhooks.secrets[%d] must be at least 16 characters", i)
+		}
+	}
+	if w.TimestampTolerance < 0 {
+		return fmt.Errorf("webhooks.timestamp_tolerance must be >= 0, got %v", time.Duration(w.TimestampTolerance))
+	}
+	if w.DeletedTTL < 0 {
+		return fmt.Errorf("webhooks.deleted_ttl must be >= 0, got %v", time.Duration(w.DeletedTTL))
+	}
+
+	for i, tag := range w.Tags {
+		if tag.Name == "" {
+			return fmt.Errorf("webhooks.tags[%d].name must be specified", i)
+		}
+		if tag.HostID <= 0 {
+			return fmt.Errorf("webhooks.tags[%d].host_id must be > 0, got %d", i, tag.HostID)
+		}
+		if len(tag.URLPatterns) == 0 {
+			return fmt.Errorf("webhooks.tags[%d].url_patterns must be non-empty", i)
+		}
+		for _, p := range tag.URLPatterns {
+			if _, err := pattern.Compile(p); err != nil {
+				return fmt.Errorf("webhooks.tags[%d].url_patterns: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -347,3 +347,340 @@ func TestCacheDaemonConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheDaemonConfig_ValidateFairShare(t *testing.T) {
+	newConfig := func(fairShare CacheDaemonFairShare) *CacheDaemonConfig {
+		return &CacheDaemonConfig{
+			EgConfig: "/path/to/edge-gateway.yaml",
+			DaemonID: "daemon-1",
+			Redis:    RedisConfig{Addr: "localhost:6379"},
+			Scheduler: CacheDaemonScheduler{
+				TickInterval:        types.Duration(1 * time.Second),
+				NormalCheckInterval: types.Duration(60 * time.Second),
+				FairShare:           fairShare,
+			},
+			InternalQueue: CacheDaemonInternalQueue{MaxSize: 1000, MaxRetries: 3},
+			Recache: CacheDaemonRecache{
+				RSCapacityReserved: 0.30,
+				TimeoutPerURL:      types.Duration(60 * time.Second),
+			},
+		}
+	}
+	negative := -1
+
+	tests := []struct {
+		name      string
+		fairShare CacheDaemonFairShare
+		errMsg    string
+	}{
+		{
+			name: "valid overrides",
+			fairShare: CacheDaemonFairShare{
+				Enabled: true,
+				Quantum: 5,
+				Hosts:   []CacheDaemonHostSchedulePolicy{{HostID: 1, Weight: 3}},
+			},
+		},
+		{
+			name:      "negative quantum",
+			fairShare: CacheDaemonFairShare{Quantum: -1},
+			errMsg:    "fair_share.quantum must be >= 0",
+		},
+		{
+			name:      "negative default_max_concurrent",
+			fairShare: CacheDaemonFairShare{DefaultMaxConcurrent: -1},
+			errMsg:    "fair_share.default_max_concurrent must be >= 0",
+		},
+		{
+			name:      "missing host_id",
+			fairShare: CacheDaemonFairShare{Hosts: []CacheDaemonHostSchedulePolicy{{Weight: 2}}},
+			errMsg:    "hosts[0].host_id must be > 0",
+		},
+		{
+			name: "duplicate host_id",
+			fairShare: CacheDaemonFairShare{Hosts: []CacheDaemonHostSchedulePolicy{
+				{HostID: 1, Weight: 2},
+				{HostID: 1, Weight: 3},
+			}},
+			errMsg: "duplicate host_id 1",
+		},
+		{
+			name:      "negative host max_concurrent",
+			fairShare: CacheDaemonFairShare{Hosts: []CacheDaemonHostSchedulePolicy{{HostID: 1, MaxConcurrent: &negative}}},
+			errMsg:    "hosts[0].max_concurrent must be >= 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newConfig(tt.fairShare).Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCacheDaemonConfig_ValidateSchedules(t *testing.T) {
+	newConfig := func(schedules CacheDaemonSchedules) *CacheDaemonConfig {
+		return &CacheDaemonConfig{
+			EgConfig: "/path/to/edge-gateway.yaml",
+			DaemonID: "daemon-1",
+			Redis:    RedisConfig{Addr: "localhost:6379"},
+			Scheduler: CacheDaemonScheduler{
+				TickInterval:        types.Duration(1 * time.Second),
+				NormalCheckInterval: types.Duration(60 * time.Second),
+			},
+			InternalQueue: CacheDaemonInternalQueue{MaxSize: 1000, MaxRetries: 3},
+			Recache: CacheDaemonRecache{
+				RSCapacityReserved: 0.30,
+				TimeoutPerURL:      types.Duration(60 * time.Second),
+			},
+			Schedules: schedules,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		schedules CacheDaemonSchedules
+		errMsg    string
+	}{
+		{
+			name: "valid windows and jobs",
+			schedules: CacheDaemonSchedules{
+				Timezone: "Europe/Berlin",
+				Hosts: []CacheDaemonHostWindows{
+					{HostID: 1, AllowedWindows: []string{"01:00-06:00"}, Queues: []string{"normal"}},
+				},
+				Jobs: []CacheDaemonScheduledJob{
+					{Name: "nightly", HostID: 1, Cron: "0 3 * * *", URLPatterns: []string{"/category/*"}},
+				},
+			},
+		},
+		{
+			name:      "invalid timezone",
+			schedules: CacheDaemonSchedules{Timezone: "Mars/Olympus"},
+			errMsg:    "schedules.timezone",
+		},
+		{
+			name:      "host without windows",
+			schedules: CacheDaemonSchedules{Hosts: []CacheDaemonHostWindows{{HostID: 1}}},
+			errMsg:    "allowed_windows or blackout_windows must be specified",
+		},
+		{
+			name:      "invalid window",
+			schedules: CacheDaemonSchedules{Hosts: []CacheDaemonHostWindows{{HostID: 1, BlackoutWindows: []string{"noon"}}}},
+			errMsg:    "blackout_windows",
+		},
+		{
+			name: "invalid gated queue",
+			schedules: CacheDaemonSchedules{Hosts: []CacheDaemonHostWindows{
+				{HostID: 1, AllowedWindows: []string{"01:00-02:00"}, Queues: []string{"urgent"}},
+			}},
+			errMsg: "invalid queue 'urgent'",
+		},
+		{
+			name:      "job without name",
+			schedules: CacheDaemonSchedules{Jobs: []CacheDaemonScheduledJob{{HostID: 1, Cron: "@daily"}}},
+			errMsg:    "jobs[0].name must be specified",
+		},
+		{
+			name: "duplicate job name",
+			schedules: CacheDaemonSchedules{Jobs: []CacheDaemonScheduledJob{
+				{Name: "a", HostID: 1, Cron: "@daily"},
+				{Name: "a", HostID: 1, Cron: "@hourly"},
+			}},
+			errMsg: "duplicate name 'a'",
+		},
+		{
+			name:      "invalid cron",
+			schedules: CacheDaemonSchedules{Jobs: []CacheDaemonScheduledJob{{Name: "a", HostID: 1, Cron: "0 25 * * *"}}},
+			errMsg:    "jobs[0].cron",
+		},
+		{
+			name:      "invalid priority",
+			schedules: CacheDaemonSchedules{Jobs: []CacheDaemonScheduledJob{{Name: "a", HostID: 1, Cron: "@daily", Priority: "autorecache"}}},
+			errMsg:    "priority must be 'normal' or 'high'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newConfig(tt.schedules).Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCacheDaemonConfig_ValidateWebhooks(t *testing.T) {
+	newConfig := func(webhooks CacheDaemonWebhooks) *CacheDaemonConfig {
+		return &CacheDaemonConfig{
+			EgConfig: "/path/to/edge-gateway.yaml",
+			DaemonID: "daemon-1",
+			Redis:    RedisConfig{Addr: "localhost:6379"},
+			Scheduler: CacheDaemonScheduler{
+				TickInterval:        types.Duration(1 * time.Second),
+				NormalCheckInterval: types.Duration(60 * time.Second),
+			},
+			InternalQueue: CacheDaemonInternalQueue{MaxSize: 1000, MaxRetries: 3},
+			Recache: CacheDaemonRecache{
+				RSCapacityReserved: 0.30,
+				TimeoutPerURL:      types.Duration(60 * time.Second),
+			},
+			Webhooks: webhooks,
+		}
+	}
+	secret := "0123456789abcdef0123"
+
+	tests := []struct {
+		name     string
+		webhooks CacheDaemonWebhooks
+		errMsg   string
+	}{
+		{name: "disabled without secrets", webhooks: CacheDaemonWebhooks{}},
+		{
+			name: "valid tags",
+			webhooks: CacheDaemonWebhooks{
+				Enabled: true,
+				Secrets: []string{secret},
+				Tags:    []CacheDaemonWebhookTag{{Name: "shoes", HostID: 1, URLPatterns: []string{"/shoes/*"}}},
+			},
+		},
+		{
+			name:     "enabled without secrets",
+			webhooks: CacheDaemonWebhooks{Enabled: true},
+			errMsg:   "webhooks.secrets must be specified",
+		},
+		{
+			name:     "short secret",
+			webhooks: CacheDaemonWebhooks{Enabled: true, Secrets: []string{"short"}},
+			errMsg:   "at least 16 characters",
+		},
+		{
+			name: "tag without patterns",
+			webhooks: CacheDaemonWebhooks{
+				Enabled: true,
+				Secrets: []string{secret},
+				Tags:    []CacheDaemonWebhookTag{{Name: "shoes", HostID: 1}},
+			},
+			errMsg: "tags[0].url_patterns must be non-empty",
+		},
+		{
+			name: "invalid tag pattern",
+			webhooks: CacheDaemonWebhooks{
+				Enabled: true,
+				Secrets: []string{secret},
+				Tags:    []CacheDaemonWebhookTag{{Name: "shoes", HostID: 1, URLPatterns: []string{"~[invalid"}}},
+			},
+			errMsg: "tags[0].url_patterns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newConfig(tt.webhooks).Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCacheDaemonConfig_ValidateAPICredentials(t *testing.T) {
+	newConfig := func(api CacheDaemonHTTPApi, egClient CacheDaemonEGClient) *CacheDaemonConfig {
+		api.Enabled = true
+		api.Listen = ":10090"
+		api.RequestTimeout = types.Duration(30 * time.Second)
+		return &CacheDaemonConfig{
+			EgConfig: "/path/to/edge-gateway.yaml",
+			DaemonID: "daemon-1",
+			Redis:    RedisConfig{Addr: "localhost:6379"},
+			Scheduler: CacheDaemonScheduler{
+				TickInterval:        types.Duration(1 * time.Second),
+				NormalCheckInterval: types.Duration(60 * time.Second),
+			},
+			InternalQueue: CacheDaemonInternalQueue{MaxSize: 1000, MaxRetries: 3},
+			Recache: CacheDaemonRecache{
+				RSCapacityReserved: 0.30,
+				TimeoutPerURL:      types.Duration(60 * time.Second),
+			},
+			HTTPApi:  api,
+			EGClient: egClient,
+		}
+	}
+	key := "0123456789abcdef0123"
+	mtls := InternalTLSConfig{Enabled: true, CertFile: "daemon.pem", KeyFile: "daemon-key.pem", CAFile: "ca.pem"}
+
+	tests := []struct {
+		name     string
+		api      CacheDaemonHTTPApi
+		egClient CacheDaemonEGClient
+		errMsg   string
+	}{
+		{name: "no credentials"},
+		{
+			name: "scoped key and certificate",
+			api: CacheDaemonHTTPApi{
+				TLS: mtls,
+				Credentials: []InternalCredential{
+					{Name: "cms", Key: key, Scopes: []string{InternalScopeInvalidate}},
+					{Name: "ops", CertCN: "ops.internal", Scopes: []string{InternalScopeRead, InternalScopeScheduler}},
+				},
+			},
+			egClient: CacheDaemonEGClient{AuthKey: key, TLS: InternalTLSConfig{Enabled: true, CAFile: "ca.pem"}},
+		},
+		{
+			name:   "credential without name",
+			api:    CacheDaemonHTTPApi{Credentials: []InternalCredential{{Key: key, Scopes: []string{InternalScopeRead}}}},
+			errMsg: "credentials[0].name is required",
+		},
+		{
+			name:   "short key",
+			api:    CacheDaemonHTTPApi{Credentials: []InternalCredential{{Name: "cms", Key: "short", Scopes: []string{InternalScopeRead}}}},
+			errMsg: "at least 16 characters",
+		},
+		{
+			name:   "unknown scope",
+			api:    CacheDaemonHTTPApi{Credentials: []InternalCredential{{Name: "cms", Key: key, Scopes: []string{"admin"}}}},
+			errMsg: `unknown scope "admin"`,
+		},
+		{
+			name:   "cert_cn without mTLS",
+			api:    CacheDaemonHTTPApi{Credentials: []InternalCredential{{Name: "ops", CertCN: "ops", Scopes: []string{InternalScopeRead}}}},
+			errMsg: "cert_cn requires TLS with ca_file",
+		},
+		{
+			name:   "TLS without key file",
+			api:    CacheDaemonHTTPApi{TLS: InternalTLSConfig{Enabled: true, CertFile: "daemon.pem"}},
+			errMsg: "http_api.tls.key_file is required",
+		},
+		{
+			name:     "eg_client certificate without key",
+			egClient: CacheDaemonEGClient{TLS: InternalTLSConfig{Enabled: true, CertFile: "daemon.pem"}},
+			errMsg:   "must be set together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newConfig(tt.api, tt.egClient).Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package configtypes
 
 import (
+	"fmt"
+	"path/filepath"
+	"time"
+
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -41,14 +45,159 @@ type EgConfig struct {
 	Headers            *types.HeadersConfig        `yaml:"headers,omitempty"`
 	ClientIP           *types.ClientIPConfig       `yaml:"client_ip,omitempty"`
 	EventLogging       *EventLoggingConfig         `yaml:"event_logging,omitempty"`
+	ChangeDetection    *ChangeDetectionConfig      `yaml:"change_detection,omitempty"`
+	Popularity         *PopularityConfig           `yaml:"popularity,omitempty"`
+	HotCache           *HotCacheConfig             `yaml:"hot_cache,omitempty"`
+	DuplicateDetection *DuplicateDetectionConfig   `yaml:"duplicate_detection,omitempty"`
+	SEOHistory         *SEOHistoryConfig           `yaml:"seo_history,omitempty"`
+	LinkGraph          *LinkGraphConfig            `yaml:"link_graph,omitempty"`
 	EgID               string                      `yaml:"eg_id,omitempty"`
 	Internal           InternalConfig              `yaml:"internal"`
 }
 
 // InternalConfig configures internal server for inter-EG and daemon communication
 type InternalConfig struct {
-	Listen  string `yaml:"listen"`
-	AuthKey string `yaml:"auth_key"`
+	Listen      string               `yaml:"listen"`
+	AuthKey     string               `yaml:"auth_key"`              // Legacy shared key, grants all scopes
+	ClientKey   string               `yaml:"client_key,omitempty"`  // X-Internal-Auth sent to peer EGs (default: auth_key)
+	TLS         InternalTLSConfig    `yaml:"tls,omitempty"`         // TLS/mTLS for the internal listener and peer client
+	Credentials []InternalCredential `yaml:"credentials,omitempty"` // Scoped caller credentials
+}
+
+// GetClientKey returns the key sent to peer EGs
+func (c *InternalConfig) GetClientKey() string {
+	if c.ClientKey != "" {
+		return c.ClientKey
+	}
+	return c.AuthKey
+}
+
+// InternalTLSConfig enables TLS on internal APIs. Setting ca_file enables mutual TLS:
+// the listener verifies client certificates and outgoing calls verify servers against it.
+type InternalTLSConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	CertFile   string `yaml:"cert_file"`   // Server certificate, also presented as client certificate
+	KeyFile    string `yaml:"key_file"`    // Private key for cert_file
+	CAFile     string `yaml:"ca_file"`     // CA bundle for peer verification (enables mTLS)
+	ClientAuth string `yaml:"client_auth"` // "require" (default) or "optional" when ca_file is set
+	ServerName string `yaml:"server_name"` // Name verified in server certificates on outgoing calls (default: address host)
+}
+
+// ResolvePaths makes relative certificate paths relative to baseDir
+func (c *InternalTLSConfig) ResolvePaths(baseDir string) {
+	for _, path := range []*string{&c.CertFile, &c.KeyFile, &c.CAFile} {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(baseDir, *path)
+		}
+	}
+}
+
+// Client certificate policies for InternalTLSConfig.ClientAuth
+const (
+	InternalClientAuthRequire  = "require"
+	InternalClientAuthOptional = "optional"
+)
+
+// InternalCredential is a caller identity for internal APIs, matched by key or client certificate
+type InternalCredential struct {
+	Name   string   `yaml:"name"`    // Identity name for logs
+	Key    string   `yaml:"key"`     // X-Internal-Auth value
+	CertCN string   `yaml:"cert_cn"` // Client certificate common name or DNS SAN (requires tls.ca_file)
+	Scopes []string `yaml:"scopes"`  // Granted scopes, "*" for all
+}
+
+// Scopes granted to internal API callers
+const (
+	InternalScopeAll        = "*"
+	InternalScopePeer       = "peer"       // Inter-EG cache pull, push and status
+	InternalScopeRecache    = "recache"    // Recache requests (EG internal API and daemon API)
+	InternalScopeInvalidate = "invalidate" // Daemon cache invalidation
+	InternalScopeRead       = "read"       // Daemon status and cache listings
+	InternalScopeScheduler  = "scheduler"  // Daemon scheduler pause/resume
+	InternalScopeDebug      = "debug"      // HAR debug endpoints
+)
+
+var validInternalScopes = map[string]bool{
+	InternalScopeAll:        true,
+	InternalScopePeer:       true,
+	InternalScopeRecache:    true,
+	InternalScopeInvalidate: true,
+	InternalScopeRead:       true,
+	InternalScopeScheduler:  true,
+	InternalScopeDebug:      true,
+}
+
+// IsValidInternalScope reports whether scope is a known scope name
+func IsValidInternalScope(scope string) bool {
+	return validInternalScopes[scope]
+}
+
+// Validate checks TLS settings; prefix is the YAML path used in messages (e.g. "internal.tls").
+// File existence is checked by callers that know the config directory.
+func (c *InternalTLSConfig) Validate(prefix string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.CertFile == "" {
+		return fmt.Errorf("%s.cert_file is required when TLS is enabled", prefix)
+	}
+	if c.KeyFile == "" {
+		return fmt.Errorf("%s.key_file is required when TLS is enabled", prefix)
+	}
+	switch c.ClientAuth {
+	case "", InternalClientAuthRequire, InternalClientAuthOptional:
+	default:
+		return fmt.Errorf("%s.client_auth must be %q or %q, got %q", prefix, InternalClientAuthRequire, InternalClientAuthOptional, c.ClientAuth)
+	}
+	if c.ClientAuth != "" && c.CAFile == "" {
+		return fmt.Errorf("%s.client_auth requires %s.ca_file", prefix, prefix)
+	}
+	return nil
+}
+
+// ValidateInternalCredentials checks scoped credentials; prefix is the YAML path used in messages.
+// cert_cn credentials require mutual TLS on the listener described by tlsCfg.
+func ValidateInternalCredentials(prefix string, credentials []InternalCredential, tlsCfg *InternalTLSConfig) error {
+	names := make(map[string]bool, len(credentials))
+	keys := make(map[string]bool, len(credentials))
+
+	for i, cred := range credentials {
+		field := fmt.Sprintf("%s[%d]", prefix, i)
+		if cred.Name == "" {
+			return fmt.Errorf("%s.name is required", field)
+		}
+		if names[cred.Name] {
+			return fmt.Errorf("%s.name %q is duplicated", field, cred.Name)
+		}
+		names[cred.Name] = true
+
+		if cred.Key == "" && cred.CertCN == "" {
+			return fmt.Errorf("%s (%s) requires key or cert_cn", field, cred.Name)
+		}
+		if cred.Key != "" {
+			if len(cred.Key) < 16 {
+				return fmt.Errorf("%s (%s) key must be at least 16 characters", field, cred.Name)
+			}
+			if keys[cred.Key] {
+				return fmt.Errorf("%s (%s) key is shared with another credential", field, cred.Name)
+			}
+			keys[cred.Key] = true
+		}
+		if cred.CertCN != "" && (tlsCfg == nil || !tlsCfg.Enabled || tlsCfg.CAFile == "") {
+			return fmt.Errorf("%s (%s) cert_cn requires TLS with ca_file (mutual TLS)", field, cred.Name)
+		}
+
+		if len(cred.Scopes) == 0 {
+			return fmt.Errorf("%s (%s) requires at least one scope", field, cred.Name)
+		}
+		for _, scope := range cred.Scopes {
+			if !IsValidInternalScope(scope) {
+				return fmt.Errorf("%s (%s) has unknown scope %q", field, cred.Name, scope)
+			}
+		}
+	}
+
+	return nil
 }
 
 // TLSConfig holds TLS/HTTPS configuration for the external server
@@ -89,6 +238,8 @@ type GlobalRenderConfig struct {
 	BlockedResourceTypes []string                `yaml:"blocked_resource_types,omitempty"`
 	BlockedPatterns      []string                `yaml:"blocked_patterns,omitempty"`
 	StripScripts         *bool                   `yaml:"strip_scripts,omitempty"`
+	Transforms           []types.HTMLTransform   `yaml:"transforms,omitempty"` // HTML post-processing applied to rendered pages
+	Markdown             *types.MarkdownConfig   `yaml:"markdown,omitempty"`   // Markdown output variant
 }
 
 type GlobalBypassConfig struct {
@@ -99,7 +250,151 @@ type GlobalBypassConfig struct {
 }
 
 type EdgeRegistryConfig struct {
-	SelectionStrategy string `yaml:"selection_strategy"` // Default: "least_loaded"
+	SelectionStrategy string          `yaml:"selection_strategy"` // Default: "least_loaded"
+	Health            *RSHealthConfig `yaml:"health,omitempty"`   // Render service health tracking and circuit breaker
+	Retry             *RSRetryConfig  `yaml:"retry,omitempty"`    // Retry on another render service after infrastructure failures
+}
+
+// Render service health defaults
+const (
+	DefaultRSHealthHalfLife           = 5 * time.Minute
+	DefaultRSHealthMinRequests        = 20
+	DefaultRSHealthMinSuccessRate     = 0.5
+	DefaultRSHealthMaxHardTimeoutRate = 0.3
+	DefaultRSHealthOpenDuration       = 30 * time.Second
+	DefaultRSHealthMaxOpenDuration    = 5 * time.Minute
+)
+
+// RSHealthConfig configures render service health tracking. Every EG records render
+// outcomes per service in Redis; a service whose recent success rate or hard-timeout
+// rate crosses a threshold is ejected from selection until its circuit closes again.
+type RSHealthConfig struct {
+	Enabled            bool           `yaml:"enabled"`
+	HalfLife           types.Duration `yaml:"half_life,omitempty"`             // Time for recorded outcomes to lose half their weight, default 5m
+	MinRequests        int            `yaml:"min_requests,omitempty"`          // Outcomes needed before a circuit can open, default 20
+	MinSuccessRate     float64        `yaml:"min_success_rate,omitempty"`      // Open the circuit below this success rate, default 0.5
+	MaxHardTimeoutRate float64        `yaml:"max_hard_timeout_rate,omitempty"` // Open the circuit above this hard-timeout rate, default 0.3
+	OpenDuration       types.Duration `yaml:"open_duration,omitempty"`         // Ejection time after the first trip, default 30s
+	MaxOpenDuration    types.Duration `yaml:"max_open_duration,omitempty"`     // Upper bound as ejection time doubles on repeated trips, default 5m
+}
+
+// IsEnabled reports whether health tracking is enabled (nil config = disabled)
+func (c *RSHealthConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetHalfLife returns the outcome half-life or the default
+func (c *RSHealthConfig) GetHalfLife() time.Duration {
+	if c == nil || c.HalfLife == 0 {
+		return DefaultRSHealthHalfLife
+	}
+	return time.Duration(c.HalfLife)
+}
+
+// GetMinRequests returns the outcomes needed before a circuit can open or the default
+func (c *RSHealthConfig) GetMinRequests() int {
+	if c == nil || c.MinRequests == 0 {
+		return DefaultRSHealthMinRequests
+	}
+	return c.MinRequests
+}
+
+// GetMinSuccessRate returns the success rate threshold or the default
+func (c *RSHealthConfig) GetMinSuccessRate() float64 {
+	if c == nil || c.MinSuccessRate == 0 {
+		return DefaultRSHealthMinSuccessRate
+	}
+	return c.MinSuccessRate
+}
+
+// GetMaxHardTimeoutRate returns the hard-timeout rate threshold or the default
+func (c *RSHealthConfig) GetMaxHardTimeoutRate() float64 {
+	if c == nil || c.MaxHardTimeoutRate == 0 {
+		return DefaultRSHealthMaxHardTimeoutRate
+	}
+	return c.MaxHardTimeoutRate
+}
+
+// GetOpenDuration returns the ejection time after the first trip or the default
+func (c *RSHealthConfig) GetOpenDuration() time.Duration {
+	if c == nil || c.OpenDuration == 0 {
+		return DefaultRSHealthOpenDuration
+	}
+	return time.Duration(c.OpenDuration)
+}
+
+// GetMaxOpenDuration returns the ejection time upper bound or the default
+func (c *RSHealthConfig) GetMaxOpenDuration() time.Duration {
+	if c == nil || c.MaxOpenDuration == 0 {
+		return DefaultRSHealthMaxOpenDuration
+	}
+	return time.Duration(c.MaxOpenDuration)
+}
+
+// Render retry defaults
+const (
+	DefaultRSRetryMaxAttempts  = 2
+	DefaultRSRetryMinRemaining = 3 * time.Second
+)
+
+// DefaultRSRetryErrorTypes are the render failures retried by default: the service or its
+// Chrome pool failed, not the page
+var DefaultRSRetryErrorTypes = []string{
+	types.ErrorTypeChromeCrash,
+	types.ErrorTypePoolUnavailable,
+	types.ErrorTypeHardTimeout,
+	types.ErrorTypeConnectionFailed,
+}
+
+// RSRetryConfig configures render retries. A render failing with one of ErrorTypes is
+// retried on a different render service while enough of the request deadline remains.
+type RSRetryConfig struct {
+	Enabled      bool           `yaml:"enabled"`
+	MaxAttempts  int            `yaml:"max_attempts,omitempty"`  // Render attempts per request including the first, default 2
+	ErrorTypes   []string       `yaml:"error_types,omitempty"`   // Retried error types, default chrome_crash, pool_unavailable, hard_timeout, connection_failed
+	MinRemaining types.Duration `yaml:"min_remaining,omitempty"` // Retry only if at least this much of the request timeout is left, default 3s
+}
+
+// IsEnabled reports whether render retries are enabled (nil config = disabled)
+func (c *RSRetryConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetMaxAttempts returns the render attempts per request or the default
+func (c *RSRetryConfig) GetMaxAttempts() int {
+	if c == nil || c.MaxAttempts == 0 {
+		return DefaultRSRetryMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// GetErrorTypes returns the retried error types or the defaults
+func (c *RSRetryConfig) GetErrorTypes() []string {
+	if c == nil || len(c.ErrorTypes) == 0 {
+		return DefaultRSRetryErrorTypes
+	}
+	return c.ErrorTypes
+}
+
+// GetMinRemaining returns the request time needed for a retry or the default
+func (c *RSRetryConfig) GetMinRemaining() time.Duration {
+	if c == nil || c.MinRemaining == 0 {
+		return DefaultRSRetryMinRemaining
+	}
+	return time.Duration(c.MinRemaining)
+}
+
+// ShouldRetry reports whether a render failing with errorType is retried
+func (c *RSRetryConfig) ShouldRetry(errorType string) bool {
+	if !c.IsEnabled() || errorType == "" {
+		return false
+	}
+	for _, t := range c.GetErrorTypes() {
+		if t == errorType {
+			return true
+		}
+	}
+	return false
 }
 
 type LogConfig struct {
@@ -154,6 +449,284 @@ type EventLoggingConfig struct {
 	File EventFileConfig `yaml:"file"`
 }
 
+// DefaultChangeSimilarityThreshold requires every MinHash slot to match, so only
+// changes outside the visible text (scripts, styles, attributes) count as unchanged
+const DefaultChangeSimilarityThreshold = 1.0
+
+// ChangeDetectionConfig configures content-change detection on recache.
+// When a re-render matches the cached version, the existing entry's TTL is
+// extended instead of writing a new file.
+type ChangeDetectionConfig struct {
+	Enabled             *bool   `yaml:"enabled,omitempty"`              // Default: true
+	SimilarityThreshold float64 `yaml:"similarity_threshold,omitempty"` // MinHash similarity (0-1] treated as unchanged, default 1.0
+}
+
+// IsEnabled reports whether change detection is enabled (nil config = enabled)
+func (c *ChangeDetectionConfig) IsEnabled() bool {
+	return c == nil || c.Enabled == nil || *c.Enabled
+}
+
+// Threshold returns the configured similarity threshold or the default
+func (c *ChangeDetectionConfig) Threshold() float64 {
+	if c == nil || c.SimilarityThreshold == 0 {
+		return DefaultChangeSimilarityThreshold
+	}
+	return c.SimilarityThreshold
+}
+
+// DuplicateDetectionConfig configures the near-duplicate content index. When enabled,
+// page MinHash signatures are added to a per-host LSH index in Redis on cache save.
+type DuplicateDetectionConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// IsEnabled reports whether duplicate detection is enabled (nil config = disabled)
+func (c *DuplicateDetectionConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// SEO history defaults
+const (
+	DefaultSEOHistoryMaxVersions = 10
+	DefaultSEOHistoryRetention   = 30 * 24 * time.Hour
+)
+
+// SEOHistoryConfig configures the per-URL history of PageSEO snapshots. When enabled,
+// every render whose PageSEO differs from the latest version adds a version in Redis.
+type SEOHistoryConfig struct {
+	Enabled     bool           `yaml:"enabled"`
+	MaxVersions int            `yaml:"max_versions,omitempty"` // Versions kept per cache entry, default 10
+	Retention   types.Duration `yaml:"retention,omitempty"`    // History kept after the last render, default 30d
+}
+
+// IsEnabled reports whether SEO history is enabled (nil config = disabled)
+func (c *SEOHistoryConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetMaxVersions returns the number of versions kept per cache entry or the default
+func (c *SEOHistoryConfig) GetMaxVersions() int {
+	if c == nil || c.MaxVersions <= 0 {
+		return DefaultSEOHistoryMaxVersions
+	}
+	return c.MaxVersions
+}
+
+// GetRetention returns how long history is kept after the last render or the default
+func (c *SEOHistoryConfig) GetRetention() time.Duration {
+	if c == nil || c.Retention <= 0 {
+		return DefaultSEOHistoryRetention
+	}
+	return time.Duration(c.Retention)
+}
+
+// Link graph defaults
+const (
+	DefaultLinkGraphRetention   = 7 * 24 * time.Hour
+	DefaultLinkDiscoverMaxDepth = 2
+)
+
+// LinkGraphConfig configures the per-host internal link graph. When enabled, the followed
+// internal links of every rendered 200 page are stored in Redis for the Cache Daemon link
+// reports. With discover enabled, link targets seen for the first time are added to the
+// normal priority recache queue while within max_depth links of a page requested by traffic.
+type LinkGraphConfig struct {
+	Enabled   bool                 `yaml:"enabled"`
+	Retention types.Duration       `yaml:"retention,omitempty"` // Links kept after the last render of the linking page, default 7d
+	Discover  *LinkDiscoveryConfig `yaml:"discover,omitempty"`
+}
+
+// LinkDiscoveryConfig configures pre-rendering of newly discovered internal URLs
+type LinkDiscoveryConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	MaxDepth int  `yaml:"max_depth,omitempty"` // Link hops from a page requested by traffic, default 2
+}
+
+// IsEnabled reports whether the link graph is enabled (nil config = disabled)
+func (c *LinkGraphConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetRetention returns how long links are kept after the last render or the default
+func (c *LinkGraphConfig) GetRetention() time.Duration {
+	if c == nil || c.Retention <= 0 {
+		return DefaultLinkGraphRetention
+	}
+	return time.Duration(c.Retention)
+}
+
+// DiscoverMaxDepth returns the discovery depth, 0 when discovery is disabled
+func (c *LinkGraphConfig) DiscoverMaxDepth() int {
+	if c == nil || c.Discover == nil || !c.Discover.Enabled {
+		return 0
+	}
+	if c.Discover.MaxDepth <= 0 {
+		return DefaultLinkDiscoverMaxDepth
+	}
+	return c.Discover.MaxDepth
+}
+
+// Popularity defaults
+const (
+	DefaultPopularityHalfLife    = 24 * time.Hour
+	DefaultPopularityHotScore    = 100.0
+	DefaultPopularityHotFactor   = 0.25
+	DefaultPopularityColdFactor  = 4.0
+	DefaultPopularityMinTTL      = 5 * time.Minute
+	DefaultPopularityMaxTTL      = 30 * 24 * time.Hour
+	DefaultPopularityMinInterval = 30 * time.Minute
+	DefaultPopularityMaxInterval = 24 * time.Hour
+)
+
+// PopularityConfig configures popularity-based adaptive TTL and autorecache interval.
+// Every request adds to an exponentially decayed per-URL hit score in Redis; the
+// resolved TTL and bothit_recache interval are scaled by a factor between
+// cold_factor (score 0) and hot_factor (score >= hot_score), then clamped to bounds.
+type PopularityConfig struct {
+	Enabled            bool           `yaml:"enabled"`
+	HalfLife           types.Duration `yaml:"half_life,omitempty"`            // Score half-life, default 24h
+	HotScore           float64        `yaml:"hot_score,omitempty"`            // Decayed score treated as fully hot, default 100
+	HotFactor          float64        `yaml:"hot_factor,omitempty"`           // Multiplier for hot pages, default 0.25
+	ColdFactor         float64        `yaml:"cold_factor,omitempty"`          // Multiplier for unvisited pages, default 4
+	MinTTL             types.Duration `yaml:"min_ttl,omitempty"`              // Lower TTL bound, default 5m
+	MaxTTL             types.Duration `yaml:"max_ttl,omitempty"`              // Upper TTL bound, default 30d
+	MinRecacheInterval types.Duration `yaml:"min_recache_interval,omitempty"` // Lower autorecache interval bound, default 30m
+	MaxRecacheInterval types.Duration `yaml:"max_recache_interval,omitempty"` // Upper autorecache interval bound, default 24h
+}
+
+// IsEnabled reports whether adaptive popularity is enabled (nil config = disabled)
+func (c *PopularityConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetHalfLife returns the score half-life or the default
+func (c *PopularityConfig) GetHalfLife() time.Duration {
+	if c == nil || c.HalfLife == 0 {
+		return DefaultPopularityHalfLife
+	}
+	return time.Duration(c.HalfLife)
+}
+
+// GetHotScore returns the hot score or the default
+func (c *PopularityConfig) GetHotScore() float64 {
+	if c == nil || c.HotScore == 0 {
+		return DefaultPopularityHotScore
+	}
+	return c.HotScore
+}
+
+// GetHotFactor returns the hot factor or the default
+func (c *PopularityConfig) GetHotFactor() float64 {
+	if c == nil || c.HotFactor == 0 {
+		return DefaultPopularityHotFactor
+	}
+	return c.HotFactor
+}
+
+// GetColdFactor returns the cold factor or the default
+func (c *PopularityConfig) GetColdFactor() float64 {
+	if c == nil || c.ColdFactor == 0 {
+		return DefaultPopularityColdFactor
+	}
+	return c.ColdFactor
+}
+
+// GetMinTTL returns the lower TTL bound or the default
+func (c *PopularityConfig) GetMinTTL() time.Duration {
+	if c == nil || c.MinTTL == 0 {
+		return DefaultPopularityMinTTL
+	}
+	return time.Duration(c.MinTTL)
+}
+
+// GetMaxTTL returns the upper TTL bound or the default
+func (c *PopularityConfig) GetMaxTTL() time.Duration {
+	if c == nil || c.MaxTTL == 0 {
+		return DefaultPopularityMaxTTL
+	}
+	return time.Duration(c.MaxTTL)
+}
+
+// GetMinRecacheInterval returns the lower autorecache interval bound or the default
+func (c *PopularityConfig) GetMinRecacheInterval() time.Duration {
+	if c == nil || c.MinRecacheInterval == 0 {
+		return DefaultPopularityMinInterval
+	}
+	return time.Duration(c.MinRecacheInterval)
+}
+
+// GetMaxRecacheInterval returns the upper autorecache interval bound or the default
+func (c *PopularityConfig) GetMaxRecacheInterval() time.Duration {
+	if c == nil || c.MaxRecacheInterval == 0 {
+		return DefaultPopularityMaxInterval
+	}
+	return time.Duration(c.MaxRecacheInterval)
+}
+
+// Hot cache defaults
+const (
+	DefaultHotCacheMaxEntries  = 10000
+	DefaultHotCacheMaxSize     = 256 // MB
+	DefaultHotCacheMaxBodySize = 1   // MB
+	DefaultHotCacheMaxAge      = 5 * time.Minute
+)
+
+// HotCacheConfig configures the in-process hot cache tier. Frequently requested entries
+// keep their metadata and decoded body in memory, skipping the Redis lookup, file read
+// and decompression. Entries are invalidated cluster-wide via Redis pub/sub.
+type HotCacheConfig struct {
+	Enabled     bool           `yaml:"enabled"`
+	MaxEntries  int            `yaml:"max_entries,omitempty"`   // Entry limit, default 10000
+	MaxSize     int            `yaml:"max_size,omitempty"`      // Memory limit for bodies in MB, default 256
+	MaxBodySize int            `yaml:"max_body_size,omitempty"` // Largest body kept in memory in MB, default 1
+	StoreBodies *bool          `yaml:"store_bodies,omitempty"`  // Keep decoded bodies (false = metadata only), default true
+	MaxAge      types.Duration `yaml:"max_age,omitempty"`       // Upper bound on time an entry is served from memory, default 5m
+}
+
+// IsEnabled reports whether the hot cache is enabled (nil config = disabled)
+func (c *HotCacheConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetMaxEntries returns the entry limit or the default
+func (c *HotCacheConfig) GetMaxEntries() int {
+	if c == nil || c.MaxEntries == 0 {
+		return DefaultHotCacheMaxEntries
+	}
+	return c.MaxEntries
+}
+
+// GetMaxSizeBytes returns the body memory limit in bytes
+func (c *HotCacheConfig) GetMaxSizeBytes() int64 {
+	size := DefaultHotCacheMaxSize
+	if c != nil && c.MaxSize != 0 {
+		size = c.MaxSize
+	}
+	return int64(size) * 1024 * 1024
+}
+
+// GetMaxBodySizeBytes returns the largest body kept in memory in bytes
+func (c *HotCacheConfig) GetMaxBodySizeBytes() int64 {
+	size := DefaultHotCacheMaxBodySize
+	if c != nil && c.MaxBodySize != 0 {
+		size = c.MaxBodySize
+	}
+	return int64(size) * 1024 * 1024
+}
+
+// ShouldStoreBodies reports whether decoded bodies are kept in memory (default true)
+func (c *HotCacheConfig) ShouldStoreBodies() bool {
+	return c == nil || c.StoreBodies == nil || *c.StoreBodies
+}
+
+// GetMaxAge returns the maximum in-memory age of an entry or the default
+func (c *HotCacheConfig) GetMaxAge() time.Duration {
+	if c == nil || c.MaxAge == 0 {
+		return DefaultHotCacheMaxAge
+	}
+	return time.Duration(c.MaxAge)
+}
+
 // EventFileConfig configures file-based event logging
 type EventFileConfig struct {
 	Enabled  bool           `yaml:"enabled"`
@@ -0,0 +1,143 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+)
+
+// StreamKey is the Redis stream carrying cluster events. A stream is used instead of
+// plain pub/sub so subscribers that were disconnected or restarted can replay missed events.
+const StreamKey = "events:cluster"
+
+// streamMaxLen is the approximate number of events retained for replay
+const streamMaxLen = 100000
+
+// eventField is the stream entry field holding the JSON-encoded event
+const eventField = "event"
+
+// Type identifies what changed
+type Type string
+
+const (
+	// EntryWritten is published when cache metadata is stored or updated
+	EntryWritten Type = "entry_written"
+	// EntryInvalidated is published when cache metadata is deleted or replaced by a marker
+	EntryInvalidated Type = "entry_invalidated"
+	// HostPurged is published when all entries of a host (optionally some dimensions) are deleted
+	HostPurged Type = "host_purged"
+	// ConfigReloaded is published when a component loads its configuration
+	ConfigReloaded Type = "config_reloaded"
+)
+
+// Components publishing events
+const (
+	ComponentEdgeGateway   = "edge-gateway"
+	ComponentRenderService = "render-service"
+	ComponentCacheDaemon   = "cache-daemon"
+)
+
+// Entry identifies a cache entry affected by an event
+type Entry struct {
+	Key      string `json:"key"`                 // Cache key (cache:{host_id}:{dimension_id}:{url_hash})
+	FilePath string `json:"file_path,omitempty"` // Relative path of the cache file, when known
+}
+
+// Event is a cluster event. Handlers must be idempotent: events are delivered at least once.
+type Event struct {
+	ID           string    `json:"-"` // Stream entry ID, set on delivery
+	Type         Type      `json:"type"`
+	Component    string    `json:"component"`
+	Instance     string    `json:"instance,omitempty"`
+	Time         time.Time `json:"time"`
+	Entries      []Entry   `json:"entries,omitempty"`       // entry_written, entry_invalidated
+	HostID       int       `json:"host_id,omitempty"`       // host_purged
+	DimensionIDs []int     `json:"dimension_ids,omitempty"` // host_purged, empty = all dimensions
+}
+
+// Keys returns the cache keys of the event entries
+func (e *Event) Keys() []string {
+	keys := make([]string, len(e.Entries))
+	for i, entry := range e.Entries {
+		keys[i] = entry.Key
+	}
+	return keys
+}
+
+// Bus publishes events of one component instance
+type Bus struct {
+	redis     *redis.Client
+	component string
+	instance  string
+	logger    *zap.Logger
+}
+
+// New creates a Bus publishing as component/instance
+func New(redisClient *redis.Client, component, instance string, logger *zap.Logger) *Bus {
+	return &Bus{
+		redis:     redisClient,
+		component: component,
+		instance:  instance,
+		logger:    logger,
+	}
+}
+
+// Publish appends event to the cluster stream. Component, instance and time are filled in.
+func (b *Bus) Publish(ctx context.Context, event *Event) error {
+	event.Component = b.component
+	event.Instance = b.instance
+	event.Time = time.Now().UTC()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	id, err := b.redis.XAdd(ctx, StreamKey, streamMaxLen, map[string]interface{}{eventField: payload})
+	if err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", event.Type, err)
+	}
+	event.ID = id
+	return nil
+}
+
+// PublishEntries publishes an entry_written or entry_invalidated event. Nothing is sent
+// without entries. Failures are logged: events are best effort for publishers.
+func (b *Bus) PublishEntries(ctx context.Context, eventType Type, entries ...Entry) {
+	if b == nil || len(entries) == 0 {
+		return
+	}
+	if err := b.Publish(ctx, &Event{Type: eventType, Entries: entries}); err != nil {
+		b.logger.Warn("Failed to publish cache event",
+			zap.String("type", string(eventType)),
+			zap.Int("entries", len(entries)),
+			zap.Error(err))
+	}
+}
+
+// PublishHostPurged publishes a host_purged event
+func (b *Bus) PublishHostPurged(ctx context.Context, hostID int, dimensionIDs []int) {
+	if b == nil {
+		return
+	}
+	if err := b.Publish(ctx, &Event{Type: HostPurged, HostID: hostID, DimensionIDs: dimensionIDs}); err != nil {
+		b.logger.Warn("Failed to publish host purge event",
+			zap.Int("host_id", hostID),
+			zap.Error(err))
+	}
+}
+
+// PublishConfigReloaded publishes a config_reloaded event
+func (b *Bus) PublishConfigReloaded(ctx context.Context) {
+	if b == nil {
+		return
+	}
+	if err := b.Publish(ctx, &Event{Type: ConfigReloaded}); err != nil {
+		b.logger.Warn("Failed to publish config reload event", zap.Error(err))
+	}
+}
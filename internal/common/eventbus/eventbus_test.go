@@ -0,0 +1,183 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+)
+
+// recordingHandler records delivered events, resyncs and live state
+type recordingHandler struct {
+	mu      sync.Mutex
+	events  []*Event
+	resyncs int
+	live    bool
+}
+
+func (h *recordingHandler) HandleEvent(ctx context.Context, event *Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+func (h *recordingHandler) Resync(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.resyncs++
+}
+
+func (h *recordingHandler) SetLive(live bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.live = live
+}
+
+func (h *recordingHandler) snapshot() ([]*Event, int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*Event(nil), h.events...), h.resyncs, h.live
+}
+
+func (h *recordingHandler) isLive() bool {
+	_, _, live := h.snapshot()
+	return live
+}
+
+func setupTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+	return redisClient, mr
+}
+
+func startSubscriber(t *testing.T, redisClient *redis.Client, handler *recordingHandler) *Subscriber {
+	t.Helper()
+	sub := NewSubscriber(redisClient, "eg-test", zap.NewNop(), handler)
+	sub.Start()
+	require.Eventually(t, handler.isLive, 3*time.Second, 10*time.Millisecond)
+	return sub
+}
+
+func waitForEvents(t *testing.T, handler *recordingHandler, n int) []*Event {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		events, _, _ := handler.snapshot()
+		return len(events) >= n
+	}, 3*time.Second, 10*time.Millisecond)
+	events, _, _ := handler.snapshot()
+	return events
+}
+
+func TestSubscriber_DeliversEvents(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	bus := New(redisClient, ComponentCacheDaemon, "daemon-1", zap.NewNop())
+	handler := &recordingHandler{}
+	sub := startSubscriber(t, redisClient, handler)
+	defer sub.Shutdown()
+
+	ctx := context.Background()
+	bus.PublishEntries(ctx, EntryInvalidated, Entry{Key: "cache:1:1:a", FilePath: "1/a_1.html"})
+	bus.PublishHostPurged(ctx, 2, []int{1})
+
+	events := waitForEvents(t, handler, 2)
+	assert.Equal(t, EntryInvalidated, events[0].Type)
+	assert.Equal(t, ComponentCacheDaemon, events[0].Component)
+	assert.Equal(t, "daemon-1", events[0].Instance)
+	assert.Equal(t, []string{"cache:1:1:a"}, events[0].Keys())
+	assert.Equal(t, "1/a_1.html", events[0].Entries[0].FilePath)
+	assert.NotEmpty(t, events[0].ID)
+
+	assert.Equal(t, HostPurged, events[1].Type)
+	assert.Equal(t, 2, events[1].HostID)
+	assert.Equal(t, []int{1}, events[1].DimensionIDs)
+
+	// First start of a consumer resyncs once
+	_, resyncs, _ := handler.snapshot()
+	assert.Equal(t, 1, resyncs)
+}
+
+func TestSubscriber_ReplaysAfterRestart(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	bus := New(redisClient, ComponentEdgeGateway, "eg-1", zap.NewNop())
+	ctx := context.Background()
+
+	first := &recordingHandler{}
+	sub := startSubscriber(t, redisClient, first)
+	bus.PublishEntries(ctx, EntryWritten, Entry{Key: "cache:1:1:a"})
+	waitForEvents(t, first, 1)
+	sub.Shutdown()
+
+	// Published while the consumer is down
+	bus.PublishEntries(ctx, EntryWritten, Entry{Key: "cache:1:1:b"})
+	bus.PublishEntries(ctx, EntryWritten, Entry{Key: "cache:1:1:c"})
+
+	second := &recordingHandler{}
+	sub = startSubscriber(t, redisClient, second)
+	defer sub.Shutdown()
+
+	events := waitForEvents(t, second, 2)
+	assert.Equal(t, []string{"cache:1:1:b"}, events[0].Keys())
+	assert.Equal(t, []string{"cache:1:1:c"}, events[1].Keys())
+
+	_, resyncs, _ := second.snapshot()
+	assert.Equal(t, 0, resyncs, "no events were lost")
+}
+
+func TestSubscriber_ResyncsWhenOffsetTrimmed(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	bus := New(redisClient, ComponentEdgeGateway, "eg-1", zap.NewNop())
+	ctx := context.Background()
+
+	first := &recordingHandler{}
+	sub := startSubscriber(t, redisClient, first)
+	bus.PublishEntries(ctx, EntryWritten, Entry{Key: "cache:1:1:a"})
+	waitForEvents(t, first, 1)
+	sub.Shutdown()
+
+	// Simulate trimming: the handled event and its successors are gone
+	bus.PublishEntries(ctx, EntryWritten, Entry{Key: "cache:1:1:b"})
+	require.NoError(t, redisClient.GetClient().XTrimMaxLen(ctx, StreamKey, 0).Err())
+	bus.PublishEntries(ctx, EntryWritten, Entry{Key: "cache:1:1:c"})
+
+	second := &recordingHandler{}
+	sub = startSubscriber(t, redisClient, second)
+	defer sub.Shutdown()
+
+	events := waitForEvents(t, second, 1)
+	assert.Equal(t, []string{"cache:1:1:c"}, events[0].Keys())
+	_, resyncs, _ := second.snapshot()
+	assert.Equal(t, 1, resyncs)
+}
+
+func TestSubscriber_NotLiveWhileDisconnected(t *testing.T) {
+	redisClient, mr := setupTestRedis(t)
+	handler := &recordingHandler{}
+	sub := startSubscriber(t, redisClient, handler)
+	defer sub.Shutdown()
+
+	// Every command fails while Redis is unavailable
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	assert.Eventually(t, func() bool { return !handler.isLive() }, 5*time.Second, 10*time.Millisecond)
+
+	mr.SetError("")
+	assert.Eventually(t, handler.isLive, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestCompareIDs(t *testing.T) {
+	assert.Equal(t, 0, compareIDs("5-1", "5-1"))
+	assert.Equal(t, -1, compareIDs("5-1", "5-2"))
+	assert.Equal(t, 1, compareIDs("10-0", "9-99"))
+	assert.Equal(t, -1, compareIDs("0-0", "1-0"))
+}
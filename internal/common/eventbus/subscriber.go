@@ -0,0 +1,251 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+)
+
+const (
+	// readCount is the maximum number of events read per request
+	readCount = 100
+	// readBlock is how long a read waits for new events; bounds shutdown latency
+	readBlock = time.Second
+	// offsetTTL is how long a consumer offset is kept after the consumer was last active
+	offsetTTL = 7 * 24 * time.Hour
+	// minRetryDelay and maxRetryDelay bound the backoff after Redis errors
+	minRetryDelay = time.Second
+	maxRetryDelay = 30 * time.Second
+)
+
+// Handler consumes cluster events
+type Handler interface {
+	// HandleEvent applies one event. Events may be delivered more than once.
+	HandleEvent(ctx context.Context, event *Event)
+	// Resync is called before delivery starts or resumes when events may have been lost
+	// (first start of the consumer, or its offset was trimmed from the stream)
+	Resync(ctx context.Context)
+	// SetLive reports whether the subscriber is connected and caught up with the stream
+	SetLive(live bool)
+}
+
+// Subscriber delivers cluster events to handlers. The offset of the last handled event is
+// stored in Redis per consumer, so delivery resumes where it stopped after a disconnect or
+// restart. Each consumer name must be unique (e.g. one per EG instance).
+type Subscriber struct {
+	redis    *redis.Client
+	consumer string
+	handlers []Handler
+	logger   *zap.Logger
+
+	lastID string // ID of the last handled event, empty until resumed
+	live   bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSubscriber creates a subscriber for consumer delivering to handlers
+func NewSubscriber(redisClient *redis.Client, consumer string, logger *zap.Logger, handlers ...Handler) *Subscriber {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Subscriber{
+		redis:    redisClient,
+		consumer: consumer,
+		handlers: handlers,
+		logger:   logger.With(zap.String("consumer", consumer)),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start begins consuming events in the background
+func (s *Subscriber) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run()
+	}()
+}
+
+// Shutdown stops the subscriber
+func (s *Subscriber) Shutdown() {
+	s.cancel()
+	s.wg.Wait()
+	s.setLive(false)
+}
+
+func (s *Subscriber) run() {
+	resumed := false
+	retryDelay := minRetryDelay
+
+	for s.ctx.Err() == nil {
+		if !resumed {
+			if err := s.resume(s.ctx); err != nil {
+				s.fail(err, &retryDelay)
+				continue
+			}
+			resumed = true
+		}
+
+		messages, err := s.redis.XRead(s.ctx, StreamKey, s.lastID, readCount, readBlock)
+		if err != nil {
+			// Events may have been trimmed while disconnected, check the offset again
+			resumed = false
+			s.fail(err, &retryDelay)
+			continue
+		}
+		retryDelay = minRetryDelay
+
+		for _, message := range messages {
+			s.dispatch(message.ID, message.Values)
+			s.lastID = message.ID
+		}
+		if len(messages) > 0 {
+			s.saveOffset()
+		}
+
+		// A partial batch means the subscriber has caught up
+		s.setLive(len(messages) < readCount)
+	}
+}
+
+// resume determines where delivery continues and resyncs handlers when events were lost
+func (s *Subscriber) resume(ctx context.Context) error {
+	offset := s.lastID
+	if offset == "" {
+		stored, err := s.redis.Get(ctx, s.offsetKey())
+		if err != nil {
+			return err
+		}
+		offset = stored
+	}
+
+	if offset == "" {
+		// First start: history is unknown, continue from the newest event
+		newest, err := s.redis.XRevRangeN(ctx, StreamKey, "+", "-", 1)
+		if err != nil {
+			return err
+		}
+		s.lastID = "0-0"
+		if len(newest) > 0 {
+			s.lastID = newest[0].ID
+		}
+		s.logger.Info("Event subscriber starting without stored offset, resyncing",
+			zap.String("from_id", s.lastID))
+		s.resync(ctx)
+		return nil
+	}
+
+	// The stored offset refers to an event this consumer handled. If the stream no longer
+	// holds that event, anything published after it may have been trimmed as well.
+	oldest, err := s.redis.XRangeN(ctx, StreamKey, "-", "+", 1)
+	if err != nil {
+		return err
+	}
+	s.lastID = offset
+	var lost bool
+	if len(oldest) == 0 {
+		lost = offset != "0-0" // Stream was deleted
+	} else {
+		lost = compareIDs(oldest[0].ID, offset) > 0
+	}
+	if lost {
+		s.logger.Warn("Events may have been missed while disconnected, resyncing",
+			zap.String("offset", offset))
+		s.resync(ctx)
+	}
+	return nil
+}
+
+func (s *Subscriber) resync(ctx context.Context) {
+	for _, handler := range s.handlers {
+		handler.Resync(ctx)
+	}
+}
+
+func (s *Subscriber) dispatch(id string, values map[string]interface{}) {
+	payload, _ := values[eventField].(string)
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		s.logger.Warn("Skipping invalid event",
+			zap.String("id", id),
+			zap.Error(err))
+		return
+	}
+	event.ID = id
+
+	for _, handler := range s.handlers {
+		handler.HandleEvent(s.ctx, &event)
+	}
+}
+
+func (s *Subscriber) saveOffset() {
+	if err := s.redis.Set(s.ctx, s.offsetKey(), s.lastID, offsetTTL); err != nil && s.ctx.Err() == nil {
+		// Not fatal: a restart replays from the previous offset and handlers are idempotent
+		s.logger.Warn("Failed to store event offset", zap.Error(err))
+	}
+}
+
+func (s *Subscriber) fail(err error, retryDelay *time.Duration) {
+	if s.ctx.Err() != nil {
+		return
+	}
+	if s.live {
+		s.logger.Warn("Event subscriber disconnected", zap.Error(err))
+	}
+	s.setLive(false)
+
+	select {
+	case <-time.After(*retryDelay):
+	case <-s.ctx.Done():
+	}
+	*retryDelay = min(*retryDelay*2, maxRetryDelay)
+}
+
+func (s *Subscriber) setLive(live bool) {
+	if live == s.live {
+		return
+	}
+	s.live = live
+	for _, handler := range s.handlers {
+		handler.SetLive(live)
+	}
+}
+
+func (s *Subscriber) offsetKey() string {
+	return "events:offset:" + s.consumer
+}
+
+// compareIDs compares two stream entry IDs (ms-seq)
+func compareIDs(a, b string) int {
+	aMs, aSeq := splitID(a)
+	bMs, bSeq := splitID(b)
+	switch {
+	case aMs != bMs:
+		if aMs < bMs {
+			return -1
+		}
+		return 1
+	case aSeq != bSeq:
+		if aSeq < bSeq {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func splitID(id string) (uint64, uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseUint(msPart, 10, 64)
+	seq, _ := strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
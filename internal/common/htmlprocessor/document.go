@@ -19,6 +19,21 @@ type Document interface {
 	// Returns true if any were removed.
 	CleanScripts() bool
 
+	// ApplyTransforms applies HTML transforms in order. pageURL replaces the {url}
+	// placeholder. Returns the number of transforms that changed the document.
+	ApplyTransforms(transforms []types.HTMLTransform, pageURL string) (int, error)
+
+	// Markdown converts the page's main content to Markdown. pageURL resolves relative
+	// links; jsonLDSummary appends a summary of JSON-LD structured data.
+	Markdown(pageURL string, jsonLDSummary bool) []byte
+
+	// HasElement reports whether any element matches selector.
+	HasElement(selector *Selector) bool
+
+	// ContainsText reports whether the visible text of the document contains text.
+	// Matching is case-insensitive and ignores differences in whitespace.
+	ContainsText(text string) bool
+
 	// HTML returns current HTML as bytes (re-serialized from DOM).
 	HTML() []byte
 
@@ -0,0 +1,646 @@
+package htmlprocessor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/edgecomet/engine/pkg/types"
+	"golang.org/x/net/html"
+)
+
+// maxJSONLDSummaryEntities limits the entities listed in the structured data summary
+const maxJSONLDSummaryEntities = 20
+
+// markdownSkipElements are dropped with their content
+var markdownSkipElements = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "canvas": true, "iframe": true, "object": true, "embed": true,
+	"form": true, "button": true, "select": true, "input": true, "textarea": true, "dialog": true,
+	"nav": true, "aside": true, "footer": true,
+}
+
+// markdownChromeRoles are ARIA landmark roles of page chrome, dropped like nav and footer
+var markdownChromeRoles = map[string]bool{
+	"navigation": true, "banner": true, "contentinfo": true, "complementary": true, "search": true,
+}
+
+// markdownBlockElements start a new Markdown block
+var markdownBlockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true, "header": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "pre": true, "blockquote": true, "table": true, "hr": true,
+	"dl": true, "dt": true, "dd": true, "figure": true, "figcaption": true,
+	"address": true, "details": true, "summary": true, "fieldset": true,
+}
+
+// jsonLDSummaryFields are the properties listed for each structured data entity, in order
+var jsonLDSummaryFields = []string{
+	"description", "url", "sku", "brand", "offers", "aggregateRating", "author",
+	"datePublished", "dateModified", "telephone", "address",
+}
+
+// markdownConverter converts a DOM subtree to Markdown
+type markdownConverter struct {
+	baseURL string // Resolves relative link and image URLs (empty keeps them as-is)
+}
+
+func (d *domDocument) Markdown(pageURL string, jsonLDSummary bool) []byte {
+	head := findElement(d.root, "head")
+	body := findElement(d.root, "body")
+	if body == nil {
+		body = d.root
+	}
+
+	baseURL := pageURL
+	if baseHref := extractBaseHref(head); baseHref != "" && pageURL != "" {
+		baseURL = resolveURL(baseHref, pageURL)
+	}
+	c := &markdownConverter{baseURL: baseURL}
+
+	content := mainContent(body)
+	blocks := c.blocks(content)
+
+	var preamble []string
+	if findElement(content, "h1") == nil {
+		if title := extractSEOTitle(head); title != "" {
+			preamble = append(preamble, "# "+title)
+		}
+	}
+	if description := extractMetaDescription(head); description != "" {
+		preamble = append(preamble, "> "+collapseWhitespace(description))
+	}
+	blocks = append(preamble, blocks...)
+
+	if jsonLDSummary {
+		if summary := summarizeJSONLD(d.root); summary != "" {
+			blocks = append(blocks, "## Structured data", summary)
+		}
+	}
+
+	if len(blocks) == 0 {
+		return []byte{}
+	}
+	return []byte(strings.Join(blocks, "\n\n") + "\n")
+}
+
+// mainContent returns the element holding the page's main content: <main> (or role=main),
+// a single <article>, or body when neither is found
+func mainContent(body *html.Node) *html.Node {
+	if main := findElement(body, "main"); main != nil {
+		return main
+	}
+	var roleMain *html.Node
+	var articles []*html.Node
+	var search func(*html.Node)
+	search = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if roleMain == nil && strings.EqualFold(getAttr(n, "role"), "main") {
+				roleMain = n
+			}
+			if strings.ToLower(n.Data) == "article" {
+				articles = append(articles, n)
+				return // nested articles (comments) belong to the outer one
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			search(c)
+		}
+	}
+	search(body)
+
+	switch {
+	case roleMain != nil:
+		return roleMain
+	case len(articles) == 1:
+		return articles[0]
+	default:
+		return body
+	}
+}
+
+// skipMarkdownElement reports whether an element is page chrome or non-content
+func skipMarkdownElement(n *html.Node) bool {
+	tag := strings.ToLower(n.Data)
+	if markdownSkipElements[tag] {
+		return true
+	}
+	if _, hidden := lookupAttr(n, "hidden"); hidden {
+		return true
+	}
+	if strings.EqualFold(getAttr(n, "aria-hidden"), "true") {
+		return true
+	}
+	if markdownChromeRoles[strings.ToLower(getAttr(n, "role"))] {
+		return true
+	}
+	// Site headers are chrome, headers of articles and sections hold their titles
+	return tag == "header" && !hasContentAncestor(n)
+}
+
+func hasContentAncestor(n *html.Node) bool {
+	for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+		switch strings.ToLower(p.Data) {
+		case "article", "section", "main":
+			return true
+		}
+	}
+	return false
+}
+
+// blocks converts the children of n to Markdown blocks
+func (c *markdownConverter) blocks(n *html.Node) []string {
+	var blocks []string
+	var inline strings.Builder
+
+	flush := func() {
+		if text := normalizeInline(inline.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && skipMarkdownElement(child) {
+			continue
+		}
+		if child.Type != html.ElementNode || !markdownBlockElements[strings.ToLower(child.Data)] {
+			inline.WriteString(c.inline(child))
+			continue
+		}
+		flush()
+		blocks = append(blocks, c.block(child)...)
+	}
+	flush()
+	return blocks
+}
+
+// block converts a block-level element
+func (c *markdownConverter) block(n *html.Node) []string {
+	tag := strings.ToLower(n.Data)
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := normalizeInline(strings.ReplaceAll(c.inlineChildren(n), "\n", " "))
+		if text == "" {
+			return nil
+		}
+		level := int(tag[1] - '0')
+		return []string{strings.Repeat("#", level) + " " + text}
+	case "p", "dd", "figcaption", "summary":
+		if text := normalizeInline(c.inlineChildren(n)); text != "" {
+			return []string{text}
+		}
+		return nil
+	case "dt":
+		if text := normalizeInline(c.inlineChildren(n)); text != "" {
+			return []string{"**" + text + "**"}
+		}
+		return nil
+	case "ul", "ol":
+		if list := c.list(n, tag == "ol"); list != "" {
+			return []string{list}
+		}
+		return nil
+	case "pre":
+		return []string{codeBlock(n)}
+	case "blockquote":
+		inner := c.blocks(n)
+		if len(inner) == 0 {
+			return nil
+		}
+		return []string{prefixLines(strings.Join(inner, "\n\n"), "> ", ">")}
+	case "table":
+		if table := c.table(n); table != "" {
+			return []string{table}
+		}
+		return nil
+	case "hr":
+		return []string{"---"}
+	default:
+		// Generic containers (div, section, article, li outside a list, ...)
+		return c.blocks(n)
+	}
+}
+
+// list converts ul/ol items, indenting nested content under the item marker
+func (c *markdownConverter) list(n *html.Node, ordered bool) string {
+	start := 1
+	if ordered {
+		if v, err := strconv.Atoi(getAttr(n, "start")); err == nil {
+			start = v
+		}
+	}
+
+	var items []string
+	index := start
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || strings.ToLower(child.Data) != "li" || skipMarkdownElement(child) {
+			continue
+		}
+		content := strings.Join(c.blocks(child), "\n")
+		if content == "" {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(index) + ". "
+			index++
+		}
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, marker+strings.ReplaceAll(content, "\n", "\n"+indent))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table converts a table to a GFM table, using the first row as header
+func (c *markdownConverter) table(n *html.Node) string {
+	var rows [][]string
+	columns := 0
+	var collect func(*html.Node)
+	collect = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch strings.ToLower(child.Data) {
+			case "thead", "tbody", "tfoot":
+				collect(child)
+			case "tr":
+				var row []string
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type != html.ElementNode {
+						continue
+					}
+					if tag := strings.ToLower(cell.Data); tag != "td" && tag != "th" {
+						continue
+					}
+					text := normalizeInline(strings.ReplaceAll(c.inlineChildren(cell), "\n", " "))
+					row = append(row, strings.ReplaceAll(text, "|", `\|`))
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+					columns = max(columns, len(row))
+				}
+			}
+		}
+	}
+	collect(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := 0; i < columns; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// inlineChildren converts the children of n as inline content
+func (c *markdownConverter) inlineChildren(n *html.Node) string {
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(c.inline(child))
+	}
+	return sb.String()
+}
+
+// inline converts a node as inline content. Line breaks are kept as "\n".
+func (c *markdownConverter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return squeezeWhitespace(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+	if skipMarkdownElement(n) {
+		return ""
+	}
+
+	tag := strings.ToLower(n.Data)
+	switch tag {
+	case "br":
+		return "\n"
+	case "strong", "b":
+		return wrapInline(c.inlineChildren(n), "**")
+	case "em", "i":
+		return wrapInline(c.inlineChildren(n), "*")
+	case "del", "s", "strike":
+		return wrapInline(c.inlineChildren(n), "~~")
+	case "code", "kbd", "samp":
+		text := collapseWhitespace(getTextContent(n))
+		if text == "" {
+			return ""
+		}
+		fence := "`"
+		if strings.Contains(text, "`") {
+			fence = "``"
+		}
+		return fence + text + fence
+	case "a":
+		return c.link(n)
+	case "img":
+		return c.image(n)
+	}
+
+	text := c.inlineChildren(n)
+	if markdownBlockElements[tag] {
+		// Block element in inline context (e.g. a div inside a link)
+		return " " + text + " "
+	}
+	return text
+}
+
+// link converts an anchor. Links without a usable href are reduced to their text.
+func (c *markdownConverter) link(n *html.Node) string {
+	text := normalizeInline(strings.ReplaceAll(c.inlineChildren(n), "\n", " "))
+	if text == "" {
+		text = collapseWhitespace(getAttr(n, "aria-label"))
+	}
+	href := strings.TrimSpace(getAttr(n, "href"))
+	if text == "" {
+		return ""
+	}
+	lower := strings.ToLower(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(lower, "javascript:") {
+		return text
+	}
+	return "[" + text + "](" + c.resolve(href) + ")"
+}
+
+// image converts an img element, skipping inline data
+func (c *markdownConverter) image(n *html.Node) string {
+	src := strings.TrimSpace(getAttr(n, "src"))
+	if shouldSkipImageSrc(src) {
+		return ""
+	}
+	alt := collapseWhitespace(getAttr(n, "alt"))
+	return "![" + strings.ReplaceAll(alt, "]", `\]`) + "](" + c.resolve(src) + ")"
+}
+
+func (c *markdownConverter) resolve(href string) string {
+	if c.baseURL == "" {
+		return escapeMarkdownURL(href)
+	}
+	return escapeMarkdownURL(resolveURL(href, c.baseURL))
+}
+
+// escapeMarkdownURL encodes characters that would end a Markdown link destination
+func escapeMarkdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
+
+// wrapInline wraps text in a Markdown emphasis marker, keeping surrounding spaces outside it
+func wrapInline(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+	return leading + marker + trimmed + marker + trailing
+}
+
+// normalizeInline collapses spaces and trims each line of inline content, dropping empty lines
+func normalizeInline(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = collapseWhitespace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	// Markdown hard line break
+	return strings.Join(kept, "  \n")
+}
+
+// codeBlock converts a pre element to a fenced code block, taking the language from a
+// language-* or lang-* class on the pre or its code child
+func codeBlock(n *html.Node) string {
+	language := codeLanguage(n)
+	if code := findElementInParent(n, "code"); code != nil && language == "" {
+		language = codeLanguage(code)
+	}
+
+	text := strings.Trim(getTextContent(n), "\n")
+	fence := "```"
+	if strings.Contains(text, "```") {
+		fence = "~~~"
+	}
+	return fence + language + "\n" + text + "\n" + fence
+}
+
+func codeLanguage(n *html.Node) string {
+	for _, class := range strings.Fields(getAttr(n, "class")) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(class, prefix) && len(class) > len(prefix) {
+				return class[len(prefix):]
+			}
+		}
+	}
+	return ""
+}
+
+// prefixLines prefixes every line of s, using emptyPrefix for empty lines
+func prefixLines(s, prefix, emptyPrefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// summarizeJSONLD lists the JSON-LD entities of the page as a Markdown list
+func summarizeJSONLD(root *html.Node) string {
+	var entities []map[string]interface{}
+	for _, script := range findAllElementsInParent(root, "script") {
+		if !isJSONLDScript(script) {
+			continue
+		}
+		content := getTextContent(script)
+		if len(content) > types.MaxJSONLDSize {
+			continue
+		}
+		var data interface{}
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			continue
+		}
+		entities = collectJSONLDEntities(data, entities, 0)
+	}
+
+	var items []string
+	for _, entity := range entities {
+		if len(items) >= maxJSONLDSummaryEntities {
+			break
+		}
+		if item := summarizeJSONLDEntity(entity); item != "" {
+			items = append(items, item)
+		}
+	}
+	return strings.Join(items, "\n")
+}
+
+// collectJSONLDEntities returns the top-level typed objects, unwrapping arrays and @graph
+func collectJSONLDEntities(v interface{}, entities []map[string]interface{}, depth int) []map[string]interface{} {
+	if depth > types.MaxJSONLDRecursionDepth {
+		return entities
+	}
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			entities = collectJSONLDEntities(item, entities, depth+1)
+		}
+	case map[string]interface{}:
+		if graph, ok := val["@graph"]; ok {
+			entities = collectJSONLDEntities(graph, entities, depth+1)
+		}
+		if _, ok := val["@type"]; ok {
+			entities = append(entities, val)
+		}
+	}
+	return entities
+}
+
+// summarizeJSONLDEntity formats one entity as "- **Type**: name" with its key properties
+func summarizeJSONLDEntity(entity map[string]interface{}) string {
+	typeName := jsonLDText(entity["@type"])
+	if typeName == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("- **" + typeName + "**")
+	if name := firstJSONLDText(entity, "name", "headline", "title"); name != "" {
+		sb.WriteString(": " + name)
+	}
+
+	// Breadcrumbs read best as a path
+	if items, ok := entity["itemListElement"].([]interface{}); ok && strings.Contains(typeName, "BreadcrumbList") {
+		var names []string
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				name := firstJSONLDText(m, "name")
+				if name == "" {
+					name = firstJSONLDText(asJSONLDObject(m["item"]), "name")
+				}
+				if name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+		if len(names) > 0 {
+			sb.WriteString(": " + strings.Join(names, " > "))
+		}
+		return sb.String()
+	}
+
+	for _, field := range jsonLDSummaryFields {
+		value := jsonLDFieldText(field, entity[field])
+		if value == "" {
+			continue
+		}
+		sb.WriteString("\n  - " + field + ": " + truncateRunes(value, types.MaxMetaDescriptionLength))
+	}
+	return sb.String()
+}
+
+// jsonLDFieldText formats a summary field, flattening the nested objects schema.org uses
+func jsonLDFieldText(field string, v interface{}) string {
+	if list, ok := v.([]interface{}); ok && len(list) > 0 {
+		v = list[0]
+	}
+	obj := asJSONLDObject(v)
+	if obj == nil {
+		return jsonLDText(v)
+	}
+
+	switch field {
+	case "offers":
+		price := firstJSONLDText(obj, "price", "lowPrice")
+		if price == "" {
+			return ""
+		}
+		parts := []string{price}
+		if currency := firstJSONLDText(obj, "priceCurrency"); currency != "" {
+			parts = append(parts, currency)
+		}
+		if availability := firstJSONLDText(obj, "availability"); availability != "" {
+			parts = append(parts, "("+strings.TrimPrefix(strings.TrimPrefix(availability, "https://schema.org/"), "http://schema.org/")+")")
+		}
+		return strings.Join(parts, " ")
+	case "aggregateRating":
+		rating := firstJSONLDText(obj, "ratingValue")
+		if rating == "" {
+			return ""
+		}
+		if count := firstJSONLDText(obj, "reviewCount", "ratingCount"); count != "" {
+			return fmt.Sprintf("%s (%s reviews)", rating, count)
+		}
+		return rating
+	case "address":
+		var parts []string
+		for _, key := range []string{"streetAddress", "addressLocality", "addressRegion", "postalCode", "addressCountry"} {
+			if part := firstJSONLDText(obj, key); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return firstJSONLDText(obj, "name", "@id", "url")
+	}
+}
+
+func asJSONLDObject(v interface{}) map[string]interface{} {
+	obj, _ := v.(map[string]interface{})
+	return obj
+}
+
+// firstJSONLDText returns the first non-empty scalar among keys
+func firstJSONLDText(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if text := jsonLDText(obj[key]); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// jsonLDText formats a scalar (or list of strings, e.g. multiple @type values) as text
+func jsonLDText(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return collapseWhitespace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		var parts []string
+		for _, item := range val {
+			if s, ok := item.(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
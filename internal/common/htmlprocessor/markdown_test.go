@@ -0,0 +1,98 @@
+package htmlprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func toMarkdown(t *testing.T, input string, jsonLDSummary bool) string {
+	t.Helper()
+	doc, err := ParseWithDOM([]byte(input))
+	require.NoError(t, err)
+	return string(doc.Markdown("https://example.com/blog/post", jsonLDSummary))
+}
+
+func TestMarkdown_MainContent(t *testing.T) {
+	out := toMarkdown(t, `<html><head><title>Post title</title><meta name="description" content="About  the post"></head>
+<body>
+<header><a href="/">Logo</a></header>
+<nav><ul><li><a href="/a">Menu</a></li></ul></nav>
+<main>
+  <h1>Hello <em>world</em></h1>
+  <p>First <strong>bold</strong> paragraph with a <a href="../docs?q=1">relative link</a>
+  and a <a href="#top">fragment</a>.</p>
+  <div hidden>Hidden</div>
+  <p>Line one<br>Line two</p>
+</main>
+<footer>Copyright</footer>
+<script>var x = 1;</script>
+</body></html>`, false)
+
+	assert.Equal(t, "> About the post\n\n"+
+		"# Hello *world*\n\n"+
+		"First **bold** paragraph with a [relative link](https://example.com/docs?q=1) and a fragment.\n\n"+
+		"Line one  \nLine two\n", out)
+}
+
+func TestMarkdown_TitleWithoutH1(t *testing.T) {
+	out := toMarkdown(t, `<html><head><title>Page</title></head><body><article><h2>Section</h2><p>Text</p></article><article><p>Other</p></article></body></html>`, false)
+	assert.Equal(t, "# Page\n\n## Section\n\nText\n\nOther\n", out)
+}
+
+func TestMarkdown_Lists(t *testing.T) {
+	out := toMarkdown(t, `<body><main>
+<ul><li>One</li><li>Two<ul><li>Nested</li></ul></li></ul>
+<ol start="3"><li>Three</li><li><p>Four</p></li></ol>
+</main></body>`, false)
+	assert.Equal(t, "- One\n- Two\n  - Nested\n\n3. Three\n4. Four\n", out)
+}
+
+func TestMarkdown_Table(t *testing.T) {
+	out := toMarkdown(t, `<body><main><table>
+<thead><tr><th>Plan</th><th>Price</th></tr></thead>
+<tbody><tr><td>Basic</td><td>$1 | month</td></tr><tr><td>Pro</td></tr></tbody>
+</table></main></body>`, false)
+	assert.Equal(t, "| Plan | Price |\n| --- | --- |\n| Basic | $1 \\| month |\n| Pro |  |\n", out)
+}
+
+func TestMarkdown_CodeQuoteImage(t *testing.T) {
+	out := toMarkdown(t, `<body><main>
+<pre><code class="language-go">func main() {
+	fmt.Println("hi")
+}</code></pre>
+<blockquote><p>Quoted</p><p>Twice</p></blockquote>
+<p>Run <code>make build</code> <img src="/img/a b.png" alt="Diagram"> <img src="data:image/png;base64,AAAA"></p>
+<hr>
+</main></body>`, false)
+	assert.Equal(t, "```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n\n"+
+		"> Quoted\n>\n> Twice\n\n"+
+		"Run `make build` ![Diagram](https://example.com/img/a%20b.png)\n\n"+
+		"---\n", out)
+}
+
+func TestMarkdown_JSONLDSummary(t *testing.T) {
+	input := `<html><head>
+<script type="application/ld+json">{"@context":"https://schema.org","@graph":[
+  {"@type":"Product","name":"Widget","description":"A widget","brand":{"@type":"Brand","name":"Acme"},
+   "offers":{"@type":"Offer","price":"19.99","priceCurrency":"USD","availability":"https://schema.org/InStock"},
+   "aggregateRating":{"ratingValue":4.5,"reviewCount":12}},
+  {"@type":"BreadcrumbList","itemListElement":[{"name":"Home"},{"item":{"name":"Widgets"}}]}
+]}</script>
+<script type="application/ld+json">not json</script>
+</head><body><main><h1>Widget</h1></main></body></html>`
+
+	assert.Equal(t, "# Widget\n", toMarkdown(t, input, false))
+	assert.Equal(t, "# Widget\n\n## Structured data\n\n"+
+		"- **Product**: Widget\n"+
+		"  - description: A widget\n"+
+		"  - brand: Acme\n"+
+		"  - offers: 19.99 USD (InStock)\n"+
+		"  - aggregateRating: 4.5 (12 reviews)\n"+
+		"- **BreadcrumbList**: Home > Widgets\n", toMarkdown(t, input, true))
+}
+
+func TestMarkdown_Empty(t *testing.T) {
+	assert.Equal(t, "", toMarkdown(t, `<html><body><script>app()</script></body></html>`, true))
+}
@@ -0,0 +1,88 @@
+package htmlprocessor
+
+import (
+	"math"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// MinHashSize is the number of hash functions (signature length) in a page MinHash
+	MinHashSize = 64
+
+	// minHashShingleSize is the number of consecutive words per shingle
+	minHashShingleSize = 3
+)
+
+// minHashSeeds are fixed per-slot multipliers/offsets so signatures are stable across
+// processes and releases. Changing them invalidates every stored signature.
+var minHashSeeds = func() [MinHashSize][2]uint64 {
+	var seeds [MinHashSize][2]uint64
+	state := uint64(0x9E3779B97F4A7C15)
+	for i := range seeds {
+		state = splitMix64(state)
+		seeds[i][0] = state | 1 // odd multiplier
+		state = splitMix64(state)
+		seeds[i][1] = state
+	}
+	return seeds
+}()
+
+func splitMix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// ComputeMinHash returns the MinHash signature of word 3-gram shingles.
+// Pages shorter than a shingle are hashed as a single shingle. Returns nil for no words.
+func ComputeMinHash(words []string) []uint64 {
+	if len(words) == 0 {
+		return nil
+	}
+
+	signature := make([]uint64, MinHashSize)
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+
+	shingleCount := len(words) - minHashShingleSize + 1
+	if shingleCount < 1 {
+		shingleCount = 1
+	}
+
+	for i := 0; i < shingleCount; i++ {
+		end := i + minHashShingleSize
+		if end > len(words) {
+			end = len(words)
+		}
+		h := xxhash.Sum64String(strings.Join(words[i:end], " "))
+
+		for j := range signature {
+			v := h*minHashSeeds[j][0] + minHashSeeds[j][1]
+			if v < signature[j] {
+				signature[j] = v
+			}
+		}
+	}
+
+	return signature
+}
+
+// MinHashSimilarity estimates the Jaccard similarity of two signatures as the
+// fraction of equal slots. Returns 0 if either signature is empty or lengths differ.
+func MinHashSimilarity(a, b []uint64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
@@ -0,0 +1,64 @@
+package htmlprocessor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMinHash(t *testing.T) {
+	t.Run("empty input", func(t *testing.T) {
+		assert.Nil(t, ComputeMinHash(nil))
+	})
+
+	t.Run("short input still produces a signature", func(t *testing.T) {
+		sig := ComputeMinHash([]string{"hello"})
+		assert.Len(t, sig, MinHashSize)
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		words := strings.Fields("the quick brown fox jumps over the lazy dog")
+		assert.Equal(t, ComputeMinHash(words), ComputeMinHash(words))
+	})
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	base := strings.Fields(strings.Repeat("alpha beta gamma delta epsilon zeta eta theta iota kappa ", 20))
+
+	t.Run("identical content", func(t *testing.T) {
+		assert.Equal(t, 1.0, MinHashSimilarity(ComputeMinHash(base), ComputeMinHash(base)))
+	})
+
+	t.Run("small edit stays similar", func(t *testing.T) {
+		edited := append([]string{}, base...)
+		edited = append(edited, "lambda", "mu")
+		sim := MinHashSimilarity(ComputeMinHash(base), ComputeMinHash(edited))
+		assert.Greater(t, sim, 0.7)
+		assert.Less(t, sim, 1.0+1e-9)
+	})
+
+	t.Run("unrelated content", func(t *testing.T) {
+		other := strings.Fields(strings.Repeat("red green blue cyan magenta yellow black white gray pink ", 20))
+		assert.Less(t, MinHashSimilarity(ComputeMinHash(base), ComputeMinHash(other)), 0.2)
+	})
+
+	t.Run("mismatched or empty signatures", func(t *testing.T) {
+		assert.Equal(t, 0.0, MinHashSimilarity(nil, ComputeMinHash(base)))
+		assert.Equal(t, 0.0, MinHashSimilarity([]uint64{1}, []uint64{1, 2}))
+	})
+}
+
+func TestExtractPageSEO_PageMinHash(t *testing.T) {
+	doc, err := ParseWithDOM([]byte(`<html><body><p>one two three four five</p><script>var token = "abc";</script></body></html>`))
+	require.NoError(t, err)
+	withScript := doc.ExtractPageSEO(200, "https://example.com/")
+
+	doc, err = ParseWithDOM([]byte(`<html><body><p>one two three four five</p><script>var token = "xyz";</script></body></html>`))
+	require.NoError(t, err)
+	changedScript := doc.ExtractPageSEO(200, "https://example.com/")
+
+	require.Len(t, withScript.PageMinHash, MinHashSize)
+	assert.Equal(t, withScript.PageMinHash, changedScript.PageMinHash, "script changes must not affect content signature")
+}
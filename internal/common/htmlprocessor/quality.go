@@ -0,0 +1,71 @@
+package htmlprocessor
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// invisibleTextElements hold text that is not displayed to visitors
+var invisibleTextElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+}
+
+// HasElement reports whether any element matches selector.
+func (d *domDocument) HasElement(selector *Selector) bool {
+	var found bool
+	var search func(*html.Node)
+	search = func(n *html.Node) {
+		if found {
+			return
+		}
+		if selector.Match(n) {
+			found = true
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			search(c)
+		}
+	}
+	search(d.root)
+	return found
+}
+
+// ContainsText reports whether the visible text of the document contains text.
+// Matching is case-insensitive and ignores differences in whitespace.
+func (d *domDocument) ContainsText(text string) bool {
+	needle := normalizeText(text)
+	if needle == "" {
+		return false
+	}
+	return strings.Contains(normalizeText(visibleText(findElement(d.root, "body"))), needle)
+}
+
+// visibleText returns the text of node and its descendants, excluding scripts and styles
+func visibleText(node *html.Node) string {
+	if node == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && invisibleTextElements[n.Data] {
+			return
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(node)
+	return sb.String()
+}
+
+// normalizeText lowercases s and collapses whitespace runs to single spaces
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
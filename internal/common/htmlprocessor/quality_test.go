@@ -0,0 +1,38 @@
+package htmlprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_HasElement(t *testing.T) {
+	doc, err := ParseWithDOM([]byte(`<html><body><div id="root"><main class="product">Shoes</main></div></body></html>`))
+	require.NoError(t, err)
+
+	for selector, want := range map[string]bool{
+		"main.product":    true,
+		"#root > main":    true,
+		"#root article":   false,
+		"div.error, main": true,
+	} {
+		sel, err := CompileSelector(selector)
+		require.NoError(t, err)
+		assert.Equal(t, want, doc.HasElement(sel), selector)
+	}
+}
+
+func TestDocument_ContainsText(t *testing.T) {
+	doc, err := ParseWithDOM([]byte(`<html><head><title>Oops</title></head><body>
+<div class="error">Something
+  went   <b>WRONG</b></div>
+<script>var msg = "Please reload";</script>
+</body></html>`))
+	require.NoError(t, err)
+
+	assert.True(t, doc.ContainsText("something went wrong"), "case and whitespace are ignored")
+	assert.False(t, doc.ContainsText("please reload"), "script text is not visible")
+	assert.False(t, doc.ContainsText("oops"), "head text is not visible")
+	assert.False(t, doc.ContainsText("  "))
+}
@@ -0,0 +1,347 @@
+package htmlprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Selector is a compiled CSS selector. Supported syntax is a subset of CSS3:
+// type and universal selectors, #id, .class, attribute selectors ([a], [a=v], [a~=v],
+// [a^=v], [a$=v], [a*=v]), descendant (space) and child (>) combinators and
+// selector lists (a, b). Pseudo-classes are not supported.
+type Selector struct {
+	alternatives []complexSelector
+}
+
+// complexSelector is a chain of compound selectors joined by combinators
+type complexSelector struct {
+	compounds   []compoundSelector
+	combinators []byte // combinators[i] joins compounds[i] and compounds[i+1]: ' ' or '>'
+}
+
+// compoundSelector matches a single element
+type compoundSelector struct {
+	tag     string // lowercase, empty for any element
+	id      string
+	classes []string
+	attrs   []attrSelector
+}
+
+// attrSelector matches an attribute: op is empty for presence, or one of =, ~=, ^=, $=, *=
+type attrSelector struct {
+	name  string
+	op    string
+	value string
+}
+
+// CompileSelector parses a CSS selector
+func CompileSelector(selector string) (*Selector, error) {
+	p := &selectorParser{input: selector}
+	sel := &Selector{}
+	for {
+		complex, err := p.parseComplex()
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+		sel.alternatives = append(sel.alternatives, complex)
+		if p.eof() {
+			return sel, nil
+		}
+		// parseComplex stops at end of input or at a comma
+		p.pos++
+	}
+}
+
+// Match reports whether node matches the selector
+func (s *Selector) Match(node *html.Node) bool {
+	if node == nil || node.Type != html.ElementNode {
+		return false
+	}
+	for _, alternative := range s.alternatives {
+		if alternative.matchAt(node, len(alternative.compounds)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchAll returns all elements under root matching the selector, in document order
+func (s *Selector) MatchAll(root *html.Node) []*html.Node {
+	var results []*html.Node
+	var search func(*html.Node)
+	search = func(n *html.Node) {
+		if s.Match(n) {
+			results = append(results, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			search(c)
+		}
+	}
+	search(root)
+	return results
+}
+
+// matchAt matches compounds[0..i] right to left, starting with node for compounds[i]
+func (c complexSelector) matchAt(node *html.Node, i int) bool {
+	if !c.compounds[i].match(node) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	if c.combinators[i-1] == '>' {
+		parent := node.Parent
+		return parent != nil && parent.Type == html.ElementNode && c.matchAt(parent, i-1)
+	}
+	for ancestor := node.Parent; ancestor != nil && ancestor.Type == html.ElementNode; ancestor = ancestor.Parent {
+		if c.matchAt(ancestor, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c compoundSelector) match(node *html.Node) bool {
+	if node.Type != html.ElementNode {
+		return false
+	}
+	if c.tag != "" && strings.ToLower(node.Data) != c.tag {
+		return false
+	}
+	if c.id != "" && getAttr(node, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(getAttr(node, "class"))
+		for _, class := range c.classes {
+			if !containsString(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		if !attr.match(node) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) match(node *html.Node) bool {
+	value, ok := lookupAttr(node, a.name)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return value == a.value
+	case "~=":
+		return containsString(strings.Fields(value), a.value)
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	}
+	return false
+}
+
+// lookupAttr returns the value of an attribute (case-insensitive name) and whether it is present
+func lookupAttr(node *html.Node, name string) (string, bool) {
+	for _, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, name) {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorParser is a recursive descent parser for the supported selector syntax
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *selectorParser) peek() byte {
+	return p.input[p.pos]
+}
+
+// skipSpace skips whitespace and reports whether any was skipped
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\n\r\f", p.peek()) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	var c complexSelector
+	p.skipSpace()
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return c, err
+		}
+		c.compounds = append(c.compounds, compound)
+
+		hadSpace := p.skipSpace()
+		if p.eof() || p.peek() == ',' {
+			return c, nil
+		}
+
+		combinator := byte(' ')
+		if p.peek() == '>' {
+			combinator = '>'
+			p.pos++
+			p.skipSpace()
+		} else if !hadSpace {
+			return c, fmt.Errorf("unexpected %q at offset %d", p.peek(), p.pos)
+		}
+		c.combinators = append(c.combinators, combinator)
+	}
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	var c compoundSelector
+	start := p.pos
+
+	if !p.eof() && p.peek() == '*' {
+		p.pos++
+	} else if name := p.parseIdent(); name != "" {
+		c.tag = strings.ToLower(name)
+	}
+
+	for !p.eof() {
+		switch p.peek() {
+		case '#':
+			p.pos++
+			id := p.parseIdent()
+			if id == "" {
+				return c, fmt.Errorf("expected id at offset %d", p.pos)
+			}
+			c.id = id
+		case '.':
+			p.pos++
+			class := p.parseIdent()
+			if class == "" {
+				return c, fmt.Errorf("expected class name at offset %d", p.pos)
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			attr, err := p.parseAttr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attr)
+		case ':':
+			return c, fmt.Errorf("pseudo-classes are not supported (offset %d)", p.pos)
+		default:
+			if p.pos == start {
+				return c, fmt.Errorf("expected selector at offset %d", p.pos)
+			}
+			return c, nil
+		}
+	}
+
+	if p.pos == start {
+		return c, fmt.Errorf("expected selector at offset %d", p.pos)
+	}
+	return c, nil
+}
+
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	var a attrSelector
+	p.pos++ // '['
+	p.skipSpace()
+	a.name = strings.ToLower(p.parseIdent())
+	if a.name == "" {
+		return a, fmt.Errorf("expected attribute name at offset %d", p.pos)
+	}
+	p.skipSpace()
+	if p.eof() {
+		return a, fmt.Errorf("unterminated attribute selector")
+	}
+
+	if p.peek() != ']' {
+		for _, op := range []string{"=", "~=", "^=", "$=", "*="} {
+			if strings.HasPrefix(p.input[p.pos:], op) {
+				a.op = op
+				p.pos += len(op)
+				break
+			}
+		}
+		if a.op == "" {
+			return a, fmt.Errorf("unexpected %q at offset %d", p.peek(), p.pos)
+		}
+		p.skipSpace()
+		value, err := p.parseValue()
+		if err != nil {
+			return a, err
+		}
+		a.value = value
+		p.skipSpace()
+	}
+
+	if p.eof() || p.peek() != ']' {
+		return a, fmt.Errorf("unterminated attribute selector")
+	}
+	p.pos++
+	return a, nil
+}
+
+// parseValue parses a quoted string or an identifier
+func (p *selectorParser) parseValue() (string, error) {
+	if p.eof() {
+		return "", fmt.Errorf("expected attribute value")
+	}
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		value := p.parseIdent()
+		if value == "" {
+			return "", fmt.Errorf("expected attribute value at offset %d", p.pos)
+		}
+		return value, nil
+	}
+
+	end := strings.IndexByte(p.input[p.pos+1:], quote)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at offset %d", p.pos)
+	}
+	value := p.input[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+func (p *selectorParser) parseIdent() string {
+	start := p.pos
+	for !p.eof() {
+		ch := p.peek()
+		if ch == '-' || ch == '_' || ch >= 0x80 ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
@@ -0,0 +1,84 @@
+package htmlprocessor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+const selectorTestHTML = `<!DOCTYPE html><html><head>
+<link rel="canonical" href="https://staging.example.com/page">
+<script src="https://widget.chat.io/loader.js"></script>
+</head><body>
+<div id="cookie-banner" class="banner consent"><p>Cookies</p></div>
+<div class="content"><p class="lead">Intro</p><section><p>Nested</p></section></div>
+<img data-track="1" src="/pixel.gif">
+</body></html>`
+
+func matchedTags(t *testing.T, selector string) []string {
+	t.Helper()
+	root, err := html.Parse(strings.NewReader(selectorTestHTML))
+	require.NoError(t, err)
+	sel, err := CompileSelector(selector)
+	require.NoError(t, err)
+
+	var tags []string
+	for _, node := range sel.MatchAll(root) {
+		tags = append(tags, node.Data)
+	}
+	return tags
+}
+
+func TestSelector_Match(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"div", []string{"div", "div"}},
+		{"#cookie-banner", []string{"div"}},
+		{"div.banner.consent", []string{"div"}},
+		{".banner.missing", nil},
+		{"p.lead", []string{"p"}},
+		{"div p", []string{"p", "p", "p"}},
+		{"div > p", []string{"p", "p"}},
+		{".content > section > p", []string{"p"}},
+		{"[data-track]", []string{"img"}},
+		{`link[rel="canonical"]`, []string{"link"}},
+		{"link[rel=canonical]", []string{"link"}},
+		{`script[src*="chat.io"]`, []string{"script"}},
+		{`script[src^='https://widget']`, []string{"script"}},
+		{`script[src$=".js"]`, []string{"script"}},
+		{`div[class~="consent"]`, []string{"div"}},
+		{"#cookie-banner, img", []string{"div", "img"}},
+		{"* > section", []string{"section"}},
+		{"DIV#COOKIE-BANNER", nil}, // ids are case-sensitive
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchedTags(t, tt.selector))
+		})
+	}
+}
+
+func TestCompileSelector_Invalid(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"div,",
+		"div >",
+		"#",
+		"[href",
+		`[href="x]`,
+		"[href!=x]",
+		"a:hover",
+		"div + p",
+	} {
+		t.Run(selector, func(t *testing.T) {
+			_, err := CompileSelector(selector)
+			assert.Error(t, err)
+		})
+	}
+}
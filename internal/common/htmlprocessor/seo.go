@@ -243,6 +243,7 @@ func resolveURL(href, baseURL string) string {
 
 // extractLinkMetrics populates link metrics in the PageSEO struct.
 // Extracts from body only, handles base tag, classifies internal/external.
+// Followed internal links are kept as absolute URLs without fragment.
 func extractLinkMetrics(body *html.Node, baseHref, pageURL string, seo *types.PageSEO) {
 	if body == nil || seo == nil {
 		return
@@ -263,6 +264,7 @@ func extractLinkMetrics(body *html.Node, baseHref, pageURL string, seo *types.Pa
 
 	links := findAllElementsInParent(body, "a")
 	externalDomains := make(map[string]int)
+	internalTargets := make(map[string]struct{})
 
 	for _, link := range links {
 		href := getAttr(link, "href")
@@ -293,6 +295,15 @@ func extractLinkMetrics(body *html.Node, baseHref, pageURL string, seo *types.Pa
 
 		if isInternal {
 			seo.LinksInternal++
+			if !isNofollow && linkHost != "" && len(seo.InternalLinks) < types.MaxInternalLinks {
+				parsed.Fragment = ""
+				parsed.RawFragment = ""
+				target := parsed.String()
+				if _, seen := internalTargets[target]; !seen {
+					internalTargets[target] = struct{}{}
+					seo.InternalLinks = append(seo.InternalLinks, target)
+				}
+			}
 		} else {
 			seo.LinksExternal++
 			hostname := urlutil.ExtractHostname(linkHost)
@@ -591,6 +602,7 @@ func (d *domDocument) ExtractPageSEO(statusCode int, pageURL string) *types.Page
 	words := extractBodyWords(body)
 	if len(words) > 0 {
 		seo.WordCount = len(words)
+		seo.PageMinHash = ComputeMinHash(words)
 	}
 
 	// International SEO
@@ -599,6 +611,7 @@ func (d *domDocument) ExtractPageSEO(statusCode int, pageURL string) *types.Page
 
 	// Structured data
 	seo.StructuredDataTypes = extractStructuredDataTypes(d.root)
+	seo.StructuredDataFormats, seo.StructuredDataIssues = extractStructuredData(d.root)
 
 	return seo
 }
@@ -998,6 +998,24 @@ func TestLinkMetricsWithBaseTag(t *testing.T) {
 	assert.Equal(t, 1, seo.LinksInternal)
 }
 
+func TestLinkMetricsInternalLinks(t *testing.T) {
+	html := `<html><body>
+		<a href="/a">A</a>
+		<a href="/a#reviews">A again</a>
+		<a href="https://example.com/b?x=1">B</a>
+		<a href="/c" rel="nofollow">Nofollow</a>
+		<a href="https://other.com/d">External</a>
+		<a href="#top">Fragment</a>
+	</body></html>`
+	body := parseAndFindBody(t, html)
+	seo := &types.PageSEO{}
+
+	extractLinkMetrics(body, "", "https://example.com/page", seo)
+
+	assert.Equal(t, 4, seo.LinksInternal)
+	assert.Equal(t, []string{"https://example.com/a", "https://example.com/b?x=1"}, seo.InternalLinks)
+}
+
 // Helper to parse full HTML document
 func parseDocument(t *testing.T, htmlStr string) *html.Node {
 	t.Helper()
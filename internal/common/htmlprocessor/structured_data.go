@@ -0,0 +1,199 @@
+package htmlprocessor
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+var articleRequirements = []string{"headline", "author", "datePublished", "image"}
+
+// structuredDataRequirements lists the properties an item of a schema.org type needs to be
+// eligible for rich results. An entry with alternatives joined by "|" is satisfied by any of them.
+// Types not listed are not validated.
+var structuredDataRequirements = map[string][]string{
+	"Product":         {"name", "offers|review|aggregateRating"},
+	"Offer":           {"price|priceSpecification"},
+	"AggregateOffer":  {"lowPrice", "priceCurrency"},
+	"AggregateRating": {"ratingValue", "ratingCount|reviewCount"},
+	"Review":          {"author", "reviewRating"},
+	"Article":         articleRequirements,
+	"NewsArticle":     articleRequirements,
+	"BlogPosting":     articleRequirements,
+	"BreadcrumbList":  {"itemListElement"},
+	"ListItem":        {"position", "name|item"},
+	"FAQPage":         {"mainEntity"},
+	"Question":        {"name", "acceptedAnswer|suggestedAnswer"},
+	"Answer":          {"text"},
+	"Event":           {"name", "startDate", "location"},
+	"Recipe":          {"name", "image"},
+	"JobPosting":      {"title", "description", "datePosted", "hiringOrganization"},
+	"VideoObject":     {"name", "thumbnailUrl", "uploadDate"},
+	"LocalBusiness":   {"name", "address"},
+	"Organization":    {"name"},
+}
+
+// extractStructuredData detects the structured data formats of the page and validates its
+// JSON-LD blocks: parse errors and missing required properties of common schema.org types.
+// Issues are deduplicated by type and property.
+func extractStructuredData(root *html.Node) ([]string, []types.StructuredDataIssue) {
+	if root == nil {
+		return nil, nil
+	}
+
+	var hasJSONLD, hasMicrodata, hasRDFa bool
+	v := &structuredDataValidator{seen: make(map[types.StructuredDataIssue]struct{})}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			for _, attr := range n.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "itemscope":
+					hasMicrodata = true
+				case "typeof", "vocab":
+					hasRDFa = true
+				}
+			}
+			if n.Data == "script" && strings.ToLower(strings.TrimSpace(getAttr(n, "type"))) == "application/ld+json" {
+				hasJSONLD = true
+				v.validateBlock(getTextContent(n))
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	var formats []string
+	if hasJSONLD {
+		formats = append(formats, types.StructuredDataFormatJSONLD)
+	}
+	if hasMicrodata {
+		formats = append(formats, types.StructuredDataFormatMicrodata)
+	}
+	if hasRDFa {
+		formats = append(formats, types.StructuredDataFormatRDFa)
+	}
+	return formats, v.issues
+}
+
+// structuredDataValidator collects the issues of all JSON-LD blocks of a page
+type structuredDataValidator struct {
+	issues []types.StructuredDataIssue
+	seen   map[types.StructuredDataIssue]struct{}
+}
+
+func (v *structuredDataValidator) add(issue types.StructuredDataIssue) {
+	if len(v.issues) >= types.MaxStructuredDataIssues {
+		return
+	}
+	if _, ok := v.seen[issue]; ok {
+		return
+	}
+	v.seen[issue] = struct{}{}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *structuredDataValidator) validateBlock(content string) {
+	if len(content) > types.MaxJSONLDSize {
+		// Skip oversized JSON-LD blocks
+		return
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &data); err != nil {
+		v.add(types.StructuredDataIssue{Issue: types.StructuredDataIssueParseError})
+		return
+	}
+	v.validateValue(data, 0)
+}
+
+// validateValue checks every typed item in a JSON-LD value, including nested items and @graph
+func (v *structuredDataValidator) validateValue(value interface{}, depth int) {
+	if depth > types.MaxJSONLDRecursionDepth {
+		return
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		for _, schemaType := range itemTypes(val["@type"]) {
+			for _, required := range structuredDataRequirements[schemaType] {
+				if !hasAnyProperty(val, required) {
+					v.add(types.StructuredDataIssue{
+						Issue:    types.StructuredDataIssueMissingProperty,
+						Type:     schemaType,
+						Property: required,
+					})
+				}
+			}
+		}
+		// Sorted keys keep the issue order deterministic
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v.validateValue(val[key], depth+1)
+		}
+	case []interface{}:
+		for _, item := range val {
+			v.validateValue(item, depth+1)
+		}
+	}
+}
+
+// itemTypes returns the schema.org type names of an @type value, without vocabulary prefix
+// ("https://schema.org/Product" and "schema:Product" are "Product")
+func itemTypes(value interface{}) []string {
+	var raw []string
+	switch val := value.(type) {
+	case string:
+		raw = []string{val}
+	case []interface{}:
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(raw))
+	for _, t := range raw {
+		if i := strings.LastIndexAny(t, "/:"); i >= 0 {
+			t = t[i+1:]
+		}
+		if t != "" {
+			names = append(names, t)
+		}
+	}
+	return names
+}
+
+// hasAnyProperty reports whether the item has a non-empty value for one of the "|"-separated properties
+func hasAnyProperty(item map[string]interface{}, properties string) bool {
+	for _, property := range strings.Split(properties, "|") {
+		switch val := item[property].(type) {
+		case nil:
+			continue
+		case string:
+			if strings.TrimSpace(val) != "" {
+				return true
+			}
+		case []interface{}:
+			if len(val) > 0 {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package htmlprocessor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func missing(schemaType, property string) types.StructuredDataIssue {
+	return types.StructuredDataIssue{Issue: types.StructuredDataIssueMissingProperty, Type: schemaType, Property: property}
+}
+
+func TestExtractStructuredData(t *testing.T) {
+	tests := []struct {
+		name    string
+		html    string
+		formats []string
+		issues  []types.StructuredDataIssue
+	}{
+		{
+			name: "no structured data",
+			html: `<html><head><meta property="og:title" content="Page"></head><body><p>Text</p></body></html>`,
+		},
+		{
+			name: "complete product",
+			html: `<html><head><script type="application/ld+json">{
+				"@context": "https://schema.org", "@type": "Product", "name": "Shoe",
+				"offers": {"@type": "Offer", "price": "49.00", "priceCurrency": "EUR"}
+			}</script></head></html>`,
+			formats: []string{types.StructuredDataFormatJSONLD},
+		},
+		{
+			name: "incomplete product and nested offer",
+			html: `<html><head><script type="application/ld+json">{
+				"@type": "https://schema.org/Product", "name": " ",
+				"offers": [{"@type": "Offer", "priceCurrency": "EUR"}]
+			}</script></head></html>`,
+			formats: []string{types.StructuredDataFormatJSONLD},
+			issues:  []types.StructuredDataIssue{missing("Product", "name"), missing("Offer", "price|priceSpecification")},
+		},
+		{
+			name: "breadcrumbs and faq in @graph",
+			html: `<html><head><script type="application/ld+json">{"@graph": [
+				{"@type": "BreadcrumbList", "itemListElement": [
+					{"@type": "ListItem", "position": 1, "name": "Home", "item": "https://example.com/"},
+					{"@type": "ListItem", "name": "Shoes"},
+					{"@type": "ListItem", "name": "Boots"}
+				]},
+				{"@type": "FAQPage", "mainEntity": [{"@type": "Question", "name": "Sizes?", "acceptedAnswer": {"@type": "Answer"}}]}
+			]}</script></head></html>`,
+			formats: []string{types.StructuredDataFormatJSONLD},
+			issues:  []types.StructuredDataIssue{missing("ListItem", "position"), missing("Answer", "text")},
+		},
+		{
+			name: "parse error",
+			html: `<html><head>
+				<script type="application/ld+json">{"@type": "Article", "headline": "News",}</script>
+				<script type="application/ld+json">{"@type": "Article", "headline": "News", "author": "A", "datePublished": "2025-01-01", "image": "a.jpg"}</script>
+			</head></html>`,
+			formats: []string{types.StructuredDataFormatJSONLD},
+			issues:  []types.StructuredDataIssue{{Issue: types.StructuredDataIssueParseError}},
+		},
+		{
+			name: "microdata and rdfa",
+			html: `<html><body>
+				<div itemscope itemtype="https://schema.org/Product"><span itemprop="name">Shoe</span></div>
+				<div vocab="https://schema.org/" typeof="Person"><span property="name">Ann</span></div>
+			</body></html>`,
+			formats: []string{types.StructuredDataFormatMicrodata, types.StructuredDataFormatRDFa},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := html.Parse(strings.NewReader(tt.html))
+			require.NoError(t, err)
+
+			formats, issues := extractStructuredData(root)
+			assert.Equal(t, tt.formats, formats)
+			assert.Equal(t, tt.issues, issues)
+		})
+	}
+}
+
+func TestExtractStructuredData_IssueLimit(t *testing.T) {
+	// Empty items of these types have more missing properties than the limit
+	schemaTypes := []string{"Product", "Article", "JobPosting", "Event", "VideoObject", "Review", "AggregateRating", "Recipe", "LocalBusiness"}
+
+	var sb strings.Builder
+	sb.WriteString(`<html><head><script type="application/ld+json">[`)
+	for i, schemaType := range schemaTypes {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(`{"@type": "` + schemaType + `"}`)
+	}
+	sb.WriteString(`]</script></head></html>`)
+
+	root, err := html.Parse(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+
+	_, issues := extractStructuredData(root)
+	assert.Len(t, issues, types.MaxStructuredDataIssues)
+}
@@ -0,0 +1,299 @@
+package htmlprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgecomet/engine/pkg/types"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// urlAttributes are the attributes rewritten by rewrite_urls
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"poster":     true,
+	"cite":       true,
+	"content":    true,
+	"data-src":   true,
+}
+
+// srcsetAttributes hold comma-separated URL candidates
+var srcsetAttributes = map[string]bool{
+	"srcset":      true,
+	"data-srcset": true,
+}
+
+// whitespaceSensitiveElements keep their text content as-is when minifying
+var whitespaceSensitiveElements = map[string]bool{
+	"pre":      true,
+	"textarea": true,
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+}
+
+// ValidateTransform checks that a transform is complete and its selector compiles
+func ValidateTransform(t types.HTMLTransform) error {
+	switch t.Type {
+	case types.HTMLTransformRemove:
+		if t.Selector == "" {
+			return fmt.Errorf("selector is required")
+		}
+	case types.HTMLTransformSetAttribute:
+		if t.Selector == "" || t.Attribute == "" {
+			return fmt.Errorf("selector and attribute are required")
+		}
+	case types.HTMLTransformRemoveAttribute:
+		if t.Selector == "" || t.Attribute == "" {
+			return fmt.Errorf("selector and attribute are required")
+		}
+	case types.HTMLTransformInjectHead:
+		if strings.TrimSpace(t.HTML) == "" {
+			return fmt.Errorf("html is required")
+		}
+	case types.HTMLTransformRewriteURLs:
+		if t.From == "" {
+			return fmt.Errorf("from is required")
+		}
+		if t.From == t.To {
+			return fmt.Errorf("from and to must differ")
+		}
+	case types.HTMLTransformMinify:
+	default:
+		return fmt.Errorf("unknown transform type '%s', must be one of: %s, %s, %s, %s, %s, %s", t.Type,
+			types.HTMLTransformRemove, types.HTMLTransformSetAttribute, types.HTMLTransformRemoveAttribute,
+			types.HTMLTransformInjectHead, types.HTMLTransformRewriteURLs, types.HTMLTransformMinify)
+	}
+
+	if t.Selector != "" {
+		if _, err := CompileSelector(t.Selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *domDocument) ApplyTransforms(transforms []types.HTMLTransform, pageURL string) (int, error) {
+	changed := 0
+	for i, t := range transforms {
+		if err := ValidateTransform(t); err != nil {
+			return changed, fmt.Errorf("transform %d (%s): %w", i, t.Type, err)
+		}
+
+		var selector *Selector
+		if t.Selector != "" {
+			// Compiled by ValidateTransform
+			selector, _ = CompileSelector(t.Selector)
+		}
+
+		var modified bool
+		switch t.Type {
+		case types.HTMLTransformRemove:
+			modified = removeNodes(selector.MatchAll(d.root))
+		case types.HTMLTransformSetAttribute:
+			value := strings.ReplaceAll(t.Value, types.HTMLTransformURLPlaceholder, pageURL)
+			for _, node := range selector.MatchAll(d.root) {
+				modified = setAttr(node, t.Attribute, value) || modified
+			}
+		case types.HTMLTransformRemoveAttribute:
+			for _, node := range selector.MatchAll(d.root) {
+				modified = removeAttr(node, t.Attribute) || modified
+			}
+		case types.HTMLTransformInjectHead:
+			var err error
+			modified, err = d.injectHead(selector, strings.ReplaceAll(t.HTML, types.HTMLTransformURLPlaceholder, pageURL))
+			if err != nil {
+				return changed, fmt.Errorf("transform %d (%s): %w", i, t.Type, err)
+			}
+		case types.HTMLTransformRewriteURLs:
+			modified = d.rewriteURLs(selector, t.From, t.To)
+		case types.HTMLTransformMinify:
+			modified = minifyNode(d.root)
+		}
+
+		if modified {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// removeNodes detaches nodes from the tree. Returns true if any were removed.
+func removeNodes(nodes []*html.Node) bool {
+	for _, node := range nodes {
+		if node.Parent != nil {
+			node.Parent.RemoveChild(node)
+		}
+	}
+	return len(nodes) > 0
+}
+
+// setAttr sets an attribute, replacing an existing one (case-insensitive name).
+// Returns true if the node changed.
+func setAttr(node *html.Node, name, value string) bool {
+	for i, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, name) {
+			if attr.Val == value {
+				return false
+			}
+			node.Attr[i].Val = value
+			return true
+		}
+	}
+	node.Attr = append(node.Attr, html.Attribute{Key: strings.ToLower(name), Val: value})
+	return true
+}
+
+// removeAttr removes an attribute (case-insensitive name). Returns true if it was present.
+func removeAttr(node *html.Node, name string) bool {
+	for i, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, name) {
+			node.Attr = append(node.Attr[:i], node.Attr[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// injectHead removes elements matching replace (if set) and appends snippet to <head>
+func (d *domDocument) injectHead(replace *Selector, snippet string) (bool, error) {
+	head := findElement(d.root, "head")
+	if head == nil {
+		return false, fmt.Errorf("document has no <head>")
+	}
+
+	context := &html.Node{Type: html.ElementNode, Data: "head", DataAtom: atom.Head}
+	nodes, err := html.ParseFragment(strings.NewReader(snippet), context)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	if replace != nil {
+		removeNodes(replace.MatchAll(d.root))
+	}
+	for _, node := range nodes {
+		head.AppendChild(node)
+	}
+	return len(nodes) > 0, nil
+}
+
+// rewriteURLs replaces the from prefix with to in URL attributes of elements matching
+// selector (all elements if nil) and in JSON-LD scripts
+func (d *domDocument) rewriteURLs(selector *Selector, from, to string) bool {
+	modified := false
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (selector == nil || selector.Match(n)) {
+			for i, attr := range n.Attr {
+				key := strings.ToLower(attr.Key)
+				var rewritten string
+				switch {
+				case urlAttributes[key]:
+					if !strings.HasPrefix(attr.Val, from) {
+						continue
+					}
+					rewritten = to + attr.Val[len(from):]
+				case srcsetAttributes[key]:
+					rewritten = rewriteSrcset(attr.Val, from, to)
+				default:
+					continue
+				}
+				if rewritten != attr.Val {
+					n.Attr[i].Val = rewritten
+					modified = true
+				}
+			}
+
+			if isJSONLDScript(n) && n.FirstChild != nil && n.FirstChild.Type == html.TextNode {
+				if text := n.FirstChild.Data; strings.Contains(text, from) {
+					n.FirstChild.Data = strings.ReplaceAll(text, from, to)
+					modified = true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(d.root)
+	return modified
+}
+
+// rewriteSrcset replaces the from prefix of each srcset candidate URL
+func rewriteSrcset(srcset, from, to string) string {
+	candidates := strings.Split(srcset, ",")
+	for i, candidate := range candidates {
+		trimmed := strings.TrimLeft(candidate, " \t\n")
+		if strings.HasPrefix(trimmed, from) {
+			candidates[i] = candidate[:len(candidate)-len(trimmed)] + to + trimmed[len(from):]
+		}
+	}
+	return strings.Join(candidates, ",")
+}
+
+// isJSONLDScript checks if a node is a <script type="application/ld+json"> element
+func isJSONLDScript(node *html.Node) bool {
+	return strings.ToLower(node.Data) == "script" &&
+		strings.ToLower(strings.TrimSpace(getAttr(node, "type"))) == "application/ld+json"
+}
+
+// minifyNode removes comments (except conditional comments) and collapses whitespace in
+// text outside whitespace-sensitive elements. Whitespace-only text directly in <html> or
+// <head> is dropped. Returns true if anything changed.
+func minifyNode(node *html.Node) bool {
+	modified := false
+	var toRemove []*html.Node
+
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case html.CommentNode:
+			if !strings.HasPrefix(strings.TrimSpace(c.Data), "[if") {
+				toRemove = append(toRemove, c)
+			}
+		case html.TextNode:
+			collapsed := squeezeWhitespace(c.Data)
+			if collapsed == " " && node.Type == html.ElementNode &&
+				(node.DataAtom == atom.Html || node.DataAtom == atom.Head) {
+				toRemove = append(toRemove, c)
+			} else if collapsed != c.Data {
+				c.Data = collapsed
+				modified = true
+			}
+		case html.ElementNode:
+			if !whitespaceSensitiveElements[strings.ToLower(c.Data)] {
+				modified = minifyNode(c) || modified
+			}
+		default:
+			modified = minifyNode(c) || modified
+		}
+	}
+
+	for _, c := range toRemove {
+		node.RemoveChild(c)
+	}
+	return modified || len(toRemove) > 0
+}
+
+// squeezeWhitespace replaces each run of whitespace with a single space, keeping a leading or
+// trailing space (significant between inline elements)
+func squeezeWhitespace(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	inSpace := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !inSpace {
+				sb.WriteByte(' ')
+				inSpace = true
+			}
+			continue
+		}
+		inSpace = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,115 @@
+package htmlprocessor
+
+import (
+	"testing"
+
+	"github.com/edgecomet/engine/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyTransforms(t *testing.T, input string, transforms ...types.HTMLTransform) (string, int) {
+	t.Helper()
+	doc, err := ParseWithDOM([]byte(input))
+	require.NoError(t, err)
+	changed, err := doc.ApplyTransforms(transforms, "https://example.com/page?id=1")
+	require.NoError(t, err)
+	return string(doc.HTML()), changed
+}
+
+func TestApplyTransforms_Remove(t *testing.T) {
+	out, changed := applyTransforms(t,
+		`<html><head></head><body><div id="cookie-banner">Cookies</div><p>Text</p><noscript><img src="/px"></noscript></body></html>`,
+		types.HTMLTransform{Type: types.HTMLTransformRemove, Selector: "#cookie-banner, noscript"},
+		types.HTMLTransform{Type: types.HTMLTransformRemove, Selector: ".absent"},
+	)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, `<html><head></head><body><p>Text</p></body></html>`, out)
+}
+
+func TestApplyTransforms_Attributes(t *testing.T) {
+	out, changed := applyTransforms(t,
+		`<html><head><link rel="canonical" href="https://staging.example.com/x"></head><body><a href="/a" onclick="track()">A</a></body></html>`,
+		types.HTMLTransform{Type: types.HTMLTransformSetAttribute, Selector: `link[rel="canonical"]`, Attribute: "href", Value: "{url}"},
+		types.HTMLTransform{Type: types.HTMLTransformRemoveAttribute, Selector: "a", Attribute: "onclick"},
+		types.HTMLTransform{Type: types.HTMLTransformSetAttribute, Selector: "a", Attribute: "rel", Value: "nofollow"},
+	)
+	assert.Equal(t, 3, changed)
+	assert.Equal(t, `<html><head><link rel="canonical" href="https://example.com/page?id=1"/></head><body><a href="/a" rel="nofollow">A</a></body></html>`, out)
+}
+
+func TestApplyTransforms_InjectHead(t *testing.T) {
+	out, changed := applyTransforms(t,
+		`<html><head><title>T</title><link rel="canonical" href="/old"></head><body></body></html>`,
+		types.HTMLTransform{
+			Type:     types.HTMLTransformInjectHead,
+			Selector: `link[rel="canonical"]`,
+			HTML:     `<link rel="canonical" href="{url}"><link rel="alternate" hreflang="de" href="https://example.de/">`,
+		},
+	)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, `<html><head><title>T</title><link rel="canonical" href="https://example.com/page?id=1"/><link rel="alternate" hreflang="de" href="https://example.de/"/></head><body></body></html>`, out)
+}
+
+func TestApplyTransforms_RewriteURLs(t *testing.T) {
+	out, changed := applyTransforms(t,
+		`<html><head><meta property="og:url" content="https://staging.example.com/p">`+
+			`<script type="application/ld+json">{"url":"https://staging.example.com/p"}</script></head>`+
+			`<body><a href="https://staging.example.com/a">A</a><a href="https://other.com/staging.example.com">B</a>`+
+			`<img srcset="https://staging.example.com/1x.png 1x, https://staging.example.com/2x.png 2x"></body></html>`,
+		types.HTMLTransform{Type: types.HTMLTransformRewriteURLs, From: "https://staging.example.com", To: "https://www.example.com"},
+	)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, `<html><head><meta property="og:url" content="https://www.example.com/p"/>`+
+		`<script type="application/ld+json">{"url":"https://www.example.com/p"}</script></head>`+
+		`<body><a href="https://www.example.com/a">A</a><a href="https://other.com/staging.example.com">B</a>`+
+		`<img srcset="https://www.example.com/1x.png 1x, https://www.example.com/2x.png 2x"/></body></html>`, out)
+
+	// Selector limits the rewritten elements
+	out, _ = applyTransforms(t,
+		`<html><head></head><body><a href="https://staging.example.com/a">A</a><img src="https://staging.example.com/i.png"></body></html>`,
+		types.HTMLTransform{Type: types.HTMLTransformRewriteURLs, Selector: "a", From: "https://staging.example.com", To: ""},
+	)
+	assert.Equal(t, `<html><head></head><body><a href="/a">A</a><img src="https://staging.example.com/i.png"/></body></html>`, out)
+}
+
+func TestApplyTransforms_Minify(t *testing.T) {
+	out, changed := applyTransforms(t, "<html>\n<head>\n  <title>T</title>\n  <!-- build 123 -->\n</head>\n"+
+		"<body>\n  <p>Hello   <b>big</b>\n  world</p>\n  <pre>  keep\n  this </pre>\n<!--[if IE]>ie<![endif]-->\n</body>\n</html>",
+		types.HTMLTransform{Type: types.HTMLTransformMinify},
+	)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "<html><head><title>T</title></head><body> <p>Hello <b>big</b> world</p> <pre>  keep\n  this </pre> <!--[if IE]>ie<![endif]--> </body></html>", out)
+
+	_, changed = applyTransforms(t, out, types.HTMLTransform{Type: types.HTMLTransformMinify})
+	assert.Equal(t, 0, changed, "already minified")
+}
+
+func TestValidateTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform types.HTMLTransform
+		wantErr   string
+	}{
+		{name: "remove", transform: types.HTMLTransform{Type: types.HTMLTransformRemove, Selector: ".chat"}},
+		{name: "minify", transform: types.HTMLTransform{Type: types.HTMLTransformMinify}},
+		{name: "unknown type", transform: types.HTMLTransform{Type: "replace"}, wantErr: "unknown transform type"},
+		{name: "remove without selector", transform: types.HTMLTransform{Type: types.HTMLTransformRemove}, wantErr: "selector is required"},
+		{name: "set attribute without attribute", transform: types.HTMLTransform{Type: types.HTMLTransformSetAttribute, Selector: "a"}, wantErr: "attribute are required"},
+		{name: "inject without html", transform: types.HTMLTransform{Type: types.HTMLTransformInjectHead}, wantErr: "html is required"},
+		{name: "rewrite without from", transform: types.HTMLTransform{Type: types.HTMLTransformRewriteURLs, To: "x"}, wantErr: "from is required"},
+		{name: "invalid selector", transform: types.HTMLTransform{Type: types.HTMLTransformRemove, Selector: "a:hover"}, wantErr: "pseudo-classes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTransform(tt.transform)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package internalauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+)
+
+// HeaderName is the request header carrying the caller key
+const HeaderName = "X-Internal-Auth"
+
+// Scopes granted to internal API callers
+const (
+	ScopeAll        = configtypes.InternalScopeAll
+	ScopePeer       = configtypes.InternalScopePeer
+	ScopeRecache    = configtypes.InternalScopeRecache
+	ScopeInvalidate = configtypes.InternalScopeInvalidate
+	ScopeRead       = configtypes.InternalScopeRead
+	ScopeScheduler  = configtypes.InternalScopeScheduler
+	ScopeDebug      = configtypes.InternalScopeDebug
+)
+
+// LegacyIdentityName identifies callers authenticated with the shared auth_key
+const LegacyIdentityName = "legacy"
+
+// Identity is an authenticated internal API caller
+type Identity struct {
+	Name   string
+	scopes map[string]bool
+}
+
+// Allows reports whether the identity was granted scope
+func (i *Identity) Allows(scope string) bool {
+	return i.scopes[ScopeAll] || i.scopes[scope]
+}
+
+func newIdentity(name string, scopes []string) *Identity {
+	identity := &Identity{Name: name, scopes: make(map[string]bool, len(scopes))}
+	for _, scope := range scopes {
+		identity.scopes[scope] = true
+	}
+	return identity
+}
+
+type keyCredential struct {
+	key      []byte
+	identity *Identity
+}
+
+// Authenticator resolves callers of internal APIs by X-Internal-Auth key or
+// verified client certificate and checks their scopes
+type Authenticator struct {
+	keys  []keyCredential
+	certs map[string]*Identity // cert_cn -> identity
+}
+
+// NewAuthenticator creates an authenticator. legacyKey, when set, grants all scopes.
+func NewAuthenticator(legacyKey string, credentials []configtypes.InternalCredential) *Authenticator {
+	a := &Authenticator{certs: make(map[string]*Identity)}
+
+	if legacyKey != "" {
+		a.keys = append(a.keys, keyCredential{
+			key:      []byte(legacyKey),
+			identity: newIdentity(LegacyIdentityName, []string{ScopeAll}),
+		})
+	}
+
+	for _, cred := range credentials {
+		identity := newIdentity(cred.Name, cred.Scopes)
+		if cred.Key != "" {
+			a.keys = append(a.keys, keyCredential{key: []byte(cred.Key), identity: identity})
+		}
+		if cred.CertCN != "" {
+			a.certs[cred.CertCN] = identity
+		}
+	}
+
+	return a
+}
+
+// Enabled reports whether any credential is configured
+func (a *Authenticator) Enabled() bool {
+	return len(a.keys) > 0 || len(a.certs) > 0
+}
+
+// Identify returns the caller identity, or nil when the request carries no valid credential.
+// A verified client certificate takes precedence over the header key.
+func (a *Authenticator) Identify(ctx *fasthttp.RequestCtx) *Identity {
+	if identity := a.identifyCert(ctx); identity != nil {
+		return identity
+	}
+
+	header := ctx.Request.Header.Peek(HeaderName)
+	if len(header) == 0 {
+		return nil
+	}
+
+	// Compare against every key so timing does not reveal which one matched
+	var matched *Identity
+	for _, cred := range a.keys {
+		if subtle.ConstantTimeCompare(header, cred.key) == 1 && matched == nil {
+			matched = cred.identity
+		}
+	}
+	return matched
+}
+
+// identifyCert matches the verified client certificate CN or DNS SANs against cert_cn credentials
+func (a *Authenticator) identifyCert(ctx *fasthttp.RequestCtx) *Identity {
+	if len(a.certs) == 0 || !ctx.IsTLS() {
+		return nil
+	}
+
+	state := ctx.TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return nil
+	}
+
+	leaf := state.VerifiedChains[0][0]
+	if identity, ok := a.certs[leaf.Subject.CommonName]; ok {
+		return identity
+	}
+	for _, name := range leaf.DNSNames {
+		if identity, ok := a.certs[name]; ok {
+			return identity
+		}
+	}
+	return nil
+}
+
+// Authorize identifies the caller and checks scope. On failure it returns the
+// HTTP status to respond with: 401 for unknown callers, 403 for missing scope.
+func (a *Authenticator) Authorize(ctx *fasthttp.RequestCtx, scope string) (*Identity, int, error) {
+	identity := a.Identify(ctx)
+	if identity == nil {
+		if len(ctx.Request.Header.Peek(HeaderName)) == 0 {
+			return nil, fasthttp.StatusUnauthorized, fmt.Errorf("missing %s header", HeaderName)
+		}
+		return nil, fasthttp.StatusUnauthorized, fmt.Errorf("invalid %s header", HeaderName)
+	}
+
+	if !identity.Allows(scope) {
+		return identity, fasthttp.StatusForbidden, fmt.Errorf("caller %q lacks scope %q", identity.Name, scope)
+	}
+
+	return identity, fasthttp.StatusOK, nil
+}
@@ -0,0 +1,76 @@
+package internalauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+)
+
+func requestWithKey(key string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	if key != "" {
+		ctx.Request.Header.Set(HeaderName, key)
+	}
+	return ctx
+}
+
+func TestAuthenticator_Authorize(t *testing.T) {
+	auth := NewAuthenticator("legacy-shared-key", []configtypes.InternalCredential{
+		{Name: "daemon", Key: "daemon-key-0123456789", Scopes: []string{ScopeRecache}},
+		{Name: "ops", Key: "ops-key-0123456789", Scopes: []string{ScopeRead, ScopeDebug}},
+	})
+
+	tests := []struct {
+		name     string
+		key      string
+		scope    string
+		caller   string
+		expected int
+	}{
+		{"legacy key has all scopes", "legacy-shared-key", ScopePeer, LegacyIdentityName, fasthttp.StatusOK},
+		{"scoped key allowed", "daemon-key-0123456789", ScopeRecache, "daemon", fasthttp.StatusOK},
+		{"scoped key forbidden", "daemon-key-0123456789", ScopePeer, "daemon", fasthttp.StatusForbidden},
+		{"second scope allowed", "ops-key-0123456789", ScopeDebug, "ops", fasthttp.StatusOK},
+		{"unknown key", "nope", ScopeRead, "", fasthttp.StatusUnauthorized},
+		{"missing header", "", ScopeRead, "", fasthttp.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, status, err := auth.Authorize(requestWithKey(tt.key), tt.scope)
+			assert.Equal(t, tt.expected, status)
+			assert.Equal(t, tt.expected == fasthttp.StatusOK, err == nil)
+			if tt.caller == "" {
+				assert.Nil(t, identity)
+			} else {
+				assert.Equal(t, tt.caller, identity.Name)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_NoLegacyKey(t *testing.T) {
+	auth := NewAuthenticator("", nil)
+	assert.False(t, auth.Enabled())
+
+	// An empty header must never match an unset key
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(HeaderName, "")
+	_, status, err := auth.Authorize(ctx, ScopeRead)
+	assert.Error(t, err)
+	assert.Equal(t, fasthttp.StatusUnauthorized, status)
+}
+
+func TestAuthenticator_CertIdentityIgnoredWithoutTLS(t *testing.T) {
+	auth := NewAuthenticator("", []configtypes.InternalCredential{
+		{Name: "peer", CertCN: "eg-01.internal", Scopes: []string{ScopePeer}},
+	})
+	assert.True(t, auth.Enabled())
+
+	_, status, err := auth.Authorize(requestWithKey(""), ScopePeer)
+	assert.Error(t, err)
+	assert.Equal(t, fasthttp.StatusUnauthorized, status)
+}
@@ -0,0 +1,104 @@
+package internalauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+)
+
+// ServerTLSConfig builds the TLS config for an internal listener. When ca_file is set,
+// client certificates are verified against it (required unless client_auth is "optional").
+// Relative paths are resolved against baseDir. Returns nil when TLS is disabled.
+func ServerTLSConfig(cfg *configtypes.InternalTLSConfig, baseDir string) (*tls.Config, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(resolvePath(cfg.CertFile, baseDir), resolvePath(cfg.KeyFile, baseDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load internal TLS certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if cfg.CAFile != "" {
+		pool, err := loadCertPool(resolvePath(cfg.CAFile, baseDir))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		if cfg.ClientAuth == configtypes.InternalClientAuthOptional {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// ClientTLSConfig builds the TLS config for outgoing internal calls. The certificate is
+// presented as client certificate and servers are verified against ca_file (system roots
+// when unset). Relative paths are resolved against baseDir. Returns nil when TLS is disabled.
+func ClientTLSConfig(cfg *configtypes.InternalTLSConfig, baseDir string) (*tls.Config, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ServerName: cfg.ServerName,
+	}
+
+	if cfg.CertFile != "" && cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(resolvePath(cfg.CertFile, baseDir), resolvePath(cfg.KeyFile, baseDir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load internal TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.CAFile != "" {
+		pool, err := loadCertPool(resolvePath(cfg.CAFile, baseDir))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// Scheme returns the URL scheme for internal calls
+func Scheme(cfg *configtypes.InternalTLSConfig) string {
+	if cfg != nil && cfg.Enabled {
+		return "https"
+	}
+	return "http"
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read internal TLS CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+	}
+	return pool, nil
+}
+
+func resolvePath(path, baseDir string) string {
+	if path == "" || filepath.IsAbs(path) || baseDir == "" {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
@@ -0,0 +1,208 @@
+package internalauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+)
+
+// testPKI is a throwaway CA with certificates written to dir
+type testPKI struct {
+	dir    string
+	caCert *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	serial int64
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	p := &testPKI{dir: t.TempDir(), caCert: cert, caKey: key, serial: 1}
+	writePEM(t, filepath.Join(p.dir, "ca.pem"), "CERTIFICATE", der)
+	return p
+}
+
+// issue writes name.pem and name-key.pem signed by the CA
+func (p *testPKI) issue(t *testing.T, name string, dnsNames ...string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(p.serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     dnsNames,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.caCert, &key.PublicKey, p.caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	writePEM(t, filepath.Join(p.dir, name+".pem"), "CERTIFICATE", der)
+	writePEM(t, filepath.Join(p.dir, name+"-key.pem"), "EC PRIVATE KEY", keyDER)
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
+
+func (p *testPKI) config(name string) *configtypes.InternalTLSConfig {
+	return &configtypes.InternalTLSConfig{
+		Enabled:  true,
+		CertFile: name + ".pem",
+		KeyFile:  name + "-key.pem",
+		CAFile:   "ca.pem",
+	}
+}
+
+func TestTLSConfig_Disabled(t *testing.T) {
+	serverCfg, err := ServerTLSConfig(&configtypes.InternalTLSConfig{}, "")
+	require.NoError(t, err)
+	assert.Nil(t, serverCfg)
+
+	clientCfg, err := ClientTLSConfig(nil, "")
+	require.NoError(t, err)
+	assert.Nil(t, clientCfg)
+
+	assert.Equal(t, "http", Scheme(nil))
+	assert.Equal(t, "https", Scheme(&configtypes.InternalTLSConfig{Enabled: true}))
+}
+
+func TestServerTLSConfig_ClientAuth(t *testing.T) {
+	pki := newTestPKI(t)
+	pki.issue(t, "eg-01")
+
+	cfg := pki.config("eg-01")
+	serverCfg, err := ServerTLSConfig(cfg, pki.dir)
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, serverCfg.ClientAuth)
+
+	cfg.ClientAuth = configtypes.InternalClientAuthOptional
+	serverCfg, err = ServerTLSConfig(cfg, pki.dir)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, serverCfg.ClientAuth)
+
+	cfg.CAFile = ""
+	serverCfg, err = ServerTLSConfig(cfg, pki.dir)
+	require.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, serverCfg.ClientAuth)
+
+	cfg.CertFile = "missing.pem"
+	_, err = ServerTLSConfig(cfg, pki.dir)
+	assert.Error(t, err)
+}
+
+func TestMutualTLS_CertIdentity(t *testing.T) {
+	pki := newTestPKI(t)
+	pki.issue(t, "eg-01")
+	pki.issue(t, "cache-daemon", "daemon.internal")
+	pki.issue(t, "stranger")
+
+	serverCfg, err := ServerTLSConfig(pki.config("eg-01"), pki.dir)
+	require.NoError(t, err)
+
+	auth := NewAuthenticator("", []configtypes.InternalCredential{
+		{Name: "daemon", CertCN: "daemon.internal", Scopes: []string{ScopeRecache}},
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fasthttp.Server{Handler: func(ctx *fasthttp.RequestCtx) {
+		identity, status, err := auth.Authorize(ctx, string(ctx.Path()[1:]))
+		if err != nil {
+			ctx.SetStatusCode(status)
+			return
+		}
+		ctx.SetBodyString(identity.Name)
+	}}
+	go func() { _ = server.Serve(tls.NewListener(listener, serverCfg)) }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	call := func(t *testing.T, clientName, path string) (int, string, error) {
+		clientCfg, err := ClientTLSConfig(pki.config(clientName), pki.dir)
+		require.NoError(t, err)
+		client := &fasthttp.Client{TLSConfig: clientCfg}
+
+		req := fasthttp.AcquireRequest()
+		defer fasthttp.ReleaseRequest(req)
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(resp)
+
+		req.SetRequestURI("https://" + listener.Addr().String() + path)
+		if err := client.DoTimeout(req, resp, 5*time.Second); err != nil {
+			return 0, "", err
+		}
+		return resp.StatusCode(), string(resp.Body()), nil
+	}
+
+	t.Run("matched by DNS SAN", func(t *testing.T) {
+		status, body, err := call(t, "cache-daemon", "/"+ScopeRecache)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, status)
+		assert.Equal(t, "daemon", body)
+	})
+
+	t.Run("scope enforced for cert identity", func(t *testing.T) {
+		status, _, err := call(t, "cache-daemon", "/"+ScopePeer)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusForbidden, status)
+	})
+
+	t.Run("verified but unknown certificate", func(t *testing.T) {
+		status, _, err := call(t, "stranger", "/"+ScopeRecache)
+		require.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusUnauthorized, status)
+	})
+
+	t.Run("client without certificate rejected in handshake", func(t *testing.T) {
+		cfg := pki.config("cache-daemon")
+		cfg.CertFile, cfg.KeyFile = "", ""
+
+		clientCfg, err := ClientTLSConfig(cfg, pki.dir)
+		require.NoError(t, err)
+		client := &fasthttp.Client{TLSConfig: clientCfg}
+		_, _, err = client.GetTimeout(nil, "https://"+listener.Addr().String()+"/"+ScopeRecache, 5*time.Second)
+		assert.Error(t, err)
+	})
+}
@@ -358,6 +358,157 @@ func (c *Client) ZAdd(ctx context.Context, key string, score float64, member str
 	return nil
 }
 
+// ZRem removes members from a sorted set
+func (c *Client) ZRem(ctx context.Context, key string, members ...string) error {
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	err := c.rdb.ZRem(ctx, key, args...).Err()
+	if err != nil {
+		c.logger.Error("Redis ZREM failed",
+			zap.String("key", key),
+			zap.Int("members", len(members)),
+			zap.Error(err))
+		return fmt.Errorf("redis zrem failed: %w", err)
+	}
+	return nil
+}
+
+// LRange returns the elements of a list between start and stop (empty if the key does not exist)
+func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	values, err := c.rdb.LRange(ctx, key, start, stop).Result()
+	if err != nil {
+		c.logger.Error("Redis LRANGE failed",
+			zap.String("key", key),
+			zap.Error(err))
+		return nil, fmt.Errorf("redis lrange failed: %w", err)
+	}
+	return values, nil
+}
+
+// SMembers returns all members of a set (empty if the key does not exist)
+func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := c.rdb.SMembers(ctx, key).Result()
+	if err != nil {
+		c.logger.Error("Redis SMEMBERS failed",
+			zap.String("key", key),
+			zap.Error(err))
+		return nil, fmt.Errorf("redis smembers failed: %w", err)
+	}
+	return members, nil
+}
+
+// SRem removes members from a set
+func (c *Client) SRem(ctx context.Context, key string, members ...string) error {
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	err := c.rdb.SRem(ctx, key, args...).Err()
+	if err != nil {
+		c.logger.Error("Redis SREM failed",
+			zap.String("key", key),
+			zap.Int("members", len(members)),
+			zap.Error(err))
+		return fmt.Errorf("redis srem failed: %w", err)
+	}
+	return nil
+}
+
+// Scan iterates keys matching pattern, returning one page and the next cursor (0 when done)
+func (c *Client) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	keys, next, err := c.rdb.Scan(ctx, cursor, match, count).Result()
+	if err != nil {
+		c.logger.Error("Redis SCAN failed",
+			zap.String("match", match),
+			zap.Error(err))
+		return nil, 0, fmt.Errorf("redis scan failed: %w", err)
+	}
+	return keys, next, nil
+}
+
+// XAdd appends an entry to stream, trimming it to approximately maxLen entries. Returns the entry ID.
+func (c *Client) XAdd(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error) {
+	id, err := c.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+	if err != nil {
+		c.logger.Error("Redis XADD failed",
+			zap.String("stream", stream),
+			zap.Error(err))
+		return "", fmt.Errorf("redis xadd failed: %w", err)
+	}
+	return id, nil
+}
+
+// XRead returns up to count entries of stream after lastID, waiting up to block for new entries.
+// Returns an empty slice when no entry arrived in time.
+func (c *Client) XRead(ctx context.Context, stream, lastID string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	result, err := c.rdb.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{stream, lastID},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		c.logger.Error("Redis XREAD failed",
+			zap.String("stream", stream),
+			zap.String("last_id", lastID),
+			zap.Error(err))
+		return nil, fmt.Errorf("redis xread failed: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result[0].Messages, nil
+}
+
+// XRangeN returns up to count entries of stream between start and stop (inclusive)
+func (c *Client) XRangeN(ctx context.Context, stream, start, stop string, count int64) ([]redis.XMessage, error) {
+	result, err := c.rdb.XRangeN(ctx, stream, start, stop, count).Result()
+	if err != nil {
+		c.logger.Error("Redis XRANGE failed",
+			zap.String("stream", stream),
+			zap.Error(err))
+		return nil, fmt.Errorf("redis xrange failed: %w", err)
+	}
+	return result, nil
+}
+
+// XRevRangeN returns up to count entries of stream between end and start (inclusive), newest first
+func (c *Client) XRevRangeN(ctx context.Context, stream, end, start string, count int64) ([]redis.XMessage, error) {
+	result, err := c.rdb.XRevRangeN(ctx, stream, end, start, count).Result()
+	if err != nil {
+		c.logger.Error("Redis XREVRANGE failed",
+			zap.String("stream", stream),
+			zap.Error(err))
+		return nil, fmt.Errorf("redis xrevrange failed: %w", err)
+	}
+	return result, nil
+}
+
+// Publish posts message to a pub/sub channel
+func (c *Client) Publish(ctx context.Context, channel string, message interface{}) error {
+	if err := c.rdb.Publish(ctx, channel, message).Err(); err != nil {
+		c.logger.Error("Redis PUBLISH failed",
+			zap.String("channel", channel),
+			zap.Error(err))
+		return fmt.Errorf("redis publish failed: %w", err)
+	}
+	return nil
+}
+
+// PSubscribe subscribes to pub/sub channels matching patterns. The caller must close the subscription.
+func (c *Client) PSubscribe(ctx context.Context, patterns ...string) *redis.PubSub {
+	return c.rdb.PSubscribe(ctx, patterns...)
+}
+
 func (c *Client) GetClient() *redis.Client {
 	return c.rdb
 }
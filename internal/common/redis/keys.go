@@ -7,8 +7,12 @@ import (
 )
 
 const (
-	lockKeyPrefix     = "lock:"
-	metadataKeyPrefix = "meta:"
+	lockKeyPrefix       = "lock:"
+	metadataKeyPrefix   = "meta:"
+	popularityKeyPrefix = "pop:"
+	duplicateKeyPrefix  = "dup:"
+	seoHistoryKeyPrefix = "seohist:"
+	linkKeyPrefix       = "links:"
 )
 
 // Priority levels for recache queues
@@ -62,8 +66,87 @@ func (kg *KeyGenerator) GenerateMetadataKey(cacheKey *types.CacheKey) string {
 	return metadataKeyPrefix + cacheKey.String()
 }
 
+// MetadataScanPattern returns the SCAN pattern matching all cache metadata keys
+func (kg *KeyGenerator) MetadataScanPattern() string {
+	return metadataKeyPrefix + "cache:*"
+}
+
+// HostMetadataScanPattern returns the SCAN pattern matching cache metadata keys of one host
+func (kg *KeyGenerator) HostMetadataScanPattern(hostID int) string {
+	return fmt.Sprintf("%scache:%d:*", metadataKeyPrefix, hostID)
+}
+
+// GeneratePopularityKey generates the Redis popularity score key for a cache key
+func (kg *KeyGenerator) GeneratePopularityKey(cacheKey *types.CacheKey) string {
+	return popularityKeyPrefix + cacheKey.String()
+}
+
+// DuplicateBucketKey returns the Redis key of an LSH bucket (SET of cache keys)
+// Format: dup:{hostID}:{band}:{bucket}
+func (kg *KeyGenerator) DuplicateBucketKey(hostID int, band int, bucket string) string {
+	return fmt.Sprintf("%s%d:%d:%s", duplicateKeyPrefix, hostID, band, bucket)
+}
+
+// DuplicateBucketScanPattern returns the SCAN pattern matching all LSH buckets of one host
+func (kg *KeyGenerator) DuplicateBucketScanPattern(hostID int) string {
+	return fmt.Sprintf("%s%d:*", duplicateKeyPrefix, hostID)
+}
+
+// DuplicateEntryKey returns the Redis key listing the LSH buckets a cache entry is in
+// Format: dup:entry:cache:{hostID}:{dimensionID}:{urlHash}
+func (kg *KeyGenerator) DuplicateEntryKey(cacheKey *types.CacheKey) string {
+	return duplicateKeyPrefix + "entry:" + cacheKey.String()
+}
+
+// SEOHistoryKey returns the Redis key of a cache entry's PageSEO history (LIST, newest first)
+// Format: seohist:cache:{hostID}:{dimensionID}:{urlHash}
+func (kg *KeyGenerator) SEOHistoryKey(cacheKey *types.CacheKey) string {
+	return seoHistoryKeyPrefix + cacheKey.String()
+}
+
+// LinkOutKey returns the Redis key listing the inlink sets a cache entry's page is in
+// Format: links:out:cache:{hostID}:{dimensionID}:{urlHash}
+func (kg *KeyGenerator) LinkOutKey(cacheKey *types.CacheKey) string {
+	return linkKeyPrefix + "out:" + cacheKey.String()
+}
+
+// LinkInKey returns the Redis key of the pages linking to a URL (SET of source URL hashes)
+// Format: links:in:{hostID}:{dimensionID}:{urlHash}
+func (kg *KeyGenerator) LinkInKey(hostID, dimensionID int, urlHash string) string {
+	return fmt.Sprintf("%sin:%d:%d:%s", linkKeyPrefix, hostID, dimensionID, urlHash)
+}
+
+// LinkInScanPattern returns the SCAN pattern matching the inlink sets of one host dimension
+func (kg *KeyGenerator) LinkInScanPattern(hostID, dimensionID int) string {
+	return fmt.Sprintf("%sin:%d:%d:*", linkKeyPrefix, hostID, dimensionID)
+}
+
+// LinkURLKey returns the Redis key holding the normalized URL of a link target
+// Format: links:url:{hostID}:{urlHash}
+func (kg *KeyGenerator) LinkURLKey(hostID int, urlHash string) string {
+	return fmt.Sprintf("%surl:%d:%s", linkKeyPrefix, hostID, urlHash)
+}
+
+// LinkDepthKey returns the Redis key holding a discovered URL's link depth
+// Format: links:depth:{hostID}:{dimensionID}:{urlHash}
+func (kg *KeyGenerator) LinkDepthKey(hostID, dimensionID int, urlHash string) string {
+	return fmt.Sprintf("%sdepth:%d:%d:%s", linkKeyPrefix, hostID, dimensionID, urlHash)
+}
+
 // RecacheQueueKey returns Redis key for recache queue (ZSET)
 // Format: recache:{hostID}:{priority}
 func (kg *KeyGenerator) RecacheQueueKey(hostID int, priority string) string {
 	return fmt.Sprintf("recache:%d:%s", hostID, priority)
 }
+
+// ScheduledJobRunKey returns Redis key used to claim a single cron job run
+// Format: schedule:run:{jobName}:{fireUnix}
+func (kg *KeyGenerator) ScheduledJobRunKey(jobName string, fireUnix int64) string {
+	return fmt.Sprintf("schedule:run:%s:%d", jobName, fireUnix)
+}
+
+// WebhookDeliveryKey returns Redis key used to claim a signed webhook delivery once
+// Format: webhook:delivery:{signature}
+func (kg *KeyGenerator) WebhookDeliveryKey(signature string) string {
+	return "webhook:delivery:" + signature
+}
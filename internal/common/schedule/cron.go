@@ -0,0 +1,267 @@
+// Package schedule provides cron expressions and daily time windows used to
+// gate and trigger background work.
+//
+// Cron expressions use the standard five fields:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Each field accepts "*", single values, ranges ("1-5"), lists ("1,15,30")
+// and steps ("*/15", "0-30/10"). Month and weekday names (jan, mon) are
+// accepted case-insensitively, and day-of-week 7 is an alias for Sunday.
+// The macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are supported.
+//
+// As in classic cron, when both day-of-month and day-of-week are restricted
+// a time matches if either field matches.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronSearch bounds Next() so impossible expressions (e.g. "0 0 30 2 *") terminate
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// CronExpr is a parsed five-field cron expression
+type CronExpr struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	{name: "day-of-week", min: 0, max: 7, names: weekdayNames},
+}
+
+// ParseCron parses a five-field cron expression or macro
+func ParseCron(expr string) (*CronExpr, error) {
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		return nil, fmt.Errorf("cron expression cannot be empty")
+	}
+
+	spec := trimmed
+	if strings.HasPrefix(spec, "@") {
+		macro, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron macro %q", spec)
+		}
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", trimmed, len(parts))
+	}
+
+	bits := make([]uint64, len(cronFields))
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", trimmed, err)
+		}
+		bits[i] = b
+	}
+
+	// Day-of-week 7 is Sunday
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = (bits[4] &^ (1 << 7)) | 1
+	}
+
+	return &CronExpr{
+		expr:    trimmed,
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: parts[2] == "*" || parts[2] == "?",
+		dowStar: parts[4] == "*" || parts[4] == "?",
+	}, nil
+}
+
+// String returns the original expression
+func (c *CronExpr) String() string {
+	return c.expr
+}
+
+// Matches reports whether t (truncated to the minute) satisfies the expression
+func (c *CronExpr) Matches(t time.Time) bool {
+	if c.minute&(1<<uint(t.Minute())) == 0 {
+		return false
+	}
+	if c.hour&(1<<uint(t.Hour())) == 0 {
+		return false
+	}
+	if c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	return c.dayMatches(t)
+}
+
+// Next returns the first matching minute strictly after t, in t's location.
+// Returns the zero time if no match exists within five years.
+func (c *CronExpr) Next(t time.Time) time.Time {
+	// Advance by local wall-clock fields: Truncate works in absolute time and misses
+	// local minute and hour boundaries in zones with non-whole-hour offsets
+	loc := t.Location()
+	next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.Add(maxCronSearch)
+
+	for next.Before(limit) {
+		if c.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(next.Hour())) == 0 {
+			next = advance(next, time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc), time.Hour)
+			continue
+		}
+		if c.minute&(1<<uint(next.Minute())) == 0 {
+			next = advance(next, time.Date(next.Year(), next.Month(), next.Day(), next.Hour(), next.Minute()+1, 0, 0, loc), time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+// advance returns candidate, or current+step when a repeated wall-clock time at a
+// daylight saving transition resolves to a time that is not after current
+func advance(current, candidate time.Time, step time.Duration) time.Time {
+	if candidate.After(current) {
+		return candidate
+	}
+	return current.Add(step)
+}
+
+func (c *CronExpr) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowMatch
+	case c.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// parseCronField parses one comma-separated field into a bitset
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(field, ",") {
+		if item == "" {
+			return 0, fmt.Errorf("%s: empty list item", spec.name)
+		}
+
+		rangePart, step := item, 1
+		if idx := strings.Index(item, "/"); idx != -1 {
+			rangePart = item[:idx]
+			s, err := strconv.Atoi(item[idx+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("%s: invalid step in %q", spec.name, item)
+			}
+			step = s
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = spec.min, spec.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseCronValue(bounds[0], spec); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(bounds[1], spec); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s: invalid range %q", spec.name, rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			// "5/10" means starting at 5 through the maximum
+			if step > 1 {
+				hi = spec.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(s string, spec cronField) (int, error) {
+	if spec.names != nil {
+		if v, ok := spec.names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", spec.name, s)
+	}
+	if v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("%s: value %d out of range %d-%d", spec.name, v, spec.min, spec.max)
+	}
+	return v, nil
+}
@@ -0,0 +1,160 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func utc(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		expr   string
+		errMsg string
+	}{
+		{"empty", "", "cannot be empty"},
+		{"too few fields", "0 3 * *", "must have 5 fields"},
+		{"minute out of range", "60 * * * *", "out of range"},
+		{"bad step", "*/0 * * * *", "invalid step"},
+		{"reversed range", "0 5-1 * * *", "invalid range"},
+		{"unknown macro", "@often", "unknown cron macro"},
+		{"garbage", "a b c d e", "invalid value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestCronExpr_Next(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		from     string
+		expected string
+	}{
+		{"nightly at 03:00 same day", "0 3 * * *", "2026-03-10 01:15", "2026-03-10 03:00"},
+		{"nightly at 03:00 next day", "0 3 * * *", "2026-03-10 03:00", "2026-03-11 03:00"},
+		{"every 15 minutes", "*/15 * * * *", "2026-03-10 10:07", "2026-03-10 10:15"},
+		{"weekdays only", "30 2 * * mon-fri", "2026-03-13 03:00", "2026-03-16 02:30"},
+		{"first of month", "@monthly", "2026-03-10 00:00", "2026-04-01 00:00"},
+		{"sunday as 7", "0 0 * * 7", "2026-03-10 00:00", "2026-03-15 00:00"},
+		{"named month", "0 0 1 jan *", "2026-03-10 00:00", "2027-01-01 00:00"},
+		{"list of hours", "0 1,13 * * *", "2026-03-10 02:00", "2026-03-10 13:00"},
+		{"dom or dow when both restricted", "0 0 15 * fri", "2026-03-10 00:00", "2026-03-13 00:00"},
+		{"leap day", "0 0 29 2 *", "2026-03-01 00:00", "2028-02-29 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, utc(tt.expected), c.Next(utc(tt.from)))
+		})
+	}
+}
+
+func TestCronExpr_NextLocalTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		zone     string
+		expr     string
+		from     string
+		expected string
+	}{
+		{"half-hour offset next hour", "Asia/Kolkata", "0 3 * * *", "2026-03-10 01:15", "2026-03-10 03:00"},
+		{"half-hour offset next day", "Asia/Kolkata", "0 3 * * *", "2026-03-10 03:00", "2026-03-11 03:00"},
+		{"half-hour offset with dst", "Australia/Adelaide", "30 2 * * *", "2026-03-10 23:45", "2026-03-11 02:30"},
+		{"quarter-hour offset every 15 minutes", "Asia/Kathmandu", "*/15 * * * *", "2026-03-10 10:07", "2026-03-10 10:15"},
+		{"skipped hour at dst start", "America/New_York", "30 * * * *", "2026-03-08 01:45", "2026-03-08 03:30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			require.NoError(t, err)
+			from, err := time.ParseInLocation("2006-01-02 15:04", tt.from, loc)
+			require.NoError(t, err)
+			expected, err := time.ParseInLocation("2006-01-02 15:04", tt.expected, loc)
+			require.NoError(t, err)
+
+			c, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.True(t, expected.Equal(c.Next(from)), "got %s", c.Next(from))
+		})
+	}
+}
+
+func TestCronExpr_NextImpossible(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, c.Next(utc("2026-01-01 00:00")).IsZero())
+}
+
+func TestCronExpr_Matches(t *testing.T) {
+	c, err := ParseCron("0 3 * * *")
+	require.NoError(t, err)
+
+	assert.True(t, c.Matches(utc("2026-03-10 03:00")))
+	assert.False(t, c.Matches(utc("2026-03-10 03:01")))
+	assert.False(t, c.Matches(utc("2026-03-10 04:00")))
+}
+
+func TestWindow(t *testing.T) {
+	t.Run("simple window", func(t *testing.T) {
+		w, err := ParseWindow("01:00-06:00")
+		require.NoError(t, err)
+
+		assert.False(t, w.Contains(utc("2026-03-10 00:59")))
+		assert.True(t, w.Contains(utc("2026-03-10 01:00")))
+		assert.True(t, w.Contains(utc("2026-03-10 05:59")))
+		assert.False(t, w.Contains(utc("2026-03-10 06:00")))
+	})
+
+	t.Run("window wrapping midnight", func(t *testing.T) {
+		w, err := ParseWindow("22:00-04:00")
+		require.NoError(t, err)
+
+		assert.True(t, w.Contains(utc("2026-03-10 23:30")))
+		assert.True(t, w.Contains(utc("2026-03-10 03:59")))
+		assert.False(t, w.Contains(utc("2026-03-10 12:00")))
+	})
+
+	t.Run("whole day", func(t *testing.T) {
+		w, err := ParseWindow("00:00-24:00")
+		require.NoError(t, err)
+
+		assert.True(t, w.Contains(utc("2026-03-10 00:00")))
+		assert.True(t, w.Contains(utc("2026-03-10 23:59")))
+	})
+
+	t.Run("invalid windows", func(t *testing.T) {
+		for _, s := range []string{"", "01:00", "25:00-26:00", "01:00-01:00", "24:00-01:00", "1am-2am"} {
+			_, err := ParseWindow(s)
+			assert.Error(t, err, s)
+		}
+	})
+
+	t.Run("any contains", func(t *testing.T) {
+		windows, err := ParseWindows([]string{"01:00-02:00", "13:00-14:00"})
+		require.NoError(t, err)
+
+		assert.True(t, AnyContains(windows, utc("2026-03-10 13:30")))
+		assert.False(t, AnyContains(windows, utc("2026-03-10 12:30")))
+		assert.False(t, AnyContains(nil, utc("2026-03-10 12:30")))
+	})
+}
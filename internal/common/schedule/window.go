@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range in "HH:MM-HH:MM" form.
+// Start is inclusive and end is exclusive; a window whose end is before its
+// start wraps past midnight (e.g. "22:00-04:00"). "00:00-24:00" covers the whole day.
+type Window struct {
+	raw   string
+	start int // Minutes since midnight
+	end   int // Minutes since midnight (may be 1440)
+}
+
+// ParseWindow parses a daily time window
+func ParseWindow(s string) (Window, error) {
+	raw := strings.TrimSpace(s)
+	parts := strings.Split(raw, "-")
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("time window %q must be in HH:MM-HH:MM format", s)
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("time window %q: %w", s, err)
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return Window{}, fmt.Errorf("time window %q: %w", s, err)
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("time window %q: start and end must differ", s)
+	}
+	if start == 24*60 {
+		return Window{}, fmt.Errorf("time window %q: start must be before 24:00", s)
+	}
+
+	return Window{raw: raw, start: start, end: end}, nil
+}
+
+// ParseWindows parses a list of daily time windows
+func ParseWindows(list []string) ([]Window, error) {
+	windows := make([]Window, 0, len(list))
+	for _, s := range list {
+		w, err := ParseWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// String returns the original window definition
+func (w Window) String() string {
+	return w.raw
+}
+
+// Contains reports whether the wall-clock time of t falls within the window
+func (w Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end
+	}
+	// Wraps past midnight
+	return minute >= w.start || minute < w.end
+}
+
+// AnyContains reports whether any window contains t
+func AnyContains(windows []Window, t time.Time) bool {
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseClock(s string) (int, error) {
+	t := strings.TrimSpace(s)
+	if t == "24:00" {
+		return 24 * 60, nil
+	}
+	parsed, err := time.Parse("15:04", t)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", t)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
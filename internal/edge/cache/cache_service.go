@@ -267,6 +267,11 @@ func (cs *CacheService) GetAbsoluteFilePath(relativePath string) (string, error)
 	return cs.metadata.GetAbsoluteFilePath(relativePath)
 }
 
+// NotifyMetadataChange reports metadata updated outside the metadata store (nil = deleted)
+func (cs *CacheService) NotifyMetadataChange(ctx context.Context, cacheKey *types.CacheKey, metadata *CacheMetadata) {
+	cs.metadata.NotifyChange(ctx, cacheKey, metadata)
+}
+
 // GenerateFilePath generates file path based on cache key and timestamp (delegates to MetadataStore)
 func (cs *CacheService) GenerateFilePath(key *types.CacheKey, expiresAt time.Time) string {
 	return cs.metadata.GenerateFilePath(key, expiresAt)
@@ -0,0 +1,455 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// LSH banding of the page MinHash signature: pages land in the same bucket of a band
+// when all rows of the band match. 16 bands of 4 rows find most pairs with similarity
+// above ~0.5 (the probability of sharing at least one bucket is 1-(1-s^4)^16).
+const (
+	duplicateBands    = 16
+	duplicateBandRows = htmlprocessor.MinHashSize / duplicateBands
+
+	// MinDuplicateSimilarity is the lowest similarity the banding reliably detects
+	MinDuplicateSimilarity = 0.5
+
+	// maxDuplicateBucketMembers caps pairwise comparisons in one bucket. Larger buckets
+	// (boilerplate-only pages) are compared on their first members only.
+	maxDuplicateBucketMembers = 500
+
+	duplicateScanCount = 500
+)
+
+// Lua script to move a cache entry to its current LSH buckets
+// KEYS[1] = entry key (list of the entry's buckets), KEYS[2..] = bucket keys
+// ARGV[1] = member (cache key), ARGV[2] = TTL (ms) of the entry and minimum bucket TTL
+// Buckets the entry is no longer in are cleaned up; without bucket keys the entry is removed.
+const luaDuplicateIngest = `
+local keep = {}
+for i = 2, #KEYS do
+  keep[KEYS[i]] = true
+end
+local old = redis.call('GET', KEYS[1])
+if old then
+  for bucket in string.gmatch(old, '[^ ]+') do
+    if not keep[bucket] then
+      redis.call('SREM', bucket, ARGV[1])
+    end
+  end
+end
+if #KEYS == 1 then
+  redis.call('DEL', KEYS[1])
+  return 0
+end
+local ttl = tonumber(ARGV[2])
+for i = 2, #KEYS do
+  redis.call('SADD', KEYS[i], ARGV[1])
+  if redis.call('PTTL', KEYS[i]) < ttl then
+    redis.call('PEXPIRE', KEYS[i], ttl)
+  end
+end
+redis.call('SET', KEYS[1], table.concat(KEYS, ' ', 2), 'PX', ttl)
+return #KEYS - 1
+`
+
+// DuplicateIndex is a per-host LSH index of page MinHash signatures in Redis, used to
+// find duplicate and near-duplicate pages. All methods are no-ops on a nil index.
+type DuplicateIndex struct {
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+	logger       *zap.Logger
+}
+
+// NewDuplicateIndex creates a new DuplicateIndex
+func NewDuplicateIndex(redisClient *redis.Client, keyGenerator *redis.KeyGenerator, logger *zap.Logger) *DuplicateIndex {
+	return &DuplicateIndex{
+		redis:        redisClient,
+		keyGenerator: keyGenerator,
+		logger:       logger,
+	}
+}
+
+// Ingest adds a cache entry's signature to the index, replacing its previous buckets.
+// Entries without a valid signature (redirects, empty pages) are removed. ttl should
+// match the metadata key TTL; buckets live as long as their longest-lived member.
+func (di *DuplicateIndex) Ingest(ctx context.Context, cacheKey *types.CacheKey, signature []uint64, ttl time.Duration) error {
+	if di == nil {
+		return nil
+	}
+	if len(signature) != htmlprocessor.MinHashSize || ttl <= 0 {
+		return di.Remove(ctx, cacheKey)
+	}
+
+	keys := make([]string, 0, duplicateBands+1)
+	keys = append(keys, di.keyGenerator.DuplicateEntryKey(cacheKey))
+	for band, bucket := range duplicateBuckets(signature) {
+		keys = append(keys, di.keyGenerator.DuplicateBucketKey(cacheKey.HostID, band, bucket))
+	}
+
+	if _, err := di.redis.Eval(ctx, luaDuplicateIngest, keys, cacheKey.String(), ttl.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to index %s: %w", cacheKey.String(), err)
+	}
+	return nil
+}
+
+// Remove drops a cache entry from the index
+func (di *DuplicateIndex) Remove(ctx context.Context, cacheKey *types.CacheKey) error {
+	if di == nil {
+		return nil
+	}
+	keys := []string{di.keyGenerator.DuplicateEntryKey(cacheKey)}
+	if _, err := di.redis.Eval(ctx, luaDuplicateIngest, keys, cacheKey.String(), 0); err != nil {
+		return fmt.Errorf("failed to remove %s from duplicate index: %w", cacheKey.String(), err)
+	}
+	return nil
+}
+
+// duplicateBuckets returns the bucket ID of each band of a signature
+func duplicateBuckets(signature []uint64) []string {
+	buckets := make([]string, duplicateBands)
+	buf := make([]byte, 8*duplicateBandRows)
+	for band := 0; band < duplicateBands; band++ {
+		for row := 0; row < duplicateBandRows; row++ {
+			binary.LittleEndian.PutUint64(buf[row*8:], signature[band*duplicateBandRows+row])
+		}
+		buckets[band] = strconv.FormatUint(xxhash.Sum64(buf), 16)
+	}
+	return buckets
+}
+
+// DuplicatePage is a cached page in a duplicate cluster
+type DuplicatePage struct {
+	URL          string  `json:"url"`
+	Title        string  `json:"title"`
+	CacheKey     string  `json:"cache_key"`
+	StatusCode   int     `json:"status_code"`
+	IndexStatus  int     `json:"index_status"`
+	CanonicalURL string  `json:"canonical_url,omitempty"`
+	Similarity   float64 `json:"similarity"` // Similarity to the first page of the cluster
+}
+
+// DuplicateCluster is a group of pages of one dimension with near-identical content.
+// The first page is the cluster's representative: the page most others declare as
+// canonical, otherwise the shortest URL.
+type DuplicateCluster struct {
+	Dimension           string          `json:"dimension"`
+	Size                int             `json:"size"`
+	MinSimilarity       float64         `json:"min_similarity"`
+	MaxSimilarity       float64         `json:"max_similarity"`
+	CanonicalTargets    []string        `json:"canonical_targets"`    // Distinct canonical URLs (own URL when none is declared)
+	ConsistentCanonical bool            `json:"consistent_canonical"` // All pages declare the same canonical URL
+	Pages               []DuplicatePage `json:"pages"`
+}
+
+// DuplicateReport lists the duplicate clusters of a host
+type DuplicateReport struct {
+	Clusters         []DuplicateCluster `json:"clusters"`
+	IndexedPages     int                `json:"indexed_pages"`     // Pages sharing at least one bucket with another page
+	ComparedPairs    int                `json:"compared_pairs"`    // Candidate pairs whose similarity was computed
+	TruncatedBuckets int                `json:"truncated_buckets"` // Buckets compared on their first members only
+}
+
+type duplicateBucket struct {
+	key     string
+	members []string
+}
+
+type duplicateCandidate struct {
+	page        DuplicatePage
+	dimensionID int
+	dimension   string
+	signature   []uint64
+}
+
+// FindClusters groups the host's indexed pages whose MinHash similarity is at least
+// minSimilarity. Only pages of the same dimension with different URLs are compared.
+// Index members whose metadata has expired are skipped; Prune removes them.
+func (di *DuplicateIndex) FindClusters(ctx context.Context, hostID int, minSimilarity float64) (*DuplicateReport, error) {
+	report := &DuplicateReport{Clusters: []DuplicateCluster{}}
+	if di == nil {
+		return report, nil
+	}
+
+	buckets, truncated, err := di.loadBuckets(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	report.TruncatedBuckets = truncated
+
+	candidates, err := di.loadCandidates(ctx, buckets)
+	if err != nil {
+		return nil, err
+	}
+	report.IndexedPages = len(candidates)
+
+	// Verify candidate pairs and union the duplicates
+	parent := make(map[string]string, len(candidates))
+	var find func(string) string
+	find = func(key string) string {
+		if parent[key] == key {
+			return key
+		}
+		root := find(parent[key])
+		parent[key] = root
+		return root
+	}
+	for key := range candidates {
+		parent[key] = key
+	}
+
+	compared := make(map[[2]string]bool)
+	for _, bucket := range buckets {
+		members := bucket.members
+		for i := 0; i < len(members); i++ {
+			a, ok := candidates[members[i]]
+			if !ok {
+				continue
+			}
+			for j := i + 1; j < len(members); j++ {
+				b, ok := candidates[members[j]]
+				if !ok || a.dimensionID != b.dimensionID || a.page.URL == b.page.URL {
+					continue
+				}
+				pair := [2]string{members[i], members[j]}
+				if pair[0] > pair[1] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				if htmlprocessor.MinHashSimilarity(a.signature, b.signature) >= minSimilarity {
+					parent[find(pair[0])] = find(pair[1])
+				}
+			}
+		}
+	}
+	report.ComparedPairs = len(compared)
+
+	groups := make(map[string][]*duplicateCandidate)
+	for key, candidate := range candidates {
+		root := find(key)
+		groups[root] = append(groups[root], candidate)
+	}
+	for _, group := range groups {
+		if len(group) > 1 {
+			report.Clusters = append(report.Clusters, buildDuplicateCluster(group))
+		}
+	}
+
+	sort.Slice(report.Clusters, func(i, j int) bool {
+		if report.Clusters[i].Size != report.Clusters[j].Size {
+			return report.Clusters[i].Size > report.Clusters[j].Size
+		}
+		return report.Clusters[i].Pages[0].URL < report.Clusters[j].Pages[0].URL
+	})
+	return report, nil
+}
+
+// loadBuckets returns the host's buckets that hold more than one page
+func (di *DuplicateIndex) loadBuckets(ctx context.Context, hostID int) ([]duplicateBucket, int, error) {
+	var buckets []duplicateBucket
+	truncated := 0
+	pattern := di.keyGenerator.DuplicateBucketScanPattern(hostID)
+
+	var cursor uint64
+	for {
+		keys, next, err := di.redis.Scan(ctx, cursor, pattern, duplicateScanCount)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, key := range keys {
+			members, err := di.redis.SMembers(ctx, key)
+			if err != nil {
+				return nil, 0, err
+			}
+			if len(members) < 2 {
+				continue
+			}
+			// SMEMBERS order is arbitrary; sort so truncation is stable
+			sort.Strings(members)
+			if len(members) > maxDuplicateBucketMembers {
+				members = members[:maxDuplicateBucketMembers]
+				truncated++
+			}
+			buckets = append(buckets, duplicateBucket{key: key, members: members})
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return buckets, truncated, nil
+}
+
+// loadCandidates reads the metadata of every bucket member. Members without metadata
+// (expired or deleted entries) are skipped.
+func (di *DuplicateIndex) loadCandidates(ctx context.Context, buckets []duplicateBucket) (map[string]*duplicateCandidate, error) {
+	candidates := make(map[string]*duplicateCandidate)
+	missing := make(map[string]bool)
+
+	for _, bucket := range buckets {
+		for _, member := range bucket.members {
+			if candidates[member] != nil || missing[member] {
+				continue
+			}
+			cacheKey, err := di.keyGenerator.ParseCacheKey(member)
+			if err != nil {
+				missing[member] = true
+				continue
+			}
+
+			data, err := di.redis.HGetAll(ctx, di.keyGenerator.GenerateMetadataKey(cacheKey))
+			if err != nil {
+				return nil, err
+			}
+			var metadata CacheMetadata
+			if len(data) == 0 || metadata.FromHash(data) != nil || len(metadata.MinHash) != htmlprocessor.MinHashSize {
+				missing[member] = true
+				continue
+			}
+
+			candidates[member] = &duplicateCandidate{
+				page: DuplicatePage{
+					URL:          metadata.URL,
+					Title:        metadata.Title,
+					CacheKey:     member,
+					StatusCode:   metadata.StatusCode,
+					IndexStatus:  metadata.IndexStatus,
+					CanonicalURL: metadata.CanonicalURL,
+				},
+				dimensionID: cacheKey.DimensionID,
+				dimension:   metadata.Dimension,
+				signature:   metadata.MinHash,
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// Prune removes the host's index members whose metadata has expired without a delete
+// and returns the number of removed bucket memberships
+func (di *DuplicateIndex) Prune(ctx context.Context, hostID int) (int, error) {
+	if di == nil {
+		return 0, nil
+	}
+
+	live := make(map[string]bool)
+	removed := 0
+	pattern := di.keyGenerator.DuplicateBucketScanPattern(hostID)
+
+	var cursor uint64
+	for {
+		keys, next, err := di.redis.Scan(ctx, cursor, pattern, duplicateScanCount)
+		if err != nil {
+			return removed, err
+		}
+		for _, key := range keys {
+			members, err := di.redis.SMembers(ctx, key)
+			if err != nil {
+				return removed, err
+			}
+
+			var stale []string
+			for _, member := range members {
+				exists, checked := live[member]
+				if !checked {
+					if exists, err = di.metadataExists(ctx, member); err != nil {
+						return removed, err
+					}
+					live[member] = exists
+				}
+				if !exists {
+					stale = append(stale, member)
+				}
+			}
+			if len(stale) == 0 {
+				continue
+			}
+			if err := di.redis.SRem(ctx, key, stale...); err != nil {
+				return removed, err
+			}
+			removed += len(stale)
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return removed, nil
+}
+
+// metadataExists reports whether an index member still has cache metadata
+func (di *DuplicateIndex) metadataExists(ctx context.Context, member string) (bool, error) {
+	cacheKey, err := di.keyGenerator.ParseCacheKey(member)
+	if err != nil {
+		return false, nil
+	}
+	return di.redis.Exists(ctx, di.keyGenerator.GenerateMetadataKey(cacheKey))
+}
+
+func buildDuplicateCluster(group []*duplicateCandidate) DuplicateCluster {
+	// Canonical target of each page: the declared canonical URL, or the page itself
+	canonicalVotes := make(map[string]int)
+	for _, candidate := range group {
+		canonicalVotes[canonicalTarget(candidate.page)]++
+	}
+
+	sort.Slice(group, func(i, j int) bool {
+		vi, vj := canonicalVotes[group[i].page.URL], canonicalVotes[group[j].page.URL]
+		if vi != vj {
+			return vi > vj
+		}
+		if len(group[i].page.URL) != len(group[j].page.URL) {
+			return len(group[i].page.URL) < len(group[j].page.URL)
+		}
+		return group[i].page.URL < group[j].page.URL
+	})
+
+	cluster := DuplicateCluster{
+		Dimension:     group[0].dimension,
+		Size:          len(group),
+		MinSimilarity: 1,
+		Pages:         make([]DuplicatePage, 0, len(group)),
+	}
+	for i, candidate := range group {
+		page := candidate.page
+		page.Similarity = 1
+		if i > 0 {
+			page.Similarity = htmlprocessor.MinHashSimilarity(group[0].signature, candidate.signature)
+			cluster.MinSimilarity = min(cluster.MinSimilarity, page.Similarity)
+			cluster.MaxSimilarity = max(cluster.MaxSimilarity, page.Similarity)
+		}
+		cluster.Pages = append(cluster.Pages, page)
+	}
+
+	for target := range canonicalVotes {
+		cluster.CanonicalTargets = append(cluster.CanonicalTargets, target)
+	}
+	sort.Strings(cluster.CanonicalTargets)
+	cluster.ConsistentCanonical = len(cluster.CanonicalTargets) == 1
+	return cluster
+}
+
+func canonicalTarget(page DuplicatePage) string {
+	if page.CanonicalURL != "" {
+		return page.CanonicalURL
+	}
+	return page.URL
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func setupTestDuplicateIndex(t *testing.T) (*MetadataStore, *DuplicateIndex, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	keyGenerator := redis.NewKeyGenerator()
+	index := NewDuplicateIndex(redisClient, keyGenerator, zap.NewNop())
+	store := NewMetadataStore(redisClient, keyGenerator, t.TempDir(), zap.NewNop())
+	store.SetDuplicateIndex(index)
+	return store, index, mr
+}
+
+// testWords returns n distinct words prefixed with prefix
+func testWords(prefix string, n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return words
+}
+
+func storeIndexedPage(t *testing.T, store *MetadataStore, dimensionID int, urlHash, url, canonical string, words []string) *types.CacheKey {
+	t.Helper()
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: dimensionID, URLHash: urlHash}
+	now := time.Now().UTC()
+	metadata := &CacheMetadata{
+		Key:          cacheKey.String(),
+		URL:          url,
+		HostID:       1,
+		Dimension:    fmt.Sprintf("dim%d", dimensionID),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Hour),
+		LastAccess:   now,
+		Source:       SourceRender,
+		StatusCode:   200,
+		IndexStatus:  1,
+		Title:        "Page " + urlHash,
+		CanonicalURL: canonical,
+		MinHash:      htmlprocessor.ComputeMinHash(words),
+	}
+	require.NoError(t, store.StoreMetadata(context.Background(), metadata, cacheKey, 0))
+	return cacheKey
+}
+
+func TestDuplicateIndex_FindClusters(t *testing.T) {
+	store, index, _ := setupTestDuplicateIndex(t)
+	ctx := context.Background()
+
+	base := testWords("w", 300)
+	nearDuplicate := append([]string{}, base...)
+	for i := 100; i < 110; i++ {
+		nearDuplicate[i] = fmt.Sprintf("changed%d", i)
+	}
+
+	storeIndexedPage(t, store, 1, "a", "https://example.com/product?color=red", "https://example.com/product", base)
+	storeIndexedPage(t, store, 1, "b", "https://example.com/product", "", base)
+	storeIndexedPage(t, store, 1, "c", "https://example.com/product?ref=mail", "", nearDuplicate)
+	storeIndexedPage(t, store, 1, "d", "https://example.com/about", "", testWords("about", 300))
+	// Same URL in another dimension is not a duplicate of the desktop page
+	storeIndexedPage(t, store, 2, "b", "https://example.com/product", "", base)
+
+	report, err := index.FindClusters(ctx, 1, 0.8)
+	require.NoError(t, err)
+	require.Len(t, report.Clusters, 1)
+
+	cluster := report.Clusters[0]
+	assert.Equal(t, "dim1", cluster.Dimension)
+	assert.Equal(t, 3, cluster.Size)
+	assert.Equal(t, "https://example.com/product", cluster.Pages[0].URL, "canonical target is the representative")
+	assert.Equal(t, 1.0, cluster.Pages[0].Similarity)
+	assert.Equal(t, 1.0, cluster.MaxSimilarity)
+	assert.Less(t, cluster.MinSimilarity, 1.0)
+	assert.GreaterOrEqual(t, cluster.MinSimilarity, 0.8)
+	assert.Equal(t, []string{"https://example.com/product", "https://example.com/product?ref=mail"}, cluster.CanonicalTargets)
+	assert.False(t, cluster.ConsistentCanonical)
+	assert.Equal(t, 1, cluster.Pages[0].IndexStatus)
+
+	t.Run("other hosts are not included", func(t *testing.T) {
+		report, err := index.FindClusters(ctx, 2, 0.9)
+		require.NoError(t, err)
+		assert.Empty(t, report.Clusters)
+	})
+}
+
+func TestDuplicateIndex_ReingestMovesEntry(t *testing.T) {
+	store, index, _ := setupTestDuplicateIndex(t)
+	ctx := context.Background()
+
+	base := testWords("w", 300)
+	storeIndexedPage(t, store, 1, "a", "https://example.com/a", "", base)
+	storeIndexedPage(t, store, 1, "b", "https://example.com/b", "", base)
+
+	report, err := index.FindClusters(ctx, 1, 0.9)
+	require.NoError(t, err)
+	require.Len(t, report.Clusters, 1)
+
+	// Page b is re-rendered with different content
+	storeIndexedPage(t, store, 1, "b", "https://example.com/b", "", testWords("new", 300))
+	report, err = index.FindClusters(ctx, 1, 0.9)
+	require.NoError(t, err)
+	assert.Empty(t, report.Clusters)
+	assert.Equal(t, 0, report.IndexedPages, "page a no longer shares a bucket")
+}
+
+func TestDuplicateIndex_RemovesDeletedEntries(t *testing.T) {
+	store, index, mr := setupTestDuplicateIndex(t)
+	ctx := context.Background()
+
+	base := testWords("w", 300)
+	storeIndexedPage(t, store, 1, "a", "https://example.com/a", "", base)
+	keyB := storeIndexedPage(t, store, 1, "b", "https://example.com/b", "", base)
+	keyC := storeIndexedPage(t, store, 1, "c", "https://example.com/c", "", base)
+
+	require.NoError(t, store.DeleteMetadata(ctx, keyB))
+	assert.False(t, mr.Exists(store.keyGenerator.DuplicateEntryKey(keyB)))
+
+	// Metadata expired without a delete: member is skipped but left in place
+	mr.Del(store.keyGenerator.GenerateMetadataKey(keyC))
+	report, err := index.FindClusters(ctx, 1, 0.9)
+	require.NoError(t, err)
+	assert.Empty(t, report.Clusters)
+	assert.Equal(t, duplicateBands, countBucketMemberships(mr, keyC.String()), "building clusters does not write")
+
+	// Prune removes the member from every bucket
+	removed, err := index.Prune(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, duplicateBands, removed)
+	assert.Zero(t, countBucketMemberships(mr, keyC.String()))
+
+	removed, err = index.Prune(ctx, 1)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
+// countBucketMemberships returns the number of sets holding member
+func countBucketMemberships(mr *miniredis.Miniredis, member string) int {
+	count := 0
+	for _, key := range mr.Keys() {
+		if isMember, _ := mr.SIsMember(key, member); isMember {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDuplicateIndex_SkipsInvalidSignatures(t *testing.T) {
+	store, index, mr := setupTestDuplicateIndex(t)
+	ctx := context.Background()
+
+	cacheKey := storeIndexedPage(t, store, 1, "a", "https://example.com/a", "", testWords("w", 300))
+	require.True(t, mr.Exists(store.keyGenerator.DuplicateEntryKey(cacheKey)))
+
+	// Page re-rendered as empty: no signature, entry leaves the index
+	require.NoError(t, index.Ingest(ctx, cacheKey, nil, time.Hour))
+	assert.False(t, mr.Exists(store.keyGenerator.DuplicateEntryKey(cacheKey)))
+
+	var nilIndex *DuplicateIndex
+	assert.NoError(t, nilIndex.Ingest(ctx, cacheKey, make([]uint64, htmlprocessor.MinHashSize), time.Hour))
+}
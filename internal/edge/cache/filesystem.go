@@ -152,3 +152,19 @@ func (fc *FilesystemCache) DeleteFile(filePath string) error {
 	fc.logger.Debug("File deleted successfully", zap.String("file_path", filePath))
 	return nil
 }
+
+// MoveFile relocates a cache file within the cache directory, creating parent directories as needed
+func (fc *FilesystemCache) MoveFile(oldPath, newPath string) error {
+	if err := fc.ensureDirectory(newPath); err != nil {
+		return err
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to move file: %w", err)
+	}
+
+	fc.logger.Debug("File moved successfully",
+		zap.String("old_path", oldPath),
+		zap.String("new_path", newPath))
+	return nil
+}
@@ -0,0 +1,345 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/hash"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+const linkScanCount = 500
+
+// Lua script to replace the outlinks of a page and discover new link targets
+// KEYS[1] = out key (list of the page's inlink sets), KEYS[2] = depth key of the page,
+// KEYS[3] = normal priority recache queue, then per target: inlink set, URL key,
+// depth key and metadata key
+// ARGV[1] = member (source URL hash), ARGV[2] = TTL (ms), ARGV[3] = max discovery depth
+// (0 disables discovery), ARGV[4] = queue score, then per target: URL and queue member
+// Inlink sets the page no longer links to are cleaned up; without targets the page is removed.
+// Returns the number of targets added to the recache queue.
+const luaLinkIngest = `
+local n = (#KEYS - 3) / 4
+local keep = {}
+for i = 0, n - 1 do
+  keep[KEYS[4 + i * 4]] = true
+end
+local old = redis.call('GET', KEYS[1])
+if old then
+  for key in string.gmatch(old, '[^ ]+') do
+    if not keep[key] then
+      redis.call('SREM', key, ARGV[1])
+    end
+  end
+end
+if n == 0 then
+  redis.call('DEL', KEYS[1])
+  return 0
+end
+local ttl = tonumber(ARGV[2])
+local maxDepth = tonumber(ARGV[3])
+local depth = tonumber(redis.call('GET', KEYS[2]) or '0') + 1
+local ins = {}
+local discovered = 0
+for i = 0, n - 1 do
+  local inKey = KEYS[4 + i * 4]
+  local depthKey = KEYS[6 + i * 4]
+  ins[#ins + 1] = inKey
+  redis.call('SADD', inKey, ARGV[1])
+  if redis.call('PTTL', inKey) < ttl then
+    redis.call('PEXPIRE', inKey, ttl)
+  end
+  redis.call('SET', KEYS[5 + i * 4], ARGV[5 + i * 2], 'PX', ttl)
+  if maxDepth > 0 then
+    local known = redis.call('GET', depthKey)
+    if not known then
+      redis.call('SET', depthKey, depth, 'PX', ttl)
+      if depth <= maxDepth and redis.call('EXISTS', KEYS[7 + i * 4]) == 0 then
+        discovered = discovered + redis.call('ZADD', KEYS[3], 'NX', ARGV[4], ARGV[6 + i * 2])
+      end
+    elseif tonumber(known) > depth then
+      redis.call('SET', depthKey, depth, 'PX', ttl)
+    end
+  end
+end
+redis.call('SET', KEYS[1], table.concat(ins, ' '), 'PX', ttl)
+return discovered
+`
+
+// LinkGraph is a per-host graph of internal links between rendered pages in Redis.
+// Links are stored per dimension: each link target has a set of the URL hashes of the
+// pages linking to it. All methods are no-ops on a nil graph.
+type LinkGraph struct {
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+	normalizer   *hash.URLNormalizer
+	logger       *zap.Logger
+	retention    time.Duration
+	maxDepth     int
+}
+
+// NewLinkGraph creates a LinkGraph keeping links for retention after the last render of the
+// linking page. maxDepth > 0 enables discovery of new URLs within that many links of a page
+// requested by traffic. Readers that never ingest may pass zero limits.
+func NewLinkGraph(redisClient *redis.Client, keyGenerator *redis.KeyGenerator, retention time.Duration, maxDepth int, logger *zap.Logger) *LinkGraph {
+	return &LinkGraph{
+		redis:        redisClient,
+		keyGenerator: keyGenerator,
+		normalizer:   hash.NewURLNormalizer(),
+		logger:       logger,
+		retention:    retention,
+		maxDepth:     maxDepth,
+	}
+}
+
+// Ingest replaces the outlinks of a rendered page. links are absolute URLs; they are
+// normalized with stripPatterns and kept when their hostname is one of the host's domains.
+// Returns the number of newly discovered URLs added to the normal priority recache queue.
+func (lg *LinkGraph) Ingest(ctx context.Context, cacheKey *types.CacheKey, links []string, domains []string, stripPatterns []config.CompiledStripPattern) (int, error) {
+	if lg == nil {
+		return 0, nil
+	}
+
+	hostDomains := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		hostDomains[strings.ToLower(domain)] = true
+	}
+
+	keys := []string{
+		lg.keyGenerator.LinkOutKey(cacheKey),
+		lg.keyGenerator.LinkDepthKey(cacheKey.HostID, cacheKey.DimensionID, cacheKey.URLHash),
+		lg.keyGenerator.RecacheQueueKey(cacheKey.HostID, redis.PriorityNormal),
+	}
+	args := []interface{}{cacheKey.URLHash, lg.retention.Milliseconds(), lg.maxDepth, time.Now().UTC().Unix()}
+
+	seen := make(map[string]bool, len(links))
+	for _, link := range links {
+		parsed, err := url.Parse(link)
+		if err != nil || !hostDomains[strings.ToLower(parsed.Hostname())] {
+			continue
+		}
+		normalized, err := lg.normalizer.Normalize(link, stripPatterns)
+		if err != nil {
+			continue
+		}
+		urlHash := lg.normalizer.Hash(normalized.NormalizedURL)
+		if urlHash == cacheKey.URLHash || seen[urlHash] {
+			continue
+		}
+		seen[urlHash] = true
+
+		target := &types.CacheKey{HostID: cacheKey.HostID, DimensionID: cacheKey.DimensionID, URLHash: urlHash}
+		member, _ := json.Marshal(types.RecacheMember{URL: normalized.NormalizedURL, DimensionID: cacheKey.DimensionID})
+		keys = append(keys,
+			lg.keyGenerator.LinkInKey(cacheKey.HostID, cacheKey.DimensionID, urlHash),
+			lg.keyGenerator.LinkURLKey(cacheKey.HostID, urlHash),
+			lg.keyGenerator.LinkDepthKey(cacheKey.HostID, cacheKey.DimensionID, urlHash),
+			lg.keyGenerator.GenerateMetadataKey(target))
+		args = append(args, normalized.NormalizedURL, string(member))
+	}
+
+	result, err := lg.redis.Eval(ctx, luaLinkIngest, keys, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store links of %s: %w", cacheKey.String(), err)
+	}
+	discovered, _ := result.(int64)
+	return int(discovered), nil
+}
+
+// Remove drops the outlinks of a page
+func (lg *LinkGraph) Remove(ctx context.Context, cacheKey *types.CacheKey) error {
+	if lg == nil {
+		return nil
+	}
+	keys := []string{
+		lg.keyGenerator.LinkOutKey(cacheKey),
+		lg.keyGenerator.LinkDepthKey(cacheKey.HostID, cacheKey.DimensionID, cacheKey.URLHash),
+		lg.keyGenerator.RecacheQueueKey(cacheKey.HostID, redis.PriorityNormal),
+	}
+	if _, err := lg.redis.Eval(ctx, luaLinkIngest, keys, cacheKey.URLHash, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to remove links of %s: %w", cacheKey.String(), err)
+	}
+	return nil
+}
+
+// LinkedPage is a link target with the number of pages linking to it
+type LinkedPage struct {
+	URL        string `json:"url"`
+	Inlinks    int    `json:"inlinks"`
+	Cached     bool   `json:"cached"`
+	StatusCode int    `json:"status_code,omitempty"` // Status of the cache entry (0 when not cached)
+}
+
+// LinkedPages is a page of link targets and the number of targets matching the report
+type LinkedPages struct {
+	Total int          `json:"total"`
+	Pages []LinkedPage `json:"pages"`
+}
+
+// TopLinked returns the link targets of a host dimension with the most inlinks
+func (lg *LinkGraph) TopLinked(ctx context.Context, hostID, dimensionID, limit int) (*LinkedPages, error) {
+	inlinks, err := lg.InlinkCounts(ctx, hostID, dimensionID)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := sortedByInlinks(inlinks)
+	report := &LinkedPages{Total: len(hashes), Pages: []LinkedPage{}}
+	for _, urlHash := range hashes[:min(limit, len(hashes))] {
+		page, err := lg.linkedPage(ctx, hostID, dimensionID, urlHash, inlinks[urlHash])
+		if err != nil {
+			return nil, err
+		}
+		report.Pages = append(report.Pages, *page)
+	}
+	return report, nil
+}
+
+// BrokenLinks returns the link targets of a host dimension that are not cached or whose
+// cache entry is not a 200 response, most linked first
+func (lg *LinkGraph) BrokenLinks(ctx context.Context, hostID, dimensionID, limit int) (*LinkedPages, error) {
+	inlinks, err := lg.InlinkCounts(ctx, hostID, dimensionID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LinkedPages{Pages: []LinkedPage{}}
+	for _, urlHash := range sortedByInlinks(inlinks) {
+		page, err := lg.linkedPage(ctx, hostID, dimensionID, urlHash, inlinks[urlHash])
+		if err != nil {
+			return nil, err
+		}
+		if page.Cached && page.StatusCode == 200 {
+			continue
+		}
+		report.Total++
+		if len(report.Pages) < limit {
+			report.Pages = append(report.Pages, *page)
+		}
+	}
+	return report, nil
+}
+
+// PageInlinks returns the sorted URLs of the pages linking to one URL of a dimension
+func (lg *LinkGraph) PageInlinks(ctx context.Context, cacheKey *types.CacheKey) ([]string, error) {
+	if lg == nil {
+		return []string{}, nil
+	}
+	sources, err := lg.redis.SMembers(ctx, lg.keyGenerator.LinkInKey(cacheKey.HostID, cacheKey.DimensionID, cacheKey.URLHash))
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(sources))
+	for _, source := range sources {
+		sourceURL, err := lg.pageURL(ctx, cacheKey.HostID, cacheKey.DimensionID, source)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, sourceURL)
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
+
+// InlinkCounts returns the number of linking pages of every link target of a host
+// dimension, keyed by URL hash
+func (lg *LinkGraph) InlinkCounts(ctx context.Context, hostID, dimensionID int) (map[string]int, error) {
+	inlinks := make(map[string]int)
+	if lg == nil {
+		return inlinks, nil
+	}
+
+	pattern := lg.keyGenerator.LinkInScanPattern(hostID, dimensionID)
+	prefix := strings.TrimSuffix(pattern, "*")
+	var cursor uint64
+	for {
+		keys, next, err := lg.redis.Scan(ctx, cursor, pattern, linkScanCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan link graph: %w", err)
+		}
+		if len(keys) > 0 {
+			// Count the linking pages of the scanned page in one round trip
+			pipe := lg.redis.GetClient().Pipeline()
+			counts := make([]*goredis.IntCmd, len(keys))
+			for i, key := range keys {
+				counts[i] = pipe.SCard(ctx, key)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return nil, fmt.Errorf("failed to count inlinks: %w", err)
+			}
+			for i, key := range keys {
+				if count := counts[i].Val(); count > 0 {
+					inlinks[strings.TrimPrefix(key, prefix)] = int(count)
+				}
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return inlinks, nil
+}
+
+// linkedPage reads the URL and cache status of a link target
+func (lg *LinkGraph) linkedPage(ctx context.Context, hostID, dimensionID int, urlHash string, inlinks int) (*LinkedPage, error) {
+	page := &LinkedPage{Inlinks: inlinks}
+	metaKey := lg.keyGenerator.GenerateMetadataKey(&types.CacheKey{HostID: hostID, DimensionID: dimensionID, URLHash: urlHash})
+	status, err := lg.redis.HGet(ctx, metaKey, "status_code")
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		page.Cached = true
+		page.StatusCode, _ = strconv.Atoi(status)
+	}
+
+	page.URL, err = lg.pageURL(ctx, hostID, dimensionID, urlHash)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// pageURL returns the URL of a URL hash from the link graph or the cache metadata,
+// the hash itself when neither is known
+func (lg *LinkGraph) pageURL(ctx context.Context, hostID, dimensionID int, urlHash string) (string, error) {
+	pageURL, err := lg.redis.Get(ctx, lg.keyGenerator.LinkURLKey(hostID, urlHash))
+	if err != nil || pageURL != "" {
+		return pageURL, err
+	}
+	metaKey := lg.keyGenerator.GenerateMetadataKey(&types.CacheKey{HostID: hostID, DimensionID: dimensionID, URLHash: urlHash})
+	pageURL, err = lg.redis.HGet(ctx, metaKey, "url")
+	if err != nil || pageURL != "" {
+		return pageURL, err
+	}
+	return urlHash, nil
+}
+
+// sortedByInlinks returns URL hashes ordered by inlink count, most linked first
+func sortedByInlinks(inlinks map[string]int) []string {
+	hashes := make([]string, 0, len(inlinks))
+	for urlHash := range inlinks {
+		hashes = append(hashes, urlHash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		if inlinks[hashes[i]] != inlinks[hashes[j]] {
+			return inlinks[hashes[i]] > inlinks[hashes[j]]
+		}
+		return hashes[i] < hashes[j]
+	})
+	return hashes
+}
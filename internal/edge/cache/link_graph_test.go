@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/hash"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+var linkTestDomains = []string{"example.com"}
+
+func setupTestLinkGraph(t *testing.T, maxDepth int) (*MetadataStore, *LinkGraph, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	keyGenerator := redis.NewKeyGenerator()
+	graph := NewLinkGraph(redisClient, keyGenerator, time.Hour, maxDepth, zap.NewNop())
+	store := NewMetadataStore(redisClient, keyGenerator, t.TempDir(), zap.NewNop())
+	store.SetLinkGraph(graph)
+	return store, graph, mr
+}
+
+// linkTestKey returns the cache key of a URL in dimension 1 of host 1
+func linkTestKey(rawURL string) *types.CacheKey {
+	normalizer := hash.NewURLNormalizer()
+	normalized, _ := normalizer.Normalize(rawURL, nil)
+	return &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: normalizer.Hash(normalized.NormalizedURL)}
+}
+
+func storeLinkedPage(t *testing.T, store *MetadataStore, rawURL string, statusCode int) *types.CacheKey {
+	t.Helper()
+	cacheKey := linkTestKey(rawURL)
+	now := time.Now().UTC()
+	metadata := &CacheMetadata{
+		Key:        cacheKey.String(),
+		URL:        rawURL,
+		HostID:     1,
+		Dimension:  "desktop",
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(time.Hour),
+		LastAccess: now,
+		Source:     SourceRender,
+		StatusCode: statusCode,
+	}
+	require.NoError(t, store.StoreMetadata(context.Background(), metadata, cacheKey, 0))
+	return cacheKey
+}
+
+func TestLinkGraphIngest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("counts distinct linking pages and replaces outlinks", func(t *testing.T) {
+		store, graph, _ := setupTestLinkGraph(t, 0)
+		home := storeLinkedPage(t, store, "https://example.com/", 200)
+		about := storeLinkedPage(t, store, "https://example.com/about", 200)
+
+		_, err := graph.Ingest(ctx, home, []string{
+			"https://example.com/about",
+			"https://EXAMPLE.com/about", // Same page after normalization
+			"https://example.com/",      // Self link
+			"https://other.com/x",       // Other host
+			"https://example.com/missing",
+		}, linkTestDomains, nil)
+		require.NoError(t, err)
+		_, err = graph.Ingest(ctx, about, []string{"https://example.com/missing"}, linkTestDomains, nil)
+		require.NoError(t, err)
+
+		counts, err := graph.InlinkCounts(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{about.URLHash: 1, linkTestKey("https://example.com/missing").URLHash: 2}, counts)
+
+		// The home page no longer links to /missing
+		_, err = graph.Ingest(ctx, home, []string{"https://example.com/about"}, linkTestDomains, nil)
+		require.NoError(t, err)
+		sources, err := graph.PageInlinks(ctx, linkTestKey("https://example.com/missing"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/about"}, sources)
+	})
+
+	t.Run("deleting metadata removes outlinks", func(t *testing.T) {
+		store, graph, _ := setupTestLinkGraph(t, 0)
+		home := storeLinkedPage(t, store, "https://example.com/", 200)
+		_, err := graph.Ingest(ctx, home, []string{"https://example.com/about"}, linkTestDomains, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteMetadata(ctx, home))
+
+		counts, err := graph.InlinkCounts(ctx, 1, 1)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("discover queues uncached targets within max depth", func(t *testing.T) {
+		store, graph, mr := setupTestLinkGraph(t, 1)
+		home := storeLinkedPage(t, store, "https://example.com/", 200)
+		storeLinkedPage(t, store, "https://example.com/cached", 200)
+
+		discovered, err := graph.Ingest(ctx, home, []string{
+			"https://example.com/new",
+			"https://example.com/cached",
+		}, linkTestDomains, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, discovered)
+
+		members, err := mr.ZMembers("recache:1:normal")
+		require.NoError(t, err)
+		assert.Equal(t, []string{`{"url":"https://example.com/new","dimension_id":1}`}, members)
+
+		// Seen again: not queued twice
+		discovered, err = graph.Ingest(ctx, home, []string{"https://example.com/new"}, linkTestDomains, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, discovered)
+
+		// Depth 2 exceeds max depth
+		discovered, err = graph.Ingest(ctx, linkTestKey("https://example.com/new"), []string{"https://example.com/deeper"}, linkTestDomains, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, discovered)
+	})
+}
+
+func TestLinkGraphReports(t *testing.T) {
+	ctx := context.Background()
+	store, graph, _ := setupTestLinkGraph(t, 0)
+	home := storeLinkedPage(t, store, "https://example.com/", 200)
+	about := storeLinkedPage(t, store, "https://example.com/about", 200)
+	storeLinkedPage(t, store, "https://example.com/gone", 404)
+
+	_, err := graph.Ingest(ctx, home, []string{"https://example.com/about", "https://example.com/gone", "https://example.com/missing"}, linkTestDomains, nil)
+	require.NoError(t, err)
+	_, err = graph.Ingest(ctx, about, []string{"https://example.com/gone"}, linkTestDomains, nil)
+	require.NoError(t, err)
+
+	top, err := graph.TopLinked(ctx, 1, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, top.Total)
+	require.Len(t, top.Pages, 1)
+	assert.Equal(t, LinkedPage{URL: "https://example.com/gone", Inlinks: 2, Cached: true, StatusCode: 404}, top.Pages[0])
+
+	broken, err := graph.BrokenLinks(ctx, 1, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, broken.Total)
+	assert.Equal(t, []LinkedPage{
+		{URL: "https://example.com/gone", Inlinks: 2, Cached: true, StatusCode: 404},
+		{URL: "https://example.com/missing", Inlinks: 1},
+	}, broken.Pages)
+}
@@ -0,0 +1,11 @@
+package cache
+
+// MarkdownExt is the suffix of the Markdown variant stored next to a rendered cache file
+const MarkdownExt = ".md"
+
+// MarkdownPath returns the path of the Markdown variant of a cache file. The variant is
+// stored uncompressed in the same directory, so it is removed with the cache file's
+// directory by the cleanup worker.
+func MarkdownPath(filePath string) string {
+	return filePath + MarkdownExt
+}
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -10,8 +12,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"go.uber.org/zap"
 
+	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/common/eventbus"
 	"github.com/edgecomet/engine/internal/common/redis"
 	"github.com/edgecomet/engine/pkg/types"
 )
@@ -20,6 +25,10 @@ import (
 const (
 	SourceRender = "render" // Cache entry from rendered content
 	SourceBypass = "bypass" // Cache entry from bypass (direct fetch)
+
+	// SourceDeleted marks a metadata-only entry for content deleted at the origin.
+	// Written by the cache daemon webhook receiver and served as a bodyless 404/410.
+	SourceDeleted = "deleted"
 )
 
 type CacheMetadata struct {
@@ -41,6 +50,16 @@ type CacheMetadata struct {
 	LastBotHit  *int64              `json:"last_bot_hit,omitempty"` // Unix timestamp, nil if not tracked
 	IndexStatus int                 `json:"index_status,omitempty"` // Indexation status (1=indexable, 2=non200, 3=blocked, 4=noncanonical)
 	Title       string              `json:"title,omitempty"`        // Page title extracted from HTML
+	Description string              `json:"description,omitempty"`  // Meta description extracted from HTML
+
+	// Content fingerprint for change detection on recache
+	ContentHash  string   `json:"content_hash,omitempty"`  // xxhash64 of uncompressed content (hex)
+	CanonicalURL string   `json:"canonical_url,omitempty"` // Canonical URL extracted from HTML
+	MinHash      []uint64 `json:"minhash,omitempty"`       // Page content MinHash signature
+
+	// SEO snapshot of the rendered page and the audit results
+	SEO       *types.PageSEO `json:"seo,omitempty"`        // Full PageSEO (without the MinHash and internal links)
+	SEOIssues []string       `json:"seo_issues,omitempty"` // Page-level SEO rule violations (seo_audit enabled)
 }
 
 func (cm *CacheMetadata) IsExpired() bool {
@@ -74,6 +93,11 @@ func (cm *CacheMetadata) StaleAge() time.Duration {
 	return time.Now().UTC().Sub(cm.ExpiresAt)
 }
 
+// IsDeleted reports whether the entry is a deleted-content marker (no cache file)
+func (cm *CacheMetadata) IsDeleted() bool {
+	return cm.Source == SourceDeleted
+}
+
 // IsEmpty returns true if no EG IDs are stored
 func (cm *CacheMetadata) IsEmpty() bool {
 	return len(cm.EgIDs) == 0
@@ -165,6 +189,30 @@ func (cm *CacheMetadata) ToHash() map[string]interface{} {
 	if cm.Title != "" {
 		hash["title"] = cm.Title
 	}
+	if cm.Description != "" {
+		hash["description"] = cm.Description
+	}
+
+	// Add content fingerprint fields if present
+	if cm.ContentHash != "" {
+		hash["content_hash"] = cm.ContentHash
+	}
+	if cm.CanonicalURL != "" {
+		hash["canonical_url"] = cm.CanonicalURL
+	}
+	if len(cm.MinHash) > 0 {
+		hash["minhash"] = EncodeMinHash(cm.MinHash)
+	}
+
+	// Add SEO audit fields if present
+	if len(cm.SEOIssues) > 0 {
+		hash["seo_issues"] = strings.Join(cm.SEOIssues, ",")
+	}
+	if cm.SEO != nil {
+		if seoJSON, err := EncodePageSEO(cm.SEO); err == nil {
+			hash["seo"] = seoJSON
+		}
+	}
 
 	return hash
 }
@@ -261,15 +309,94 @@ func (cm *CacheMetadata) FromHash(data map[string]string) error {
 
 	// Parse title if present
 	cm.Title = data["title"]
+	cm.Description = data["description"]
+
+	// Parse content fingerprint fields if present (invalid minhash is ignored)
+	cm.ContentHash = data["content_hash"]
+	cm.CanonicalURL = data["canonical_url"]
+	if minHashStr, exists := data["minhash"]; exists && minHashStr != "" {
+		if minHash, err := DecodeMinHash(minHashStr); err == nil {
+			cm.MinHash = minHash
+		}
+	}
+
+	// Parse SEO fields if present (invalid snapshot JSON is ignored)
+	if seoIssuesStr, exists := data["seo_issues"]; exists && seoIssuesStr != "" {
+		cm.SEOIssues = strings.Split(seoIssuesStr, ",")
+	}
+	if seoJSON, exists := data["seo"]; exists && seoJSON != "" {
+		if seo, err := DecodePageSEO(seoJSON); err == nil {
+			cm.SEO = seo
+		}
+	}
 
 	return nil
 }
 
+// ContentHash returns the fingerprint of uncompressed cache content used for change detection
+func ContentHash(content []byte) string {
+	return strconv.FormatUint(xxhash.Sum64(content), 16)
+}
+
+// EncodeMinHash packs a MinHash signature as base64 of little-endian uint64 values
+func EncodeMinHash(signature []uint64) string {
+	buf := make([]byte, 8*len(signature))
+	for i, v := range signature {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	return base64.RawStdEncoding.EncodeToString(buf)
+}
+
+// DecodeMinHash unpacks a signature produced by EncodeMinHash
+func DecodeMinHash(encoded string) ([]uint64, error) {
+	buf, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minhash encoding: %w", err)
+	}
+	if len(buf)%8 != 0 {
+		return nil, fmt.Errorf("invalid minhash length: %d bytes", len(buf))
+	}
+
+	signature := make([]uint64, len(buf)/8)
+	for i := range signature {
+		signature[i] = binary.LittleEndian.Uint64(buf[i*8:])
+	}
+	return signature, nil
+}
+
+// EncodePageSEO serializes a PageSEO snapshot for storage. The MinHash signature is left
+// out: it is stored in its own field and not meaningful to compare between versions.
+// Internal link targets are kept in the link graph instead.
+func EncodePageSEO(seo *types.PageSEO) (string, error) {
+	snapshot := *seo
+	snapshot.PageMinHash = nil
+	snapshot.InternalLinks = nil
+	encoded, err := json.Marshal(&snapshot)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page SEO: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// DecodePageSEO parses a snapshot produced by EncodePageSEO
+func DecodePageSEO(encoded string) (*types.PageSEO, error) {
+	var seo types.PageSEO
+	if err := json.Unmarshal([]byte(encoded), &seo); err != nil {
+		return nil, fmt.Errorf("invalid page SEO encoding: %w", err)
+	}
+	return &seo, nil
+}
+
 type MetadataStore struct {
 	redis        *redis.Client
 	keyGenerator *redis.KeyGenerator
 	logger       *zap.Logger
 	cacheDir     string
+	onChange     func(ctx context.Context, cacheKey *types.CacheKey)
+	events       *eventbus.Bus   // Optional, publishes entry changes to the cluster
+	duplicates   *DuplicateIndex // Optional, indexes page MinHash signatures for duplicate detection
+	seoHistory   *SEOHistory     // Optional, keeps previous PageSEO versions
+	links        *LinkGraph      // Optional, stores internal links of rendered pages
 }
 
 func NewMetadataStore(redisClient *redis.Client, keyGenerator *redis.KeyGenerator, cacheDir string, logger *zap.Logger) *MetadataStore {
@@ -336,30 +463,115 @@ func (ms *MetadataStore) ReleaseLock(ctx context.Context, key string) error {
 	return nil
 }
 
+// IsLocked reports whether the render lock key is held
+func (ms *MetadataStore) IsLocked(ctx context.Context, key string) (bool, error) {
+	locked, err := ms.redis.Exists(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to check lock: %w", err)
+	}
+	return locked, nil
+}
+
 // GenerateFilePath generates the filesystem path for a cache key without creating metadata
 func (ms *MetadataStore) GenerateFilePath(cacheKey *types.CacheKey, timestamp time.Time) string {
 	return ms.generateFilePath(cacheKey, timestamp)
 }
 
+// SetOnChange registers a callback invoked after metadata is stored or deleted
+// (used to invalidate in-memory copies of the entry)
+func (ms *MetadataStore) SetOnChange(onChange func(ctx context.Context, cacheKey *types.CacheKey)) {
+	ms.onChange = onChange
+}
+
+// SetEventBus publishes entry_written and entry_invalidated cluster events for metadata changes
+func (ms *MetadataStore) SetEventBus(events *eventbus.Bus) {
+	ms.events = events
+}
+
+// SetDuplicateIndex adds page signatures to the duplicate index when metadata is stored
+func (ms *MetadataStore) SetDuplicateIndex(duplicates *DuplicateIndex) {
+	ms.duplicates = duplicates
+}
+
+// SetSEOHistory records PageSEO snapshots in the history when metadata is stored
+func (ms *MetadataStore) SetSEOHistory(seoHistory *SEOHistory) {
+	ms.seoHistory = seoHistory
+}
+
+// SetLinkGraph stores the internal links of rendered pages in the link graph
+func (ms *MetadataStore) SetLinkGraph(links *LinkGraph) {
+	ms.links = links
+}
+
+// StoreLinks replaces the outlinks of a rendered page in the link graph (no-op when disabled).
+// Returns the number of newly discovered URLs queued for pre-rendering.
+func (ms *MetadataStore) StoreLinks(ctx context.Context, cacheKey *types.CacheKey, links []string, domains []string, stripPatterns []config.CompiledStripPattern) (int, error) {
+	return ms.links.Ingest(ctx, cacheKey, links, domains, stripPatterns)
+}
+
 // StoreMetadata stores pre-constructed metadata directly
 func (ms *MetadataStore) StoreMetadata(ctx context.Context, metadata *CacheMetadata, cacheKey *types.CacheKey, staleTTL time.Duration) error {
-	return ms.storeMetadata(ctx, metadata, cacheKey, staleTTL)
+	if err := ms.storeMetadata(ctx, metadata, cacheKey, staleTTL); err != nil {
+		return err
+	}
+	ms.NotifyChange(ctx, cacheKey, metadata)
+
+	// Non-fatal: the index only feeds duplicate reports
+	if err := ms.duplicates.Ingest(ctx, cacheKey, metadata.MinHash, metadataRedisTTL(metadata, staleTTL)); err != nil {
+		ms.logger.Warn("Failed to update duplicate index",
+			zap.String("key", cacheKey.String()),
+			zap.Error(err))
+	}
+
+	// Non-fatal: history is kept for reporting only. created_at is the render time
+	// (kept when an unchanged entry is extended in place).
+	if err := ms.seoHistory.Record(ctx, cacheKey, metadata.SEO, metadata.CreatedAt); err != nil {
+		ms.logger.Warn("Failed to record SEO history",
+			zap.String("key", cacheKey.String()),
+			zap.Error(err))
+	}
+	return nil
 }
 
 // DeleteMetadata deletes cache metadata from Redis
 func (ms *MetadataStore) DeleteMetadata(ctx context.Context, cacheKey *types.CacheKey) error {
 	metaKey := ms.keyGenerator.GenerateMetadataKey(cacheKey)
-	return ms.redis.Del(ctx, metaKey)
+	if err := ms.redis.Del(ctx, metaKey); err != nil {
+		return err
+	}
+	ms.NotifyChange(ctx, cacheKey, nil)
+
+	if err := ms.duplicates.Remove(ctx, cacheKey); err != nil {
+		ms.logger.Warn("Failed to update duplicate index",
+			zap.String("key", cacheKey.String()),
+			zap.Error(err))
+	}
+	if err := ms.links.Remove(ctx, cacheKey); err != nil {
+		ms.logger.Warn("Failed to update link graph",
+			zap.String("key", cacheKey.String()),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// NotifyChange reports a metadata change made outside StoreMetadata/DeleteMetadata
+// (e.g. eg_ids updated by a script). metadata is the new state, nil when deleted.
+func (ms *MetadataStore) NotifyChange(ctx context.Context, cacheKey *types.CacheKey, metadata *CacheMetadata) {
+	if ms.onChange != nil {
+		ms.onChange(ctx, cacheKey)
+	}
+
+	if metadata == nil {
+		ms.events.PublishEntries(ctx, eventbus.EntryInvalidated, eventbus.Entry{Key: cacheKey.String()})
+		return
+	}
+	ms.events.PublishEntries(ctx, eventbus.EntryWritten, eventbus.Entry{Key: cacheKey.String(), FilePath: metadata.FilePath})
 }
 
 func (ms *MetadataStore) storeMetadata(ctx context.Context, metadata *CacheMetadata, cacheKey *types.CacheKey, staleTTL time.Duration) error {
 	metaKey := ms.keyGenerator.GenerateMetadataKey(cacheKey)
 
-	// Calculate Redis TTL: base TTL + stale TTL (if enabled)
-	redisTTL := metadata.TTL()
-	if staleTTL > 0 {
-		redisTTL = redisTTL + staleTTL
-	}
+	redisTTL := metadataRedisTTL(metadata, staleTTL)
 
 	// CRITICAL: Refuse to store metadata that's already expired (TTL=0 means no expiration in Redis)
 	if redisTTL <= 0 {
@@ -389,6 +601,15 @@ func (ms *MetadataStore) storeMetadata(ctx context.Context, metadata *CacheMetad
 	return nil
 }
 
+// metadataRedisTTL returns the Redis TTL of a metadata key: base TTL + stale TTL (if enabled)
+func metadataRedisTTL(metadata *CacheMetadata, staleTTL time.Duration) time.Duration {
+	redisTTL := metadata.TTL()
+	if staleTTL > 0 {
+		redisTTL = redisTTL + staleTTL
+	}
+	return redisTTL
+}
+
 func (ms *MetadataStore) generateFilePath(cacheKey *types.CacheKey, timestamp time.Time) string {
 	year := timestamp.Format("2006")
 	month := timestamp.Format("01")
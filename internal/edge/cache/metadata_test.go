@@ -1,12 +1,15 @@
 package cache
 
 import (
+	"fmt"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/edgecomet/engine/pkg/types"
 )
 
 func TestCacheMetadata_ToHash(t *testing.T) {
@@ -1100,3 +1103,136 @@ func TestMetadataStore_GetAbsoluteFilePath(t *testing.T) {
 		assert.Equal(t, "/var/cache/edgecomet/1/2025/10/18/abc123_1.html.snappy", path)
 	})
 }
+
+func TestCacheMetadata_ContentFingerprint(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	signature := []uint64{1, 42, 1<<63 + 7}
+
+	metadata := &CacheMetadata{
+		Key:          "cache:1:1:abc123",
+		URL:          "https://example.com/test",
+		HostID:       1,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Hour),
+		LastAccess:   now,
+		Source:       SourceRender,
+		StatusCode:   200,
+		ContentHash:  ContentHash([]byte("<html>hello</html>")),
+		CanonicalURL: "https://example.com/canonical",
+		MinHash:      signature,
+	}
+
+	hash := metadata.ToHash()
+	data := make(map[string]string, len(hash))
+	for k, v := range hash {
+		switch val := v.(type) {
+		case string:
+			data[k] = val
+		case int:
+			data[k] = strconv.Itoa(val)
+		case int64:
+			data[k] = strconv.FormatInt(val, 10)
+		}
+	}
+
+	parsed := &CacheMetadata{}
+	require.NoError(t, parsed.FromHash(data))
+	assert.Equal(t, metadata.ContentHash, parsed.ContentHash)
+	assert.Equal(t, "https://example.com/canonical", parsed.CanonicalURL)
+	assert.Equal(t, signature, parsed.MinHash)
+
+	t.Run("content hash differs for different content", func(t *testing.T) {
+		assert.NotEqual(t, ContentHash([]byte("a")), ContentHash([]byte("b")))
+	})
+
+	t.Run("invalid minhash is ignored", func(t *testing.T) {
+		data["minhash"] = "not base64!"
+		parsed := &CacheMetadata{}
+		require.NoError(t, parsed.FromHash(data))
+		assert.Nil(t, parsed.MinHash)
+	})
+}
+
+func TestCacheMetadata_SEORoundTrip(t *testing.T) {
+	original := &CacheMetadata{
+		Key:        "cache:1:1:abc",
+		URL:        "https://example.com/en/",
+		HostID:     1,
+		StatusCode: 200,
+		CreatedAt:  time.Unix(1700000000, 0).UTC(),
+		ExpiresAt:  time.Unix(1700003600, 0).UTC(),
+		LastAccess: time.Unix(1700000000, 0).UTC(),
+		SEOIssues:  []string{"missing_h1", "low_word_count"},
+		SEO: &types.PageSEO{
+			Title:       "Home",
+			IndexStatus: types.IndexStatusIndexable,
+			MetaRobots:  []string{"index", "follow"},
+			H1s:         []string{"Welcome"},
+			WordCount:   250,
+			PageMinHash: []uint64{1, 2, 3},
+			Hreflang: []types.HreflangEntry{
+				{Lang: "en", URL: "https://example.com/en/"},
+				{Lang: "de", URL: "https://example.com/de/"},
+			},
+			StructuredDataTypes: []string{"Organization"},
+		},
+	}
+
+	hash := original.ToHash()
+	assert.Equal(t, "missing_h1,low_word_count", hash["seo_issues"])
+
+	data := make(map[string]string, len(hash))
+	for k, v := range hash {
+		data[k] = fmt.Sprintf("%v", v)
+	}
+
+	parsed := &CacheMetadata{}
+	require.NoError(t, parsed.FromHash(data))
+	assert.Equal(t, original.SEOIssues, parsed.SEOIssues)
+	require.NotNil(t, parsed.SEO)
+	assert.Nil(t, parsed.SEO.PageMinHash, "signature is stored in its own field")
+	parsed.SEO.PageMinHash = original.SEO.PageMinHash
+	assert.Equal(t, original.SEO, parsed.SEO)
+
+	t.Run("invalid snapshot is ignored", func(t *testing.T) {
+		data["seo"] = "{not json"
+		parsed := &CacheMetadata{}
+		require.NoError(t, parsed.FromHash(data))
+		assert.Nil(t, parsed.SEO)
+	})
+
+	t.Run("fields are omitted when empty", func(t *testing.T) {
+		hash := (&CacheMetadata{}).ToHash()
+		assert.NotContains(t, hash, "seo_issues")
+		assert.NotContains(t, hash, "seo")
+	})
+}
+
+func TestCacheMetadata_PageTextRoundTrip(t *testing.T) {
+	original := &CacheMetadata{
+		Key:         "cache:1:1:abc",
+		URL:         "https://example.com/",
+		HostID:      1,
+		StatusCode:  200,
+		CreatedAt:   time.Unix(1700000000, 0).UTC(),
+		ExpiresAt:   time.Unix(1700003600, 0).UTC(),
+		LastAccess:  time.Unix(1700000000, 0).UTC(),
+		Title:       "Home",
+		Description: "Welcome to the example shop",
+	}
+
+	hash := original.ToHash()
+	assert.Equal(t, "Welcome to the example shop", hash["description"])
+
+	data := make(map[string]string, len(hash))
+	for k, v := range hash {
+		data[k] = fmt.Sprint(v)
+	}
+	restored := &CacheMetadata{}
+	require.NoError(t, restored.FromHash(data))
+	assert.Equal(t, original.Title, restored.Title)
+	assert.Equal(t, original.Description, restored.Description)
+
+	_, exists := (&CacheMetadata{Key: "k"}).ToHash()["description"]
+	assert.False(t, exists, "empty description is not stored")
+}
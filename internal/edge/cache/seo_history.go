@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// luaSEOHistoryPush adds a PageSEO version unless the newest version has the same fingerprint.
+// Versions are stored as "{rendered_at}\t{fingerprint}\t{snapshot}", newest first.
+// KEYS[1] = history key
+// ARGV[1] = rendered_at (unix), ARGV[2] = fingerprint, ARGV[3] = snapshot JSON,
+// ARGV[4] = max versions, ARGV[5] = retention (ms)
+// Returns 1 if a version was added, 0 if unchanged.
+const luaSEOHistoryPush = `
+local head = redis.call("LINDEX", KEYS[1], 0)
+if head then
+    local first = string.find(head, "\t", 1, true)
+    if first then
+        local second = string.find(head, "\t", first + 1, true)
+        if second and string.sub(head, first + 1, second - 1) == ARGV[2] then
+            redis.call("PEXPIRE", KEYS[1], ARGV[5])
+            return 0
+        end
+    end
+end
+redis.call("LPUSH", KEYS[1], ARGV[1] .. "\t" .. ARGV[2] .. "\t" .. ARGV[3])
+redis.call("LTRIM", KEYS[1], 0, tonumber(ARGV[4]) - 1)
+redis.call("PEXPIRE", KEYS[1], ARGV[5])
+return 1
+`
+
+// SEOVersion is a PageSEO snapshot of one render
+type SEOVersion struct {
+	RenderedAt time.Time      `json:"rendered_at"`
+	SEO        *types.PageSEO `json:"seo"`
+}
+
+// SEOFieldChange is a tracked PageSEO field that differs between two versions
+type SEOFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type seoField struct {
+	name  string
+	value interface{}
+}
+
+// trackedSEOFields returns the PageSEO fields compared between versions. Counts such as
+// links and words change on most renders and are kept in snapshots without adding versions.
+func trackedSEOFields(seo *types.PageSEO) []seoField {
+	return []seoField{
+		{"title", seo.Title},
+		{"meta_description", seo.MetaDescription},
+		{"canonical_url", seo.CanonicalURL},
+		{"index_status", seo.IndexStatus},
+		{"meta_robots", nilIfEmpty(seo.MetaRobots)},
+		{"h1s", nilIfEmpty(seo.H1s)},
+		{"h2s", nilIfEmpty(seo.H2s)},
+		{"h3s", nilIfEmpty(seo.H3s)},
+		{"hreflang", hreflangOrNil(seo.Hreflang)},
+		{"structured_data_types", nilIfEmpty(seo.StructuredDataTypes)},
+	}
+}
+
+func nilIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func hreflangOrNil(entries []types.HreflangEntry) []types.HreflangEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+	return entries
+}
+
+// DiffPageSEO returns the tracked fields that changed from before to after
+func DiffPageSEO(before, after *types.PageSEO) []SEOFieldChange {
+	if before == nil {
+		before = &types.PageSEO{}
+	}
+	if after == nil {
+		after = &types.PageSEO{}
+	}
+
+	oldFields := trackedSEOFields(before)
+	newFields := trackedSEOFields(after)
+	var changes []SEOFieldChange
+	for i := range oldFields {
+		if !reflect.DeepEqual(oldFields[i].value, newFields[i].value) {
+			changes = append(changes, SEOFieldChange{
+				Field: oldFields[i].name,
+				Old:   oldFields[i].value,
+				New:   newFields[i].value,
+			})
+		}
+	}
+	return changes
+}
+
+// seoFingerprint hashes the tracked fields of a snapshot
+func seoFingerprint(seo *types.PageSEO) (string, error) {
+	fields := trackedSEOFields(seo)
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		values[i] = f.value
+	}
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(xxhash.Sum64(encoded), 16), nil
+}
+
+// SEOHistory keeps the last PageSEO versions of each cache entry in a Redis list.
+// A version is added only when a tracked field changed since the newest version.
+// A nil *SEOHistory is a disabled history.
+type SEOHistory struct {
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+	logger       *zap.Logger
+	maxVersions  int
+	retention    time.Duration
+}
+
+// NewSEOHistory creates an SEOHistory keeping maxVersions versions per cache entry for
+// retention after the last render. Readers that never record may pass zero limits.
+func NewSEOHistory(redisClient *redis.Client, keyGenerator *redis.KeyGenerator, maxVersions int, retention time.Duration, logger *zap.Logger) *SEOHistory {
+	return &SEOHistory{
+		redis:        redisClient,
+		keyGenerator: keyGenerator,
+		logger:       logger,
+		maxVersions:  maxVersions,
+		retention:    retention,
+	}
+}
+
+// Record adds the snapshot of a render to the entry's history
+func (h *SEOHistory) Record(ctx context.Context, cacheKey *types.CacheKey, seo *types.PageSEO, renderedAt time.Time) error {
+	if h == nil || seo == nil || h.maxVersions <= 0 || h.retention <= 0 {
+		return nil
+	}
+
+	fingerprint, err := seoFingerprint(seo)
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint page SEO: %w", err)
+	}
+	snapshot, err := EncodePageSEO(seo)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.redis.Eval(ctx, luaSEOHistoryPush,
+		[]string{h.keyGenerator.SEOHistoryKey(cacheKey)},
+		renderedAt.Unix(), fingerprint, snapshot, h.maxVersions, h.retention.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to record SEO history: %w", err)
+	}
+	return nil
+}
+
+// Versions returns the stored versions of a cache entry, newest first.
+// Versions that cannot be parsed are skipped.
+func (h *SEOHistory) Versions(ctx context.Context, cacheKey *types.CacheKey) ([]SEOVersion, error) {
+	key := h.keyGenerator.SEOHistoryKey(cacheKey)
+	values, err := h.redis.LRange(ctx, key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]SEOVersion, 0, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "\t", 3)
+		if len(parts) != 3 {
+			h.logger.Debug("Skipping invalid SEO history version", zap.String("key", key))
+			continue
+		}
+		renderedAt, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			h.logger.Debug("Skipping invalid SEO history version", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		seo, err := DecodePageSEO(parts[2])
+		if err != nil {
+			h.logger.Debug("Skipping invalid SEO history version", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		versions = append(versions, SEOVersion{RenderedAt: time.Unix(renderedAt, 0).UTC(), SEO: seo})
+	}
+	return versions, nil
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func setupTestSEOHistory(t *testing.T, maxVersions int) (*SEOHistory, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	return NewSEOHistory(redisClient, redis.NewKeyGenerator(), maxVersions, 24*time.Hour, zap.NewNop()), mr
+}
+
+func TestSEOHistory_Record(t *testing.T) {
+	history, mr := setupTestSEOHistory(t, 2)
+	ctx := context.Background()
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "abc"}
+	start := time.Unix(1700000000, 0).UTC()
+
+	v1 := &types.PageSEO{Title: "Shoes", H1s: []string{"Shoes"}, WordCount: 300, PageMinHash: []uint64{1, 2}}
+	require.NoError(t, history.Record(ctx, cacheKey, v1, start))
+
+	// Only untracked fields changed: no new version
+	unchanged := *v1
+	unchanged.WordCount = 320
+	unchanged.LinksTotal = 40
+	unchanged.H2s = []string{}
+	require.NoError(t, history.Record(ctx, cacheKey, &unchanged, start.Add(time.Hour)))
+
+	versions, err := history.Versions(ctx, cacheKey)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, start, versions[0].RenderedAt)
+	assert.Equal(t, "Shoes", versions[0].SEO.Title)
+	assert.Nil(t, versions[0].SEO.PageMinHash)
+
+	v2 := &types.PageSEO{Title: "Shoes | Shop", H1s: []string{"Shoes"}}
+	v3 := &types.PageSEO{Title: "Shoes | Shop", CanonicalURL: "https://example.com/shoes"}
+	require.NoError(t, history.Record(ctx, cacheKey, v2, start.Add(2*time.Hour)))
+	require.NoError(t, history.Record(ctx, cacheKey, v3, start.Add(3*time.Hour)))
+
+	versions, err = history.Versions(ctx, cacheKey)
+	require.NoError(t, err)
+	require.Len(t, versions, 2, "trimmed to max versions")
+	assert.Equal(t, start.Add(3*time.Hour), versions[0].RenderedAt, "newest first")
+	assert.Equal(t, "https://example.com/shoes", versions[0].SEO.CanonicalURL)
+	assert.Equal(t, start.Add(2*time.Hour), versions[1].RenderedAt)
+
+	assert.Equal(t, 24*time.Hour, mr.TTL(history.keyGenerator.SEOHistoryKey(cacheKey)))
+
+	t.Run("nil history is disabled", func(t *testing.T) {
+		var disabled *SEOHistory
+		assert.NoError(t, disabled.Record(ctx, cacheKey, v1, start))
+	})
+}
+
+func TestDiffPageSEO(t *testing.T) {
+	before := &types.PageSEO{
+		Title:               "Shoes",
+		IndexStatus:         types.IndexStatusIndexable,
+		H1s:                 []string{"Shoes"},
+		StructuredDataTypes: []string{"Product"},
+		WordCount:           300,
+	}
+	after := &types.PageSEO{
+		Title:        "Shoes",
+		IndexStatus:  types.IndexStatusBlockedByMeta,
+		MetaRobots:   []string{"noindex"},
+		H1s:          []string{"Shoes"},
+		WordCount:    20,
+		CanonicalURL: "https://example.com/shoes",
+	}
+
+	changes := DiffPageSEO(before, after)
+	assert.Equal(t, []SEOFieldChange{
+		{Field: "canonical_url", Old: "", New: "https://example.com/shoes"},
+		{Field: "index_status", Old: types.IndexStatusIndexable, New: types.IndexStatusBlockedByMeta},
+		{Field: "meta_robots", Old: []string(nil), New: []string{"noindex"}},
+		{Field: "structured_data_types", Old: []string{"Product"}, New: []string(nil)},
+	}, changes)
+
+	assert.Empty(t, DiffPageSEO(before, before))
+	assert.Len(t, DiffPageSEO(nil, &types.PageSEO{Title: "New"}), 1)
+}
+
+func TestMetadataStore_RecordsSEOHistory(t *testing.T) {
+	store, _, _ := setupTestDuplicateIndex(t)
+	history := NewSEOHistory(store.redis, store.keyGenerator, 10, time.Hour, zap.NewNop())
+	store.SetSEOHistory(history)
+
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "abc"}
+	now := time.Now().UTC().Truncate(time.Second)
+	metadata := &CacheMetadata{
+		Key:        cacheKey.String(),
+		URL:        "https://example.com/",
+		HostID:     1,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(time.Hour),
+		LastAccess: now,
+		Source:     SourceRender,
+		StatusCode: 200,
+		SEO:        &types.PageSEO{Title: "Home"},
+	}
+	require.NoError(t, store.StoreMetadata(context.Background(), metadata, cacheKey, 0))
+
+	versions, err := history.Versions(context.Background(), cacheKey)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, now, versions[0].RenderedAt)
+}
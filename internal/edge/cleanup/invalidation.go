@@ -0,0 +1,157 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/eventbus"
+	"github.com/edgecomet/engine/internal/edge/cache"
+)
+
+// InvalidationHandler deletes local cache files as soon as their entries are invalidated
+// cluster-wide, instead of leaving them for the cleanup worker. It implements eventbus.Handler.
+//
+// Only files written before the event are deleted: a file rendered after the invalidation
+// can have the same path (paths have minute resolution) and belongs to the new entry.
+type InvalidationHandler struct {
+	basePath    string
+	resolvePath func(relativePath string) (string, error)
+	logger      *zap.Logger
+}
+
+// NewInvalidationHandler creates a handler for the cache directory basePath. resolvePath
+// converts relative metadata file paths to absolute paths inside basePath.
+func NewInvalidationHandler(basePath string, resolvePath func(string) (string, error), logger *zap.Logger) *InvalidationHandler {
+	return &InvalidationHandler{
+		basePath:    basePath,
+		resolvePath: resolvePath,
+		logger:      logger,
+	}
+}
+
+// HandleEvent deletes the files of invalidated entries and purged hosts
+func (h *InvalidationHandler) HandleEvent(ctx context.Context, event *eventbus.Event) {
+	switch event.Type {
+	case eventbus.EntryInvalidated:
+		for _, entry := range event.Entries {
+			if entry.FilePath != "" {
+				h.deleteEntryFile(entry, event.Time)
+			}
+		}
+	case eventbus.HostPurged:
+		deleted, err := h.purgeHost(event.HostID, event.DimensionIDs, event.Time)
+		if err != nil {
+			h.logger.Warn("Failed to delete files of purged host",
+				zap.Int("host_id", event.HostID),
+				zap.Error(err))
+			return
+		}
+		h.logger.Info("Deleted cache files of purged host",
+			zap.Int("host_id", event.HostID),
+			zap.Ints("dimension_ids", event.DimensionIDs),
+			zap.Int("files_deleted", deleted))
+	}
+}
+
+// Resync only logs: files of missed invalidations are removed by the cleanup worker once expired
+func (h *InvalidationHandler) Resync(ctx context.Context) {
+	h.logger.Info("Cache invalidation events may have been missed, orphaned files are left to the cleanup worker")
+}
+
+// SetLive is a no-op, files are deleted whenever events arrive
+func (h *InvalidationHandler) SetLive(live bool) {}
+
+func (h *InvalidationHandler) deleteEntryFile(entry eventbus.Entry, before time.Time) {
+	path, err := h.resolvePath(entry.FilePath)
+	if err != nil {
+		h.logger.Warn("Ignoring invalid file path in invalidation event",
+			zap.String("cache_key", entry.Key),
+			zap.String("file_path", entry.FilePath),
+			zap.Error(err))
+		return
+	}
+
+	// The Markdown variant is written lazily on first request and may be missing
+	if _, err := removeIfOlder(cache.MarkdownPath(path), before); err != nil {
+		h.logger.Debug("Failed to delete Markdown variant of invalidated cache file",
+			zap.String("cache_key", entry.Key),
+			zap.Error(err))
+	}
+
+	removed, err := removeIfOlder(path, before)
+	if err != nil {
+		h.logger.Warn("Failed to delete invalidated cache file",
+			zap.String("cache_key", entry.Key),
+			zap.String("file_path", entry.FilePath),
+			zap.Error(err))
+		return
+	}
+	if removed {
+		h.logger.Debug("Deleted invalidated cache file",
+			zap.String("cache_key", entry.Key),
+			zap.String("file_path", entry.FilePath))
+	}
+}
+
+// purgeHost deletes files of hostID (limited to dimensionIDs when set) written before the purge
+func (h *InvalidationHandler) purgeHost(hostID int, dimensionIDs []int, before time.Time) (int, error) {
+	hostDir := filepath.Join(h.basePath, strconv.Itoa(hostID))
+	if _, err := os.Stat(hostDir); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	deleted := 0
+	err := filepath.WalkDir(hostDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if len(dimensionIDs) > 0 {
+			dimensionID, ok := fileDimensionID(d.Name())
+			if !ok || !slices.Contains(dimensionIDs, dimensionID) {
+				return nil
+			}
+		}
+		if removed, err := removeIfOlder(path, before); err == nil && removed {
+			deleted++
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// removeIfOlder removes path if it was last modified before t. A missing file is not an error.
+func removeIfOlder(path string, t time.Time) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !info.ModTime().Before(t) {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// fileDimensionID extracts the dimension ID from a cache file name ({url_hash}_{dimension_id}.html[.ext])
+func fileDimensionID(name string) (int, bool) {
+	stem, _, _ := strings.Cut(name, ".")
+	idx := strings.LastIndex(stem, "_")
+	if idx < 0 {
+		return 0, false
+	}
+	dimensionID, err := strconv.Atoi(stem[idx+1:])
+	return dimensionID, err == nil
+}
@@ -0,0 +1,96 @@
+package cleanup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/eventbus"
+)
+
+func writeCacheFile(t *testing.T, basePath, relPath string, modTime time.Time) string {
+	t.Helper()
+	path := filepath.Join(basePath, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("<html></html>"), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func newTestInvalidationHandler(basePath string) *InvalidationHandler {
+	resolve := func(rel string) (string, error) { return filepath.Join(basePath, rel), nil }
+	return NewInvalidationHandler(basePath, resolve, zap.NewNop())
+}
+
+func TestInvalidationHandler_EntryInvalidated(t *testing.T) {
+	basePath := t.TempDir()
+	now := time.Now().UTC()
+	old := writeCacheFile(t, basePath, "1/2025/10/17/14/30/abc_1.html", now.Add(-time.Hour))
+	oldMarkdown := writeCacheFile(t, basePath, "1/2025/10/17/14/30/abc_1.html.md", now.Add(-time.Hour))
+	fresh := writeCacheFile(t, basePath, "1/2025/10/17/14/31/def_1.html", now.Add(time.Minute))
+
+	h := newTestInvalidationHandler(basePath)
+	h.HandleEvent(context.Background(), &eventbus.Event{
+		Type: eventbus.EntryInvalidated,
+		Time: now,
+		Entries: []eventbus.Entry{
+			{Key: "cache:1:1:abc", FilePath: "1/2025/10/17/14/30/abc_1.html"},
+			{Key: "cache:1:1:def", FilePath: "1/2025/10/17/14/31/def_1.html"}, // Rewritten after the event
+			{Key: "cache:1:1:gone", FilePath: "1/2025/10/17/14/30/gone_1.html"},
+			{Key: "cache:1:1:nofile"},
+		},
+	})
+
+	assert.NoFileExists(t, old)
+	assert.NoFileExists(t, oldMarkdown)
+	assert.FileExists(t, fresh)
+}
+
+func TestInvalidationHandler_HostPurged(t *testing.T) {
+	basePath := t.TempDir()
+	now := time.Now().UTC()
+	before := now.Add(-time.Hour)
+	dim1 := writeCacheFile(t, basePath, "1/2025/10/17/14/30/abc_1.html", before)
+	dim2 := writeCacheFile(t, basePath, "1/2025/10/17/14/30/abc_2.html.br", before)
+	otherHost := writeCacheFile(t, basePath, "2/2025/10/17/14/30/abc_2.html", before)
+	afterPurge := writeCacheFile(t, basePath, "1/2025/10/17/14/32/new_2.html", now.Add(time.Minute))
+
+	h := newTestInvalidationHandler(basePath)
+	h.HandleEvent(context.Background(), &eventbus.Event{Type: eventbus.HostPurged, Time: now, HostID: 1, DimensionIDs: []int{2}})
+
+	assert.FileExists(t, dim1)
+	assert.NoFileExists(t, dim2)
+	assert.FileExists(t, otherHost)
+	assert.FileExists(t, afterPurge)
+
+	h.HandleEvent(context.Background(), &eventbus.Event{Type: eventbus.HostPurged, Time: now, HostID: 1})
+	assert.NoFileExists(t, dim1)
+	assert.FileExists(t, afterPurge)
+
+	// Unknown host directory is not an error
+	h.HandleEvent(context.Background(), &eventbus.Event{Type: eventbus.HostPurged, Time: now, HostID: 9})
+}
+
+func TestFileDimensionID(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantID int
+		wantOK bool
+	}{
+		{"abc123_1.html", 1, true},
+		{"abc123_12.html.zst", 12, true},
+		{"abc.html", 0, false},
+		{"abc_x.html", 0, false},
+	}
+	for _, tt := range tests {
+		id, ok := fileDimensionID(tt.name)
+		assert.Equal(t, tt.wantOK, ok, tt.name)
+		assert.Equal(t, tt.wantID, id, tt.name)
+	}
+}
@@ -2,6 +2,10 @@ package configtest
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/edgecomet/engine/pkg/types"
@@ -75,9 +79,52 @@ func printRenderConfig(result *HostTestResult) {
 
 	fmt.Println("  - JavaScript: enabled")
 
+	fmt.Println()
+	printTransforms(os.Stdout, result.Config.Render.Transforms)
+
 	// TODO: Print extra headers if configured
 }
 
+// maxTransformHTMLLength truncates inject_head snippets in the transform list
+const maxTransformHTMLLength = 60
+
+// printTransforms prints the resolved HTML transforms in the order they are applied
+func printTransforms(w io.Writer, transforms []types.HTMLTransform) {
+	if len(transforms) == 0 {
+		fmt.Fprintln(w, "Transforms: (none)")
+		return
+	}
+
+	fmt.Fprintln(w, "Transforms:")
+	for i, transform := range transforms {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, formatTransform(transform))
+	}
+}
+
+// formatTransform formats a transform as its type followed by the options it sets
+func formatTransform(transform types.HTMLTransform) string {
+	parts := []string{transform.Type}
+	add := func(name, value string) {
+		if value != "" {
+			parts = append(parts, name+"="+strconv.Quote(value))
+		}
+	}
+
+	add("selector", transform.Selector)
+	add("attribute", transform.Attribute)
+	add("value", transform.Value)
+	add("from", transform.From)
+	add("to", transform.To)
+	if html := transform.HTML; html != "" {
+		if len(html) > maxTransformHTMLLength {
+			html = html[:maxTransformHTMLLength] + "..."
+		}
+		add("html", html)
+	}
+
+	return strings.Join(parts, " ")
+}
+
 // printBypassConfig prints bypass action configuration
 func printBypassConfig(result *HostTestResult) {
 	// Check if bypass cache is enabled
@@ -0,0 +1,72 @@
+package configtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgecomet/engine/internal/edge/validate"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestPrintTransforms(t *testing.T) {
+	result, err := validate.ValidateConfiguration("../../../tests/integration/fixtures/configtest-url-tester/edge-gateway.yaml")
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+
+	t.Run("matched rule transforms in order", func(t *testing.T) {
+		urlResult, err := TestURL("https://example.com/blog/post", result)
+		require.NoError(t, err)
+		require.Len(t, urlResult.HostResults, 1)
+
+		var out bytes.Buffer
+		printTransforms(&out, urlResult.HostResults[0].Config.Render.Transforms)
+		assert.Equal(t, `Transforms:
+  1. remove selector="script.tracking"
+  2. rewrite_urls from="https://cdn-old.example.com/" to="https://cdn.example.com/"
+`, out.String())
+	})
+
+	t.Run("no transforms", func(t *testing.T) {
+		urlResult, err := TestURL("https://blog.example.com/post", result)
+		require.NoError(t, err)
+		require.Len(t, urlResult.HostResults, 1)
+
+		var out bytes.Buffer
+		printTransforms(&out, urlResult.HostResults[0].Config.Render.Transforms)
+		assert.Equal(t, "Transforms: (none)\n", out.String())
+	})
+}
+
+func TestFormatTransform(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform types.HTMLTransform
+		expected  string
+	}{
+		{
+			name:      "set attribute",
+			transform: types.HTMLTransform{Type: types.HTMLTransformSetAttribute, Selector: "link[rel=canonical]", Attribute: "href", Value: "{url}"},
+			expected:  `set_attribute selector="link[rel=canonical]" attribute="href" value="{url}"`,
+		},
+		{
+			name:      "minify has no options",
+			transform: types.HTMLTransform{Type: types.HTMLTransformMinify},
+			expected:  "minify",
+		},
+		{
+			name:      "long inject_head snippet is truncated",
+			transform: types.HTMLTransform{Type: types.HTMLTransformInjectHead, HTML: strings.Repeat("a", 70)},
+			expected:  `inject_head html="` + strings.Repeat("a", 60) + `..."`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatTransform(tt.transform))
+		})
+	}
+}
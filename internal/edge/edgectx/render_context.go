@@ -42,6 +42,13 @@ type RenderContext struct {
 	// Dimension action tracking
 	DimensionAction string
 
+	// Response format: html or markdown (empty means html)
+	OutputFormat string
+
+	// robots.txt verdict for the requesting bot (empty if not checked)
+	RobotsVerdict string // allow or disallow
+	RobotsGroup   string // User agent token of the applied robots.txt group
+
 	// Event logging flags
 	IsPrecache bool // True if this is a precache/recache request (set by recache handler)
 }
@@ -168,3 +175,8 @@ func (rc *RenderContext) ContextWithTimeout(operationTimeout time.Duration) (con
 
 	return context.WithTimeout(context.Background(), timeout)
 }
+
+// WantsMarkdown reports whether the rendered page is served as Markdown
+func (rc *RenderContext) WantsMarkdown() bool {
+	return rc.OutputFormat == types.OutputFormatMarkdown
+}
@@ -53,6 +53,11 @@ func BuildRequestEvent(
 		}
 		event.ClientIP = renderCtx.ClientIP
 		event.DimensionAction = renderCtx.DimensionAction
+		if renderCtx.WantsMarkdown() {
+			event.OutputFormat = renderCtx.OutputFormat
+		}
+		event.RobotsVerdict = renderCtx.RobotsVerdict
+		event.RobotsGroup = renderCtx.RobotsGroup
 
 		if renderCtx.Host != nil {
 			event.Host = renderCtx.Host.Domain
@@ -80,6 +85,7 @@ func BuildRequestEvent(
 		event.ErrorType = result.ErrorType
 		event.ErrorMessage = result.ErrorMessage
 		event.RedirectTo = result.RedirectTo
+		event.RenderAttempts = convertRenderAttempts(result.Attempts)
 
 		// Map ResponseSource to EventType and Source
 		event.EventType, event.Source = mapResponseSource(result.Source)
@@ -93,6 +99,11 @@ func BuildRequestEvent(
 		if result.PageSEO != nil {
 			event.PageSEO = convertPageSEO(result.PageSEO)
 		}
+
+		// Convert ContentChange if present (recache only)
+		if result.ContentChange != nil {
+			event.ContentChange = convertContentChange(result.ContentChange)
+		}
 	}
 
 	// Override EventType for precache requests
@@ -232,6 +243,8 @@ func convertPageSEO(seo *types.PageSEO) *PageSEOEvent {
 		PageMinHash:         seo.PageMinHash,
 		HreflangSelf:        seo.HreflangSelf,
 		StructuredDataTypes: seo.StructuredDataTypes,
+
+		StructuredDataFormats: seo.StructuredDataFormats,
 	}
 
 	// Convert hreflang entries
@@ -245,5 +258,45 @@ func convertPageSEO(seo *types.PageSEO) *PageSEOEvent {
 		}
 	}
 
+	// Convert structured data issues
+	if len(seo.StructuredDataIssues) > 0 {
+		event.StructuredDataIssues = make([]StructuredDataIssueEvent, len(seo.StructuredDataIssues))
+		for i, issue := range seo.StructuredDataIssues {
+			event.StructuredDataIssues[i] = StructuredDataIssueEvent{
+				Issue:    issue.Issue,
+				Type:     issue.Type,
+				Property: issue.Property,
+			}
+		}
+	}
+
 	return event
 }
+
+// convertRenderAttempts converts orchestrator.RenderAttempt entries to RenderAttemptEvent (nil if none)
+func convertRenderAttempts(attempts []orchestrator.RenderAttempt) []RenderAttemptEvent {
+	if len(attempts) == 0 {
+		return nil
+	}
+	result := make([]RenderAttemptEvent, len(attempts))
+	for i, attempt := range attempts {
+		result[i] = RenderAttemptEvent{
+			RenderServiceID: attempt.ServiceID,
+			ErrorType:       attempt.ErrorType,
+		}
+	}
+	return result
+}
+
+// convertContentChange converts orchestrator.ContentChange to ContentChangeEvent
+func convertContentChange(change *orchestrator.ContentChange) *ContentChangeEvent {
+	return &ContentChangeEvent{
+		Changed:             change.Changed,
+		ContentHashMatch:    change.ContentHashMatch,
+		Similarity:          change.Similarity,
+		ChangedFields:       change.ChangedFields,
+		PreviousTitle:       change.PreviousTitle,
+		PreviousCanonical:   change.PreviousCanonical,
+		PreviousIndexStatus: change.PreviousIndexStatus,
+	}
+}
@@ -97,6 +97,33 @@ func TestBuildRequestEvent_Render(t *testing.T) {
 	assert.Equal(t, 1, event.Metrics.WarningCount)
 }
 
+func TestBuildRequestEvent_StructuredDataIssues(t *testing.T) {
+	renderCtx := createTestRenderContext()
+	result := &orchestrator.RenderResult{
+		Source:     orchestrator.ServedFromRender,
+		StatusCode: 200,
+		PageSEO: &types.PageSEO{
+			StructuredDataTypes:   []string{"Product"},
+			StructuredDataFormats: []string{types.StructuredDataFormatJSONLD},
+			StructuredDataIssues: []types.StructuredDataIssue{
+				{Issue: types.StructuredDataIssueMissingProperty, Type: "Product", Property: "offers|review|aggregateRating"},
+			},
+		},
+	}
+
+	event := BuildRequestEvent(renderCtx, result, time.Second, "eg-1")
+
+	require.NotNil(t, event.PageSEO)
+	assert.Equal(t, []string{types.StructuredDataFormatJSONLD}, event.PageSEO.StructuredDataFormats)
+	assert.Equal(t, []StructuredDataIssueEvent{
+		{Issue: types.StructuredDataIssueMissingProperty, Type: "Product", Property: "offers|review|aggregateRating"},
+	}, event.PageSEO.StructuredDataIssues)
+
+	formatter, err := NewTemplateFormatter("{structured_data_issues}")
+	require.NoError(t, err)
+	assert.Equal(t, "1", formatter.Format(event))
+}
+
 func TestBuildRequestEvent_Bypass(t *testing.T) {
 	renderCtx := createTestRenderContext()
 	result := &orchestrator.RenderResult{
@@ -174,6 +201,59 @@ func TestBuildRequestEvent_PrecacheOverride(t *testing.T) {
 	assert.Equal(t, SourceRender, event.Source)
 }
 
+func TestBuildRequestEvent_ContentChange(t *testing.T) {
+	renderCtx := createTestRenderContext()
+	renderCtx.IsPrecache = true
+
+	result := &orchestrator.RenderResult{
+		Source:     orchestrator.ServedFromRender,
+		StatusCode: 200,
+		ContentChange: &orchestrator.ContentChange{
+			Changed:       true,
+			Similarity:    0.8,
+			ChangedFields: []string{orchestrator.ContentFieldTitle},
+			PreviousTitle: "Old Title",
+		},
+	}
+
+	event := BuildRequestEvent(renderCtx, result, time.Second, "eg-1")
+
+	require.NotNil(t, event.ContentChange)
+	assert.True(t, event.ContentChange.Changed)
+	assert.Equal(t, 0.8, event.ContentChange.Similarity)
+	assert.Equal(t, []string{"title"}, event.ContentChange.ChangedFields)
+	assert.Equal(t, "Old Title", event.ContentChange.PreviousTitle)
+
+	// Non-recache results carry no content change
+	event = BuildRequestEvent(renderCtx, &orchestrator.RenderResult{Source: orchestrator.ServedFromRender}, time.Second, "eg-1")
+	assert.Nil(t, event.ContentChange)
+}
+
+func TestBuildRequestEvent_RenderAttempts(t *testing.T) {
+	renderCtx := createTestRenderContext()
+
+	result := &orchestrator.RenderResult{
+		Source:     orchestrator.ServedFromRender,
+		ServiceID:  "rs-2",
+		StatusCode: 200,
+		Attempts: []orchestrator.RenderAttempt{
+			{ServiceID: "rs-1", ErrorType: types.ErrorTypeChromeCrash},
+			{ServiceID: "rs-2"},
+		},
+	}
+
+	event := BuildRequestEvent(renderCtx, result, time.Second, "eg-1")
+
+	assert.Equal(t, []RenderAttemptEvent{
+		{RenderServiceID: "rs-1", ErrorType: types.ErrorTypeChromeCrash},
+		{RenderServiceID: "rs-2"},
+	}, event.RenderAttempts)
+
+	// First-attempt renders carry no attempts
+	event = BuildRequestEvent(renderCtx, &orchestrator.RenderResult{Source: orchestrator.ServedFromRender}, time.Second, "eg-1")
+	assert.Nil(t, event.RenderAttempts)
+}
+
 func TestBuildRequestEvent_MatchedRule(t *testing.T) {
 	renderCtx := createTestRenderContext()
 	renderCtx.ResolvedConfig = &config.ResolvedConfig{
@@ -739,3 +819,35 @@ func createTestRenderContext() *edgectx.RenderContext {
 		},
 	}
 }
+
+func TestBuildRequestEvent_OutputFormat(t *testing.T) {
+	result := &orchestrator.RenderResult{
+		Source:     orchestrator.ServedFromCache,
+		StatusCode: 200,
+	}
+
+	renderCtx := createTestRenderContext()
+	event := BuildRequestEvent(renderCtx, result, 10*time.Millisecond, "eg-1")
+	assert.Empty(t, event.OutputFormat, "HTML responses leave output_format unset")
+
+	renderCtx.OutputFormat = types.OutputFormatMarkdown
+	event = BuildRequestEvent(renderCtx, result, 10*time.Millisecond, "eg-1")
+	assert.Equal(t, "markdown", event.OutputFormat)
+}
+
+func TestBuildRequestEvent_RobotsVerdict(t *testing.T) {
+	result := &orchestrator.RenderResult{
+		Source:     orchestrator.ServedFromCache,
+		StatusCode: 200,
+	}
+
+	renderCtx := createTestRenderContext()
+	event := BuildRequestEvent(renderCtx, result, 10*time.Millisecond, "eg-1")
+	assert.Empty(t, event.RobotsVerdict, "unchecked requests leave robots_verdict unset")
+
+	renderCtx.RobotsVerdict = "disallow"
+	renderCtx.RobotsGroup = "googlebot"
+	event = BuildRequestEvent(renderCtx, result, 10*time.Millisecond, "eg-1")
+	assert.Equal(t, "disallow", event.RobotsVerdict)
+	assert.Equal(t, "googlebot", event.RobotsGroup)
+}
@@ -30,11 +30,21 @@ type RequestEvent struct {
 	Source     string  `json:"source"`     // cache, render, bypass, bypass_cache
 	RedirectTo string  `json:"redirect_to,omitempty"`
 
+	// OutputFormat is set when the response was converted from the rendered HTML (markdown)
+	OutputFormat string `json:"output_format,omitempty"`
+
+	// robots.txt verdict for the requesting bot's user agent group (hosts with robots_txt enabled)
+	RobotsVerdict string `json:"robots_verdict,omitempty"` // allow, disallow
+	RobotsGroup   string `json:"robots_group,omitempty"`
+
 	// Render-specific
 	RenderServiceID string  `json:"render_service_id"`
 	RenderTime      float64 `json:"render_time"` // seconds
 	ChromeID        string  `json:"chrome_id"`
 
+	// Render service calls, set when a render attempt failed (retries included)
+	RenderAttempts []RenderAttemptEvent `json:"render_attempts,omitempty"`
+
 	// Cache metadata
 	CacheAge int    `json:"cache_age"` // seconds
 	CacheKey string `json:"cache_key"`
@@ -49,11 +59,31 @@ type RequestEvent struct {
 	// SEO metadata (nil for cache hits, bypass)
 	PageSEO *PageSEOEvent `json:"page_seo,omitempty"`
 
+	// Content comparison with the previous cached version (recache only)
+	ContentChange *ContentChangeEvent `json:"content_change,omitempty"`
+
 	// Timestamps
 	CreatedAt    time.Time `json:"created_at"`
 	EGInstanceID string    `json:"eg_instance_id"`
 }
 
+// RenderAttemptEvent is one render service call made for a request
+type RenderAttemptEvent struct {
+	RenderServiceID string `json:"render_service_id"`
+	ErrorType       string `json:"error_type,omitempty"` // Empty for the successful attempt
+}
+
+// ContentChangeEvent describes how recached content compares to the previous version
+type ContentChangeEvent struct {
+	Changed             bool     `json:"changed"`
+	ContentHashMatch    bool     `json:"content_hash_match"`
+	Similarity          float64  `json:"similarity"`
+	ChangedFields       []string `json:"changed_fields,omitempty"` // title, canonical, index_status
+	PreviousTitle       string   `json:"previous_title,omitempty"`
+	PreviousCanonical   string   `json:"previous_canonical,omitempty"`
+	PreviousIndexStatus int      `json:"previous_index_status,omitempty"`
+}
+
 // PageMetricsEvent contains render performance metrics
 type PageMetricsEvent struct {
 	FinalURL           string               `json:"final_url"`
@@ -120,4 +150,14 @@ type PageSEOEvent struct {
 	Hreflang            []HreflangEntryEvent `json:"hreflang,omitempty"`
 	HreflangSelf        string               `json:"hreflang_self,omitempty"`
 	StructuredDataTypes []string             `json:"structured_data_types,omitempty"`
+
+	StructuredDataFormats []string                   `json:"structured_data_formats,omitempty"`
+	StructuredDataIssues  []StructuredDataIssueEvent `json:"structured_data_issues,omitempty"` // Invalid or incomplete JSON-LD
+}
+
+// StructuredDataIssueEvent represents a structured data issue in events
+type StructuredDataIssueEvent struct {
+	Issue    string `json:"issue"`
+	Type     string `json:"type,omitempty"`
+	Property string `json:"property,omitempty"`
 }
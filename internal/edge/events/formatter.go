@@ -39,11 +39,17 @@ var validFields = map[string]bool{
 	"serve_time":                    true,
 	"source":                        true,
 	"redirect_to":                   true,
+	"output_format":                 true,
+	"robots_verdict":                true,
+	"robots_group":                  true,
 	"render_service_id":             true,
 	"render_time":                   true,
 	"chrome_id":                     true,
+	"render_attempts":               true,
 	"title":                         true,
 	"index_status":                  true,
+	"structured_data_issues":        true,
+	"content_changed":               true,
 	"cache_age":                     true,
 	"cache_key":                     true,
 	"error_type":                    true,
@@ -215,12 +221,23 @@ func (f *TemplateFormatter) getTopLevelFieldValue(event *RequestEvent, field str
 		return formatString(event.Source)
 	case "redirect_to":
 		return formatString(event.RedirectTo)
+	case "output_format":
+		return formatString(event.OutputFormat)
+	case "robots_verdict":
+		return formatString(event.RobotsVerdict)
+	case "robots_group":
+		return formatString(event.RobotsGroup)
 	case "render_service_id":
 		return formatString(event.RenderServiceID)
 	case "render_time":
 		return formatFloat(event.RenderTime)
 	case "chrome_id":
 		return formatString(event.ChromeID)
+	case "render_attempts":
+		if len(event.RenderAttempts) > 0 {
+			return formatInt(len(event.RenderAttempts))
+		}
+		return "-"
 	case "title":
 		if event.PageSEO != nil {
 			return formatString(event.PageSEO.Title)
@@ -231,6 +248,16 @@ func (f *TemplateFormatter) getTopLevelFieldValue(event *RequestEvent, field str
 			return formatInt(event.PageSEO.IndexStatus)
 		}
 		return formatInt(0)
+	case "structured_data_issues":
+		if event.PageSEO != nil {
+			return formatInt(len(event.PageSEO.StructuredDataIssues))
+		}
+		return "-"
+	case "content_changed":
+		if event.ContentChange != nil {
+			return formatBool(event.ContentChange.Changed)
+		}
+		return "-"
 	case "cache_age":
 		return formatInt(event.CacheAge)
 	case "cache_key":
@@ -174,9 +174,9 @@ func TestNewTemplateFormatter_AllValidFields(t *testing.T) {
 	validFieldsList := []string{
 		"timestamp", "request_id", "host", "host_id", "url", "url_hash",
 		"event_type", "dimension", "user_agent", "client_ip", "matched_rule",
-		"status_code", "page_size", "serve_time", "source",
+		"status_code", "page_size", "serve_time", "source", "robots_verdict", "robots_group",
 		"render_service_id", "render_time", "chrome_id",
-		"title", "index_status", "cache_age", "cache_key",
+		"title", "index_status", "structured_data_issues", "content_changed", "cache_age", "cache_key",
 		"error_type", "error_message", "eg_instance_id",
 		"metrics.final_url", "metrics.total_requests", "metrics.total_bytes",
 		"metrics.same_origin_requests", "metrics.same_origin_bytes",
@@ -0,0 +1,344 @@
+package hotcache
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// entry is a cached metadata record with an optional decoded body
+type entry struct {
+	key       string
+	hash      uint64
+	hostID    int
+	dimension int
+	metadata  *cache.CacheMetadata
+	body      []byte // Decoded body ready to serve, nil when only metadata is held
+	expiresAt time.Time
+}
+
+// Cache is a bounded in-process cache of hot entries. Recency is tracked with an LRU list;
+// new entries are admitted only when they were requested more often than the entries they
+// would evict (TinyLFU admission), so one-off requests do not flush popular pages.
+type Cache struct {
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	lru      *list.List // Front = most recently used
+	sketch   *frequencySketch
+	bodySize int64 // Total bytes of held bodies
+
+	maxEntries  int
+	maxBytes    int64
+	maxBodySize int64
+	storeBodies bool
+	maxAge      time.Duration
+
+	// epoch increments on every invalidation; inserts based on reads that started
+	// before an invalidation are dropped so stale metadata is never re-admitted
+	epoch     atomic.Uint64
+	available atomic.Bool
+
+	metrics *Metrics
+	now     func() time.Time
+}
+
+// New creates a hot cache sized from cfg. The cache starts unavailable until
+// MarkAvailable is called (the invalidation subscriber does this once subscribed).
+func New(cfg *configtypes.HotCacheConfig, metrics *Metrics) *Cache {
+	maxEntries := cfg.GetMaxEntries()
+	return &Cache{
+		items:       make(map[string]*list.Element, maxEntries),
+		lru:         list.New(),
+		sketch:      newFrequencySketch(maxEntries),
+		maxEntries:  maxEntries,
+		maxBytes:    cfg.GetMaxSizeBytes(),
+		maxBodySize: cfg.GetMaxBodySizeBytes(),
+		storeBodies: cfg.ShouldStoreBodies(),
+		maxAge:      cfg.GetMaxAge(),
+		metrics:     metrics,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Epoch returns a token to pass to SetMetadata. Take it before reading metadata from Redis.
+func (c *Cache) Epoch() uint64 {
+	return c.epoch.Load()
+}
+
+// MarkAvailable enables or disables serving from the cache. While unavailable (invalidation
+// feed disconnected) every lookup misses and nothing is stored.
+func (c *Cache) MarkAvailable(available bool) {
+	c.available.Store(available)
+}
+
+// Available reports whether the cache serves lookups
+func (c *Cache) Available() bool {
+	return c.available.Load()
+}
+
+// GetMetadata returns a copy of the cached metadata for key and records the request
+// for admission decisions
+func (c *Cache) GetMetadata(key string) (*cache.CacheMetadata, bool) {
+	if !c.Available() {
+		return nil, false
+	}
+	hash := xxhash.Sum64String(key)
+
+	c.mu.Lock()
+	c.sketch.increment(hash)
+	e, ok := c.lookup(key)
+	var metadata *cache.CacheMetadata
+	if ok {
+		metadata = cloneMetadata(e.metadata)
+	}
+	c.mu.Unlock()
+
+	c.metrics.recordRequest(TierMetadata, ok)
+	return metadata, ok
+}
+
+// GetBody returns the decoded body cached for key when it belongs to filePath
+func (c *Cache) GetBody(key, filePath string) ([]byte, bool) {
+	if !c.Available() || !c.storeBodies {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	e, ok := c.lookup(key)
+	var body []byte
+	if ok && e.body != nil && e.metadata.FilePath == filePath {
+		body = e.body
+	}
+	c.mu.Unlock()
+
+	c.metrics.recordRequest(TierBody, body != nil)
+	return body, body != nil
+}
+
+// SetMetadata offers metadata read at epoch for key. Only fresh entries are admitted.
+// Returns true if the entry was stored.
+func (c *Cache) SetMetadata(key string, metadata *cache.CacheMetadata, epoch uint64) bool {
+	if !c.Available() || metadata == nil || metadata.IsExpired() {
+		return false
+	}
+
+	now := c.now()
+	expiresAt := now.Add(c.maxAge)
+	if metadata.ExpiresAt.Before(expiresAt) {
+		expiresAt = metadata.ExpiresAt
+	}
+
+	hostID, dimension := 0, 0
+	if cacheKey, err := types.ParseCacheKey(key); err == nil {
+		hostID, dimension = cacheKey.HostID, cacheKey.DimensionID
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch.Load() != epoch {
+		return false // Invalidated while the caller was reading from Redis
+	}
+
+	if elem, ok := c.items[key]; ok {
+		// Refresh in place, dropping a body that no longer matches
+		e := elem.Value.(*entry)
+		if e.metadata.FilePath != metadata.FilePath {
+			c.bodySize -= int64(len(e.body))
+			e.body = nil
+		}
+		e.metadata = cloneMetadata(metadata)
+		e.expiresAt = expiresAt
+		c.lru.MoveToFront(elem)
+		c.metrics.updateSize(c.lru.Len(), c.bodySize)
+		return true
+	}
+
+	e := &entry{
+		key:       key,
+		hash:      xxhash.Sum64String(key),
+		hostID:    hostID,
+		dimension: dimension,
+		metadata:  cloneMetadata(metadata),
+		expiresAt: expiresAt,
+	}
+
+	if c.lru.Len() >= c.maxEntries {
+		victim := c.lru.Back()
+		if !c.admit(e.hash, []*list.Element{victim}) {
+			c.metrics.recordAdmission(false)
+			return false
+		}
+		c.remove(victim)
+		c.metrics.recordEvictions(evictCapacity, 1)
+	}
+
+	c.items[key] = c.lru.PushFront(e)
+	c.metrics.recordAdmission(true)
+	c.metrics.updateSize(c.lru.Len(), c.bodySize)
+	return true
+}
+
+// SetBody attaches a decoded body to the cached entry for key if the entry still points
+// at filePath. Less frequently requested entries are evicted to stay within max_size.
+func (c *Cache) SetBody(key, filePath string, body []byte) bool {
+	size := int64(len(body))
+	if !c.Available() || !c.storeBodies || size == 0 || size > c.maxBodySize || size > c.maxBytes {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	e := elem.Value.(*entry)
+	if e.metadata.FilePath != filePath || e.body != nil {
+		return false
+	}
+
+	// Collect least recently used bodies until the new one fits
+	var victims []*list.Element
+	freed := int64(0)
+	for victim := c.lru.Back(); victim != nil && c.bodySize-freed+size > c.maxBytes; victim = victim.Prev() {
+		if victim == elem {
+			continue
+		}
+		if ve := victim.Value.(*entry); ve.body != nil {
+			victims = append(victims, victim)
+			freed += int64(len(ve.body))
+		}
+	}
+	if c.bodySize-freed+size > c.maxBytes || !c.admit(e.hash, victims) {
+		c.metrics.recordAdmission(false)
+		return false
+	}
+
+	for _, victim := range victims {
+		c.remove(victim)
+	}
+	c.metrics.recordEvictions(evictCapacity, len(victims))
+
+	e.body = body
+	c.bodySize += size
+	c.metrics.updateSize(c.lru.Len(), c.bodySize)
+	return true
+}
+
+// Invalidate removes key
+func (c *Cache) Invalidate(key string) {
+	c.epoch.Add(1)
+
+	c.mu.Lock()
+	removed := 0
+	if elem, ok := c.items[key]; ok {
+		c.remove(elem)
+		removed = 1
+	}
+	c.metrics.updateSize(c.lru.Len(), c.bodySize)
+	c.mu.Unlock()
+
+	c.metrics.recordEvictions(evictInvalidated, removed)
+}
+
+// InvalidateHost removes all entries of hostID, limited to dimensionIDs when not empty
+func (c *Cache) InvalidateHost(hostID int, dimensionIDs []int) {
+	c.epoch.Add(1)
+
+	c.mu.Lock()
+	removed := 0
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		e := elem.Value.(*entry)
+		if e.hostID == hostID && (len(dimensionIDs) == 0 || slices.Contains(dimensionIDs, e.dimension)) {
+			c.remove(elem)
+			removed++
+		}
+		elem = next
+	}
+	c.metrics.updateSize(c.lru.Len(), c.bodySize)
+	c.mu.Unlock()
+
+	c.metrics.recordEvictions(evictInvalidated, removed)
+}
+
+// Purge removes all entries
+func (c *Cache) Purge() {
+	c.epoch.Add(1)
+
+	c.mu.Lock()
+	removed := c.lru.Len()
+	c.items = make(map[string]*list.Element, c.maxEntries)
+	c.lru.Init()
+	c.bodySize = 0
+	c.metrics.updateSize(0, 0)
+	c.mu.Unlock()
+
+	c.metrics.recordEvictions(evictPurged, removed)
+}
+
+// MaxBodySize returns the largest body size kept in memory
+func (c *Cache) MaxBodySize() int64 {
+	return c.maxBodySize
+}
+
+// Len returns the number of cached entries
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// lookup returns the live entry for key, dropping it when past its in-memory expiry.
+// Caller must hold c.mu.
+func (c *Cache) lookup(key string) (*entry, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+		c.metrics.recordEvictions(evictExpired, 1)
+		c.metrics.updateSize(c.lru.Len(), c.bodySize)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return e, true
+}
+
+// admit reports whether a candidate is requested more often than every victim.
+// Caller must hold c.mu.
+func (c *Cache) admit(candidate uint64, victims []*list.Element) bool {
+	freq := c.sketch.estimate(candidate)
+	for _, victim := range victims {
+		if c.sketch.estimate(victim.Value.(*entry).hash) >= freq {
+			return false
+		}
+	}
+	return true
+}
+
+// remove drops elem from the cache. Caller must hold c.mu.
+func (c *Cache) remove(elem *list.Element) {
+	e := c.lru.Remove(elem).(*entry)
+	delete(c.items, e.key)
+	c.bodySize -= int64(len(e.body))
+}
+
+// cloneMetadata copies metadata so callers can modify their copy (e.g. eg_ids)
+func cloneMetadata(metadata *cache.CacheMetadata) *cache.CacheMetadata {
+	clone := *metadata
+	clone.EgIDs = slices.Clone(metadata.EgIDs)
+	return &clone
+}
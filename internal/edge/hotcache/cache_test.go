@@ -0,0 +1,209 @@
+package hotcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func newTestCache(t *testing.T, cfg *configtypes.HotCacheConfig) *Cache {
+	t.Helper()
+	c := New(cfg, NewMetricsWithRegistry("test", prometheus.NewRegistry()))
+	c.MarkAvailable(true)
+	return c
+}
+
+func testMetadata(key string) *cache.CacheMetadata {
+	now := time.Now().UTC()
+	return &cache.CacheMetadata{
+		Key:       key,
+		FilePath:  "/cache/" + key + ".html",
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+		EgIDs:     []string{"eg-1"},
+	}
+}
+
+func testKey(hostID, dimensionID int, hash string) string {
+	return fmt.Sprintf("cache:%d:%d:%s", hostID, dimensionID, hash)
+}
+
+func TestCache_MetadataRoundTrip(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	key := testKey(1, 1, "a")
+
+	_, ok := c.GetMetadata(key)
+	assert.False(t, ok)
+
+	require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+
+	got, ok := c.GetMetadata(key)
+	require.True(t, ok)
+	assert.Equal(t, key, got.Key)
+
+	// Callers get a copy
+	got.EgIDs[0] = "changed"
+	again, _ := c.GetMetadata(key)
+	assert.Equal(t, "eg-1", again.EgIDs[0])
+}
+
+func TestCache_Unavailable(t *testing.T) {
+	c := New(&configtypes.HotCacheConfig{Enabled: true}, nil)
+	key := testKey(1, 1, "a")
+
+	assert.False(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+	_, ok := c.GetMetadata(key)
+	assert.False(t, ok)
+}
+
+func TestCache_RejectsExpiredMetadata(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	key := testKey(1, 1, "a")
+	meta := testMetadata(key)
+	meta.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+
+	assert.False(t, c.SetMetadata(key, meta, c.Epoch()))
+}
+
+func TestCache_EpochDropsRacingInsert(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	key := testKey(1, 1, "a")
+
+	epoch := c.Epoch()
+	c.Invalidate(key) // Invalidation arrives while the caller reads Redis
+
+	assert.False(t, c.SetMetadata(key, testMetadata(key), epoch))
+	assert.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+}
+
+func TestCache_MaxAge(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true, MaxAge: types.Duration(time.Minute)})
+	key := testKey(1, 1, "a")
+	require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+
+	c.now = func() time.Time { return time.Now().UTC().Add(2 * time.Minute) }
+	_, ok := c.GetMetadata(key)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_AdmissionKeepsPopularEntries(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true, MaxEntries: 2})
+	popular := []string{testKey(1, 1, "p1"), testKey(1, 1, "p2")}
+
+	for _, key := range popular {
+		for i := 0; i < 5; i++ {
+			c.GetMetadata(key)
+		}
+		require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+	}
+
+	// A one-off request does not displace popular entries
+	oneOff := testKey(1, 1, "once")
+	c.GetMetadata(oneOff)
+	assert.False(t, c.SetMetadata(oneOff, testMetadata(oneOff), c.Epoch()))
+
+	// A key requested more often than the LRU victim is admitted
+	rising := testKey(1, 1, "rising")
+	for i := 0; i < 10; i++ {
+		c.GetMetadata(rising)
+	}
+	assert.True(t, c.SetMetadata(rising, testMetadata(rising), c.Epoch()))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCache_Body(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	key := testKey(1, 1, "a")
+	meta := testMetadata(key)
+
+	// Bodies attach only to cached entries
+	assert.False(t, c.SetBody(key, meta.FilePath, []byte("<html>a</html>")))
+
+	require.True(t, c.SetMetadata(key, meta, c.Epoch()))
+	assert.False(t, c.SetBody(key, "/other/path.html", []byte("<html>a</html>")))
+	require.True(t, c.SetBody(key, meta.FilePath, []byte("<html>a</html>")))
+
+	body, ok := c.GetBody(key, meta.FilePath)
+	require.True(t, ok)
+	assert.Equal(t, "<html>a</html>", string(body))
+
+	// New file path drops the body held for the old one
+	updated := testMetadata(key)
+	updated.FilePath = "/cache/new.html"
+	require.True(t, c.SetMetadata(key, updated, c.Epoch()))
+	_, ok = c.GetBody(key, updated.FilePath)
+	assert.False(t, ok)
+}
+
+func TestCache_BodyLimits(t *testing.T) {
+	storeBodies := false
+	t.Run("store bodies disabled", func(t *testing.T) {
+		c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true, StoreBodies: &storeBodies})
+		key := testKey(1, 1, "a")
+		meta := testMetadata(key)
+		require.True(t, c.SetMetadata(key, meta, c.Epoch()))
+		assert.False(t, c.SetBody(key, meta.FilePath, []byte("body")))
+	})
+
+	t.Run("body above max_body_size", func(t *testing.T) {
+		c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true, MaxBodySize: 1})
+		key := testKey(1, 1, "a")
+		meta := testMetadata(key)
+		require.True(t, c.SetMetadata(key, meta, c.Epoch()))
+		assert.False(t, c.SetBody(key, meta.FilePath, make([]byte, 2<<20)))
+	})
+
+	t.Run("max_size evicts colder bodies", func(t *testing.T) {
+		c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true, MaxSize: 1, MaxBodySize: 1})
+		half := make([]byte, 600*1024)
+
+		cold := testKey(1, 1, "cold")
+		coldMeta := testMetadata(cold)
+		require.True(t, c.SetMetadata(cold, coldMeta, c.Epoch()))
+		require.True(t, c.SetBody(cold, coldMeta.FilePath, half))
+
+		hot := testKey(1, 1, "hot")
+		hotMeta := testMetadata(hot)
+		for i := 0; i < 5; i++ {
+			c.GetMetadata(hot)
+		}
+		require.True(t, c.SetMetadata(hot, hotMeta, c.Epoch()))
+		require.True(t, c.SetBody(hot, hotMeta.FilePath, half))
+
+		_, ok := c.GetMetadata(cold)
+		assert.False(t, ok, "cold entry evicted to make room")
+		body, ok := c.GetBody(hot, hotMeta.FilePath)
+		require.True(t, ok)
+		assert.Len(t, body, len(half))
+	})
+}
+
+func TestCache_InvalidateHost(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	keys := []string{testKey(1, 1, "a"), testKey(1, 2, "b"), testKey(2, 1, "c")}
+	for _, key := range keys {
+		require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+	}
+
+	c.InvalidateHost(1, []int{2})
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.GetMetadata(keys[1])
+	assert.False(t, ok)
+
+	c.InvalidateHost(1, nil)
+	assert.Equal(t, 1, c.Len())
+	_, ok = c.GetMetadata(keys[2])
+	assert.True(t, ok)
+
+	c.Invalidate(keys[2])
+	assert.Equal(t, 0, c.Len())
+}
@@ -0,0 +1,38 @@
+package hotcache
+
+import (
+	"context"
+
+	"github.com/edgecomet/engine/internal/common/eventbus"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// HandleEvent removes the entries named by event. Cache is an eventbus.Handler: it is
+// disabled while the subscriber is disconnected or catching up, and purged when events
+// may have been lost.
+func (c *Cache) HandleEvent(ctx context.Context, event *eventbus.Event) {
+	switch event.Type {
+	case eventbus.EntryWritten, eventbus.EntryInvalidated:
+		for _, entry := range event.Entries {
+			c.Invalidate(entry.Key)
+		}
+	case eventbus.HostPurged:
+		c.InvalidateHost(event.HostID, event.DimensionIDs)
+	}
+}
+
+// Resync purges the cache since invalidations may have been missed
+func (c *Cache) Resync(ctx context.Context) {
+	c.Purge()
+}
+
+// SetLive enables the cache only while invalidations are delivered without delay
+func (c *Cache) SetLive(live bool) {
+	c.MarkAvailable(live)
+}
+
+// OnMetadataChange invalidates an entry changed by this EG right away, ahead of its
+// cluster event. Installed as the MetadataStore change callback.
+func (c *Cache) OnMetadataChange(ctx context.Context, cacheKey *types.CacheKey) {
+	c.Invalidate(cacheKey.String())
+}
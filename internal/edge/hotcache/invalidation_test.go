@@ -0,0 +1,63 @@
+package hotcache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/eventbus"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestCache_HandleEvent(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	ctx := context.Background()
+	keys := []string{testKey(1, 1, "a"), testKey(1, 2, "b"), testKey(2, 1, "c")}
+	for _, key := range keys {
+		require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+	}
+
+	c.HandleEvent(ctx, &eventbus.Event{Type: eventbus.ConfigReloaded})
+	assert.Equal(t, 3, c.Len())
+
+	c.HandleEvent(ctx, &eventbus.Event{Type: eventbus.EntryWritten, Entries: []eventbus.Entry{{Key: keys[0]}}})
+	_, ok := c.GetMetadata(keys[0])
+	assert.False(t, ok)
+
+	c.HandleEvent(ctx, &eventbus.Event{Type: eventbus.HostPurged, HostID: 1})
+	assert.Equal(t, 1, c.Len())
+
+	c.HandleEvent(ctx, &eventbus.Event{Type: eventbus.EntryInvalidated, Entries: []eventbus.Entry{{Key: keys[2]}}})
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_LiveAndResync(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	key := testKey(1, 1, "a")
+	require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+
+	c.SetLive(false)
+	_, ok := c.GetMetadata(key)
+	assert.False(t, ok, "disabled while events are delayed")
+
+	// Entries survive a disconnect when no events were lost
+	c.SetLive(true)
+	_, ok = c.GetMetadata(key)
+	assert.True(t, ok)
+
+	c.Resync(context.Background())
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_OnMetadataChange(t *testing.T) {
+	c := newTestCache(t, &configtypes.HotCacheConfig{Enabled: true})
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "a"}
+	key := cacheKey.String()
+	require.True(t, c.SetMetadata(key, testMetadata(key), c.Epoch()))
+
+	c.OnMetadataChange(context.Background(), cacheKey)
+	assert.Equal(t, 0, c.Len())
+}
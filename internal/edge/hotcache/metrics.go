@@ -0,0 +1,129 @@
+package hotcache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Tiers reported in request metrics
+const (
+	TierMetadata = "metadata"
+	TierBody     = "body"
+)
+
+// Eviction reasons reported in metrics
+const (
+	evictCapacity    = "capacity"
+	evictExpired     = "expired"
+	evictInvalidated = "invalidated"
+	evictPurged      = "purged"
+)
+
+// Metrics holds Prometheus metrics for the hot cache
+type Metrics struct {
+	requestsTotal   *prometheus.CounterVec
+	admissionsTotal *prometheus.CounterVec
+	evictionsTotal  *prometheus.CounterVec
+	entries         prometheus.Gauge
+	bytes           prometheus.Gauge
+}
+
+// NewMetrics creates and registers hot cache metrics with the default registry
+func NewMetrics(namespace string) *Metrics {
+	return NewMetricsWithRegistry(namespace, prometheus.DefaultRegisterer)
+}
+
+// NewMetricsWithRegistry creates and registers hot cache metrics with registerer
+func NewMetricsWithRegistry(namespace string, registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requestsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "eg_hotcache",
+				Name:      "requests_total",
+				Help:      "Hot cache lookups by tier and result",
+			},
+			[]string{"tier", "result"},
+		),
+		admissionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "eg_hotcache",
+				Name:      "admissions_total",
+				Help:      "Hot cache insert attempts by result",
+			},
+			[]string{"result"},
+		),
+		evictionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "eg_hotcache",
+				Name:      "evictions_total",
+				Help:      "Hot cache entries removed by reason",
+			},
+			[]string{"reason"},
+		),
+		entries: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "eg_hotcache",
+				Name:      "entries",
+				Help:      "Entries held in the hot cache",
+			},
+		),
+		bytes: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "eg_hotcache",
+				Name:      "bytes",
+				Help:      "Body bytes held in the hot cache",
+			},
+		),
+	}
+
+	registerer.MustRegister(
+		m.requestsTotal,
+		m.admissionsTotal,
+		m.evictionsTotal,
+		m.entries,
+		m.bytes,
+	)
+
+	return m
+}
+
+func (m *Metrics) recordRequest(tier string, hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.requestsTotal.WithLabelValues(tier, result).Inc()
+}
+
+func (m *Metrics) recordAdmission(admitted bool) {
+	if m == nil {
+		return
+	}
+	result := "rejected"
+	if admitted {
+		result = "admitted"
+	}
+	m.admissionsTotal.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) recordEvictions(reason string, count int) {
+	if m == nil || count == 0 {
+		return
+	}
+	m.evictionsTotal.WithLabelValues(reason).Add(float64(count))
+}
+
+func (m *Metrics) updateSize(entries int, bytes int64) {
+	if m == nil {
+		return
+	}
+	m.entries.Set(float64(entries))
+	m.bytes.Set(float64(bytes))
+}
@@ -0,0 +1,69 @@
+package hotcache
+
+// sketchDepth is the number of counter rows in the frequency sketch
+const sketchDepth = 4
+
+// sketchMaxCount caps counters (4-bit counters as in TinyLFU)
+const sketchMaxCount = 15
+
+// frequencySketch is a count-min sketch estimating how often keys were requested.
+// Counters are halved every resetAt increments so old popularity fades (TinyLFU aging).
+type frequencySketch struct {
+	counters  []uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+// newFrequencySketch sizes the sketch for the expected number of cached entries
+func newFrequencySketch(capacity int) *frequencySketch {
+	width := uint64(16)
+	for width < uint64(capacity)*2 {
+		width <<= 1
+	}
+	return &frequencySketch{
+		counters: make([]uint8, sketchDepth*int(width)),
+		mask:     width - 1,
+		resetAt:  capacity * 10,
+	}
+}
+
+// index returns the counter position of hash in row (double hashing)
+func (s *frequencySketch) index(hash uint64, row int) uint64 {
+	h := hash + uint64(row)*((hash>>32)|1)
+	return uint64(row)*(s.mask+1) + (h & s.mask)
+}
+
+// increment records one request for hash
+func (s *frequencySketch) increment(hash uint64) {
+	for row := 0; row < sketchDepth; row++ {
+		i := s.index(hash, row)
+		if s.counters[i] < sketchMaxCount {
+			s.counters[i]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+// estimate returns the approximate request count for hash
+func (s *frequencySketch) estimate(hash uint64) uint8 {
+	min := uint8(sketchMaxCount)
+	for row := 0; row < sketchDepth; row++ {
+		if c := s.counters[s.index(hash, row)]; c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+// reset halves all counters
+func (s *frequencySketch) reset() {
+	for i := range s.counters {
+		s.counters[i] >>= 1
+	}
+	s.additions /= 2
+}
@@ -2,29 +2,54 @@ package internal_server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 
 	"github.com/edgecomet/engine/internal/common/httputil"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 )
 
 // Path constants for internal endpoints
 const (
 	PathCachePull      = "/internal/cache/pull"
 	PathCachePush      = "/internal/cache/push"
+	PathCachePushBatch = "/internal/cache/push-batch"
 	PathCacheStatus    = "/internal/cache/status"
 	PathCacheRecache   = "/internal/cache/recache"
 	PathDebugHAR       = "/debug/har"
 	PathDebugHARRender = "/debug/har/render"
 )
 
+// pathScopes maps internal endpoints to the scope a caller needs
+var pathScopes = map[string]string{
+	PathCachePull:      internalauth.ScopePeer,
+	PathCachePush:      internalauth.ScopePeer,
+	PathCachePushBatch: internalauth.ScopePeer,
+	PathCacheStatus:    internalauth.ScopePeer,
+	PathCacheRecache:   internalauth.ScopeRecache,
+}
+
+// requiredScope returns the scope needed for path. Unknown paths require all scopes.
+func requiredScope(path string) string {
+	if scope, ok := pathScopes[path]; ok {
+		return scope
+	}
+	if isPrefixMatch(path, PathDebugHAR) {
+		return internalauth.ScopeDebug
+	}
+	return internalauth.ScopeAll
+}
+
 // InternalServer handles inter-EG and daemon-to-EG HTTP requests
 type InternalServer struct {
-	authKey   string
+	auth      *internalauth.Authenticator
+	tlsConfig *tls.Config
 	routes    map[string]map[string]fasthttp.RequestHandler // method -> path -> handler
 	server    *fasthttp.Server
 	listener  net.Listener
@@ -36,13 +61,23 @@ type InternalServer struct {
 // NewInternalServer creates a new internal HTTP server
 func NewInternalServer(authKey string, logger *zap.Logger) *InternalServer {
 	return &InternalServer{
-		authKey:   authKey,
+		auth:      internalauth.NewAuthenticator(authKey, nil),
 		routes:    make(map[string]map[string]fasthttp.RequestHandler),
 		logger:    logger,
 		startTime: time.Now().UTC(),
 	}
 }
 
+// SetAuthenticator replaces the default shared-key authenticator with scoped credentials
+func (s *InternalServer) SetAuthenticator(auth *internalauth.Authenticator) {
+	s.auth = auth
+}
+
+// SetTLSConfig enables TLS (and mTLS when client CAs are set) on the listener
+func (s *InternalServer) SetTLSConfig(tlsConfig *tls.Config) {
+	s.tlsConfig = tlsConfig
+}
+
 // RegisterHandler registers a handler for a specific method and path
 func (s *InternalServer) RegisterHandler(method, path string, handler fasthttp.RequestHandler) {
 	if s.routes[method] == nil {
@@ -68,16 +103,23 @@ func (s *InternalServer) Start(address string) error {
 	s.server = &fasthttp.Server{
 		Handler: s.Handler(),
 		Name:    "EdgeGateway-Internal",
+		// Cache pushes are streamed to disk instead of buffered in memory
+		StreamRequestBody: true,
 	}
 
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", address, err)
 	}
+	if s.tlsConfig != nil {
+		listener = tls.NewListener(listener, s.tlsConfig)
+	}
 	s.listener = listener
 
 	s.logger.Info("Internal server started",
-		zap.String("address", address))
+		zap.String("address", address),
+		zap.Bool("tls", s.tlsConfig != nil),
+		zap.Bool("mtls", s.tlsConfig != nil && s.tlsConfig.ClientAuth != tls.NoClientCert))
 
 	return s.server.Serve(listener)
 }
@@ -146,27 +188,26 @@ func isPrefixMatch(requestPath, registeredPath string) bool {
 		(len(requestPath) == len(registeredPath) || requestPath[len(registeredPath)] == '/')
 }
 
-// authenticate validates the X-Internal-Auth header
+// authenticate identifies the caller and checks the scope required by the path
 func (s *InternalServer) authenticate(ctx *fasthttp.RequestCtx) bool {
-	authHeader := string(ctx.Request.Header.Peek("X-Internal-Auth"))
-
-	if authHeader == "" {
-		s.logger.Warn("Missing X-Internal-Auth header",
-			zap.String("remote_addr", ctx.RemoteAddr().String()),
-			zap.String("path", string(ctx.Path())))
-		httputil.JSONError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
-		return false
+	path := string(ctx.Path())
+	identity, status, err := s.auth.Authorize(ctx, requiredScope(path))
+	if err == nil {
+		return true
 	}
 
-	if authHeader != s.authKey {
-		s.logger.Warn("Invalid X-Internal-Auth header",
-			zap.String("remote_addr", ctx.RemoteAddr().String()),
-			zap.String("path", string(ctx.Path())))
-		httputil.JSONError(ctx, "unauthorized", fasthttp.StatusUnauthorized)
-		return false
+	fields := []zap.Field{
+		zap.String("remote_addr", ctx.RemoteAddr().String()),
+		zap.String("path", path),
+		zap.Error(err),
+	}
+	if identity != nil {
+		fields = append(fields, zap.String("caller", identity.Name))
 	}
+	s.logger.Warn("Internal request rejected", fields...)
 
-	return true
+	httputil.JSONError(ctx, strings.ToLower(fasthttp.StatusMessage(status)), status)
+	return false
 }
 
 // GetStartTime returns the server start time
@@ -6,6 +6,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/internalauth"
 )
 
 func TestNewInternalServer(t *testing.T) {
@@ -14,7 +17,7 @@ func TestNewInternalServer(t *testing.T) {
 	server := NewInternalServer("test-key", logger)
 
 	assert.NotNil(t, server)
-	assert.Equal(t, "test-key", server.authKey)
+	assert.True(t, server.auth.Enabled())
 	assert.NotNil(t, server.routes)
 }
 
@@ -95,6 +98,47 @@ func TestAuthentication_ValidHeader(t *testing.T) {
 	assert.Equal(t, "success", string(ctx.Response.Body()))
 }
 
+func TestAuthentication_Scopes(t *testing.T) {
+	server := NewInternalServer("legacy-shared-key", zap.NewNop())
+	server.SetAuthenticator(internalauth.NewAuthenticator("legacy-shared-key", []configtypes.InternalCredential{
+		{Name: "cache-daemon", Key: "daemon-key-0123456789", Scopes: []string{internalauth.ScopeRecache}},
+		{Name: "peer", Key: "peer-key-0123456789", Scopes: []string{internalauth.ScopePeer}},
+	}))
+
+	ok := func(ctx *fasthttp.RequestCtx) { ctx.SetStatusCode(fasthttp.StatusOK) }
+	server.RegisterHandler("POST", PathCacheRecache, ok)
+	server.RegisterHandler("POST", PathCachePush, ok)
+	server.RegisterHandler("GET", PathDebugHAR, ok)
+
+	tests := []struct {
+		name     string
+		key      string
+		method   string
+		path     string
+		expected int
+	}{
+		{"daemon recache", "daemon-key-0123456789", "POST", PathCacheRecache, fasthttp.StatusOK},
+		{"daemon push forbidden", "daemon-key-0123456789", "POST", PathCachePush, fasthttp.StatusForbidden},
+		{"peer push", "peer-key-0123456789", "POST", PathCachePush, fasthttp.StatusOK},
+		{"peer debug forbidden", "peer-key-0123456789", "GET", PathDebugHAR + "/abc", fasthttp.StatusForbidden},
+		{"legacy key allows all", "legacy-shared-key", "GET", PathDebugHAR + "/abc", fasthttp.StatusOK},
+		{"unknown key", "other-key", "POST", PathCacheRecache, fasthttp.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.Set("X-Internal-Auth", tt.key)
+			ctx.Request.SetRequestURI(tt.path)
+			ctx.Request.Header.SetMethod(tt.method)
+
+			server.Handler()(ctx)
+
+			assert.Equal(t, tt.expected, ctx.Response.StatusCode())
+		})
+	}
+}
+
 func TestRouting_NotFound(t *testing.T) {
 	logger := zap.NewNop()
 	server := NewInternalServer("test-key", logger)
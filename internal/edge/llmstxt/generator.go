@@ -0,0 +1,245 @@
+package llmstxt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// Path is the request path served with the generated file
+const Path = "/llms.txt"
+
+// ContentType is the Content-Type of the generated file
+const ContentType = "text/plain; charset=utf-8"
+
+const scanCount = 500
+
+// generateTimeout bounds one regeneration. It does not depend on the request that
+// triggered it since other requests may be waiting for the same result.
+const generateTimeout = 30 * time.Second
+
+// Page is a cached page listed in llms.txt
+type Page struct {
+	URL         string
+	Title       string
+	Description string
+}
+
+type generatedFile struct {
+	content   []byte
+	expiresAt time.Time
+}
+
+// Generator builds per-host llms.txt files from cache metadata in Redis.
+// Generated files are kept in memory and reused for the host's configured TTL.
+// Concurrent regenerations of one host are coalesced.
+type Generator struct {
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+	logger       *zap.Logger
+
+	mu    sync.Mutex
+	files map[int]generatedFile
+	group singleflight.Group
+}
+
+// NewGenerator creates a new llms.txt Generator
+func NewGenerator(redisClient *redis.Client, keyGenerator *redis.KeyGenerator, logger *zap.Logger) *Generator {
+	return &Generator{
+		redis:        redisClient,
+		keyGenerator: keyGenerator,
+		logger:       logger,
+		files:        make(map[int]generatedFile),
+	}
+}
+
+// Get returns the llms.txt file for a host. An expired copy is returned while one
+// goroutine regenerates it in the background; without a copy the caller waits for
+// the regeneration already running for the host, if any.
+func (g *Generator) Get(ctx context.Context, host *types.Host, now time.Time) ([]byte, error) {
+	g.mu.Lock()
+	file, ok := g.files[host.ID]
+	g.mu.Unlock()
+	if ok && now.Before(file.expiresAt) {
+		return file.content, nil
+	}
+
+	key := strconv.Itoa(host.ID)
+	generate := func() (interface{}, error) {
+		return g.generate(host, now)
+	}
+
+	if ok {
+		// Joins the regeneration already running for this host, if any
+		g.group.DoChan(key, generate)
+		return file.content, nil
+	}
+
+	select {
+	case result := <-g.group.DoChan(key, generate):
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.([]byte), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// generate builds the host's llms.txt and stores it for the host's TTL. On failure
+// the previous copy, if any, is kept.
+func (g *Generator) generate(host *types.Host, now time.Time) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), generateTimeout)
+	defer cancel()
+
+	start := time.Now()
+	pages, err := g.CollectPages(ctx, host.ID, host.LLMsTxt.GetMaxPages())
+	if err != nil {
+		g.logger.Warn("Failed to generate llms.txt",
+			zap.String("host", host.Domain),
+			zap.Error(err))
+		return nil, err
+	}
+	content := Render(host, pages)
+
+	g.mu.Lock()
+	g.files[host.ID] = generatedFile{content: content, expiresAt: now.Add(host.LLMsTxt.GetTTL())}
+	g.mu.Unlock()
+
+	g.logger.Debug("Generated llms.txt",
+		zap.String("host", host.Domain),
+		zap.Int("pages", len(pages)),
+		zap.Duration("duration", time.Since(start)))
+
+	return content, nil
+}
+
+// CollectPages scans the host's cache metadata for indexable rendered pages with a title.
+// Pages cached for several dimensions are listed once. Shallow URLs are preferred when
+// the host has more pages than maxPages.
+func (g *Generator) CollectPages(ctx context.Context, hostID int, maxPages int) ([]Page, error) {
+	byURL := make(map[string]Page)
+	pattern := g.keyGenerator.HostMetadataScanPattern(hostID)
+
+	var cursor uint64
+	for {
+		keys, next, err := g.redis.Scan(ctx, cursor, pattern, scanCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cache metadata: %w", err)
+		}
+
+		for _, key := range keys {
+			data, err := g.redis.HGetAll(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read cache metadata %s: %w", key, err)
+			}
+			if len(data) == 0 {
+				continue
+			}
+
+			var metadata cache.CacheMetadata
+			if err := metadata.FromHash(data); err != nil {
+				g.logger.Debug("Skipping invalid cache metadata", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			if !listable(&metadata) {
+				continue
+			}
+			if _, exists := byURL[metadata.URL]; !exists {
+				byURL[metadata.URL] = Page{URL: metadata.URL, Title: metadata.Title, Description: metadata.Description}
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	pages := make([]Page, 0, len(byURL))
+	for _, page := range byURL {
+		pages = append(pages, page)
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		di, dj := pathDepth(pages[i].URL), pathDepth(pages[j].URL)
+		if di != dj {
+			return di < dj
+		}
+		return pages[i].URL < pages[j].URL
+	})
+	if maxPages > 0 && len(pages) > maxPages {
+		pages = pages[:maxPages]
+	}
+	return pages, nil
+}
+
+// listable reports whether a cache entry belongs in llms.txt
+func listable(metadata *cache.CacheMetadata) bool {
+	if metadata.Source != cache.SourceRender || metadata.StatusCode != 200 {
+		return false
+	}
+	if metadata.Title == "" || metadata.URL == "" {
+		return false
+	}
+	// Entries cached before index status tracking have status 0
+	return metadata.IndexStatus == 0 || metadata.IndexStatus == int(types.IndexStatusIndexable)
+}
+
+func pathDepth(rawURL string) int {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+	return strings.Count(strings.Trim(parsed.Path, "/"), "/")
+}
+
+// Render formats the llms.txt file: a title, an optional summary and the list of pages
+func Render(host *types.Host, pages []Page) []byte {
+	title := host.Domain
+	description := ""
+	if host.LLMsTxt != nil {
+		if host.LLMsTxt.Title != "" {
+			title = host.LLMsTxt.Title
+		}
+		description = host.LLMsTxt.Description
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", singleLine(title))
+	if description = singleLine(description); description != "" {
+		fmt.Fprintf(&buf, "\n> %s\n", description)
+	}
+
+	if len(pages) > 0 {
+		buf.WriteString("\n## Pages\n\n")
+		for _, page := range pages {
+			fmt.Fprintf(&buf, "- [%s](%s)", escapeLinkText(singleLine(page.Title)), strings.ReplaceAll(page.URL, " ", "%20"))
+			if pageDescription := singleLine(page.Description); pageDescription != "" {
+				fmt.Fprintf(&buf, ": %s", pageDescription)
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func escapeLinkText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(s)
+}
@@ -0,0 +1,157 @@
+package llmstxt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func setupTestGenerator(t *testing.T) (*Generator, *redis.Client) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	return NewGenerator(redisClient, redis.NewKeyGenerator(), zap.NewNop()), redisClient
+}
+
+func storeEntry(t *testing.T, redisClient *redis.Client, hostID, dimensionID int, urlHash string, metadata cache.CacheMetadata) {
+	t.Helper()
+	cacheKey := &types.CacheKey{HostID: hostID, DimensionID: dimensionID, URLHash: urlHash}
+	metadata.Key = cacheKey.String()
+	metadata.HostID = hostID
+	if metadata.Source == "" {
+		metadata.Source = cache.SourceRender
+	}
+	if metadata.StatusCode == 0 {
+		metadata.StatusCode = 200
+	}
+	now := time.Now().UTC()
+	metadata.CreatedAt, metadata.ExpiresAt, metadata.LastAccess = now, now.Add(time.Hour), now
+
+	key := redis.NewKeyGenerator().GenerateMetadataKey(cacheKey)
+	require.NoError(t, redisClient.HSet(context.Background(), key, metadata.ToHash()))
+}
+
+func TestGenerator_CollectPages(t *testing.T) {
+	gen, redisClient := setupTestGenerator(t)
+
+	storeEntry(t, redisClient, 1, 1, "a", cache.CacheMetadata{URL: "https://example.com/docs/guide/install", Title: "Install", Description: "How to install"})
+	storeEntry(t, redisClient, 1, 2, "a", cache.CacheMetadata{URL: "https://example.com/docs/guide/install", Title: "Install (mobile)"})
+	storeEntry(t, redisClient, 1, 1, "b", cache.CacheMetadata{URL: "https://example.com/", Title: "Home"})
+	storeEntry(t, redisClient, 1, 1, "c", cache.CacheMetadata{URL: "https://example.com/about", Title: "About", IndexStatus: int(types.IndexStatusIndexable)})
+	storeEntry(t, redisClient, 1, 1, "d", cache.CacheMetadata{URL: "https://example.com/missing", Title: "Missing", StatusCode: 404})
+	storeEntry(t, redisClient, 1, 1, "e", cache.CacheMetadata{URL: "https://example.com/noindex", Title: "Hidden", IndexStatus: int(types.IndexStatusBlockedByMeta)})
+	storeEntry(t, redisClient, 1, 1, "f", cache.CacheMetadata{URL: "https://example.com/untitled"})
+	storeEntry(t, redisClient, 1, 1, "g", cache.CacheMetadata{URL: "https://example.com/api", Title: "API", Source: cache.SourceBypass})
+	storeEntry(t, redisClient, 2, 1, "a", cache.CacheMetadata{URL: "https://other.com/", Title: "Other host"})
+
+	pages, err := gen.CollectPages(context.Background(), 1, 0)
+	require.NoError(t, err)
+	require.Len(t, pages, 3)
+	assert.Equal(t, "https://example.com/", pages[0].URL)
+	assert.Equal(t, "https://example.com/about", pages[1].URL)
+	assert.Equal(t, "https://example.com/docs/guide/install", pages[2].URL)
+	assert.Contains(t, []string{"Install", "Install (mobile)"}, pages[2].Title)
+
+	pages, err = gen.CollectPages(context.Background(), 1, 2)
+	require.NoError(t, err)
+	require.Len(t, pages, 2, "limited to max pages, shallow URLs first")
+	assert.Equal(t, "https://example.com/about", pages[1].URL)
+}
+
+func TestRender(t *testing.T) {
+	host := &types.Host{ID: 1, Domain: "example.com"}
+	pages := []Page{
+		{URL: "https://example.com/", Title: "Home [beta]", Description: "Welcome\n  to the site"},
+		{URL: "https://example.com/a b", Title: "Spaced"},
+	}
+
+	assert.Equal(t, "# example.com\n\n## Pages\n\n"+
+		"- [Home \\[beta\\]](https://example.com/): Welcome to the site\n"+
+		"- [Spaced](https://example.com/a%20b)\n", string(Render(host, pages)))
+
+	host.LLMsTxt = &types.LLMsTxtConfig{Enabled: true, Title: "Example", Description: "Docs and guides"}
+	assert.Equal(t, "# Example\n\n> Docs and guides\n", string(Render(host, nil)))
+}
+
+func TestGenerator_GetReusesFileForTTL(t *testing.T) {
+	gen, redisClient := setupTestGenerator(t)
+	ttl := types.Duration(time.Minute)
+	host := &types.Host{ID: 1, Domain: "example.com", LLMsTxt: &types.LLMsTxtConfig{Enabled: true, TTL: &ttl}}
+	now := time.Now()
+
+	storeEntry(t, redisClient, 1, 1, "a", cache.CacheMetadata{URL: "https://example.com/", Title: "Home"})
+	first, err := gen.Get(context.Background(), host, now)
+	require.NoError(t, err)
+	assert.Contains(t, string(first), "[Home]")
+
+	storeEntry(t, redisClient, 1, 1, "b", cache.CacheMetadata{URL: "https://example.com/new", Title: "New"})
+	cached, err := gen.Get(context.Background(), host, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+
+	// The expired copy is served while it is regenerated in the background
+	stale, err := gen.Get(context.Background(), host, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, first, stale)
+
+	assert.Eventually(t, func() bool {
+		regenerated, err := gen.Get(context.Background(), host, now.Add(2*time.Minute))
+		return err == nil && strings.Contains(string(regenerated), "[New]")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGenerator_GetCoalescesGeneration(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	for i := 0; i < 200; i++ {
+		storeEntry(t, redisClient, 1, 1, fmt.Sprintf("h%d", i),
+			cache.CacheMetadata{URL: fmt.Sprintf("https://example.com/p%d", i), Title: fmt.Sprintf("Page %d", i)})
+	}
+	host := &types.Host{ID: 1, Domain: "example.com", LLMsTxt: &types.LLMsTxtConfig{Enabled: true}}
+	now := time.Now()
+
+	// Redis commands issued by one generation
+	before := mr.CommandCount()
+	expected, err := NewGenerator(redisClient, redis.NewKeyGenerator(), zap.NewNop()).Get(context.Background(), host, now)
+	require.NoError(t, err)
+	perGeneration := mr.CommandCount() - before
+
+	gen := NewGenerator(redisClient, redis.NewKeyGenerator(), zap.NewNop())
+	before = mr.CommandCount()
+	var wg sync.WaitGroup
+	results := make([][]byte, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = gen.Get(context.Background(), host, now)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, content := range results {
+		assert.Equal(t, expected, content)
+	}
+	assert.Equal(t, perGeneration, mr.CommandCount()-before, "concurrent requests share one generation")
+}
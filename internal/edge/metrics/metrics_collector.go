@@ -7,6 +7,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// Recache content comparison results
+const (
+	RecacheContentChanged   = "changed"   // Content differs from the cached version
+	RecacheContentUnchanged = "unchanged" // Content matches; TTL extended in place
+	RecacheContentNew       = "new"       // No previous version to compare against
+)
+
 // MetricsCollector centralizes all metrics recording with proper labeling
 type MetricsCollector struct {
 	prometheus *PrometheusMetrics
@@ -61,6 +68,34 @@ func (mc *MetricsCollector) RecordRenderDuration(host, dimension, serviceID stri
 		zap.Duration("duration", duration))
 }
 
+// RecordRenderRetry records a render retried after a failed attempt
+func (mc *MetricsCollector) RecordRenderRetry(host, errorType string) {
+	mc.prometheus.RecordRenderRetry(host, errorType)
+
+	mc.logger.Debug("Recorded render retry metric",
+		zap.String("host", host),
+		zap.String("error_type", errorType))
+}
+
+// RecordRenderQualityFailure records a rendered page that failed a quality check
+func (mc *MetricsCollector) RecordRenderQualityFailure(host, check string) {
+	mc.prometheus.RecordRenderQualityFailure(host, check)
+
+	mc.logger.Debug("Recorded render quality failure metric",
+		zap.String("host", host),
+		zap.String("check", check))
+}
+
+// RecordStructuredDataIssue records a structured data issue found on a rendered page
+func (mc *MetricsCollector) RecordStructuredDataIssue(host, schemaType, issue string) {
+	mc.prometheus.RecordStructuredDataIssue(host, schemaType, issue)
+
+	mc.logger.Debug("Recorded structured data issue metric",
+		zap.String("host", host),
+		zap.String("schema_type", schemaType),
+		zap.String("issue", issue))
+}
+
 // RecordStatusCodeResponse records a response by status code
 func (mc *MetricsCollector) RecordStatusCodeResponse(host, dimension string, statusCode int) {
 	mc.prometheus.RecordStatusCodeResponse(host, dimension, statusCode)
@@ -119,6 +154,16 @@ func (mc *MetricsCollector) RecordWaitTimeout(host, dimension string, duration t
 		zap.Duration("duration", duration))
 }
 
+// RecordWaitFailed records a concurrent render that failed while requests waited for it
+func (mc *MetricsCollector) RecordWaitFailed(host, dimension string, duration time.Duration) {
+	mc.prometheus.RecordWaitFailed(host, dimension, duration)
+
+	mc.logger.Debug("Recorded wait failed metric",
+		zap.String("host", host),
+		zap.String("dimension", dimension),
+		zap.Duration("duration", duration))
+}
+
 // ServeHTTP serves Prometheus metrics via HTTP
 func (mc *MetricsCollector) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	mc.prometheus.ServeHTTP(ctx)
@@ -166,3 +211,21 @@ func (mc *MetricsCollector) RecordDecompressionError(algorithm string) {
 	mc.logger.Debug("Recorded decompression error metric",
 		zap.String("algorithm", algorithm))
 }
+
+// RecordRecacheContent records the content comparison result of a recache render
+func (mc *MetricsCollector) RecordRecacheContent(host, result string) {
+	mc.prometheus.RecordRecacheContent(host, result)
+
+	mc.logger.Debug("Recorded recache content metric",
+		zap.String("host", host),
+		zap.String("result", result))
+}
+
+// RecordRecacheSEOChange records an SEO field change detected on recache
+func (mc *MetricsCollector) RecordRecacheSEOChange(host, field string) {
+	mc.prometheus.RecordRecacheSEOChange(host, field)
+
+	mc.logger.Debug("Recorded recache SEO change metric",
+		zap.String("host", host),
+		zap.String("field", field))
+}
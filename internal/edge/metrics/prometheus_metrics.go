@@ -27,6 +27,9 @@ type PrometheusMetrics struct {
 	renderDuration       *prometheus.HistogramVec
 	renderStatusCodeResp *prometheus.CounterVec
 	bypassTotal          *prometheus.CounterVec
+	renderRetriesTotal   *prometheus.CounterVec
+	renderQualityFailed  *prometheus.CounterVec
+	structuredDataIssues *prometheus.CounterVec
 	activeRequests       prometheus.Gauge
 
 	// Wait metrics (for concurrent render coordination)
@@ -43,6 +46,11 @@ type PrometheusMetrics struct {
 	cacheBytesSavedTotal         *prometheus.CounterVec
 	cacheDecompressionErrorTotal *prometheus.CounterVec
 
+	// Recache content-change metrics
+	recacheContentTotal    *prometheus.CounterVec
+	recacheChangeRatio     *prometheus.GaugeVec
+	recacheSEOChangesTotal *prometheus.CounterVec
+
 	logger      *zap.Logger
 	httpHandler func(*fasthttp.RequestCtx)
 }
@@ -153,6 +161,36 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 		[]string{"host", "reason"},
 	)
 
+	pm.renderRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "eg",
+			Name:      "render_retries_total",
+			Help:      "Total number of renders retried after a failed attempt",
+		},
+		[]string{"host", "error_type"}, // error_type of the failed attempt
+	)
+
+	pm.renderQualityFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "eg",
+			Name:      "render_quality_failures_total",
+			Help:      "Total number of rendered pages that failed a quality check",
+		},
+		[]string{"host", "check"},
+	)
+
+	pm.structuredDataIssues = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "eg",
+			Name:      "structured_data_issues_total",
+			Help:      "Total number of structured data issues found on rendered pages",
+		},
+		[]string{"host", "schema_type", "issue"}, // schema_type is empty for parse errors
+	)
+
 	pm.activeRequests = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
@@ -170,7 +208,7 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 			Name:      "wait_total",
 			Help:      "Total number of requests that waited for concurrent renders",
 		},
-		[]string{"host", "dimension", "outcome"}, // outcome: success, timeout
+		[]string{"host", "dimension", "outcome"}, // outcome: success, timeout, failed
 	)
 
 	pm.waitDuration = prometheus.NewHistogramVec(
@@ -246,6 +284,36 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 		[]string{"algorithm"},
 	)
 
+	pm.recacheContentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "eg",
+			Name:      "recache_content_total",
+			Help:      "Total recache renders by content comparison result (changed, unchanged, new)",
+		},
+		[]string{"host", "result"},
+	)
+
+	pm.recacheChangeRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "eg",
+			Name:      "recache_change_ratio",
+			Help:      "Share of compared recaches (0-1) whose content changed, per host",
+		},
+		[]string{"host"},
+	)
+
+	pm.recacheSEOChangesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "eg",
+			Name:      "recache_seo_changes_total",
+			Help:      "Total SEO field changes detected on recache (title, canonical, index_status)",
+		},
+		[]string{"host", "field"},
+	)
+
 	// Register all metrics
 	registerer.MustRegister(
 		pm.requestsTotal,
@@ -257,6 +325,9 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 		pm.renderDuration,
 		pm.renderStatusCodeResp,
 		pm.bypassTotal,
+		pm.renderRetriesTotal,
+		pm.renderQualityFailed,
+		pm.structuredDataIssues,
 		pm.activeRequests,
 		pm.waitTotal,
 		pm.waitDuration,
@@ -266,6 +337,9 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 		pm.cacheCompressionRatio,
 		pm.cacheBytesSavedTotal,
 		pm.cacheDecompressionErrorTotal,
+		pm.recacheContentTotal,
+		pm.recacheChangeRatio,
+		pm.recacheSEOChangesTotal,
 	)
 
 	// Create HTTP handler - registerer implements Gatherer interface
@@ -308,6 +382,21 @@ func (pm *PrometheusMetrics) RecordRenderDuration(host, dimension, serviceID str
 	pm.renderDuration.WithLabelValues(host, dimension, serviceID).Observe(duration.Seconds())
 }
 
+// RecordRenderRetry records a render retried after a failed attempt
+func (pm *PrometheusMetrics) RecordRenderRetry(host, errorType string) {
+	pm.renderRetriesTotal.WithLabelValues(host, errorType).Inc()
+}
+
+// RecordRenderQualityFailure records a rendered page that failed a quality check
+func (pm *PrometheusMetrics) RecordRenderQualityFailure(host, check string) {
+	pm.renderQualityFailed.WithLabelValues(host, check).Inc()
+}
+
+// RecordStructuredDataIssue records a structured data issue found on a rendered page
+func (pm *PrometheusMetrics) RecordStructuredDataIssue(host, schemaType, issue string) {
+	pm.structuredDataIssues.WithLabelValues(host, schemaType, issue).Inc()
+}
+
 // RecordStatusCodeResponse records a response by status code range
 func (pm *PrometheusMetrics) RecordStatusCodeResponse(host, dimension string, statusCode int) {
 	statusRange := getStatusCodeRange(statusCode)
@@ -373,6 +462,12 @@ func (pm *PrometheusMetrics) RecordWaitTimeout(host, dimension string, duration
 	pm.waitTimeouts.WithLabelValues(host, dimension).Inc()
 }
 
+// RecordWaitFailed records a concurrent render that failed while requests waited for it
+func (pm *PrometheusMetrics) RecordWaitFailed(host, dimension string, duration time.Duration) {
+	pm.waitTotal.WithLabelValues(host, dimension, "failed").Inc()
+	pm.waitDuration.WithLabelValues(host, dimension, "failed").Observe(duration.Seconds())
+}
+
 // ServeHTTP serves Prometheus metrics via HTTP
 func (pm *PrometheusMetrics) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	pm.httpHandler(ctx)
@@ -418,3 +513,19 @@ func (pm *PrometheusMetrics) RecordBytesSaved(algorithm string, bytesSaved int64
 func (pm *PrometheusMetrics) RecordDecompressionError(algorithm string) {
 	pm.cacheDecompressionErrorTotal.WithLabelValues(algorithm).Inc()
 }
+
+// RecordRecacheContent records a recache content comparison result and updates the change ratio
+func (pm *PrometheusMetrics) RecordRecacheContent(host, result string) {
+	pm.recacheContentTotal.WithLabelValues(host, result).Inc()
+
+	changed := pm.getCounterValue(pm.recacheContentTotal.WithLabelValues(host, RecacheContentChanged))
+	unchanged := pm.getCounterValue(pm.recacheContentTotal.WithLabelValues(host, RecacheContentUnchanged))
+	if total := changed + unchanged; total > 0 {
+		pm.recacheChangeRatio.WithLabelValues(host).Set(changed / total)
+	}
+}
+
+// RecordRecacheSEOChange records a changed SEO field detected on recache
+func (pm *PrometheusMetrics) RecordRecacheSEOChange(host, field string) {
+	pm.recacheSEOChangesTotal.WithLabelValues(host, field).Inc()
+}
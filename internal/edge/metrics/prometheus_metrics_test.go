@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
@@ -42,6 +43,21 @@ func TestPrometheusMetrics_Recording(t *testing.T) {
 	assert.NotNil(t, pm)
 }
 
+func TestPrometheusMetrics_RecacheChangeRatio(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	pm := NewPrometheusMetricsWithRegistry("edgecomet", registry, zap.NewNop())
+
+	pm.RecordRecacheContent("example.com", RecacheContentNew)
+	pm.RecordRecacheContent("example.com", RecacheContentChanged)
+	pm.RecordRecacheContent("example.com", RecacheContentUnchanged)
+	pm.RecordRecacheContent("example.com", RecacheContentUnchanged)
+	pm.RecordRecacheContent("example.com", RecacheContentUnchanged)
+	pm.RecordRecacheSEOChange("example.com", "title")
+
+	assert.InDelta(t, 0.25, testutil.ToFloat64(pm.recacheChangeRatio.WithLabelValues("example.com")), 1e-9)
+	assert.Equal(t, 1.0, testutil.ToFloat64(pm.recacheSEOChangesTotal.WithLabelValues("example.com", "title")))
+}
+
 func TestPrometheusMetrics_HTTPEndpoint(t *testing.T) {
 	logger := zap.NewNop()
 	registry := prometheus.NewRegistry()
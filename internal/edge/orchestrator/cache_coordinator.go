@@ -8,10 +8,13 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/edgecomet/engine/internal/common/config"
 	"github.com/edgecomet/engine/internal/edge/bypass"
 	"github.com/edgecomet/engine/internal/edge/cache"
 	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/internal/edge/hotcache"
 	"github.com/edgecomet/engine/internal/edge/metrics"
+	"github.com/edgecomet/engine/internal/edge/seoaudit"
 	"github.com/edgecomet/engine/internal/edge/sharding"
 	"github.com/edgecomet/engine/pkg/types"
 )
@@ -42,8 +45,9 @@ type ShardingManager interface {
 	IsEnabled() bool
 	ComputeTargets(ctx context.Context, cacheKey string) ([]string, error)
 	IsTargetForCache(ctx context.Context, cacheKey string) (bool, error)
-	PushToTargets(ctx context.Context, cacheKey *types.CacheKey, content []byte, metadata *cache.CacheMetadata, targetEgIDs []string, requestID string) ([]string, error)
+	PushToTargets(ctx context.Context, cacheKey *types.CacheKey, metadata *cache.CacheMetadata, targetEgIDs []string, requestID string) ([]string, error)
 	PullFromRemote(ctx context.Context, cacheKey *types.CacheKey, egIDs []string) ([]byte, error)
+	PullFromRemoteToFile(ctx context.Context, cacheKey *types.CacheKey, egIDs []string, absolutePath string) (int64, error)
 	GetEgID() string
 	GetReplicationFactor() int
 	GetInterEgTimeout() time.Duration
@@ -58,6 +62,7 @@ type CacheCoordinator struct {
 	cacheService    *cache.CacheService
 	shardingManager ShardingManager
 	metrics         *metrics.MetricsCollector
+	hotCache        *hotcache.Cache // Optional in-memory tier, nil when disabled
 	logger          *zap.Logger
 }
 
@@ -80,10 +85,30 @@ func NewCacheCoordinator(
 	}
 }
 
+// SetHotCache enables the in-memory hot cache tier for lookups and serving
+func (cc *CacheCoordinator) SetHotCache(hotCache *hotcache.Cache) {
+	cc.hotCache = hotCache
+}
+
 // LookupCache retrieves cache metadata if available and valid
 // Returns the cache metadata and a boolean indicating if it exists
 func (cc *CacheCoordinator) LookupCache(renderCtx *edgectx.RenderContext) (*cache.CacheMetadata, bool) {
-	return cc.cacheService.GetCacheEntry(renderCtx)
+	if cc.hotCache == nil {
+		return cc.cacheService.GetCacheEntry(renderCtx)
+	}
+
+	key := renderCtx.CacheKey.String()
+	if metadata, ok := cc.hotCache.GetMetadata(key); ok {
+		return metadata, true
+	}
+
+	// Take the epoch before reading so an invalidation during the read discards the insert
+	epoch := cc.hotCache.Epoch()
+	metadata, exists := cc.cacheService.GetCacheEntry(renderCtx)
+	if exists && metadata.IsFresh() {
+		cc.hotCache.SetMetadata(key, metadata, epoch)
+	}
+	return metadata, exists
 }
 
 // IsFileLocal checks if the cache file is stored on the current EG
@@ -105,8 +130,7 @@ func (cc *CacheCoordinator) SaveCache(
 	ttl time.Duration,
 	staleTTL time.Duration,
 	pushOnRender bool,
-	indexStatus types.IndexStatus,
-	title string,
+	pageSEO *types.PageSEO,
 ) error {
 	// STEP 1: Generate file path
 	// IMPORTANT: Must use UTC for timezone consistency with cleanup worker
@@ -183,22 +207,42 @@ func (cc *CacheCoordinator) SaveCache(
 
 	// STEP 3: Create metadata
 	metadata := &cache.CacheMetadata{
-		Key:         renderCtx.CacheKey.String(),
-		URL:         renderCtx.TargetURL,
-		FilePath:    relativeFilePath,
-		HostID:      renderCtx.Host.ID,
-		Dimension:   renderCtx.Dimension,
-		RequestID:   renderCtx.RequestID,
-		CreatedAt:   now,
-		ExpiresAt:   expiresAt,
-		Size:        int64(len(content)), // Original uncompressed size (for Content-Length)
-		DiskSize:    diskSize,            // Actual size on disk (may be compressed)
-		LastAccess:  now,
-		Source:      source,
-		StatusCode:  statusCode,
-		Headers:     headers,
-		IndexStatus: int(indexStatus),
-		Title:       title,
+		Key:        renderCtx.CacheKey.String(),
+		URL:        renderCtx.TargetURL,
+		FilePath:   relativeFilePath,
+		HostID:     renderCtx.Host.ID,
+		Dimension:  renderCtx.Dimension,
+		RequestID:  renderCtx.RequestID,
+		CreatedAt:  now,
+		ExpiresAt:  expiresAt,
+		Size:       int64(len(content)), // Original uncompressed size (for Content-Length)
+		DiskSize:   diskSize,            // Actual size on disk (may be compressed)
+		LastAccess: now,
+		Source:     source,
+		StatusCode: statusCode,
+		Headers:    headers,
+	}
+
+	// SEO snapshot and content fingerprint from PageSEO (nil for non-HTML bypass responses)
+	if pageSEO != nil {
+		metadata.Title = pageSEO.Title
+		metadata.Description = pageSEO.MetaDescription
+		metadata.IndexStatus = int(pageSEO.IndexStatus)
+		metadata.CanonicalURL = pageSEO.CanonicalURL
+		metadata.MinHash = pageSEO.PageMinHash
+		metadata.SEO = pageSEO
+	}
+
+	// Page-level SEO rules run on rendered pages only
+	if pageSEO != nil && source == cache.SourceRender && renderCtx.Host.SEOAudit.IsEnabled() {
+		metadata.SEOIssues = seoaudit.Evaluate(pageSEO, statusCode, renderCtx.Host.SEOAudit)
+		if len(metadata.SEOIssues) > 0 {
+			renderCtx.Logger.Debug("SEO audit found issues",
+				zap.Strings("issues", metadata.SEOIssues))
+		}
+	}
+	if !isRedirect {
+		metadata.ContentHash = cache.ContentHash(content)
 	}
 
 	// Initialize eg_ids with current EG (only for non-redirects that have files on disk)
@@ -221,6 +265,10 @@ func (cc *CacheCoordinator) SaveCache(
 		return fmt.Errorf("failed to store metadata: %w", err)
 	}
 
+	if source == cache.SourceRender {
+		cc.storeLinks(renderCtx, statusCode, pageSEO)
+	}
+
 	renderCtx.Logger.Debug("Cache entry created successfully",
 		zap.String("relative_path", relativeFilePath),
 		zap.Int("content_size", len(content)),
@@ -242,7 +290,7 @@ func (cc *CacheCoordinator) SaveCache(
 	}
 
 	if shouldPushToCluster {
-		if err := cc.pushCacheToCluster(renderCtx, contentToWrite, metadata); err != nil {
+		if err := cc.pushCacheToCluster(renderCtx, metadata); err != nil {
 			renderCtx.Logger.Warn("Failed to push cache to cluster (cached locally)",
 				zap.String("cache_key", renderCtx.CacheKey.String()),
 				zap.String("source", source),
@@ -257,6 +305,35 @@ func (cc *CacheCoordinator) SaveCache(
 	return nil
 }
 
+// storeLinks replaces the outlinks of a rendered page in the link graph.
+// Pages other than 200 responses have no outlinks.
+func (cc *CacheCoordinator) storeLinks(renderCtx *edgectx.RenderContext, statusCode int, pageSEO *types.PageSEO) {
+	var links []string
+	if statusCode == 200 && pageSEO != nil {
+		links = pageSEO.InternalLinks
+	}
+	var stripPatterns []config.CompiledStripPattern
+	if tp := renderCtx.ResolvedConfig.TrackingParams; tp != nil && tp.Enabled {
+		stripPatterns = tp.CompiledPatterns
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheOperationTimeout)
+	defer cancel()
+
+	// Non-fatal: the link graph only feeds link reports and discovery
+	discovered, err := cc.metadata.StoreLinks(ctx, renderCtx.CacheKey, links, renderCtx.Host.Domains, stripPatterns)
+	if err != nil {
+		renderCtx.Logger.Warn("Failed to update link graph",
+			zap.String("cache_key", renderCtx.CacheKey.String()),
+			zap.Error(err))
+		return
+	}
+	if discovered > 0 {
+		renderCtx.Logger.Debug("Discovered internal URLs queued for pre-render",
+			zap.Int("discovered", discovered))
+	}
+}
+
 // SaveRenderCache saves rendered content to cache using unified SaveCache method
 func (cc *CacheCoordinator) SaveRenderCache(
 	renderCtx *edgectx.RenderContext,
@@ -280,14 +357,6 @@ func (cc *CacheCoordinator) SaveRenderCache(
 	// Filter headers using safe_response_headers configuration (forCache=true: block Set-Cookie)
 	headers := FilterHeaders(headersWithLocation, renderCtx.ResolvedConfig.SafeResponseHeaders, renderResult.StatusCode, true)
 
-	// Extract Title and IndexStatus from PageSEO for cache metadata
-	var title string
-	var indexStatus types.IndexStatus
-	if renderResult.PageSEO != nil {
-		title = renderResult.PageSEO.Title
-		indexStatus = renderResult.PageSEO.IndexStatus
-	}
-
 	staleTTL := getStaleTTL(renderCtx.ResolvedConfig.Cache.Expired)
 
 	return cc.SaveCache(
@@ -299,13 +368,12 @@ func (cc *CacheCoordinator) SaveRenderCache(
 		renderCtx.ResolvedConfig.Cache.TTL,
 		staleTTL,
 		renderCtx.ResolvedConfig.Sharding.PushOnRender,
-		indexStatus,
-		title,
+		renderResult.PageSEO,
 	)
 }
 
-// pushCacheToCluster pushes cache to other EGs in the cluster
-func (cc *CacheCoordinator) pushCacheToCluster(renderCtx *edgectx.RenderContext, html []byte, metadata *cache.CacheMetadata) error {
+// pushCacheToCluster streams the stored cache file to other EGs in the cluster
+func (cc *CacheCoordinator) pushCacheToCluster(renderCtx *edgectx.RenderContext, metadata *cache.CacheMetadata) error {
 	// Use configured inter-EG timeout from sharding manager (or default if manager is nil)
 	timeout := defaultInterEgTimeout
 	if cc.shardingManager != nil {
@@ -324,7 +392,7 @@ func (cc *CacheCoordinator) pushCacheToCluster(renderCtx *edgectx.RenderContext,
 
 	start := time.Now()
 	// Push to target EGs and get successful EG IDs
-	successfulEgIDs, pushErr := cc.shardingManager.PushToTargets(ctx, renderCtx.CacheKey, html, metadata, targetEgIDs, renderCtx.RequestID)
+	successfulEgIDs, pushErr := cc.shardingManager.PushToTargets(ctx, renderCtx.CacheKey, metadata, targetEgIDs, renderCtx.RequestID)
 
 	// Update metadata with ONLY successful EG IDs (even if pushErr != nil, we have local copy)
 	metadata.SetEgIDs(successfulEgIDs)
@@ -384,13 +452,6 @@ func (cc *CacheCoordinator) SaveBypassCache(renderCtx *edgectx.RenderContext, by
 	// Filter headers using safe_response_headers configuration (forCache=true: block Set-Cookie)
 	headers := FilterHeaders(bypassResp.Headers, renderCtx.ResolvedConfig.SafeResponseHeaders, bypassResp.StatusCode, true)
 
-	var title string
-	var indexStatus types.IndexStatus
-	if pageSEO != nil {
-		title = pageSEO.Title
-		indexStatus = pageSEO.IndexStatus
-	}
-
 	staleTTL := getStaleTTL(renderCtx.ResolvedConfig.Bypass.Cache.Expired)
 
 	return cc.SaveCache(
@@ -402,8 +463,7 @@ func (cc *CacheCoordinator) SaveBypassCache(renderCtx *edgectx.RenderContext, by
 		renderCtx.ResolvedConfig.Bypass.Cache.TTL,
 		staleTTL,
 		true, // bypass cache always attempts push (size threshold handled in SaveCache)
-		indexStatus,
-		title,
+		pageSEO,
 	)
 }
 
@@ -427,6 +487,16 @@ func (cc *CacheCoordinator) CanSaveBypassCache(renderCtx *edgectx.RenderContext,
 
 // GetCacheFileForServing prepares cache file information for serving
 func (cc *CacheCoordinator) GetCacheFileForServing(cacheEntry *cache.CacheMetadata, logger *zap.Logger) (*cache.CacheResponse, error) {
+	if cc.hotCache != nil {
+		if body, ok := cc.hotCache.GetBody(cacheEntry.Key, cacheEntry.FilePath); ok {
+			return &cache.CacheResponse{
+				Content:     body,
+				ContentSize: cacheEntry.Size,
+				CacheAge:    time.Now().UTC().Sub(cacheEntry.CreatedAt),
+			}, nil
+		}
+	}
+
 	resp, err := cc.cacheService.GetCacheFile(cacheEntry, logger)
 	if err != nil {
 		// Record decompression error metric if applicable
@@ -439,25 +509,72 @@ func (cc *CacheCoordinator) GetCacheFileForServing(cacheEntry *cache.CacheMetada
 		}
 		return nil, err
 	}
+
+	if cc.hotCache != nil {
+		cc.cacheBody(cacheEntry, resp, logger)
+	}
 	return resp, nil
 }
 
-// pullCacheContent is a private helper that pulls cache content from remote EGs
-// Common logic used by both TryPullFromRemote and PullFromRemoteToMemory
+// cacheBody offers the decoded body of a served entry to the hot cache.
+// Uncompressed files are read once so later hits skip the filesystem.
+func (cc *CacheCoordinator) cacheBody(cacheEntry *cache.CacheMetadata, resp *cache.CacheResponse, logger *zap.Logger) {
+	body := resp.Content
+	if resp.IsFileBased() {
+		if resp.ContentSize <= 0 || resp.ContentSize > cc.hotCache.MaxBodySize() {
+			return
+		}
+		content, err := cc.fsCache.ReadHTML(resp.FilePath)
+		if err != nil {
+			logger.Debug("Failed to read cache file for hot cache", zap.Error(err))
+			return
+		}
+		body = content
+	}
+	cc.hotCache.SetBody(cacheEntry.Key, cacheEntry.FilePath, body)
+}
+
+// pullCacheContent is a private helper that pulls cache content from remote EGs into memory
 // Returns (content, remoteEgIDs, nil) on success, (nil, nil, error) on failure
 func (cc *CacheCoordinator) pullCacheContent(
 	ctx context.Context,
 	renderCtx *edgectx.RenderContext,
 	metadata *cache.CacheMetadata,
 ) ([]byte, []string, error) {
+	remoteEgIDs, err := cc.pullSources(ctx, renderCtx, metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Pull content from remote (using filtered list of healthy EGs)
+	cacheKey := renderCtx.CacheKey.String()
+	content, err := cc.shardingManager.PullFromRemote(ctx, renderCtx.CacheKey, remoteEgIDs)
+	if err != nil {
+		renderCtx.Logger.Warn("Failed to pull cache from remote EGs",
+			zap.String("cache_key", cacheKey),
+			zap.Strings("remote_egs", remoteEgIDs),
+			zap.Error(err))
+		return nil, nil, fmt.Errorf("pull from remote failed: %w", err)
+	}
+
+	return content, remoteEgIDs, nil
+}
+
+// pullSources returns the healthy remote EGs holding the cache entry
+// Common logic used by both TryPullFromRemote and PullFromRemoteToMemory
+func (cc *CacheCoordinator) pullSources(
+	ctx context.Context,
+	renderCtx *edgectx.RenderContext,
+	metadata *cache.CacheMetadata,
+) ([]string, error) {
 	// 1. Check if sharding is enabled
 	if cc.shardingManager == nil || !cc.shardingManager.IsEnabled() {
-		return nil, nil, fmt.Errorf("sharding not enabled")
+		return nil, fmt.Errorf("sharding not enabled")
 	}
 
 	// 2. Check if metadata has eg_ids (indicates cache exists on other EGs)
 	if metadata.IsEmpty() {
-		return nil, nil, fmt.Errorf("no eg_ids in metadata")
+		return nil, fmt.Errorf("no eg_ids in metadata")
 	}
 
 	// 3. Get remote EG IDs (filter out self)
@@ -465,7 +582,7 @@ func (cc *CacheCoordinator) pullCacheContent(
 	remoteEgIDs := metadata.GetRemoteEgIDs(selfEgID)
 
 	if len(remoteEgIDs) == 0 {
-		return nil, nil, fmt.Errorf("no remote EGs available")
+		return nil, fmt.Errorf("no remote EGs available")
 	}
 
 	// 4. Filter remoteEgIDs to only include healthy/alive EGs (optimization)
@@ -505,22 +622,11 @@ func (cc *CacheCoordinator) pullCacheContent(
 			// All EGs appear offline - fall back to trying original list
 			renderCtx.Logger.Warn("All remote EGs appear offline", zap.Strings("remote_egs", remoteEgIDs))
 
-			return nil, nil, fmt.Errorf("no remote EGs online available")
+			return nil, fmt.Errorf("no remote EGs online available")
 		}
 	}
 
-	// 5. Pull content from remote (using filtered list of healthy EGs)
-	cacheKey := renderCtx.CacheKey.String()
-	content, err := cc.shardingManager.PullFromRemote(ctx, renderCtx.CacheKey, remoteEgIDs)
-	if err != nil {
-		renderCtx.Logger.Warn("Failed to pull cache from remote EGs",
-			zap.String("cache_key", cacheKey),
-			zap.Strings("remote_egs", remoteEgIDs),
-			zap.Error(err))
-		return nil, nil, fmt.Errorf("pull from remote failed: %w", err)
-	}
-
-	return content, remoteEgIDs, nil
+	return remoteEgIDs, nil
 }
 
 // TryPullFromRemote attempts to pull cache from remote EGs and stores locally
@@ -530,22 +636,30 @@ func (cc *CacheCoordinator) TryPullFromRemote(renderCtx *edgectx.RenderContext,
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Pull content from remote using helper
-	content, remoteEgIDs, err := cc.pullCacheContent(ctx, renderCtx, metadata)
+	remoteEgIDs, err := cc.pullSources(ctx, renderCtx, metadata)
 	if err != nil {
 		return nil, false
 	}
 
-	// Store pulled cache locally
+	// Stream pulled cache straight to its local path (already compressed, never buffered)
 	cacheKey := renderCtx.CacheKey.String()
-	err = cc.cacheService.StoreRemoteCacheLocally(ctx, renderCtx.CacheKey, content, metadata)
+	absolutePath, err := cc.cacheService.GetAbsoluteFilePath(metadata.FilePath)
 	if err != nil {
-		renderCtx.Logger.Error("Failed to store remote cache locally",
+		renderCtx.Logger.Error("Invalid cache file path for pulled cache",
 			zap.String("cache_key", cacheKey),
 			zap.Error(err))
 		return nil, false
 	}
 
+	contentSize, err := cc.shardingManager.PullFromRemoteToFile(ctx, renderCtx.CacheKey, remoteEgIDs, absolutePath)
+	if err != nil {
+		renderCtx.Logger.Warn("Failed to pull cache from remote EGs",
+			zap.String("cache_key", cacheKey),
+			zap.Strings("remote_egs", remoteEgIDs),
+			zap.Error(err))
+		return nil, false
+	}
+
 	// Update metadata to add self to eg_ids
 	selfEgID := cc.shardingManager.GetEgID()
 
@@ -596,7 +710,7 @@ func (cc *CacheCoordinator) TryPullFromRemote(renderCtx *edgectx.RenderContext,
 	renderCtx.Logger.Info("Successfully pulled and stored cache from remote EG",
 		zap.String("cache_key", cacheKey),
 		zap.Strings("source_egs", remoteEgIDs),
-		zap.Int("content_size", len(content)))
+		zap.Int64("content_size", contentSize))
 
 	return metadata, true
 }
@@ -676,3 +790,128 @@ func (cc *CacheCoordinator) DeleteStaleMetadata(renderCtx *edgectx.RenderContext
 
 	return nil
 }
+
+// SaveRecacheRender saves a recache render, extending the existing entry in place when
+// content is unchanged. previous is the entry being replaced (nil skips change detection).
+// Returns the detected content change, nil when there was nothing to compare against.
+func (cc *CacheCoordinator) SaveRecacheRender(
+	renderCtx *edgectx.RenderContext,
+	renderResult *RenderServiceResult,
+	previous *cache.CacheMetadata,
+	similarityThreshold float64,
+) (*ContentChange, error) {
+	change := DetectContentChange(previous, renderResult.HTML, renderResult.StatusCode, renderResult.PageSEO, similarityThreshold)
+	cc.recordContentChange(renderCtx, change)
+
+	if change != nil && !change.Changed && cc.canExtendInPlace(previous) {
+		err := cc.ExtendCache(renderCtx, previous)
+		if err == nil {
+			// Links are not kept in the entry, refresh them from the new render
+			cc.storeLinks(renderCtx, renderResult.StatusCode, renderResult.PageSEO)
+			return change, nil
+		}
+		renderCtx.Logger.Warn("Failed to extend unchanged cache in place, rewriting",
+			zap.String("cache_key", renderCtx.CacheKey.String()),
+			zap.Error(err))
+	}
+
+	return change, cc.SaveRenderCache(renderCtx, renderResult)
+}
+
+// ExtendCache extends the TTL of an unchanged render cache entry without rewriting content.
+// Cache files are stored under their expiration time, so the local file is moved to the
+// path for the new expiration instead of being rewritten. Replicas on other EGs still hold
+// the old path, so they are refreshed from the local file when sharding is enabled.
+func (cc *CacheCoordinator) ExtendCache(renderCtx *edgectx.RenderContext, previous *cache.CacheMetadata) error {
+	// IMPORTANT: Must use UTC for timezone consistency with cleanup worker
+	now := time.Now().UTC()
+	expiresAt := now.Add(renderCtx.ResolvedConfig.Cache.TTL)
+	staleTTL := getStaleTTL(renderCtx.ResolvedConfig.Cache.Expired)
+
+	ext := cache.GetCompressionExt(cache.DetectAlgorithmFromPath(previous.FilePath))
+	relativeFilePath := cc.metadata.GenerateFilePath(renderCtx.CacheKey, expiresAt) + ext
+
+	oldAbsolutePath, err := cc.metadata.GetAbsoluteFilePath(previous.FilePath)
+	if err != nil {
+		return fmt.Errorf("invalid cache file path: %w", err)
+	}
+	newAbsolutePath, err := cc.metadata.GetAbsoluteFilePath(relativeFilePath)
+	if err != nil {
+		return fmt.Errorf("invalid cache file path: %w", err)
+	}
+
+	if relativeFilePath != previous.FilePath {
+		if err := cc.fsCache.MoveFile(oldAbsolutePath, newAbsolutePath); err != nil {
+			return err
+		}
+		// Keep the Markdown variant with its page (missing unless requested before)
+		if cc.fsCache.FileExists(cache.MarkdownPath(oldAbsolutePath)) {
+			_ = cc.fsCache.MoveFile(cache.MarkdownPath(oldAbsolutePath), cache.MarkdownPath(newAbsolutePath))
+		}
+	}
+
+	metadata := *previous
+	metadata.FilePath = relativeFilePath
+	metadata.RequestID = renderCtx.RequestID
+	metadata.ExpiresAt = expiresAt
+	metadata.LastAccess = now
+	metadata.SetEgIDs([]string{cc.shardingManager.GetEgID()})
+
+	metaCtx, metaCancel := context.WithTimeout(context.Background(), redisCacheOperationTimeout)
+	defer metaCancel()
+
+	if err := cc.metadata.StoreMetadata(metaCtx, &metadata, renderCtx.CacheKey, staleTTL); err != nil {
+		// Put the file back so the previous metadata still points at it
+		if relativeFilePath != previous.FilePath {
+			_ = cc.fsCache.MoveFile(newAbsolutePath, oldAbsolutePath)
+		}
+		return fmt.Errorf("failed to store metadata: %w", err)
+	}
+
+	renderCtx.Logger.Info("Content unchanged, cache TTL extended in place",
+		zap.String("cache_key", renderCtx.CacheKey.String()),
+		zap.String("relative_path", relativeFilePath),
+		zap.Time("expires_at", expiresAt))
+
+	hadReplicas := len(previous.GetRemoteEgIDs(cc.shardingManager.GetEgID())) > 0
+	if hadReplicas && cc.shardingManager.IsEnabled() && renderCtx.ResolvedConfig.Sharding.PushOnRender {
+		if err := cc.pushCacheToCluster(renderCtx, &metadata); err != nil {
+			renderCtx.Logger.Warn("Failed to refresh replicas of extended cache (cached locally)",
+				zap.String("cache_key", renderCtx.CacheKey.String()),
+				zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// canExtendInPlace reports whether an existing entry has a local render file that can be kept
+func (cc *CacheCoordinator) canExtendInPlace(previous *cache.CacheMetadata) bool {
+	return previous.Source == cache.SourceRender &&
+		previous.FilePath != "" &&
+		!isRedirectStatusCode(previous.StatusCode) &&
+		previous.HasEgID(cc.shardingManager.GetEgID())
+}
+
+// recordContentChange records recache content comparison metrics
+func (cc *CacheCoordinator) recordContentChange(renderCtx *edgectx.RenderContext, change *ContentChange) {
+	if cc.metrics == nil {
+		return
+	}
+
+	host := renderCtx.Host.Domain
+	switch {
+	case change == nil:
+		cc.metrics.RecordRecacheContent(host, metrics.RecacheContentNew)
+	case change.Changed:
+		cc.metrics.RecordRecacheContent(host, metrics.RecacheContentChanged)
+	default:
+		cc.metrics.RecordRecacheContent(host, metrics.RecacheContentUnchanged)
+	}
+
+	if change != nil {
+		for _, field := range change.ChangedFields {
+			cc.metrics.RecordRecacheSEOChange(host, field)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package orchestrator
+
+import (
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// SEO fields tracked for change events
+const (
+	ContentFieldTitle       = "title"
+	ContentFieldCanonical   = "canonical"
+	ContentFieldIndexStatus = "index_status"
+)
+
+// ContentChange describes how re-rendered content compares to the cached version
+type ContentChange struct {
+	Changed             bool     // True if the cache entry must be rewritten
+	ContentHashMatch    bool     // Byte-identical content
+	Similarity          float64  // MinHash similarity to the previous version (1.0 on hash match)
+	ChangedFields       []string // SEO fields that changed (title, canonical, index_status)
+	PreviousTitle       string
+	PreviousCanonical   string
+	PreviousIndexStatus int
+}
+
+// DetectContentChange compares rendered content against the previous cache entry.
+// Content is unchanged when it is byte-identical, or when its MinHash similarity
+// reaches threshold with the same status code and no SEO field changes.
+// Returns nil when there is no previous entry to compare against, including
+// redirects and entries written before content fingerprints were stored.
+func DetectContentChange(previous *cache.CacheMetadata, content []byte, statusCode int, seo *types.PageSEO, threshold float64) *ContentChange {
+	if previous == nil || previous.ContentHash == "" {
+		return nil
+	}
+
+	change := &ContentChange{
+		PreviousTitle:       previous.Title,
+		PreviousCanonical:   previous.CanonicalURL,
+		PreviousIndexStatus: previous.IndexStatus,
+	}
+
+	var title, canonical string
+	var indexStatus int
+	var minHash []uint64
+	if seo != nil {
+		title = seo.Title
+		canonical = seo.CanonicalURL
+		indexStatus = int(seo.IndexStatus)
+		minHash = seo.PageMinHash
+	}
+
+	if title != previous.Title {
+		change.ChangedFields = append(change.ChangedFields, ContentFieldTitle)
+	}
+	if canonical != previous.CanonicalURL {
+		change.ChangedFields = append(change.ChangedFields, ContentFieldCanonical)
+	}
+	if indexStatus != previous.IndexStatus {
+		change.ChangedFields = append(change.ChangedFields, ContentFieldIndexStatus)
+	}
+
+	change.ContentHashMatch = previous.ContentHash == cache.ContentHash(content)
+	if change.ContentHashMatch {
+		change.Similarity = 1
+	} else {
+		change.Similarity = htmlprocessor.MinHashSimilarity(previous.MinHash, minHash)
+	}
+
+	sameStatus := previous.StatusCode == statusCode
+	similar := change.ContentHashMatch || (change.Similarity >= threshold && len(change.ChangedFields) == 0)
+	change.Changed = !sameStatus || !similar
+
+	return change
+}
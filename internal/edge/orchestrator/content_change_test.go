@@ -0,0 +1,228 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/internal/edge/sharding"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// localShardingManager is a sharding-disabled ShardingManager for a single EG
+type localShardingManager struct {
+	egID string
+}
+
+func (m *localShardingManager) IsEnabled() bool { return false }
+func (m *localShardingManager) ComputeTargets(ctx context.Context, cacheKey string) ([]string, error) {
+	return nil, nil
+}
+func (m *localShardingManager) IsTargetForCache(ctx context.Context, cacheKey string) (bool, error) {
+	return true, nil
+}
+func (m *localShardingManager) PushToTargets(ctx context.Context, cacheKey *types.CacheKey, metadata *cache.CacheMetadata, targetEgIDs []string, requestID string) ([]string, error) {
+	return nil, nil
+}
+func (m *localShardingManager) PullFromRemote(ctx context.Context, cacheKey *types.CacheKey, egIDs []string) ([]byte, error) {
+	return nil, nil
+}
+func (m *localShardingManager) PullFromRemoteToFile(ctx context.Context, cacheKey *types.CacheKey, egIDs []string, absolutePath string) (int64, error) {
+	return 0, nil
+}
+func (m *localShardingManager) GetEgID() string                  { return m.egID }
+func (m *localShardingManager) GetReplicationFactor() int        { return 1 }
+func (m *localShardingManager) GetInterEgTimeout() time.Duration { return time.Second }
+func (m *localShardingManager) GetHealthyEGs(ctx context.Context) ([]sharding.EGInfo, error) {
+	return nil, nil
+}
+
+func setupTestCacheCoordinator(t *testing.T) (*CacheCoordinator, *cache.MetadataStore, string) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	logger := zap.NewNop()
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, logger)
+	require.NoError(t, err)
+
+	cacheDir := t.TempDir()
+	metadataStore := cache.NewMetadataStore(redisClient, redis.NewKeyGenerator(), cacheDir, logger)
+	fsCache := cache.NewFilesystemCache(logger)
+	cacheService := cache.NewCacheService(metadataStore, fsCache, logger)
+
+	cc := NewCacheCoordinator(metadataStore, fsCache, cacheService, &localShardingManager{egID: "eg-1"}, nil, logger)
+	return cc, metadataStore, cacheDir
+}
+
+func newTestRecacheContext() *edgectx.RenderContext {
+	return &edgectx.RenderContext{
+		RequestID: "recache-1",
+		Logger:    zap.NewNop(),
+		TargetURL: "https://example.com/page",
+		Host:      &types.Host{ID: 1, Domain: "example.com"},
+		Dimension: "desktop",
+		CacheKey:  &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "abc123"},
+		ResolvedConfig: &config.ResolvedConfig{
+			Cache:       config.ResolvedCacheConfig{TTL: 24 * time.Hour},
+			Compression: types.CompressionNone,
+		},
+	}
+}
+
+func renderResultFor(t *testing.T, htmlContent string) *RenderServiceResult {
+	return &RenderServiceResult{
+		HTML:       []byte(htmlContent),
+		StatusCode: 200,
+		PageSEO:    pageSEOFor(t, htmlContent),
+	}
+}
+
+func TestDetectContentChange(t *testing.T) {
+	const page = `<html><head><title>Shoes</title><link rel="canonical" href="https://example.com/shoes"></head>
+<body><p>Red running shoes with extra cushioning for long distance training and racing.</p></body></html>`
+
+	seo := pageSEOFor(t, page)
+	previous := &cache.CacheMetadata{
+		StatusCode:   200,
+		ContentHash:  cache.ContentHash([]byte(page)),
+		Title:        seo.Title,
+		CanonicalURL: seo.CanonicalURL,
+		IndexStatus:  int(seo.IndexStatus),
+		MinHash:      seo.PageMinHash,
+	}
+
+	t.Run("no previous entry", func(t *testing.T) {
+		assert.Nil(t, DetectContentChange(nil, []byte(page), 200, seo, 1))
+	})
+
+	t.Run("legacy entry without fingerprint", func(t *testing.T) {
+		assert.Nil(t, DetectContentChange(&cache.CacheMetadata{StatusCode: 200}, []byte(page), 200, seo, 1))
+	})
+
+	t.Run("byte-identical content", func(t *testing.T) {
+		change := DetectContentChange(previous, []byte(page), 200, seo, 1)
+		require.NotNil(t, change)
+		assert.False(t, change.Changed)
+		assert.True(t, change.ContentHashMatch)
+		assert.Equal(t, 1.0, change.Similarity)
+	})
+
+	t.Run("script-only change is unchanged", func(t *testing.T) {
+		withScript := page + `<script>var nonce = "abc";</script>`
+		change := DetectContentChange(previous, []byte(withScript), 200, pageSEOFor(t, withScript), 1)
+		require.NotNil(t, change)
+		assert.False(t, change.ContentHashMatch)
+		assert.False(t, change.Changed)
+	})
+
+	t.Run("title change is reported", func(t *testing.T) {
+		retitled := `<html><head><title>Running Shoes</title><link rel="canonical" href="https://example.com/shoes"></head>
+<body><p>Red running shoes with extra cushioning for long distance training and racing.</p></body></html>`
+		change := DetectContentChange(previous, []byte(retitled), 200, pageSEOFor(t, retitled), 0.5)
+		require.NotNil(t, change)
+		assert.True(t, change.Changed)
+		assert.Equal(t, []string{ContentFieldTitle}, change.ChangedFields)
+		assert.Equal(t, "Shoes", change.PreviousTitle)
+	})
+
+	t.Run("canonical and index status changes are reported", func(t *testing.T) {
+		noindex := `<html><head><title>Shoes</title><meta name="robots" content="noindex"></head>
+<body><p>Red running shoes with extra cushioning for long distance training and racing.</p></body></html>`
+		change := DetectContentChange(previous, []byte(noindex), 200, pageSEOFor(t, noindex), 0.5)
+		require.NotNil(t, change)
+		assert.True(t, change.Changed)
+		assert.ElementsMatch(t, []string{ContentFieldCanonical, ContentFieldIndexStatus}, change.ChangedFields)
+	})
+
+	t.Run("status code change", func(t *testing.T) {
+		change := DetectContentChange(previous, []byte(page), 404, seo, 1)
+		require.NotNil(t, change)
+		assert.True(t, change.Changed)
+	})
+}
+
+func TestCacheCoordinator_SaveRecacheRender(t *testing.T) {
+	const page = `<html><head><title>Hello</title></head><body><p>one two three four five six</p></body></html>`
+
+	cc, metadataStore, cacheDir := setupTestCacheCoordinator(t)
+	renderCtx := newTestRecacheContext()
+
+	// Initial render
+	change, err := cc.SaveRecacheRender(renderCtx, renderResultFor(t, page), nil, 1)
+	require.NoError(t, err)
+	assert.Nil(t, change)
+
+	previous, err := metadataStore.GetCacheEntry(context.Background(), renderCtx.CacheKey)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.NotEmpty(t, previous.ContentHash)
+	assert.Len(t, previous.MinHash, 64)
+
+	// Pretend the entry was written an hour ago so the extended path differs
+	previous.ExpiresAt = previous.ExpiresAt.Add(-time.Hour)
+	oldPath := metadataStore.GenerateFilePath(renderCtx.CacheKey, previous.ExpiresAt)
+	oldAbs, err := metadataStore.GetAbsoluteFilePath(oldPath)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(oldAbs), 0755))
+	currentAbs, err := metadataStore.GetAbsoluteFilePath(previous.FilePath)
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(currentAbs, oldAbs))
+	previous.FilePath = oldPath
+
+	t.Run("unchanged content extends TTL and moves file", func(t *testing.T) {
+		renderCtx.RequestID = "recache-2"
+		change, err := cc.SaveRecacheRender(renderCtx, renderResultFor(t, page), previous, 1)
+		require.NoError(t, err)
+		require.NotNil(t, change)
+		assert.False(t, change.Changed)
+
+		extended, err := metadataStore.GetCacheEntry(context.Background(), renderCtx.CacheKey)
+		require.NoError(t, err)
+		assert.Equal(t, "recache-2", extended.RequestID)
+		assert.Equal(t, previous.CreatedAt, extended.CreatedAt, "created_at keeps the content's original time")
+		assert.True(t, extended.ExpiresAt.After(previous.ExpiresAt))
+		assert.NotEqual(t, oldPath, extended.FilePath)
+
+		_, err = os.Stat(oldAbs)
+		assert.True(t, os.IsNotExist(err), "old file must be moved")
+		content, err := os.ReadFile(filepath.Join(cacheDir, extended.FilePath))
+		require.NoError(t, err)
+		assert.Equal(t, page, string(content))
+	})
+
+	t.Run("changed content rewrites the entry", func(t *testing.T) {
+		current, err := metadataStore.GetCacheEntry(context.Background(), renderCtx.CacheKey)
+		require.NoError(t, err)
+
+		const updated = `<html><head><title>Hello</title></head><body><p>completely different text now</p></body></html>`
+		change, err := cc.SaveRecacheRender(renderCtx, renderResultFor(t, updated), current, 1)
+		require.NoError(t, err)
+		require.NotNil(t, change)
+		assert.True(t, change.Changed)
+
+		rewritten, err := metadataStore.GetCacheEntry(context.Background(), renderCtx.CacheKey)
+		require.NoError(t, err)
+		assert.Equal(t, cache.ContentHash([]byte(updated)), rewritten.ContentHash)
+	})
+}
+
+func pageSEOFor(t *testing.T, htmlContent string) *types.PageSEO {
+	t.Helper()
+	doc, err := htmlprocessor.ParseWithDOM([]byte(htmlContent))
+	require.NoError(t, err)
+	return doc.ExtractPageSEO(200, "https://example.com/shoes")
+}
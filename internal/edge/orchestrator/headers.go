@@ -2,6 +2,8 @@ package orchestrator
 
 import (
 	"strings"
+
+	"github.com/edgecomet/engine/internal/edge/cache"
 )
 
 // securityHeadersDenyList contains headers that must never be cached or served for security reasons
@@ -30,6 +32,12 @@ func isRedirectStatusCode(statusCode int) bool {
 	return statusCode == 301 || statusCode == 302 || statusCode == 307 || statusCode == 308
 }
 
+// isMetadataOnly reports whether a cache entry is served from Redis metadata alone
+// (redirects and deleted-content markers), so it is available on every EG
+func isMetadataOnly(meta *cache.CacheMetadata) bool {
+	return isRedirectStatusCode(meta.StatusCode) || meta.IsDeleted()
+}
+
 // getHeaderCaseInsensitive retrieves header value with case-insensitive key matching
 // HTTP headers are case-insensitive per RFC 7230
 func getHeaderCaseInsensitive(headers map[string][]string, name string) ([]string, bool) {
@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// ApplyHTMLTransforms runs the resolved HTML post-processing pipeline on a rendered page
+// before it is cached. SEO metadata is re-extracted from the transformed HTML, so fixed
+// canonical or hreflang tags are reflected in cache metadata and events. On error the
+// rendered HTML is kept unchanged. Empty pages (redirects) are skipped.
+func ApplyHTMLTransforms(result *RenderServiceResult, transforms []types.HTMLTransform, pageURL string, logger *zap.Logger) {
+	if result == nil || len(transforms) == 0 || len(result.HTML) == 0 {
+		return
+	}
+
+	doc, err := htmlprocessor.ParseWithDOM(result.HTML)
+	if err != nil {
+		logger.Warn("Failed to parse rendered HTML for transforms", zap.Error(err))
+		return
+	}
+
+	changed, err := doc.ApplyTransforms(transforms, pageURL)
+	if err != nil {
+		logger.Warn("HTML transforms failed, keeping rendered HTML", zap.Error(err))
+		return
+	}
+	if changed == 0 {
+		return
+	}
+
+	transformed := doc.HTML()
+	if len(transformed) == 0 {
+		logger.Warn("Failed to serialize transformed HTML, keeping rendered HTML")
+		return
+	}
+
+	seoURL := result.Metrics.FinalURL
+	if seoURL == "" {
+		seoURL = pageURL
+	}
+
+	logger.Debug("Applied HTML transforms",
+		zap.Int("transforms", len(transforms)),
+		zap.Int("changed", changed),
+		zap.Int("html_size_before", len(result.HTML)),
+		zap.Int("html_size_after", len(transformed)))
+
+	result.HTML = transformed
+	result.PageSEO = doc.ExtractPageSEO(result.StatusCode, seoURL)
+}
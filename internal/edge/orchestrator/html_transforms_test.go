@@ -0,0 +1,37 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestApplyHTMLTransforms(t *testing.T) {
+	rendered := `<html><head><title>Page</title><link rel="canonical" href="https://staging.example.com/page"></head>` +
+		`<body><div class="cookie-banner">Accept</div><p>Content</p></body></html>`
+	result := &RenderServiceResult{
+		HTML:       []byte(rendered),
+		StatusCode: 200,
+		PageSEO:    &types.PageSEO{CanonicalURL: "https://staging.example.com/page"},
+	}
+	result.Metrics.FinalURL = "https://example.com/page"
+
+	ApplyHTMLTransforms(result, []types.HTMLTransform{
+		{Type: types.HTMLTransformRemove, Selector: ".cookie-banner"},
+		{Type: types.HTMLTransformRewriteURLs, From: "https://staging.example.com", To: "https://example.com"},
+	}, "https://example.com/page", zap.NewNop())
+
+	assert.NotContains(t, string(result.HTML), "cookie-banner")
+	require.NotNil(t, result.PageSEO)
+	assert.Equal(t, "https://example.com/page", result.PageSEO.CanonicalURL, "SEO re-extracted from transformed HTML")
+	assert.Equal(t, types.IndexStatusIndexable, types.IndexStatus(result.PageSEO.IndexStatus))
+
+	// Invalid transforms keep the rendered HTML
+	result = &RenderServiceResult{HTML: []byte(rendered), StatusCode: 200}
+	ApplyHTMLTransforms(result, []types.HTMLTransform{{Type: types.HTMLTransformRemove, Selector: "a:hover"}}, "https://example.com/page", zap.NewNop())
+	assert.Equal(t, rendered, string(result.HTML))
+}
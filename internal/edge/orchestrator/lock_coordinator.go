@@ -15,22 +15,32 @@ import (
 // LockCoordinator handles distributed locking for render coordination
 type LockCoordinator struct {
 	metadata *cache.MetadataStore
+	notifier *RenderNotifier
 	logger   *zap.Logger
 }
 
 // NewLockCoordinator creates a new LockCoordinator instance
 func NewLockCoordinator(
 	metadata *cache.MetadataStore,
+	notifier *RenderNotifier,
 	logger *zap.Logger,
 ) *LockCoordinator {
 	return &LockCoordinator{
 		metadata: metadata,
+		notifier: notifier,
 		logger:   logger,
 	}
 }
 
-// AcquireLock attempts to acquire a render lock for the given cache key
+// AcquireLock attempts to acquire a render lock for the given cache key.
+// Requests for a key already in flight on this EG join that flight without contacting Redis.
 func (lc *LockCoordinator) AcquireLock(renderCtx *edgectx.RenderContext) (bool, error) {
+	key := renderCtx.CacheKey.String()
+	if !lc.notifier.join(key, renderCtx.RequestID) {
+		renderCtx.Logger.Debug("Render already in flight on this EG, joining it")
+		return false, nil
+	}
+
 	// Use independent timeout to prevent race condition from request cancellation
 	// This ensures lock acquisition always completes or fails atomically
 	lockCtx, cancel := context.WithTimeout(context.Background(), redisLockOperationTimeout)
@@ -46,6 +56,7 @@ func (lc *LockCoordinator) AcquireLock(renderCtx *edgectx.RenderContext) (bool,
 	acquired, err := lc.metadata.AcquireLock(lockCtx, renderCtx.LockKey, lockTTL)
 	// Uses independent context prevents inconsistent lock state
 	if err != nil {
+		lc.notifier.leave(key, renderCtx.RequestID)
 		renderCtx.Logger.Error("Failed to acquire render lock", zap.Error(err))
 		return false, fmt.Errorf("failed to acquire render lock: %w", err)
 	}
@@ -57,15 +68,19 @@ func (lc *LockCoordinator) AcquireLock(renderCtx *edgectx.RenderContext) (bool,
 	return acquired, nil
 }
 
-// ReleaseLock releases the render lock using background context for reliable cleanup
-func (lc *LockCoordinator) ReleaseLock(renderCtx *edgectx.RenderContext) {
+// ReleaseLock releases the render lock using background context for reliable cleanup,
+// then notifies requests waiting for this render on all EGs of its outcome
+func (lc *LockCoordinator) ReleaseLock(renderCtx *edgectx.RenderContext, outcome RenderOutcome) {
 	// Use background context for cleanup - must always complete
 	if err := lc.metadata.ReleaseLock(context.Background(), renderCtx.LockKey); err != nil {
 		renderCtx.Logger.Error("Failed to release render lock", zap.Error(err))
 	}
+	lc.notifier.Complete(renderCtx.CacheKey.String(), renderCtx.RequestID, outcome)
 }
 
-// WaitForConcurrentRender waits for another render to complete and tries to serve from cache
+// WaitForConcurrentRender waits for another render to complete and tries to serve from cache.
+// The render outcome is pushed by the RenderNotifier; the cache and lock are checked
+// periodically only in case a notification is lost.
 func (lc *LockCoordinator) WaitForConcurrentRender(
 	renderCtx *edgectx.RenderContext,
 	cacheCoord *CacheCoordinator,
@@ -85,51 +100,86 @@ func (lc *LockCoordinator) WaitForConcurrentRender(
 
 	renderCtx.Logger.Info("Lock not acquired, waiting for concurrent render to complete",
 		zap.Duration("wait_timeout", waitTimeout),
-		zap.Duration("host_render_timeout", baseTimeout),
-		zap.Duration("poll_interval", concurrentRenderPollInterval))
+		zap.Duration("host_render_timeout", baseTimeout))
+
+	// A leader waiting for another EG hands its local flight over once done
+	key := renderCtx.CacheKey.String()
+	defer lc.notifier.leave(key, renderCtx.RequestID)
+
+	notified, unregister := lc.notifier.wait(key)
+	defer unregister()
 
-	// Poll for cache availability within timeout window
 	startTime := time.Now().UTC()
-	deadline := startTime.Add(waitTimeout)
-	pollAttempt := 0
 
-	for time.Now().UTC().Before(deadline) {
-		// Check if request has timed out before continuing
-		if renderCtx.IsTimedOut() {
+	// The render may have completed before the waiter was registered
+	if _, exists := cacheCoord.LookupCache(renderCtx); exists {
+		return lc.waitSucceeded(renderCtx, metricsCollector, startTime), nil
+	}
+
+	waitTimer := time.NewTimer(waitTimeout)
+	defer waitTimer.Stop()
+	requestTimer := time.NewTimer(renderCtx.TimeRemaining())
+	defer requestTimer.Stop()
+	checkTicker := time.NewTicker(concurrentRenderCheckInterval)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case outcome := <-notified:
+			if outcome.Success {
+				if _, exists := cacheCoord.LookupCache(renderCtx); exists {
+					return lc.waitSucceeded(renderCtx, metricsCollector, startTime), nil
+				}
+				outcome.ErrorType = "cache_disappeared"
+			}
+			return lc.waitFailed(renderCtx, metricsCollector, startTime, outcome), nil
+
+		case <-checkTicker.C:
+			if _, exists := cacheCoord.LookupCache(renderCtx); exists {
+				return lc.waitSucceeded(renderCtx, metricsCollector, startTime), nil
+			}
+			// Lock gone without a cache entry: the render failed or its holder died
+			lockCtx, cancel := context.WithTimeout(context.Background(), redisLockOperationTimeout)
+			locked, err := lc.metadata.IsLocked(lockCtx, renderCtx.LockKey)
+			cancel()
+			if err == nil && !locked {
+				return lc.waitFailed(renderCtx, metricsCollector, startTime, renderFailed("lock_released")), nil
+			}
+
+		case <-requestTimer.C:
 			waitTime := time.Now().UTC().Sub(startTime)
 			renderCtx.Logger.Warn("Request timeout during concurrent render wait",
-				zap.Int("poll_attempts", pollAttempt),
 				zap.Duration("wait_time", waitTime),
 				zap.Duration("time_remaining", renderCtx.TimeRemaining()))
 			metricsCollector.RecordWaitTimeout(renderCtx.Host.Domain, renderCtx.Dimension, waitTime)
 			return WaitRequestTimeout, nil
-		}
-
-		time.Sleep(concurrentRenderPollInterval)
-		pollAttempt++
 
-		renderCtx.Logger.Debug("Polling for cache availability",
-			zap.Int("attempt", pollAttempt),
-			zap.Duration("elapsed", time.Now().UTC().Sub(startTime)),
-			zap.Duration("remaining", deadline.Sub(time.Now().UTC())),
-			zap.Duration("timeout_remaining", renderCtx.TimeRemaining()))
-
-		if _, exists := cacheCoord.LookupCache(renderCtx); exists {
+		case <-waitTimer.C:
 			waitTime := time.Now().UTC().Sub(startTime)
-			renderCtx.Logger.Info("Cache became available after waiting",
-				zap.Int("poll_attempts", pollAttempt),
+			renderCtx.Logger.Warn("Timeout waiting for concurrent render, using bypass mode",
 				zap.Duration("wait_time", waitTime))
-			metricsCollector.RecordWaitSuccess(renderCtx.Host.Domain, renderCtx.Dimension, waitTime)
-			return WaitCacheAvailable, nil
+			metricsCollector.RecordWaitTimeout(renderCtx.Host.Domain, renderCtx.Dimension, waitTime)
+			return WaitTimeout, nil
 		}
 	}
+}
+
+func (lc *LockCoordinator) waitSucceeded(renderCtx *edgectx.RenderContext, metricsCollector *metrics.MetricsCollector, startTime time.Time) WaitResult {
+	waitTime := time.Now().UTC().Sub(startTime)
+	renderCtx.Logger.Info("Cache became available after waiting",
+		zap.Duration("wait_time", waitTime))
+	metricsCollector.RecordWaitSuccess(renderCtx.Host.Domain, renderCtx.Dimension, waitTime)
+	return WaitCacheAvailable
+}
 
+func (lc *LockCoordinator) waitFailed(renderCtx *edgectx.RenderContext, metricsCollector *metrics.MetricsCollector, startTime time.Time, outcome RenderOutcome) WaitResult {
 	waitTime := time.Now().UTC().Sub(startTime)
-	renderCtx.Logger.Warn("Timeout waiting for concurrent render, using bypass mode",
-		zap.Int("total_attempts", pollAttempt),
+	renderCtx.Logger.Info("Concurrent render failed, not waiting any longer",
+		zap.String("error_type", outcome.ErrorType),
+		zap.String("rendering_eg", outcome.EgID),
 		zap.Duration("wait_time", waitTime))
-	metricsCollector.RecordWaitTimeout(renderCtx.Host.Domain, renderCtx.Dimension, waitTime)
-	return WaitTimeout, nil
+	metricsCollector.RecordWaitFailed(renderCtx.Host.Domain, renderCtx.Dimension, waitTime)
+	return WaitRenderFailed
 }
 
 // CalculateLockTTL calculates the lock TTL based on render timeout
@@ -0,0 +1,175 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// SelectOutputFormat returns the response format for a request. Dimensions with format
+// markdown always get Markdown; otherwise Markdown is served when Accept negotiation is
+// enabled and the client prefers text/markdown over text/html.
+func SelectOutputFormat(renderCtx *edgectx.RenderContext, dimension types.Dimension) string {
+	if dimension.EffectiveFormat() == types.OutputFormatMarkdown {
+		return types.OutputFormatMarkdown
+	}
+	if renderCtx.ResolvedConfig != nil && renderCtx.ResolvedConfig.Render.Markdown.AcceptHeader &&
+		prefersMarkdown(string(renderCtx.HTTPCtx.Request.Header.Peek("Accept"))) {
+		return types.OutputFormatMarkdown
+	}
+	return types.OutputFormatHTML
+}
+
+// prefersMarkdown reports whether an Accept header ranks text/markdown at least as high as
+// text/html. Wildcards are ignored so browsers (*/*) keep getting HTML.
+func prefersMarkdown(accept string) bool {
+	markdownQ, htmlQ := 0.0, 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(param, "=")
+			if ok && strings.TrimSpace(name) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/markdown", "text/x-markdown":
+			markdownQ = max(markdownQ, q)
+		case "text/html":
+			htmlQ = max(htmlQ, q)
+		}
+	}
+	return markdownQ > 0 && markdownQ >= htmlQ
+}
+
+// ConvertToMarkdown converts a rendered page to its Markdown variant
+func ConvertToMarkdown(htmlContent []byte, pageURL string, jsonLDSummary bool) ([]byte, error) {
+	doc, err := htmlprocessor.ParseWithDOM(htmlContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html: %w", err)
+	}
+	markdown := doc.Markdown(pageURL, jsonLDSummary)
+	if len(markdown) == 0 {
+		return nil, fmt.Errorf("page has no convertible content")
+	}
+	return markdown, nil
+}
+
+// markdownRenderedBody converts a freshly rendered page for a Markdown request.
+// Redirects, empty pages and failed conversions are served as rendered (HTML).
+func markdownRenderedBody(renderCtx *edgectx.RenderContext, body []byte, statusCode int) []byte {
+	if !renderCtx.WantsMarkdown() {
+		return body
+	}
+	if isRedirectStatusCode(statusCode) || len(body) == 0 {
+		renderCtx.OutputFormat = types.OutputFormatHTML
+		return body
+	}
+
+	markdown, err := ConvertToMarkdown(body, renderCtx.TargetURL, renderCtx.ResolvedConfig.Render.Markdown.JSONLDSummary)
+	if err != nil {
+		renderCtx.Logger.Warn("Markdown conversion failed, serving HTML", zap.Error(err))
+		renderCtx.OutputFormat = types.OutputFormatHTML
+		return body
+	}
+	return markdown
+}
+
+// markdownCacheResponse swaps a cached page for its Markdown variant when the request asks
+// for Markdown. Bypass entries and failed conversions are served unchanged as HTML.
+func (ro *RenderOrchestrator) markdownCacheResponse(renderCtx *edgectx.RenderContext, cacheEntry *cache.CacheMetadata, cacheResp *cache.CacheResponse) *cache.CacheResponse {
+	if !renderCtx.WantsMarkdown() {
+		return cacheResp
+	}
+	if cacheEntry.Source != cache.SourceRender {
+		renderCtx.OutputFormat = types.OutputFormatHTML
+		return cacheResp
+	}
+
+	markdownResp, err := ro.cacheCoord.GetMarkdownForServing(renderCtx, cacheEntry, cacheResp)
+	if err != nil {
+		renderCtx.Logger.Warn("Markdown variant unavailable, serving HTML",
+			zap.String("cache_key", cacheEntry.Key),
+			zap.Error(err))
+		renderCtx.OutputFormat = types.OutputFormatHTML
+		return cacheResp
+	}
+	return markdownResp
+}
+
+// GetMarkdownForServing returns the Markdown variant of a cached page. For entries stored
+// on this EG the variant is kept next to the cache file and converted only once per file;
+// pulled entries are converted in memory.
+func (cc *CacheCoordinator) GetMarkdownForServing(renderCtx *edgectx.RenderContext, cacheEntry *cache.CacheMetadata, htmlResp *cache.CacheResponse) (*cache.CacheResponse, error) {
+	variantPath := ""
+	if cacheEntry.FilePath != "" && cc.IsFileLocal(cacheEntry) {
+		if absolutePath, err := cc.metadata.GetAbsoluteFilePath(cacheEntry.FilePath); err == nil {
+			variantPath = cache.MarkdownPath(absolutePath)
+			if content, ok := readMarkdownVariant(absolutePath, variantPath); ok {
+				return markdownResponse(content, htmlResp), nil
+			}
+		}
+	}
+
+	htmlContent := htmlResp.Content
+	if htmlResp.IsFileBased() {
+		content, err := cc.fsCache.ReadHTML(htmlResp.FilePath)
+		if err != nil {
+			return nil, err
+		}
+		htmlContent = content
+	}
+
+	markdown, err := ConvertToMarkdown(htmlContent, cacheEntry.URL, renderCtx.ResolvedConfig.Render.Markdown.JSONLDSummary)
+	if err != nil {
+		return nil, err
+	}
+
+	if variantPath != "" {
+		if err := cc.fsCache.WriteHTML(variantPath, markdown); err != nil {
+			renderCtx.Logger.Warn("Failed to store Markdown variant (served from memory)",
+				zap.String("path", variantPath),
+				zap.Error(err))
+		}
+	}
+	return markdownResponse(markdown, htmlResp), nil
+}
+
+// readMarkdownVariant reads a stored variant that is not older than its cache file.
+// Cache file paths have minute resolution, so a re-render can replace the file under the
+// same path and leave an outdated variant behind.
+func readMarkdownVariant(cachePath, variantPath string) ([]byte, bool) {
+	variantInfo, err := os.Stat(variantPath)
+	if err != nil {
+		return nil, false
+	}
+	cacheInfo, err := os.Stat(cachePath)
+	if err != nil || variantInfo.ModTime().Before(cacheInfo.ModTime()) {
+		return nil, false
+	}
+	content, err := os.ReadFile(variantPath)
+	if err != nil || len(content) == 0 {
+		return nil, false
+	}
+	return content, true
+}
+
+func markdownResponse(markdown []byte, htmlResp *cache.CacheResponse) *cache.CacheResponse {
+	return &cache.CacheResponse{
+		Content:     markdown,
+		ContentSize: int64(len(markdown)),
+		CacheAge:    htmlResp.CacheAge,
+	}
+}
@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func newMarkdownRenderContext(accept string, acceptHeader bool) *edgectx.RenderContext {
+	ctx := &fasthttp.RequestCtx{}
+	if accept != "" {
+		ctx.Request.Header.Set("Accept", accept)
+	}
+	renderCtx := edgectx.NewRenderContext("req-1", ctx, zap.NewNop(), 30*time.Second)
+	renderCtx.TargetURL = "https://example.com/page"
+	renderCtx.ResolvedConfig = &config.ResolvedConfig{
+		Render: config.ResolvedRenderConfig{
+			Markdown: config.ResolvedMarkdownConfig{AcceptHeader: acceptHeader, JSONLDSummary: true},
+		},
+	}
+	return renderCtx
+}
+
+func TestPrefersMarkdown(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"text/markdown", true},
+		{"text/markdown, text/html;q=0.9", true},
+		{"text/html, text/markdown;q=0.5", false},
+		{"text/x-markdown;q=0.8, text/html;q=0.8", true},
+		{"TEXT/MARKDOWN", true},
+		{"text/markdown;q=0", false},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", false},
+		{"*/*", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, prefersMarkdown(tt.accept))
+		})
+	}
+}
+
+func TestSelectOutputFormat(t *testing.T) {
+	htmlDim := types.Dimension{ID: 1}
+	markdownDim := types.Dimension{ID: 2, Format: types.OutputFormatMarkdown}
+
+	assert.Equal(t, types.OutputFormatMarkdown, SelectOutputFormat(newMarkdownRenderContext("", false), markdownDim))
+	assert.Equal(t, types.OutputFormatHTML, SelectOutputFormat(newMarkdownRenderContext("text/markdown", false), htmlDim),
+		"Accept is ignored unless negotiation is enabled")
+	assert.Equal(t, types.OutputFormatMarkdown, SelectOutputFormat(newMarkdownRenderContext("text/markdown", true), htmlDim))
+	assert.Equal(t, types.OutputFormatHTML, SelectOutputFormat(newMarkdownRenderContext("text/html", true), htmlDim))
+}
+
+func TestMarkdownRenderedBody(t *testing.T) {
+	page := []byte(`<html><head><title>Page</title></head><body><main><h1>Hello</h1><p>World</p></main></body></html>`)
+
+	renderCtx := newMarkdownRenderContext("", false)
+	assert.Equal(t, page, markdownRenderedBody(renderCtx, page, 200), "HTML requests are unchanged")
+
+	renderCtx.OutputFormat = types.OutputFormatMarkdown
+	assert.Equal(t, "# Hello\n\nWorld\n", string(markdownRenderedBody(renderCtx, page, 200)))
+	assert.True(t, renderCtx.WantsMarkdown())
+
+	assert.Empty(t, markdownRenderedBody(renderCtx, nil, 301))
+	assert.False(t, renderCtx.WantsMarkdown(), "redirects are served as HTML")
+
+	renderCtx.OutputFormat = types.OutputFormatMarkdown
+	empty := []byte(`<html><body><script>app()</script></body></html>`)
+	assert.Equal(t, empty, markdownRenderedBody(renderCtx, empty, 200))
+	assert.False(t, renderCtx.WantsMarkdown(), "pages without content fall back to HTML")
+}
+
+func TestReadMarkdownVariant(t *testing.T) {
+	dir := t.TempDir()
+	cachePath := filepath.Join(dir, "abc_1.html")
+	variantPath := filepath.Join(dir, "abc_1.html.md")
+	now := time.Now()
+
+	require.NoError(t, os.WriteFile(cachePath, []byte("<html></html>"), 0644))
+	_, ok := readMarkdownVariant(cachePath, variantPath)
+	assert.False(t, ok, "missing variant")
+
+	require.NoError(t, os.WriteFile(variantPath, []byte("# Page\n"), 0644))
+	require.NoError(t, os.Chtimes(cachePath, now.Add(-time.Minute), now.Add(-time.Minute)))
+	content, ok := readMarkdownVariant(cachePath, variantPath)
+	require.True(t, ok)
+	assert.Equal(t, "# Page\n", string(content))
+
+	// Cache file rewritten under the same path after the variant was stored
+	require.NoError(t, os.Chtimes(cachePath, now.Add(time.Minute), now.Add(time.Minute)))
+	_, ok = readMarkdownVariant(cachePath, variantPath)
+	assert.False(t, ok, "outdated variant")
+}
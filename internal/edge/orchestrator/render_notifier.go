@@ -0,0 +1,172 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+)
+
+// renderDoneChannelPrefix is the pub/sub channel prefix for render completions ({prefix}{cache_key})
+const renderDoneChannelPrefix = "render:done:"
+
+// RenderOutcome is the result of a render, sent to requests waiting for it
+type RenderOutcome struct {
+	Success   bool   `json:"success"`              // Cache entry was written
+	ErrorType string `json:"error_type,omitempty"` // Failure reason (e.g., "service_failed", "not_cacheable")
+	EgID      string `json:"eg_id"`                // EG that rendered
+}
+
+func renderSucceeded() RenderOutcome {
+	return RenderOutcome{Success: true}
+}
+
+func renderFailed(errorType string) RenderOutcome {
+	return RenderOutcome{ErrorType: errorType}
+}
+
+// RenderNotifier coalesces concurrent requests for the same cache key. Requests on this EG
+// join one in-process flight per key, so only its leader contacts Redis for the render lock.
+// The lock holder publishes the outcome on the key's channel when it releases the lock, and
+// every waiting request on any EG is woken up immediately instead of polling for the cache.
+type RenderNotifier struct {
+	redis  *redis.Client
+	egID   string
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	flights map[string]string                          // cache key -> request ID of the local leader
+	waiters map[string]map[chan RenderOutcome]struct{} // cache key -> waiting requests
+
+	pubsub *goredis.PubSub
+	done   chan struct{}
+}
+
+// NewRenderNotifier creates a RenderNotifier for this EG
+func NewRenderNotifier(redisClient *redis.Client, egID string, logger *zap.Logger) *RenderNotifier {
+	return &RenderNotifier{
+		redis:   redisClient,
+		egID:    egID,
+		logger:  logger,
+		flights: make(map[string]string),
+		waiters: make(map[string]map[chan RenderOutcome]struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to render completions of other EGs
+func (n *RenderNotifier) Start() {
+	n.pubsub = n.redis.PSubscribe(context.Background(), renderDoneChannelPrefix+"*")
+	go n.run()
+	n.logger.Info("Render notifier started")
+}
+
+// Shutdown closes the subscription. Waiting requests fall back to periodic cache checks.
+func (n *RenderNotifier) Shutdown() {
+	if n.pubsub == nil {
+		return
+	}
+	if err := n.pubsub.Close(); err != nil {
+		n.logger.Warn("Failed to close render notification subscription", zap.Error(err))
+	}
+	<-n.done
+	n.logger.Info("Render notifier stopped")
+}
+
+// run delivers published outcomes. The channel reconnects on its own after Redis errors;
+// outcomes published meanwhile are missed and waiters detect them by periodic checks.
+func (n *RenderNotifier) run() {
+	defer close(n.done)
+	for msg := range n.pubsub.Channel() {
+		var outcome RenderOutcome
+		if err := json.Unmarshal([]byte(msg.Payload), &outcome); err != nil {
+			n.logger.Warn("Ignoring malformed render notification",
+				zap.String("channel", msg.Channel),
+				zap.Error(err))
+			continue
+		}
+		if outcome.EgID == n.egID {
+			continue // Delivered locally by Complete
+		}
+		n.deliver(strings.TrimPrefix(msg.Channel, renderDoneChannelPrefix), outcome)
+	}
+}
+
+// join makes requestID the leader of the local flight for key. Returns false if another
+// request on this EG already leads it; the caller then only waits for the outcome.
+func (n *RenderNotifier) join(key, requestID string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, exists := n.flights[key]; exists {
+		return false
+	}
+	n.flights[key] = requestID
+	return true
+}
+
+// leave ends the local flight for key if requestID leads it, without notifying waiters
+func (n *RenderNotifier) leave(key, requestID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.flights[key] == requestID {
+		delete(n.flights, key)
+	}
+}
+
+// wait registers for the next outcome of key. The returned function unregisters.
+func (n *RenderNotifier) wait(key string) (<-chan RenderOutcome, func()) {
+	ch := make(chan RenderOutcome, 1)
+
+	n.mu.Lock()
+	if n.waiters[key] == nil {
+		n.waiters[key] = make(map[chan RenderOutcome]struct{})
+	}
+	n.waiters[key][ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.waiters[key], ch)
+		if len(n.waiters[key]) == 0 {
+			delete(n.waiters, key)
+		}
+	}
+}
+
+// Complete ends the local flight led by requestID and notifies waiters on all EGs
+func (n *RenderNotifier) Complete(key, requestID string, outcome RenderOutcome) {
+	outcome.EgID = n.egID
+	n.leave(key, requestID)
+	n.deliver(key, outcome)
+
+	payload, err := json.Marshal(outcome)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisLockOperationTimeout)
+	defer cancel()
+	if err := n.redis.Publish(ctx, renderDoneChannelPrefix+key, payload); err != nil {
+		n.logger.Warn("Failed to publish render outcome",
+			zap.String("cache_key", key),
+			zap.Error(err))
+	}
+}
+
+// deliver hands outcome to every request currently waiting for key
+func (n *RenderNotifier) deliver(key string, outcome RenderOutcome) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.waiters[key] {
+		select {
+		case ch <- outcome:
+		default: // Already notified
+		}
+	}
+	delete(n.waiters, key)
+}
@@ -0,0 +1,88 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+)
+
+func setupNotifierRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+	return redisClient, mr
+}
+
+func receiveOutcome(t *testing.T, ch <-chan RenderOutcome) RenderOutcome {
+	t.Helper()
+	select {
+	case outcome := <-ch:
+		return outcome
+	case <-time.After(3 * time.Second):
+		require.FailNow(t, "render outcome not delivered")
+		return RenderOutcome{}
+	}
+}
+
+func TestRenderNotifier_LocalFlight(t *testing.T) {
+	redisClient, _ := setupNotifierRedis(t)
+	n := NewRenderNotifier(redisClient, "eg-1", zap.NewNop())
+	key := "cache:1:1:abc"
+
+	assert.True(t, n.join(key, "req-1"))
+	assert.False(t, n.join(key, "req-2"), "second request joins the flight")
+
+	// Only the leader ends the flight
+	n.leave(key, "req-2")
+	assert.False(t, n.join(key, "req-3"))
+
+	waiting, unregister := n.wait(key)
+	defer unregister()
+
+	n.Complete(key, "req-1", renderFailed("service_failed"))
+	outcome := receiveOutcome(t, waiting)
+	assert.False(t, outcome.Success)
+	assert.Equal(t, "service_failed", outcome.ErrorType)
+	assert.Equal(t, "eg-1", outcome.EgID)
+
+	assert.True(t, n.join(key, "req-4"), "flight ended")
+}
+
+func TestRenderNotifier_NotifiesOtherEGs(t *testing.T) {
+	redisClient, mr := setupNotifierRedis(t)
+	renderer := NewRenderNotifier(redisClient, "eg-1", zap.NewNop())
+	waiter := NewRenderNotifier(redisClient, "eg-2", zap.NewNop())
+	waiter.Start()
+	defer waiter.Shutdown()
+	require.Eventually(t, func() bool { return mr.PubSubNumPat() == 1 }, 3*time.Second, 10*time.Millisecond)
+
+	key := "cache:1:1:abc"
+	other, unregisterOther := waiter.wait("cache:1:1:other")
+	defer unregisterOther()
+	waiting, unregister := waiter.wait(key)
+	defer unregister()
+
+	require.True(t, renderer.join(key, "req-1"))
+	renderer.Complete(key, "req-1", renderSucceeded())
+
+	outcome := receiveOutcome(t, waiting)
+	assert.True(t, outcome.Success)
+	assert.Equal(t, "eg-1", outcome.EgID)
+
+	select {
+	case <-other:
+		assert.Fail(t, "waiter of another key notified")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -15,8 +15,10 @@ import (
 	"github.com/edgecomet/engine/internal/edge/bypass"
 	"github.com/edgecomet/engine/internal/edge/cache"
 	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/internal/edge/hotcache"
 	"github.com/edgecomet/engine/internal/edge/metrics"
 	"github.com/edgecomet/engine/internal/edge/rsclient"
+	"github.com/edgecomet/engine/internal/edge/rshealth"
 	"github.com/edgecomet/engine/internal/render/registry"
 	"github.com/edgecomet/engine/pkg/types"
 )
@@ -31,8 +33,8 @@ const (
 	minConcurrentWait           = 5 * time.Second
 	maxConcurrentWait           = 60 * time.Second
 
-	// Poll interval for checking cache during concurrent wait
-	concurrentRenderPollInterval = 200 * time.Millisecond
+	// Interval for checking cache and lock during concurrent wait, in case a render notification is lost
+	concurrentRenderCheckInterval = 1 * time.Second
 
 	// Redis operation timeouts (independent of request context to prevent race conditions)
 	redisTabOperationTimeout   = 2 * time.Second // Tab reservation/release
@@ -50,107 +52,6 @@ const (
 	contentTypeHTML = "text/html"
 )
 
-// selectAndReserveScript atomically selects a healthy render service and reserves an available tab
-const selectAndReserveScript = `
--- Atomically selects a healthy render service and reserves an available tab
--- ARGV[1] = request_id
--- ARGV[2] = strategy ("least_loaded", "most_available", or "round_robin")
--- ARGV[3] = reservation TTL (seconds, typically 2)
-
-local request_id = ARGV[1]
-local strategy = ARGV[2]
-local reservation_ttl = tonumber(ARGV[3])
-
--- 1. Find all render services
-local service_keys = redis.call('KEYS', 'service:render:*')
-if #service_keys == 0 then
-    return {false, 'no_services'}
-end
-
--- 2. Filter healthy services and collect tab availability info
-local candidates = {}
-for _, service_key in ipairs(service_keys) do
-    local service_data = redis.call('GET', service_key)
-    if service_data then
-        local service = cjson.decode(service_data)
-
-        -- Only consider services with available capacity (registry already handles staleness via TTL)
-        -- Check: capacity exists, is positive, and has available slots (load < capacity)
-        if service.capacity and service.capacity > 0 and (service.load or 0) < service.capacity then
-            local service_id = service.id
-            local tabs_key = 'tabs:' .. service_id
-
-            if redis.call('EXISTS', tabs_key) == 1 then
-                local tabs = redis.call('HGETALL', tabs_key)
-                local available_count = 0
-                local first_available = nil
-
-                for i = 1, #tabs, 2 do
-                    local tab_id = tonumber(tabs[i])
-                    local tab_value = tabs[i + 1]
-
-                    if tab_value == '' then
-                        available_count = available_count + 1
-                        if first_available == nil then
-                            first_available = tab_id
-                        end
-                    end
-                end
-
-                if available_count > 0 then
-                    -- Calculate load percentage with nil check
-                    local load = service.load or 0
-                    local load_pct = load / service.capacity
-
-                    table.insert(candidates, {
-                        service_id = service_id,
-                        service = service,
-                        tabs_key = tabs_key,
-                        available_count = available_count,
-                        first_available = first_available,
-                        load_pct = load_pct
-                    })
-                end
-            end
-        end
-    end
-end
-
-if #candidates == 0 then
-    return {false, 'no_capacity'}
-end
-
--- 3. Select best service based on strategy
-local selected = candidates[1]
-
-if strategy == 'least_loaded' then
-    for _, candidate in ipairs(candidates) do
-        if candidate.load_pct < selected.load_pct then
-            selected = candidate
-        end
-    end
-elseif strategy == 'most_available' then
-    for _, candidate in ipairs(candidates) do
-        if candidate.available_count > selected.available_count then
-            selected = candidate
-        end
-    end
-end
-
--- 4. Reserve the first available tab
-local tab_id = selected.first_available
-redis.call('HSET', selected.tabs_key, tostring(tab_id), request_id)
-redis.call('EXPIRE', selected.tabs_key, reservation_ttl)
-
--- 5. Return result: {service_id, tab_id, address, port}
-return {
-    selected.service_id,
-    tostring(tab_id),
-    selected.service.address,
-    tostring(selected.service.port)
-}
-`
-
 // WaitResult represents the outcome of waiting for a concurrent render
 type WaitResult int
 
@@ -158,6 +59,7 @@ const (
 	WaitCacheAvailable WaitResult = iota // Cache became available, request served
 	WaitRequestTimeout                   // Request timeout during wait
 	WaitTimeout                          // Wait timeout exceeded
+	WaitRenderFailed                     // Concurrent render finished without a cache entry
 )
 
 // ResponseSource indicates where the response content came from
@@ -179,7 +81,7 @@ type RenderResult struct {
 
 	// Extended fields for event logging
 	StatusCode   int                // HTTP status code
-	PageSEO      *types.PageSEO     // Full SEO metadata (nil for cache hits; populated for renders and bypass HTML)
+	PageSEO      *types.PageSEO     // Full SEO metadata (stored snapshot for cache hits; nil for entries without one)
 	Metrics      *types.PageMetrics // Page metrics (nil for cache hits)
 	CacheAge     time.Duration      // Cache age (for cache hits)
 	ChromeID     string             // Chrome instance ID (for renders)
@@ -187,6 +89,10 @@ type RenderResult struct {
 	ErrorType    string             // Structured error category (e.g., "soft_timeout", "origin_4xx")
 	ErrorMessage string             // Detailed error description
 	RedirectTo   string             // Redirect target URL (Location header value for 3xx)
+	Attempts     []RenderAttempt    // Render service calls, set only when a render attempt failed
+
+	// Recache only
+	ContentChange *ContentChange // Comparison with the previous cached version (nil if none)
 }
 
 // RenderOrchestrator coordinates rendering requests, service selection, and fallback handling
@@ -204,6 +110,9 @@ type RenderOrchestrator struct {
 	redis            *redis.Client
 	logger           *zap.Logger
 	configManager    configtypes.EGConfigManager
+
+	// Optional render service health tracking (nil = disabled)
+	healthTracker *rshealth.Tracker
 }
 
 // TabReservation contains service and tab info from Lua script
@@ -242,11 +151,12 @@ func NewRenderOrchestrator(
 	redisClient *redis.Client,
 	configManager configtypes.EGConfigManager,
 	shardingManager ShardingManager,
+	renderNotifier *RenderNotifier,
 	logger *zap.Logger,
 ) *RenderOrchestrator {
 	// Create specialized coordinators
 	cacheCoord := NewCacheCoordinator(metadata, fsCache, cacheService, shardingManager, metricsCollector, logger)
-	lockCoord := NewLockCoordinator(metadata, logger)
+	lockCoord := NewLockCoordinator(metadata, renderNotifier, logger)
 	responseWriter := NewResponseWriter()
 
 	return &RenderOrchestrator{
@@ -263,6 +173,16 @@ func NewRenderOrchestrator(
 	}
 }
 
+// SetHotCache enables the in-memory hot cache tier for cache lookups and serving
+func (ro *RenderOrchestrator) SetHotCache(hotCache *hotcache.Cache) {
+	ro.cacheCoord.SetHotCache(hotCache)
+}
+
+// SetHealthTracker enables recording of render outcomes for render service health
+func (ro *RenderOrchestrator) SetHealthTracker(tracker *rshealth.Tracker) {
+	ro.healthTracker = tracker
+}
+
 // ProcessRenderRequest handles the complete render workflow with caching and fallback
 func (ro *RenderOrchestrator) ProcessRenderRequest(renderCtx *edgectx.RenderContext) (*RenderResult, error) {
 	// Use pre-resolved config from renderCtx (resolved in server.go)
@@ -296,11 +216,9 @@ func (ro *RenderOrchestrator) ProcessRenderRequest(renderCtx *edgectx.RenderCont
 	if cached, exists := ro.cacheCoord.LookupCache(renderCtx); exists {
 		// Check if cache is fresh or stale
 		if cached.IsFresh() {
-			// Redirects are metadata-only and accessible via Redis on all EGs
+			// Redirects and deleted-content markers are metadata-only and accessible via Redis on all EGs
 			// Regular content requires file ownership check
-			isRedirect := isRedirectStatusCode(cached.StatusCode)
-
-			if isRedirect || ro.cacheCoord.IsFileLocal(cached) {
+			if isMetadataOnly(cached) || ro.cacheCoord.IsFileLocal(cached) {
 				result, err := ro.serveFromCache(renderCtx, cached)
 				if err == nil {
 					renderCtx.Logger.Info("Early cache hit, served without locking")
@@ -365,6 +283,12 @@ func (ro *RenderOrchestrator) ProcessRenderRequest(renderCtx *edgectx.RenderCont
 				return ro.serveStaleCache(renderCtx, staleCache, "concurrent_render_timeout")
 			}
 			return ro.serveBypass(renderCtx, "concurrent_render_timeout")
+		case WaitRenderFailed:
+			// Try to serve stale cache if available
+			if staleCache != nil {
+				return ro.serveStaleCache(renderCtx, staleCache, "concurrent_render_failed")
+			}
+			return ro.serveBypass(renderCtx, "concurrent_render_failed")
 		default:
 			// Try to serve stale cache if available
 			if staleCache != nil {
@@ -380,12 +304,12 @@ func (ro *RenderOrchestrator) ProcessRenderRequest(renderCtx *edgectx.RenderCont
 
 	// 5. DOUBLE-CHECK CACHE (another request might have rendered while we waited for lock)
 	if cached, exists := ro.cacheCoord.LookupCache(renderCtx); exists && cached.IsFresh() {
-		// Only attempt to serve locally if current EG owns the file
-		if ro.cacheCoord.IsFileLocal(cached) {
+		// Only attempt to serve locally if current EG owns the file (or the entry is metadata-only)
+		if isMetadataOnly(cached) || ro.cacheCoord.IsFileLocal(cached) {
 			result, err := ro.serveFromCache(renderCtx, cached)
 			if err == nil {
 				renderCtx.Logger.Info("Cache appeared while waiting for lock, served without rendering")
-				ro.lockCoord.ReleaseLock(renderCtx)
+				ro.lockCoord.ReleaseLock(renderCtx, renderSucceeded())
 				return result, nil
 			}
 			renderCtx.Logger.Warn("Cache metadata exists but file not accessible, proceeding to render",
@@ -399,8 +323,9 @@ func (ro *RenderOrchestrator) ProcessRenderRequest(renderCtx *edgectx.RenderCont
 	return ro.executeRenderWithExplicitServing(renderCtx, staleCache)
 }
 
-// selectServiceAndReserveTab atomically selects service and reserves tab using Lua script
-func (ro *RenderOrchestrator) selectServiceAndReserveTab(ctx context.Context, requestID string, logger *zap.Logger) (*TabReservation, error) {
+// selectServiceAndReserveTab atomically selects service and reserves tab using Lua script.
+// excluded is a comma-separated list of service IDs that must not be selected (empty for none).
+func (ro *RenderOrchestrator) selectServiceAndReserveTab(ctx context.Context, requestID, excluded string, logger *zap.Logger) (*TabReservation, error) {
 	// Use independent timeout to prevent race condition from request cancellation
 	// This ensures tab reservation always completes or fails atomically
 	redisCtx, cancel := context.WithTimeout(context.Background(), redisTabOperationTimeout)
@@ -412,11 +337,13 @@ func (ro *RenderOrchestrator) selectServiceAndReserveTab(ctx context.Context, re
 	// Execute Lua script to atomically select service and reserve tab
 	result, err := ro.redis.Eval(
 		redisCtx, // ✅ Independent context prevents orphaned reservations
-		selectAndReserveScript,
+		rshealth.SelectAndReserveScript,
 		[]string{}, // No KEYS needed
 		requestID,
-		strategy, // selection strategy from config
-		2,        // reservation TTL (seconds)
+		strategy,                     // selection strategy from config
+		2,                            // reservation TTL (seconds)
+		time.Now().UTC().UnixMilli(), // now, for circuit breaker state
+		excluded,                     // services already tried by this request
 	)
 
 	if err != nil {
@@ -534,7 +461,7 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 	if renderCtx.IsTimedOut() {
 		renderCtx.Logger.Warn("Request timeout before service selection",
 			zap.Duration("time_remaining", renderCtx.TimeRemaining()))
-		ro.lockCoord.ReleaseLock(renderCtx)
+		ro.lockCoord.ReleaseLock(renderCtx, renderFailed("request_timeout"))
 
 		// Try to serve stale cache if available
 		if staleCache != nil {
@@ -545,9 +472,9 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 
 	renderCtx.Logger.Debug("Selecting render service and reserving tab",
 		zap.Duration("time_remaining", renderCtx.TimeRemaining()))
-	reservation, err := ro.selectServiceAndReserveTab(reqCtx, renderCtx.RequestID, renderCtx.Logger)
+	reservation, err := ro.selectServiceAndReserveTab(reqCtx, renderCtx.RequestID, "", renderCtx.Logger)
 	if err != nil || reservation == nil {
-		ro.lockCoord.ReleaseLock(renderCtx)
+		ro.lockCoord.ReleaseLock(renderCtx, renderFailed("no_services"))
 
 		// Try to serve stale cache if available
 		if staleCache != nil {
@@ -562,8 +489,13 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 	// Defer cleanup: EG owns tab lifecycle (allocates and deallocates)
 	// Lock and tab released on ALL exit paths (success, failure, timeout, bypass)
 	// Separate defers for independent panic protection (LIFO: lock released last, tab released first)
-	defer ro.lockCoord.ReleaseLock(renderCtx)
-	defer ro.releaseTabReservation(context.Background(), reservation, renderCtx.RequestID, renderCtx.Logger)
+	// Waiting requests are notified whether the render produced a cache entry
+	outcome := renderFailed("request_timeout")
+	defer func() { ro.lockCoord.ReleaseLock(renderCtx, outcome) }()
+	// Closure: a retry replaces the reservation
+	defer func() {
+		ro.releaseTabReservation(context.Background(), reservation, renderCtx.RequestID, renderCtx.Logger)
+	}()
 
 	// Check timeout before forwarding to render service
 	if renderCtx.IsTimedOut() {
@@ -586,21 +518,94 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 		zap.Duration("time_remaining", renderCtx.TimeRemaining()))
 	renderStart := time.Now().UTC()
 
-	// Perform actual render with tab reservation
-	renderResult, renderErr := ro.performActualRenderWithTab(renderCtx, reservation)
+	// Perform actual render with tab reservation, retrying infrastructure failures on other services
+	// and renders failing the quality gates after a backoff
+	var attempts []RenderAttempt
+	retryPolicy := ro.configManager.GetConfig().Registry.Retry
+	quality := renderCtx.ResolvedConfig.Render.Quality
+	qualityFailures := 0
+	renderResult, renderErr := ro.performCheckedRender(renderCtx, reservation)
+	failedService := ""
+	for renderErr != nil {
+		errorType := renderErrorType(renderErr)
+		failedService = reservation.ServiceID
+		attempts = append(attempts, RenderAttempt{ServiceID: failedService, ErrorType: errorType})
+		excluded := attemptedServices(attempts)
+		if errorType == types.ErrorTypeRenderQuality {
+			qualityFailures++
+			if !shouldRetryQuality(renderCtx, quality, retryPolicy, qualityFailures) {
+				break
+			}
+			// The failed attempt's tab would sit idle for the whole backoff
+			ro.releaseTabReservation(context.Background(), reservation, renderCtx.RequestID, renderCtx.Logger)
+			reservation = nil
+			if !waitRetryBackoff(reqCtx, quality.GetRetryBackoff(qualityFailures)) {
+				break
+			}
+			// Broken pages usually come from the origin (crashed app, failing API), any service may retry
+			excluded = ""
+		} else if !shouldRetryRender(renderCtx, retryPolicy, errorType, len(attempts)) {
+			break
+		}
+
+		next, err := ro.selectServiceAndReserveTab(reqCtx, renderCtx.RequestID, excluded, renderCtx.Logger)
+		if err != nil {
+			renderCtx.Logger.Info("No other render service available for retry",
+				zap.String("rs", failedService),
+				zap.String("error_type", errorType),
+				zap.Error(err))
+			break
+		}
+		ro.releaseTabReservation(context.Background(), reservation, renderCtx.RequestID, renderCtx.Logger)
+		ro.metricsCollector.RecordRenderRetry(renderCtx.Host.Domain, errorType)
+
+		renderCtx.Logger.Info("Retrying render",
+			zap.String("failed_rs", failedService),
+			zap.String("error_type", errorType),
+			zap.String("rs", next.ServiceID),
+			zap.Int("tab_id", next.TabID),
+			zap.Int("attempt", len(attempts)+1),
+			zap.Duration("time_remaining", renderCtx.TimeRemaining()))
+		reservation = next
+		renderResult, renderErr = ro.performCheckedRender(renderCtx, reservation)
+	}
 	if renderErr != nil {
 		renderCtx.Logger.Warn("Render service failed",
-			zap.String("rs", reservation.ServiceID),
+			zap.String("rs", failedService),
+			zap.Int("attempts", len(attempts)),
 			zap.Error(renderErr))
 		// Lock and tab will be released by defer
+		reason := "service_failed"
+		if renderErrorType(renderErr) == types.ErrorTypeRenderQuality {
+			// The previous cache entry is kept and served stale
+			reason = types.ErrorTypeRenderQuality
+		}
+		outcome = renderFailed(reason)
 
 		// Try to serve stale cache if available
+		var result *RenderResult
+		var err error
 		if staleCache != nil {
-			return ro.serveStaleCache(renderCtx, staleCache, "service_failed")
+			result, err = ro.serveStaleCache(renderCtx, staleCache, reason)
+		} else {
+			result, err = ro.serveBypass(renderCtx, reason)
+		}
+		if result != nil {
+			result.Attempts = attempts
+			if reason == types.ErrorTypeRenderQuality {
+				result.ErrorType = types.ErrorTypeRenderQuality
+				result.ErrorMessage = renderErr.Error()
+			}
 		}
-		return ro.serveBypass(renderCtx, "service_failed")
+		return result, err
+	}
+	if len(attempts) > 0 {
+		attempts = append(attempts, RenderAttempt{ServiceID: reservation.ServiceID})
 	}
 
+	// Post-process rendered HTML before caching and serving
+	ApplyHTMLTransforms(renderResult, renderCtx.ResolvedConfig.Render.Transforms, renderCtx.TargetURL, renderCtx.Logger)
+
 	// Extract values for clarity
 	html := renderResult.HTML
 	statusCode := renderResult.StatusCode
@@ -613,11 +618,19 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 	// Record status code metrics
 	ro.metricsCollector.RecordStatusCodeResponse(renderCtx.Host.Domain, renderCtx.Dimension, statusCode)
 
+	// Record structured data issues of the rendered page
+	if renderResult.PageSEO != nil {
+		for _, issue := range renderResult.PageSEO.StructuredDataIssues {
+			ro.metricsCollector.RecordStructuredDataIssue(renderCtx.Host.Domain, issue.Type, issue.Issue)
+		}
+	}
+
 	// Check for 5xx responses - serve stale cache instead of caching errors
 	if statusCode >= 500 && statusCode < 600 {
 		renderCtx.Logger.Warn("Render returned 5xx status code",
 			zap.Int("status_code", statusCode))
 		// Lock and tab will be released by defer
+		outcome = renderFailed("render_5xx_error")
 
 		// Try to serve stale cache if available
 		if staleCache != nil {
@@ -636,8 +649,12 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 		if err := ro.cacheCoord.SaveRenderCache(renderCtx, renderResult); err != nil {
 			renderCtx.Logger.Error("Failed to save render to cache", zap.Error(err))
 			// Continue - we can still serve the response to client
+			outcome = renderFailed("cache_write_failed")
+		} else {
+			outcome = renderSucceeded()
 		}
 	} else {
+		outcome = renderFailed("not_cacheable")
 		renderCtx.Logger.Info("Skipping cache for status code",
 			zap.Int("status_code", statusCode),
 			zap.Ints("cacheable_codes", cacheableStatusCodes),
@@ -662,6 +679,7 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 
 	// Serve the rendered content with actual status code
 	startTime := time.Now().UTC()
+	html = markdownRenderedBody(renderCtx, html, statusCode)
 	if err := ro.responseWriter.WriteRenderedResponse(renderCtx, html, statusCode, redirectLocation, reservation.ServiceID, renderResult.Headers); err != nil {
 		return nil, err
 	}
@@ -700,6 +718,7 @@ func (ro *RenderOrchestrator) executeRenderWithExplicitServing(renderCtx *edgect
 		ErrorType:    errorType,
 		ErrorMessage: errorMessage,
 		RedirectTo:   redirectTo,
+		Attempts:     attempts,
 	}
 
 	// Lock and tab will be released by defer AFTER cache write and serving complete
@@ -732,13 +751,21 @@ func (ro *RenderOrchestrator) performActualRenderWithTab(renderCtx *edgectx.Rend
 	defer cancel()
 
 	resp, err := ro.rsClient.CallRenderService(ctx, serviceURL, req)
+	if outcome, ok := rshealth.OutcomeFromResponse(ctx, resp, err); ok {
+		ro.healthTracker.Record(context.Background(), reservation.ServiceID, outcome)
+	}
 	if err != nil {
 		renderCtx.Logger.Error("Render service call failed",
 			zap.String("rs", reservation.ServiceID),
 			zap.Int("tab_id", reservation.TabID),
 			zap.String("service_url", serviceURL),
 			zap.Error(err))
-		return nil, fmt.Errorf("render service call failed: %w", err)
+		errorType := types.ErrorTypeConnectionFailed
+		if ctx.Err() != nil {
+			// Request deadline, not the render service
+			errorType = ""
+		}
+		return nil, &renderError{errorType: errorType, err: fmt.Errorf("render service call failed: %w", err)}
 	}
 
 	// Check response success
@@ -746,8 +773,9 @@ func (ro *RenderOrchestrator) performActualRenderWithTab(renderCtx *edgectx.Rend
 		renderCtx.Logger.Warn("Render service returned failure",
 			zap.String("rs", reservation.ServiceID),
 			zap.Int("tab_id", reservation.TabID),
+			zap.String("error_type", resp.ErrorType),
 			zap.String("error", resp.Error))
-		return nil, fmt.Errorf("render failed: %s", resp.Error)
+		return nil, &renderError{errorType: resp.ErrorType, err: fmt.Errorf("render failed: %s", resp.Error)}
 	}
 
 	// Check if status code was captured (0 means failed to capture)
@@ -757,12 +785,12 @@ func (ro *RenderOrchestrator) performActualRenderWithTab(renderCtx *edgectx.Rend
 			zap.String("rs", reservation.ServiceID),
 			zap.Int("tab_id", reservation.TabID),
 			zap.String("url", renderCtx.TargetURL))
-		return nil, fmt.Errorf("status code not captured")
+		return nil, &renderError{errorType: types.ErrorTypeStatusCaptureFailed, err: fmt.Errorf("status code not captured")}
 	}
 
 	// Validate HTML content (allow empty for redirects)
 	if len(resp.HTML) == 0 && (statusCode < 300 || statusCode >= 400) {
-		return nil, fmt.Errorf("render service returned empty HTML")
+		return nil, &renderError{errorType: types.ErrorTypeEmptyResponse, err: fmt.Errorf("render service returned empty HTML")}
 	}
 
 	renderCtx.Logger.Info("Render service returned HTML successfully",
@@ -799,9 +827,27 @@ func (ro *RenderOrchestrator) serveFromCache(renderCtx *edgectx.RenderContext, c
 		source = ServedFromBypassCache
 	}
 
-	isRedirect := isRedirectStatusCode(cacheEntry.StatusCode)
+	if cacheEntry.IsDeleted() {
+		// Serve deleted-content marker (404/410) from metadata only (no file read)
+		renderCtx.Logger.Debug("Serving deleted-content marker from cache metadata",
+			zap.Int("status_code", cacheEntry.StatusCode))
+
+		if err := ro.responseWriter.WriteCachedDeletedResponse(renderCtx, cacheEntry); err != nil {
+			return nil, err
+		}
+		renderCtx.OutputFormat = types.OutputFormatHTML
 
-	if isRedirect {
+		return &RenderResult{
+			Source:      source,
+			Duration:    time.Since(startTime),
+			BytesServed: 0, // No body content
+			StatusCode:  cacheEntry.StatusCode,
+			CacheAge:    time.Since(cacheEntry.CreatedAt),
+			PageSEO:     pageSEOFromCacheMetadata(cacheEntry),
+		}, nil
+	}
+
+	if isMetadataOnly(cacheEntry) {
 		// Serve redirect from metadata only (no file read)
 		location := ""
 		if locations, ok := getHeaderCaseInsensitive(cacheEntry.Headers, "Location"); ok && len(locations) > 0 {
@@ -815,6 +861,7 @@ func (ro *RenderOrchestrator) serveFromCache(renderCtx *edgectx.RenderContext, c
 		if err := ro.responseWriter.WriteCachedRedirectResponse(renderCtx, cacheEntry); err != nil {
 			return nil, err
 		}
+		renderCtx.OutputFormat = types.OutputFormatHTML
 
 		return &RenderResult{
 			Source:      source,
@@ -840,6 +887,7 @@ func (ro *RenderOrchestrator) serveFromCache(renderCtx *edgectx.RenderContext, c
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to prepare cache file: %w", err)
 	}
+	cacheResp = ro.markdownCacheResponse(renderCtx, cacheEntry, cacheResp)
 
 	if err := ro.responseWriter.WriteCacheResponse(renderCtx, cacheEntry, cacheResp); err != nil {
 		renderCtx.Logger.Error("Failed to serve cache file to client",
@@ -861,9 +909,14 @@ func (ro *RenderOrchestrator) serveFromCache(renderCtx *edgectx.RenderContext, c
 	}, nil
 }
 
-// pageSEOFromCacheMetadata creates a minimal PageSEO from cache metadata.
-// Only Title and IndexStatus are available in Redis cache metadata.
+// pageSEOFromCacheMetadata returns the PageSEO snapshot stored with a cache entry.
+// Entries cached before snapshots were stored only provide Title and IndexStatus.
 func pageSEOFromCacheMetadata(meta *cache.CacheMetadata) *types.PageSEO {
+	if meta.SEO != nil {
+		seo := *meta.SEO
+		seo.PageMinHash = meta.MinHash
+		return &seo
+	}
 	if meta.Title == "" && meta.IndexStatus == 0 {
 		return nil
 	}
@@ -931,6 +984,7 @@ func (ro *RenderOrchestrator) tryPullFromRemoteSmartly(
 		if isBypassCache {
 			err = ro.responseWriter.WriteBypassCacheResponse(renderCtx, metadata, cacheResp)
 		} else {
+			cacheResp = ro.markdownCacheResponse(renderCtx, metadata, cacheResp)
 			err = ro.responseWriter.WriteCacheResponse(renderCtx, metadata, cacheResp)
 		}
 
@@ -951,7 +1005,7 @@ func (ro *RenderOrchestrator) tryPullFromRemoteSmartly(
 		return &RenderResult{
 			Source:      source,
 			Duration:    time.Since(startTime),
-			BytesServed: int64(len(content)),
+			BytesServed: int64(len(cacheResp.Content)),
 			StatusCode:  metadata.StatusCode,
 			CacheAge:    time.Since(metadata.CreatedAt),
 			PageSEO:     pageSEOFromCacheMetadata(metadata),
@@ -1011,6 +1065,7 @@ func (ro *RenderOrchestrator) tryPullFromRemoteSmartly(
 		if isBypassCache {
 			err = ro.responseWriter.WriteBypassCacheResponse(renderCtx, metadata, cacheResp)
 		} else {
+			cacheResp = ro.markdownCacheResponse(renderCtx, metadata, cacheResp)
 			err = ro.responseWriter.WriteCacheResponse(renderCtx, metadata, cacheResp)
 		}
 
@@ -1031,7 +1086,7 @@ func (ro *RenderOrchestrator) tryPullFromRemoteSmartly(
 		return &RenderResult{
 			Source:      source,
 			Duration:    time.Since(startTime),
-			BytesServed: int64(len(content)),
+			BytesServed: int64(len(cacheResp.Content)),
 			StatusCode:  metadata.StatusCode,
 			CacheAge:    time.Since(metadata.CreatedAt),
 			PageSEO:     pageSEOFromCacheMetadata(metadata),
@@ -1060,8 +1115,8 @@ func (ro *RenderOrchestrator) handleCacheAvailableAfterWait(
 		return ro.serveBypass(renderCtx, "cache_stale_after_wait")
 	}
 
-	// Try local cache first (fast path) - only if current EG owns the file
-	if ro.cacheCoord.IsFileLocal(cached) {
+	// Try local cache first (fast path) - only if current EG owns the file (or the entry is metadata-only)
+	if isMetadataOnly(cached) || ro.cacheCoord.IsFileLocal(cached) {
 		result, err := ro.serveFromCache(renderCtx, cached)
 		if err == nil {
 			renderCtx.Logger.Info("Served from local cache after lock wait")
@@ -1117,21 +1172,37 @@ func (ro *RenderOrchestrator) serveBypass(renderCtx *edgectx.RenderContext, reas
 	// Record bypass metrics
 	ro.metricsCollector.RecordBypass(renderCtx.Host.Domain, reason)
 
+	// Origin responses are served as fetched, the Markdown variant exists only for rendered pages
+	if renderCtx.WantsMarkdown() {
+		renderCtx.OutputFormat = types.OutputFormatHTML
+	}
+
 	var staleBypassCache *cache.CacheMetadata
 
+	cached, exists := ro.cacheCoord.LookupCache(renderCtx)
+
+	// 1. DELETED-CONTENT MARKERS from origin webhooks apply whether or not bypass caching is enabled
+	if exists && cached.IsDeleted() && cached.IsFresh() {
+		result, err := ro.serveFromCache(renderCtx, cached)
+		if err == nil {
+			renderCtx.Logger.Info("Deleted-content marker hit, not fetching from origin")
+			return result, nil
+		}
+		renderCtx.Logger.Warn("Failed to serve deleted-content marker, fetching from origin",
+			zap.Error(err))
+	}
+
 	// Check if bypass caching is enabled
 	if renderCtx.ResolvedConfig.Bypass.Cache.Enabled {
-		// 1. CHECK LOCAL CACHE FIRST for bypass entries
-		if cached, exists := ro.cacheCoord.LookupCache(renderCtx); exists {
+		// 2. CHECK LOCAL CACHE FIRST for bypass entries
+		if exists {
 			// Verify it's a bypass cache entry (not render)
 			if cached.Source == cache.SourceBypass {
 				// Check if cache is fresh
 				if cached.IsFresh() {
 					// Redirects are metadata-only and accessible via Redis on all EGs
 					// Regular content requires file ownership check
-					isRedirect := isRedirectStatusCode(cached.StatusCode)
-
-					if isRedirect || ro.cacheCoord.IsFileLocal(cached) {
+					if isMetadataOnly(cached) || ro.cacheCoord.IsFileLocal(cached) {
 						result, err := ro.serveFromCache(renderCtx, cached)
 						if err == nil {
 							renderCtx.Logger.Info("Bypass cache hit (local), served from cache")
@@ -1142,7 +1213,7 @@ func (ro *RenderOrchestrator) serveBypass(renderCtx *edgectx.RenderContext, reas
 							zap.Error(err))
 					}
 
-					// 1.5. TRY PULL FROM REMOTE EGs for bypass cache (if not local and sharding enabled)
+					// 2.5. TRY PULL FROM REMOTE EGs for bypass cache (if not local and sharding enabled)
 					if !cached.IsEmpty() {
 						// Use shared smart pull logic (handles bypass cache via metadata.Source)
 						if result, pulled := ro.tryPullFromRemoteSmartly(renderCtx, cached, false); pulled {
@@ -1159,7 +1230,7 @@ func (ro *RenderOrchestrator) serveBypass(renderCtx *edgectx.RenderContext, reas
 		}
 	}
 
-	// 2. FETCH FROM ORIGIN (cache miss or caching disabled)
+	// 3. FETCH FROM ORIGIN (cache miss or caching disabled)
 	bypassResp, err := ro.bypassSvc.FetchContent(renderCtx.TargetURL, renderCtx.ClientHeaders, renderCtx.Logger)
 	if err != nil {
 		if staleBypassCache != nil {
@@ -1301,7 +1372,7 @@ func (ro *RenderOrchestrator) tryServeStaleFromCache(
 		zap.Duration("cache_age", time.Since(staleCache.CreatedAt)),
 		zap.Duration("stale_age", staleCache.StaleAge()))
 
-	if isRedirectStatusCode(staleCache.StatusCode) {
+	if isMetadataOnly(staleCache) {
 		result, err := ro.serveFromCache(renderCtx, staleCache)
 		if err == nil {
 			ro.metricsCollector.RecordStaleServed(renderCtx.Host.Domain, renderCtx.Dimension, source)
@@ -1367,7 +1438,7 @@ func (ro *RenderOrchestrator) RenderWithHAR(ctx context.Context, req *types.Rend
 		zap.String("host", host.Domain))
 
 	// Reserve tab atomically
-	reservation, err := ro.selectServiceAndReserveTab(ctx, req.RequestID, logger)
+	reservation, err := ro.selectServiceAndReserveTab(ctx, req.RequestID, "", logger)
 	if err != nil {
 		logger.Error("Failed to reserve render tab", zap.Error(err))
 		return nil, fmt.Errorf("no available render capacity: %w", err)
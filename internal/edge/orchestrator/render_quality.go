@@ -0,0 +1,134 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// Render quality checks, reported in logs and metrics
+const (
+	QualityCheckConsoleErrors    = "console_errors"
+	QualityCheckMinWordCount     = "min_word_count"
+	QualityCheckRequiredSelector = "required_selector"
+	QualityCheckForbiddenText    = "forbidden_text"
+)
+
+// QualityFailure is a quality gate a rendered page failed
+type QualityFailure struct {
+	Check  string // QualityCheck* constant
+	Detail string // Counts, missing selector or matched text
+}
+
+func (f *QualityFailure) Error() string {
+	return fmt.Sprintf("render quality check %s failed: %s", f.Check, f.Detail)
+}
+
+// CheckRenderQuality runs the quality gates on a rendered page and returns the first failed
+// check, or nil if the page passes. Only 200 responses are checked: redirects and error
+// pages are handled by status code.
+func CheckRenderQuality(result *RenderServiceResult, cfg *types.RenderQualityConfig) *QualityFailure {
+	if result == nil || cfg == nil || result.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	if cfg.MaxConsoleErrors != nil {
+		consoleErrors := 0
+		for _, msg := range result.Metrics.ConsoleMessages {
+			if msg.Type == types.ConsoleTypeError {
+				consoleErrors++
+			}
+		}
+		if consoleErrors > *cfg.MaxConsoleErrors {
+			return &QualityFailure{
+				Check:  QualityCheckConsoleErrors,
+				Detail: fmt.Sprintf("%d console errors, max %d", consoleErrors, *cfg.MaxConsoleErrors),
+			}
+		}
+	}
+
+	// Word count comes from the SEO metadata extracted by the render service
+	if cfg.MinWordCount > 0 && result.PageSEO != nil && result.PageSEO.WordCount < cfg.MinWordCount {
+		return &QualityFailure{
+			Check:  QualityCheckMinWordCount,
+			Detail: fmt.Sprintf("%d words, min %d", result.PageSEO.WordCount, cfg.MinWordCount),
+		}
+	}
+
+	if len(cfg.RequiredSelectors) == 0 && len(cfg.ForbiddenText) == 0 {
+		return nil
+	}
+	doc, err := htmlprocessor.ParseWithDOM(result.HTML)
+	if err != nil {
+		return nil
+	}
+
+	for _, required := range cfg.RequiredSelectors {
+		// Selectors are validated at config load
+		selector, err := htmlprocessor.CompileSelector(required)
+		if err != nil {
+			continue
+		}
+		if !doc.HasElement(selector) {
+			return &QualityFailure{Check: QualityCheckRequiredSelector, Detail: fmt.Sprintf("no element matches %q", required)}
+		}
+	}
+
+	for _, forbidden := range cfg.ForbiddenText {
+		if doc.ContainsText(forbidden) {
+			return &QualityFailure{Check: QualityCheckForbiddenText, Detail: fmt.Sprintf("page contains %q", forbidden)}
+		}
+	}
+
+	return nil
+}
+
+// shouldRetryQuality reports whether a render that failed the quality gates is retried:
+// attempts are left and the request deadline leaves room for the backoff and another render
+func shouldRetryQuality(renderCtx *edgectx.RenderContext, quality *types.RenderQualityConfig, policy *configtypes.RSRetryConfig, failures int) bool {
+	if failures >= quality.GetMaxAttempts() {
+		return false
+	}
+	return renderCtx.TimeRemaining() >= quality.GetRetryBackoff(failures)+policy.GetMinRemaining()
+}
+
+// waitRetryBackoff waits before a retry. Returns false if ctx ends first.
+func waitRetryBackoff(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// performCheckedRender renders on the reserved tab and applies the resolved quality gates.
+// A page failing a gate is returned as a render_quality error so it is never cached.
+func (ro *RenderOrchestrator) performCheckedRender(renderCtx *edgectx.RenderContext, reservation *TabReservation) (*RenderServiceResult, error) {
+	result, err := ro.performActualRenderWithTab(renderCtx, reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	if failure := CheckRenderQuality(result, renderCtx.ResolvedConfig.Render.Quality); failure != nil {
+		ro.metricsCollector.RecordRenderQualityFailure(renderCtx.Host.Domain, failure.Check)
+		renderCtx.Logger.Warn("Rendered page failed quality check",
+			zap.String("rs", reservation.ServiceID),
+			zap.String("check", failure.Check),
+			zap.String("detail", failure.Detail),
+			zap.String("url", renderCtx.TargetURL))
+		return nil, &renderError{errorType: types.ErrorTypeRenderQuality, err: failure}
+	}
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestCheckRenderQuality(t *testing.T) {
+	page := func() *RenderServiceResult {
+		return &RenderServiceResult{
+			HTML:       []byte(`<html><body><div id="app"><main>Product details</main></div></body></html>`),
+			StatusCode: 200,
+			PageSEO:    &types.PageSEO{WordCount: 250},
+			Metrics: types.PageMetrics{ConsoleMessages: []types.ConsoleError{
+				{Type: types.ConsoleTypeWarning, Message: "deprecated API"},
+				{Type: types.ConsoleTypeError, Message: "analytics blocked"},
+			}},
+		}
+	}
+	maxOne := 1
+	maxZero := 0
+
+	tests := []struct {
+		name   string
+		modify func(result *RenderServiceResult)
+		cfg    *types.RenderQualityConfig
+		check  string
+	}{
+		{"no gates", nil, nil, ""},
+		{"passing page", nil, &types.RenderQualityConfig{
+			MinWordCount:      100,
+			RequiredSelectors: []string{"#app main"},
+			ForbiddenText:     []string{"Something went wrong"},
+			MaxConsoleErrors:  &maxOne,
+		}, ""},
+		{"console errors", nil, &types.RenderQualityConfig{MaxConsoleErrors: &maxZero}, QualityCheckConsoleErrors},
+		{"low word count", func(r *RenderServiceResult) { r.PageSEO.WordCount = 12 }, &types.RenderQualityConfig{MinWordCount: 100}, QualityCheckMinWordCount},
+		{
+			"skeleton page",
+			func(r *RenderServiceResult) { r.HTML = []byte(`<html><body><div id="app"></div></body></html>`) },
+			&types.RenderQualityConfig{RequiredSelectors: []string{"#app", "#app main"}},
+			QualityCheckRequiredSelector,
+		},
+		{
+			"error banner",
+			func(r *RenderServiceResult) {
+				r.HTML = []byte(`<html><body><div id="app"><p>Something went wrong.</p></div></body></html>`)
+			},
+			&types.RenderQualityConfig{ForbiddenText: []string{"something went wrong"}},
+			QualityCheckForbiddenText,
+		},
+		{
+			"non-200 pages are not checked",
+			func(r *RenderServiceResult) { r.StatusCode = 404; r.PageSEO.WordCount = 0 },
+			&types.RenderQualityConfig{MinWordCount: 100},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := page()
+			if tt.modify != nil {
+				tt.modify(result)
+			}
+			failure := CheckRenderQuality(result, tt.cfg)
+			if tt.check == "" {
+				assert.Nil(t, failure)
+				return
+			}
+			require.NotNil(t, failure)
+			assert.Equal(t, tt.check, failure.Check)
+		})
+	}
+}
+
+func TestShouldRetryQuality(t *testing.T) {
+	renderCtx := edgectx.NewRenderContext("req-1", &fasthttp.RequestCtx{}, zap.NewNop(), 30*time.Second)
+
+	assert.True(t, shouldRetryQuality(renderCtx, nil, nil, 1), "retried once by default")
+	assert.False(t, shouldRetryQuality(renderCtx, nil, nil, 2), "max attempts reached")
+	assert.True(t, shouldRetryQuality(renderCtx, &types.RenderQualityConfig{MaxAttempts: 3}, nil, 2))
+	assert.False(t, shouldRetryQuality(renderCtx, &types.RenderQualityConfig{MaxAttempts: 1}, nil, 1), "retries disabled")
+
+	// Backoff and another render must fit in the request deadline
+	longBackoff := &types.RenderQualityConfig{RetryBackoff: types.Duration(28 * time.Second)}
+	assert.False(t, shouldRetryQuality(renderCtx, longBackoff, nil, 1))
+}
+
+func TestRenderQualityConfig_GetRetryBackoff(t *testing.T) {
+	var defaults *types.RenderQualityConfig
+	assert.Equal(t, time.Second, defaults.GetRetryBackoff(1))
+	assert.Equal(t, 2*time.Second, defaults.GetRetryBackoff(2), "doubled for each further retry")
+
+	cfg := &types.RenderQualityConfig{RetryBackoff: types.Duration(300 * time.Millisecond)}
+	assert.Equal(t, 1200*time.Millisecond, cfg.GetRetryBackoff(3))
+}
+
+func TestWaitRetryBackoff(t *testing.T) {
+	assert.True(t, waitRetryBackoff(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, waitRetryBackoff(ctx, time.Minute), "request ended")
+}
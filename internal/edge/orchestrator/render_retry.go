@@ -0,0 +1,58 @@
+package orchestrator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+)
+
+// RenderAttempt is one render service call made for a request
+type RenderAttempt struct {
+	ServiceID string // Render service that handled the attempt
+	ErrorType string // Structured error category of a failed attempt (empty on success)
+}
+
+// renderError is a failed render attempt carrying the structured error category used
+// by the retry policy
+type renderError struct {
+	errorType string
+	err       error
+}
+
+func (e *renderError) Error() string {
+	return e.err.Error()
+}
+
+func (e *renderError) Unwrap() error {
+	return e.err
+}
+
+// renderErrorType returns the error category of a failed render attempt (empty if unknown)
+func renderErrorType(err error) string {
+	var re *renderError
+	if errors.As(err, &re) {
+		return re.errorType
+	}
+	return ""
+}
+
+// shouldRetryRender reports whether a failed attempt is retried on another render service:
+// the error type is retried by policy, attempts are left and enough of the request deadline
+// remains for another render
+func shouldRetryRender(renderCtx *edgectx.RenderContext, policy *configtypes.RSRetryConfig, errorType string, attempts int) bool {
+	if !policy.ShouldRetry(errorType) || attempts >= policy.GetMaxAttempts() {
+		return false
+	}
+	return renderCtx.TimeRemaining() >= policy.GetMinRemaining()
+}
+
+// attemptedServices returns the comma-separated IDs of services already tried, excluded from selection
+func attemptedServices(attempts []RenderAttempt) string {
+	ids := make([]string, 0, len(attempts))
+	for _, attempt := range attempts {
+		ids = append(ids, attempt.ServiceID)
+	}
+	return strings.Join(ids, ",")
+}
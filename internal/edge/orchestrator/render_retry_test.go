@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/edge/edgectx"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestRenderErrorType(t *testing.T) {
+	err := &renderError{errorType: types.ErrorTypeChromeCrash, err: errors.New("render failed: chrome crashed")}
+	assert.Equal(t, types.ErrorTypeChromeCrash, renderErrorType(err))
+	assert.Equal(t, types.ErrorTypeChromeCrash, renderErrorType(fmt.Errorf("wrapped: %w", err)))
+	assert.Equal(t, "render failed: chrome crashed", err.Error())
+	assert.Empty(t, renderErrorType(errors.New("dimension not found")))
+}
+
+func TestShouldRetryRender(t *testing.T) {
+	renderCtx := edgectx.NewRenderContext("req-1", &fasthttp.RequestCtx{}, zap.NewNop(), 30*time.Second)
+	policy := &configtypes.RSRetryConfig{Enabled: true}
+
+	assert.True(t, shouldRetryRender(renderCtx, policy, types.ErrorTypeChromeCrash, 1))
+	assert.True(t, shouldRetryRender(renderCtx, policy, types.ErrorTypeConnectionFailed, 1))
+	assert.False(t, shouldRetryRender(renderCtx, policy, types.ErrorTypeChromeCrash, 2), "max attempts reached")
+	assert.False(t, shouldRetryRender(renderCtx, policy, types.ErrorTypeSoftTimeout, 1), "not retried by default")
+	assert.False(t, shouldRetryRender(renderCtx, policy, "", 1), "request deadline")
+	assert.False(t, shouldRetryRender(renderCtx, nil, types.ErrorTypeChromeCrash, 1), "disabled")
+
+	// Not enough of the request deadline left
+	shortCtx := edgectx.NewRenderContext("req-2", &fasthttp.RequestCtx{}, zap.NewNop(), time.Second)
+	assert.False(t, shouldRetryRender(shortCtx, policy, types.ErrorTypeChromeCrash, 1))
+}
+
+func TestAttemptedServices(t *testing.T) {
+	assert.Equal(t, "rs-1,rs-2", attemptedServices([]RenderAttempt{
+		{ServiceID: "rs-1", ErrorType: types.ErrorTypeChromeCrash},
+		{ServiceID: "rs-2", ErrorType: types.ErrorTypeHardTimeout},
+	}))
+}
@@ -54,7 +54,7 @@ func (rw *ResponseWriter) WriteRenderedResponse(renderCtx *edgectx.RenderContext
 	}
 
 	// Set response headers
-	renderCtx.HTTPCtx.Response.Header.Set("Content-Type", "text/html; charset=utf-8")
+	renderCtx.HTTPCtx.Response.Header.Set("Content-Type", renderedContentType(renderCtx))
 	renderCtx.HTTPCtx.Response.Header.Set("X-Render-Source", "rendered")
 	renderCtx.HTTPCtx.Response.Header.Set("X-Render-Service", serviceID)
 	renderCtx.HTTPCtx.Response.Header.Set("X-Render-Cache", "new")
@@ -144,8 +144,8 @@ func (rw *ResponseWriter) WriteCacheResponse(renderCtx *edgectx.RenderContext, c
 		}
 		renderCtx.HTTPCtx.Response.Header.Set("Content-Type", contentType)
 	} else {
-		// Render cache: always text/html
-		renderCtx.HTTPCtx.Response.Header.Set("Content-Type", "text/html; charset=utf-8")
+		// Render cache: HTML or its Markdown variant
+		renderCtx.HTTPCtx.Response.Header.Set("Content-Type", renderedContentType(renderCtx))
 	}
 
 	// Set source-specific X-Render-Source header
@@ -234,14 +234,31 @@ func (rw *ResponseWriter) WriteCachedRedirectResponse(renderCtx *edgectx.RenderC
 		zap.String("location", location),
 		zap.String("source", cacheEntry.Source))
 
-	// Set redirect status code
-	renderCtx.HTTPCtx.Response.SetStatusCode(cacheEntry.StatusCode)
+	rw.writeMetadataOnlyResponse(renderCtx, cacheEntry)
 
 	// Set Location header from metadata
 	if location != "" {
 		renderCtx.HTTPCtx.Response.Header.Set("Location", location)
 	}
 
+	return nil
+}
+
+// WriteCachedDeletedResponse writes a deleted-content marker (404/410 from an origin
+// webhook) from cache metadata. The marker has no cache file, so the body is empty.
+func (rw *ResponseWriter) WriteCachedDeletedResponse(renderCtx *edgectx.RenderContext, cacheEntry *cache.CacheMetadata) error {
+	renderCtx.Logger.Debug("Serving deleted-content marker from metadata",
+		zap.Int("status_code", cacheEntry.StatusCode))
+
+	rw.writeMetadataOnlyResponse(renderCtx, cacheEntry)
+	return nil
+}
+
+// writeMetadataOnlyResponse writes the status, cached headers and cache headers of a
+// metadata-only entry with an empty body. Location is left to the caller.
+func (rw *ResponseWriter) writeMetadataOnlyResponse(renderCtx *edgectx.RenderContext, cacheEntry *cache.CacheMetadata) {
+	renderCtx.HTTPCtx.Response.SetStatusCode(cacheEntry.StatusCode)
+
 	// Serve other headers from cache (re-filter against current config for security)
 	filteredHeaders := FilterHeaders(cacheEntry.Headers, renderCtx.ResolvedConfig.SafeResponseHeaders, cacheEntry.StatusCode, false)
 	for name, values := range filteredHeaders {
@@ -283,13 +300,11 @@ func (rw *ResponseWriter) WriteCachedRedirectResponse(renderCtx *edgectx.RenderC
 		renderCtx.HTTPCtx.Response.Header.Set("X-Matched-Rule", renderCtx.ResolvedConfig.MatchedRuleID)
 	}
 
-	// No body for redirects
+	// No body for metadata-only entries
 	renderCtx.HTTPCtx.Response.SetBodyString("")
 
 	// Close connection
 	renderCtx.HTTPCtx.Response.SetConnectionClose()
-
-	return nil
 }
 
 // WriteStatusResponse writes a status action response (3xx, 4xx, 5xx)
@@ -347,6 +362,14 @@ func (rw *ResponseWriter) WriteStatusResponse(renderCtx *edgectx.RenderContext,
 	return nil
 }
 
+// renderedContentType returns the Content-Type of rendered content in the request's output format
+func renderedContentType(renderCtx *edgectx.RenderContext) string {
+	if renderCtx.WantsMarkdown() {
+		return types.MarkdownContentType
+	}
+	return "text/html; charset=utf-8"
+}
+
 func getExpiredConfigForSource(renderCtx *edgectx.RenderContext, source string) types.CacheExpiredConfig {
 	if source == cache.SourceBypass {
 		return renderCtx.ResolvedConfig.Bypass.Cache.Expired
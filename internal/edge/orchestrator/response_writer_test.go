@@ -237,3 +237,22 @@ func TestWriteCachedRedirectResponse_StaleDetection(t *testing.T) {
 		assert.Equal(t, "hit", string(renderCtx.HTTPCtx.Response.Header.Peek("X-Render-Cache")))
 	})
 }
+
+func TestWriteCachedDeletedResponse(t *testing.T) {
+	rw := NewResponseWriter()
+	renderCtx := newTestRenderContext(&config.ResolvedConfig{})
+
+	entry := &cache.CacheMetadata{
+		Source:     cache.SourceDeleted,
+		StatusCode: 410,
+		ExpiresAt:  time.Now().UTC().Add(time.Hour),
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	assert.NoError(t, rw.WriteCachedDeletedResponse(renderCtx, entry))
+	assert.Equal(t, 410, renderCtx.HTTPCtx.Response.StatusCode())
+	assert.Empty(t, renderCtx.HTTPCtx.Response.Header.Peek("Location"))
+	assert.Empty(t, renderCtx.HTTPCtx.Response.Body())
+	assert.Equal(t, "cache", string(renderCtx.HTTPCtx.Response.Header.Peek("X-Render-Source")))
+	assert.Equal(t, "hit", string(renderCtx.HTTPCtx.Response.Header.Peek("X-Render-Cache")))
+}
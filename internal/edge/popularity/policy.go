@@ -0,0 +1,56 @@
+package popularity
+
+import (
+	"math"
+	"time"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+)
+
+// Factor returns the TTL/interval multiplier for a decayed hit score.
+// The multiplier moves geometrically from cold_factor at score 0 to hot_factor
+// at hot_score, on a log scale so that the first few hits already count.
+// Scores above hot_score use hot_factor.
+func Factor(cfg *configtypes.PopularityConfig, score float64) float64 {
+	hot := cfg.GetHotFactor()
+	cold := cfg.GetColdFactor()
+
+	ratio := 0.0
+	if score > 0 {
+		ratio = math.Log1p(score) / math.Log1p(cfg.GetHotScore())
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+
+	return cold * math.Pow(hot/cold, ratio)
+}
+
+// AdjustTTL scales the resolved cache TTL by popularity within [min_ttl, max_ttl].
+// A zero TTL (caching disabled) is returned unchanged.
+func AdjustTTL(cfg *configtypes.PopularityConfig, ttl time.Duration, score float64) time.Duration {
+	if ttl <= 0 {
+		return ttl
+	}
+	return clamp(scale(ttl, Factor(cfg, score)), cfg.GetMinTTL(), cfg.GetMaxTTL())
+}
+
+// AdjustRecacheInterval scales the bothit_recache interval by popularity
+// within [min_recache_interval, max_recache_interval]
+func AdjustRecacheInterval(cfg *configtypes.PopularityConfig, interval time.Duration, score float64) time.Duration {
+	return clamp(scale(interval, Factor(cfg, score)), cfg.GetMinRecacheInterval(), cfg.GetMaxRecacheInterval())
+}
+
+func scale(d time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(d) * factor).Round(time.Second)
+}
+
+func clamp(d, min, max time.Duration) time.Duration {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
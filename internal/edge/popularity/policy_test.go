@@ -0,0 +1,58 @@
+package popularity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestFactor(t *testing.T) {
+	cfg := &configtypes.PopularityConfig{Enabled: true, HotScore: 100, HotFactor: 0.25, ColdFactor: 4}
+
+	assert.Equal(t, 4.0, Factor(cfg, 0), "unvisited pages use cold factor")
+	assert.InDelta(t, 0.25, Factor(cfg, 100), 1e-9, "hot score uses hot factor")
+	assert.InDelta(t, 0.25, Factor(cfg, 10000), 1e-9, "scores above hot score are capped")
+
+	// log1p(9)/log1p(100) ~= 0.5, halfway between 4 and 0.25 geometrically is 1
+	assert.InDelta(t, 1.0, Factor(cfg, 9.05), 0.01)
+	assert.Greater(t, Factor(cfg, 5), Factor(cfg, 50), "factor decreases as popularity grows")
+}
+
+func TestAdjustTTL(t *testing.T) {
+	cfg := &configtypes.PopularityConfig{
+		Enabled: true,
+		MinTTL:  types.Duration(time.Hour),
+		MaxTTL:  types.Duration(7 * 24 * time.Hour),
+	}
+
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		score float64
+		want  time.Duration
+	}{
+		{name: "cold page gets longer TTL", ttl: 24 * time.Hour, score: 0, want: 96 * time.Hour},
+		{name: "hot page gets shorter TTL", ttl: 24 * time.Hour, score: 100, want: 6 * time.Hour},
+		{name: "clamped to max_ttl", ttl: 3 * 24 * time.Hour, score: 0, want: 7 * 24 * time.Hour},
+		{name: "clamped to min_ttl", ttl: 2 * time.Hour, score: 1000, want: time.Hour},
+		{name: "zero TTL stays disabled", ttl: 0, score: 1000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AdjustTTL(cfg, tt.ttl, tt.score))
+		})
+	}
+}
+
+func TestAdjustRecacheInterval(t *testing.T) {
+	cfg := &configtypes.PopularityConfig{Enabled: true}
+
+	assert.Equal(t, 24*time.Hour, AdjustRecacheInterval(cfg, 12*time.Hour, 0), "cold pages clamped to 24h")
+	assert.Equal(t, 3*time.Hour, AdjustRecacheInterval(cfg, 12*time.Hour, 100))
+	assert.Equal(t, 30*time.Minute, AdjustRecacheInterval(cfg, time.Hour, 100), "hot pages clamped to 30m")
+}
@@ -0,0 +1,105 @@
+package popularity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// scoreExpiryHalfLives is how many half-lives an idle score key survives.
+// After 8 half-lives a score has decayed below 1/256 of its value.
+const scoreExpiryHalfLives = 8
+
+// Lua script for decayed hit counting
+// KEYS[1] = popularity key
+// ARGV[1] = now (unix ms), ARGV[2] = half-life (ms), ARGV[3] = increment, ARGV[4] = key expiry (ms)
+// Returns the new score as a string (Lua numbers are truncated to integers in replies)
+const luaDecayedIncrement = `
+local data = redis.call('HMGET', KEYS[1], 'score', 'ts')
+local now = tonumber(ARGV[1])
+local score = tonumber(data[1]) or 0
+local ts = tonumber(data[2]) or now
+local elapsed = now - ts
+if elapsed < 0 then
+  elapsed = 0
+end
+score = score * math.pow(0.5, elapsed / tonumber(ARGV[2])) + tonumber(ARGV[3])
+redis.call('HSET', KEYS[1], 'score', tostring(score), 'ts', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return tostring(score)
+`
+
+// Tracker maintains exponentially decayed per-URL hit scores in Redis.
+// A score equals the number of hits, each weighted by 0.5^(age/half_life).
+type Tracker struct {
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+}
+
+// NewTracker creates a new popularity Tracker
+func NewTracker(redisClient *redis.Client, keyGenerator *redis.KeyGenerator) *Tracker {
+	return &Tracker{
+		redis:        redisClient,
+		keyGenerator: keyGenerator,
+	}
+}
+
+// RecordHit decays the stored score to now, adds one hit and returns the new score
+func (t *Tracker) RecordHit(ctx context.Context, cacheKey *types.CacheKey, halfLife time.Duration, now time.Time) (float64, error) {
+	key := t.keyGenerator.GeneratePopularityKey(cacheKey)
+	expiry := halfLife * scoreExpiryHalfLives
+
+	result, err := t.redis.Eval(ctx, luaDecayedIncrement, []string{key},
+		now.UnixMilli(), halfLife.Milliseconds(), 1, expiry.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to record hit for %s: %w", key, err)
+	}
+
+	str, ok := result.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected popularity script result type %T", result)
+	}
+	score, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid popularity score %q: %w", str, err)
+	}
+	return score, nil
+}
+
+// Score returns the current decayed score without recording a hit.
+// Returns 0 if the URL has no recorded hits.
+func (t *Tracker) Score(ctx context.Context, cacheKey *types.CacheKey, halfLife time.Duration, now time.Time) (float64, error) {
+	key := t.keyGenerator.GeneratePopularityKey(cacheKey)
+
+	fields, err := t.redis.HGetAll(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read popularity for %s: %w", key, err)
+	}
+	if len(fields) == 0 {
+		return 0, nil
+	}
+
+	score, err := strconv.ParseFloat(fields["score"], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid popularity score %q: %w", fields["score"], err)
+	}
+	ts, err := strconv.ParseInt(fields["ts"], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid popularity timestamp %q: %w", fields["ts"], err)
+	}
+
+	return decay(score, time.Duration(now.UnixMilli()-ts)*time.Millisecond, halfLife), nil
+}
+
+// decay applies exponential decay to score over elapsed time
+func decay(score float64, elapsed, halfLife time.Duration) float64 {
+	if elapsed <= 0 || halfLife <= 0 {
+		return score
+	}
+	return score * math.Pow(0.5, float64(elapsed)/float64(halfLife))
+}
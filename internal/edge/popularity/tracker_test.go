@@ -0,0 +1,66 @@
+package popularity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func setupTestTracker(t *testing.T) (*Tracker, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	return NewTracker(redisClient, redis.NewKeyGenerator()), mr
+}
+
+func TestTracker_RecordHit(t *testing.T) {
+	tracker, mr := setupTestTracker(t)
+	ctx := context.Background()
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "abc"}
+	halfLife := time.Hour
+	now := time.Unix(1_700_000_000, 0)
+
+	score, err := tracker.RecordHit(ctx, cacheKey, halfLife, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, score)
+
+	score, err = tracker.RecordHit(ctx, cacheKey, halfLife, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, score)
+
+	t.Run("score decays by half per half-life", func(t *testing.T) {
+		score, err := tracker.Score(ctx, cacheKey, halfLife, now.Add(halfLife))
+		require.NoError(t, err)
+		assert.InDelta(t, 1.0, score, 1e-9)
+	})
+
+	t.Run("hit after decay adds to decayed score", func(t *testing.T) {
+		score, err := tracker.RecordHit(ctx, cacheKey, halfLife, now.Add(2*halfLife))
+		require.NoError(t, err)
+		assert.InDelta(t, 1.5, score, 1e-9)
+	})
+
+	t.Run("idle key expires", func(t *testing.T) {
+		ttl := mr.TTL("pop:" + cacheKey.String())
+		assert.Equal(t, scoreExpiryHalfLives*halfLife, ttl)
+	})
+
+	t.Run("unknown URL has zero score", func(t *testing.T) {
+		score, err := tracker.Score(ctx, &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "other"}, halfLife, now)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, score)
+	})
+}
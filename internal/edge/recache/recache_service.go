@@ -19,8 +19,12 @@ import (
 	"github.com/edgecomet/engine/internal/edge/edgectx"
 	"github.com/edgecomet/engine/internal/edge/events"
 	"github.com/edgecomet/engine/internal/edge/hash"
+	"github.com/edgecomet/engine/internal/edge/metrics"
 	"github.com/edgecomet/engine/internal/edge/orchestrator"
+	"github.com/edgecomet/engine/internal/edge/popularity"
+	"github.com/edgecomet/engine/internal/edge/robots"
 	"github.com/edgecomet/engine/internal/edge/rsclient"
+	"github.com/edgecomet/engine/internal/edge/rshealth"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -29,107 +33,6 @@ const (
 	redisCacheOperationTimeout = 5 * time.Second
 )
 
-// selectAndReserveScript atomically selects a render service with available capacity and reserves a tab
-const selectAndReserveScript = `
--- Atomically selects a healthy render service and reserves an available tab
--- ARGV[1] = request_id
--- ARGV[2] = strategy ("least_loaded", "most_available", or "round_robin")
--- ARGV[3] = reservation TTL (seconds, typically 2)
-
-local request_id = ARGV[1]
-local strategy = ARGV[2]
-local reservation_ttl = tonumber(ARGV[3])
-
--- 1. Find all render services
-local service_keys = redis.call('KEYS', 'service:render:*')
-if #service_keys == 0 then
-    return {false, 'no_services'}
-end
-
--- 2. Filter healthy services and collect tab availability info
-local candidates = {}
-for _, service_key in ipairs(service_keys) do
-    local service_data = redis.call('GET', service_key)
-    if service_data then
-        local service = cjson.decode(service_data)
-
-        -- Only consider services with available capacity (registry already handles staleness via TTL)
-        -- Check: capacity exists, is positive, and has available slots (load < capacity)
-        if service.capacity and service.capacity > 0 and (service.load or 0) < service.capacity then
-            local service_id = service.id
-            local tabs_key = 'tabs:' .. service_id
-
-            if redis.call('EXISTS', tabs_key) == 1 then
-                local tabs = redis.call('HGETALL', tabs_key)
-                local available_count = 0
-                local first_available = nil
-
-                for i = 1, #tabs, 2 do
-                    local tab_id = tonumber(tabs[i])
-                    local tab_value = tabs[i + 1]
-
-                    if tab_value == '' then
-                        available_count = available_count + 1
-                        if first_available == nil then
-                            first_available = tab_id
-                        end
-                    end
-                end
-
-                if available_count > 0 then
-                    -- Calculate load percentage with nil check
-                    local load = service.load or 0
-                    local load_pct = load / service.capacity
-
-                    table.insert(candidates, {
-                        service_id = service_id,
-                        service = service,
-                        tabs_key = tabs_key,
-                        available_count = available_count,
-                        first_available = first_available,
-                        load_pct = load_pct
-                    })
-                end
-            end
-        end
-    end
-end
-
-if #candidates == 0 then
-    return {false, 'no_capacity'}
-end
-
--- 3. Select best service based on strategy
-local selected = candidates[1]
-
-if strategy == 'least_loaded' then
-    for _, candidate in ipairs(candidates) do
-        if candidate.load_pct < selected.load_pct then
-            selected = candidate
-        end
-    end
-elseif strategy == 'most_available' then
-    for _, candidate in ipairs(candidates) do
-        if candidate.available_count > selected.available_count then
-            selected = candidate
-        end
-    end
-end
-
--- 4. Reserve the first available tab
-local tab_id = selected.first_available
-redis.call('HSET', selected.tabs_key, tostring(tab_id), request_id)
-redis.call('EXPIRE', selected.tabs_key, reservation_ttl)
-
--- 5. Return result: {service_id, tab_id, address, port}
-return {
-    selected.service_id,
-    tostring(tab_id),
-    selected.service.address,
-    tostring(selected.service.port)
-}
-`
-
 // TabReservation contains service and tab info
 type TabReservation struct {
 	ServiceID string
@@ -146,9 +49,19 @@ type RecacheService struct {
 	redis         *redis.Client
 	rsClient      *rsclient.RSClient
 	metadataStore *cache.MetadataStore
+	popularity    *popularity.Tracker
 	eventEmitter  events.EventEmitter
 	instanceID    string
 	logger        *zap.Logger
+
+	// Optional render service health tracking (nil = disabled)
+	healthTracker *rshealth.Tracker
+
+	// Optional robots.txt checks for hosts with robots_txt enabled (nil = disabled)
+	robotsFetcher *robots.Fetcher
+
+	// Optional render metrics (nil = not recorded)
+	metricsCollector *metrics.MetricsCollector
 }
 
 // NewRecacheService creates a new RecacheService instance
@@ -159,6 +72,7 @@ func NewRecacheService(
 	redisClient *redis.Client,
 	rsClient *rsclient.RSClient,
 	metadataStore *cache.MetadataStore,
+	popularityTracker *popularity.Tracker,
 	eventEmitter events.EventEmitter,
 	instanceID string,
 	logger *zap.Logger,
@@ -170,12 +84,28 @@ func NewRecacheService(
 		redis:         redisClient,
 		rsClient:      rsClient,
 		metadataStore: metadataStore,
+		popularity:    popularityTracker,
 		eventEmitter:  eventEmitter,
 		instanceID:    instanceID,
 		logger:        logger,
 	}
 }
 
+// SetHealthTracker enables recording of render outcomes for render service health
+func (rs *RecacheService) SetHealthTracker(tracker *rshealth.Tracker) {
+	rs.healthTracker = tracker
+}
+
+// SetRobotsFetcher enables robots.txt checks for hosts with robots_txt enabled
+func (rs *RecacheService) SetRobotsFetcher(fetcher *robots.Fetcher) {
+	rs.robotsFetcher = fetcher
+}
+
+// SetMetricsCollector enables render metrics for recaches
+func (rs *RecacheService) SetMetricsCollector(collector *metrics.MetricsCollector) {
+	rs.metricsCollector = collector
+}
+
 // ProcessRecache processes a recache request from the cache daemon
 // Validates host and dimension, renders the URL, and saves to cache
 func (rs *RecacheService) ProcessRecache(ctx context.Context, url string, hostID, dimensionID int) error {
@@ -216,12 +146,27 @@ func (rs *RecacheService) ProcessRecache(ctx context.Context, url string, hostID
 		return fmt.Errorf("URL hostname %q does not match any configured domain for host %d", urlHostname, hostID)
 	}
 
+	// Skip URLs robots.txt disallows. Succeeds so the daemon does not retry them.
+	robotsVerdict, robotsChecked := rs.checkRobotsTxt(host, url)
+	if robotsChecked && !robotsVerdict.Allowed && host.RobotsTxt.SkipDisallowed {
+		rs.logger.Info("Skipping recache, URL disallowed by robots.txt",
+			zap.String("url", url),
+			zap.Int("host_id", hostID),
+			zap.String("robots_group", robotsVerdict.Group))
+		return nil
+	}
+
 	// Generate request ID and build render context early
 	requestID := fmt.Sprintf("recache-%d-%d-%d", hostID, dimensionID, time.Now().UTC().Unix())
 	renderCtx, err := rs.buildRecacheContext(url, host, dimensionID, dimensionName, requestID)
 	if err != nil {
 		return err
 	}
+	rs.applyPopularityTTL(ctx, renderCtx)
+	if robotsChecked {
+		renderCtx.RobotsVerdict = robotsVerdict.String()
+		renderCtx.RobotsGroup = robotsVerdict.Group
+	}
 
 	rs.logger.Info("Processing recache request",
 		zap.String("url", url),
@@ -266,6 +211,9 @@ func (rs *RecacheService) ProcessRecache(ctx context.Context, url string, hostID
 		zap.String("service_url", serviceURL))
 
 	renderResp, err := rs.rsClient.CallRenderService(ctx, serviceURL, renderReq)
+	if outcome, ok := rshealth.OutcomeFromResponse(ctx, renderResp, err); ok {
+		rs.healthTracker.Record(context.Background(), reservation.ServiceID, outcome)
+	}
 	if err != nil {
 		return fmt.Errorf("render service failed: %w", err)
 	}
@@ -281,6 +229,15 @@ func (rs *RecacheService) ProcessRecache(ctx context.Context, url string, hostID
 
 	// Convert response to RenderServiceResult and save to cache
 	renderResult := rs.buildRenderResult(renderResp)
+
+	// A broken page must not replace the cached one: fail so the daemon retries with backoff
+	if failure := orchestrator.CheckRenderQuality(renderResult, renderCtx.ResolvedConfig.Render.Quality); failure != nil {
+		if rs.metricsCollector != nil {
+			rs.metricsCollector.RecordRenderQualityFailure(renderCtx.Host.Domain, failure.Check)
+		}
+		return fmt.Errorf("%s: %w", types.ErrorTypeRenderQuality, failure)
+	}
+	orchestrator.ApplyHTMLTransforms(renderResult, renderCtx.ResolvedConfig.Render.Transforms, url, rs.logger)
 	totalDuration := time.Since(startTime)
 	if err := rs.saveToCache(ctx, renderCtx, renderResult, reservation.ServiceID, totalDuration); err != nil {
 		return fmt.Errorf("failed to save to cache: %w", err)
@@ -302,8 +259,24 @@ func (rs *RecacheService) saveToCache(
 	serviceID string,
 	totalDuration time.Duration,
 ) error {
-	// Save to cache using cache coordinator (handles sharding)
-	if err := rs.cacheCoord.SaveRenderCache(renderCtx, renderResult); err != nil {
+	// Save to cache using cache coordinator (handles sharding).
+	// With change detection, unchanged content only extends the existing entry's TTL.
+	var contentChange *orchestrator.ContentChange
+	changeDetection := rs.configManager.GetConfig().ChangeDetection
+	if changeDetection.IsEnabled() {
+		previous, err := rs.metadataStore.GetCacheEntry(ctx, renderCtx.CacheKey)
+		if err != nil {
+			rs.logger.Warn("Failed to load previous cache entry for change detection",
+				zap.String("cache_key", renderCtx.CacheKey.String()),
+				zap.Error(err))
+			previous = nil
+		}
+
+		contentChange, err = rs.cacheCoord.SaveRecacheRender(renderCtx, renderResult, previous, changeDetection.Threshold())
+		if err != nil {
+			return fmt.Errorf("failed to save cache: %w", err)
+		}
+	} else if err := rs.cacheCoord.SaveRenderCache(renderCtx, renderResult); err != nil {
 		return fmt.Errorf("failed to save cache: %w", err)
 	}
 
@@ -318,23 +291,32 @@ func (rs *RecacheService) saveToCache(
 	// Emit precache event for access logging
 	if rs.eventEmitter != nil {
 		result := &orchestrator.RenderResult{
-			Source:      orchestrator.ServedFromRender,
-			ServiceID:   serviceID,
-			Duration:    totalDuration,
-			BytesServed: int64(len(renderResult.HTML)),
-			StatusCode:  renderResult.StatusCode,
-			Metrics:     &renderResult.Metrics,
-			RenderTime:  renderResult.RenderTime,
-			PageSEO:     renderResult.PageSEO,
+			Source:        orchestrator.ServedFromRender,
+			ServiceID:     serviceID,
+			Duration:      totalDuration,
+			BytesServed:   int64(len(renderResult.HTML)),
+			StatusCode:    renderResult.StatusCode,
+			Metrics:       &renderResult.Metrics,
+			RenderTime:    renderResult.RenderTime,
+			PageSEO:       renderResult.PageSEO,
+			ContentChange: contentChange,
 		}
 		event := events.BuildRequestEvent(renderCtx, result, totalDuration, rs.instanceID)
 		rs.eventEmitter.Emit(event)
 	}
 
-	rs.logger.Info("Recache saved to cache successfully",
+	logFields := []zap.Field{
 		zap.String("url", renderCtx.TargetURL),
 		zap.String("cache_key", renderCtx.CacheKey.String()),
-		zap.Int("html_size", len(renderResult.HTML)))
+		zap.Int("html_size", len(renderResult.HTML)),
+	}
+	if contentChange != nil {
+		logFields = append(logFields,
+			zap.Bool("content_changed", contentChange.Changed),
+			zap.Float64("similarity", contentChange.Similarity),
+			zap.Strings("changed_fields", contentChange.ChangedFields))
+	}
+	rs.logger.Info("Recache saved to cache successfully", logFields...)
 
 	return nil
 }
@@ -402,6 +384,15 @@ func (rs *RecacheService) processBypassRecache(ctx context.Context, url string,
 	return nil
 }
 
+// checkRobotsTxt returns the robots.txt verdict for the host's configured crawler.
+// Returns false if robots_txt is disabled or the origin's robots.txt is unavailable.
+func (rs *RecacheService) checkRobotsTxt(host *types.Host, url string) (robots.Verdict, bool) {
+	if !host.RobotsTxt.IsEnabled() {
+		return robots.Verdict{}, false
+	}
+	return rs.robotsFetcher.Check(url, host.RobotsTxt.GetUserAgent(), host.RobotsTxt.GetTTL(), time.Now())
+}
+
 // getHostByID retrieves a host configuration by ID
 func (rs *RecacheService) getHostByID(hostID int) *types.Host {
 	hosts := rs.configManager.GetHosts()
@@ -457,6 +448,29 @@ func (rs *RecacheService) buildRecacheContext(url string, host *types.Host, dime
 	return renderCtx, nil
 }
 
+// applyPopularityTTL scales the resolved cache TTL by the URL's current popularity score.
+// Recaches do not count as hits. Keeps the static TTL if popularity is disabled or unreadable.
+func (rs *RecacheService) applyPopularityTTL(ctx context.Context, renderCtx *edgectx.RenderContext) {
+	cfg := rs.configManager.GetConfig().Popularity
+	if !cfg.IsEnabled() || rs.popularity == nil {
+		return
+	}
+
+	score, err := rs.popularity.Score(ctx, renderCtx.CacheKey, cfg.GetHalfLife(), time.Now())
+	if err != nil {
+		rs.logger.Warn("Failed to read popularity score, using static TTL",
+			zap.String("cache_key", renderCtx.CacheKey.String()),
+			zap.Error(err))
+		return
+	}
+
+	renderCtx.ResolvedConfig.Cache.TTL = popularity.AdjustTTL(cfg, renderCtx.ResolvedConfig.Cache.TTL, score)
+	rs.logger.Debug("Popularity applied to recache TTL",
+		zap.String("cache_key", renderCtx.CacheKey.String()),
+		zap.Float64("score", score),
+		zap.Duration("ttl", renderCtx.ResolvedConfig.Cache.TTL))
+}
+
 // buildRenderResult converts render response to RenderServiceResult
 func (rs *RecacheService) buildRenderResult(renderResp *types.RenderResponse) *orchestrator.RenderServiceResult {
 	return &orchestrator.RenderServiceResult{
@@ -482,11 +496,12 @@ func (rs *RecacheService) selectServiceAndReserveTab(ctx context.Context, reques
 	// Execute Lua script to atomically select service and reserve tab
 	result, err := rs.redis.Eval(
 		redisCtx,
-		selectAndReserveScript,
+		rshealth.SelectAndReserveScript,
 		[]string{},
 		requestID,
 		strategy, // selection strategy from config
 		2,
+		time.Now().UTC().UnixMilli(), // now, for circuit breaker state
 	)
 
 	if err != nil {
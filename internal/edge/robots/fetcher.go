@@ -0,0 +1,181 @@
+package robots
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/edgecomet/engine/internal/edge/bypass"
+)
+
+// Path is the location of robots.txt on an origin
+const Path = "/robots.txt"
+
+const (
+	// maxRedirects is the number of redirects followed when fetching robots.txt
+	maxRedirects = 5
+
+	// unreachableRetry is how long an unreachable origin is not fetched again
+	unreachableRetry = time.Minute
+
+	// firstFetchWait is how long a request waits for an origin's first robots.txt fetch.
+	// Slower fetches complete in the background and the request is handled as unavailable.
+	firstFetchWait = 2 * time.Second
+)
+
+type fetchedFile struct {
+	rules     *Rules
+	expiresAt time.Time
+}
+
+// Fetcher fetches and caches robots.txt per origin (scheme and host).
+// Files are fetched through the bypass service, so its SSRF protection, timeout
+// and user agent apply, and are kept in memory for the host's configured TTL.
+// Concurrent fetches of one origin are coalesced, and expired copies keep being
+// served while they are refreshed in the background.
+type Fetcher struct {
+	bypassSvc      *bypass.BypassService
+	logger         *zap.Logger
+	firstFetchWait time.Duration
+
+	group singleflight.Group
+	mu    sync.Mutex
+	files map[string]fetchedFile
+}
+
+// NewFetcher creates a new robots.txt Fetcher
+func NewFetcher(bypassSvc *bypass.BypassService, logger *zap.Logger) *Fetcher {
+	return &Fetcher{
+		bypassSvc:      bypassSvc,
+		logger:         logger,
+		firstFetchWait: firstFetchWait,
+		files:          make(map[string]fetchedFile),
+	}
+}
+
+// Check returns the robots.txt verdict for targetURL requested by userAgent.
+// Returns false if the origin's robots.txt is unavailable (unreachable or server
+// error without a previously fetched copy); such URLs must be treated as allowed.
+func (f *Fetcher) Check(targetURL, userAgent string, ttl time.Duration, now time.Time) (Verdict, bool) {
+	if f == nil {
+		return Verdict{}, false
+	}
+
+	parsed, err := url.Parse(targetURL)
+	if err != nil || parsed.Host == "" {
+		return Verdict{}, false
+	}
+
+	rules := f.Get(parsed.Scheme+"://"+parsed.Host, ttl, now)
+	if rules == nil {
+		return Verdict{}, false
+	}
+
+	path := parsed.EscapedPath()
+	if parsed.RawQuery != "" {
+		path += "?" + parsed.RawQuery
+	}
+	return rules.Check(userAgent, path), true
+}
+
+// Get returns the parsed robots.txt of an origin. An expired copy is returned as is while
+// a single background fetch refreshes it; only the first fetch of an origin is waited for,
+// up to firstFetchWait. A missing file (4xx) allows everything. Returns nil if the origin
+// is unreachable and no copy was fetched before, or if the first fetch is still running.
+func (f *Fetcher) Get(origin string, ttl time.Duration, now time.Time) *Rules {
+	f.mu.Lock()
+	file, ok := f.files[origin]
+	f.mu.Unlock()
+
+	load := func() (interface{}, error) {
+		return f.load(origin, ttl, now), nil
+	}
+
+	if ok {
+		if !now.Before(file.expiresAt) {
+			// Joins the refresh already running for this origin, if any
+			f.group.DoChan(origin, load)
+		}
+		return file.rules
+	}
+
+	timer := time.NewTimer(f.firstFetchWait)
+	defer timer.Stop()
+	select {
+	case result := <-f.group.DoChan(origin, load):
+		return result.Val.(*Rules)
+	case <-timer.C:
+		f.logger.Debug("robots.txt fetch still running, handling request as unavailable",
+			zap.String("origin", origin))
+		return nil
+	}
+}
+
+// load fetches robots.txt and stores it for ttl. If the origin is unreachable the
+// previous copy is kept and fetched again after at most unreachableRetry.
+func (f *Fetcher) load(origin string, ttl time.Duration, now time.Time) *Rules {
+	rules, reachable := f.fetch(origin)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !reachable {
+		rules = f.files[origin].rules
+		if unreachableRetry < ttl {
+			ttl = unreachableRetry
+		}
+	}
+	f.files[origin] = fetchedFile{rules: rules, expiresAt: now.Add(ttl)}
+	return rules
+}
+
+// fetch downloads and parses robots.txt, following up to maxRedirects redirects.
+// Returns false if the origin is unreachable or answers with a server error.
+func (f *Fetcher) fetch(origin string) (*Rules, bool) {
+	target := origin + Path
+	for redirects := 0; ; redirects++ {
+		resp, err := f.bypassSvc.FetchContent(target, nil, f.logger)
+		if err != nil {
+			f.logger.Warn("Failed to fetch robots.txt", zap.String("url", target), zap.Error(err))
+			return nil, false
+		}
+
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			f.logger.Debug("Fetched robots.txt",
+				zap.String("url", target),
+				zap.Int("size", len(resp.Body)))
+			return Parse(resp.Body), true
+		case resp.StatusCode >= 300 && resp.StatusCode < 400:
+			location := firstHeader(resp.Headers, "Location")
+			next, err := url.Parse(target)
+			if err == nil {
+				next, err = next.Parse(location)
+			}
+			if location == "" || err != nil || redirects >= maxRedirects {
+				// Unfollowable redirects are treated as a missing file
+				return &Rules{}, true
+			}
+			target = next.String()
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			return &Rules{}, true
+		default:
+			f.logger.Warn("robots.txt unavailable",
+				zap.String("url", target),
+				zap.Int("status_code", resp.StatusCode))
+			return nil, false
+		}
+	}
+}
+
+func firstHeader(headers map[string][]string, name string) string {
+	for key, values := range headers {
+		if len(values) > 0 && strings.EqualFold(key, name) {
+			return values[0]
+		}
+	}
+	return ""
+}
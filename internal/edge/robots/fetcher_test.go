@@ -0,0 +1,178 @@
+package robots
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/edge/bypass"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func newTestFetcher(t *testing.T, ssrfProtection bool) *Fetcher {
+	t.Helper()
+	timeout := types.Duration(5 * time.Second)
+	bypassSvc := bypass.NewBypassService(&config.GlobalBypassConfig{
+		Timeout:        &timeout,
+		UserAgent:      "EdgeComet",
+		SSRFProtection: &ssrfProtection,
+	}, zap.NewNop())
+	return NewFetcher(bypassSvc, zap.NewNop())
+}
+
+func TestFetcherCheck(t *testing.T) {
+	var fetches atomic.Int32
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fetches.Add(1)
+			http.Redirect(w, r, "/real-robots.txt", http.StatusMovedPermanently)
+		case "/real-robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(origin.Close)
+
+	fetcher := newTestFetcher(t, false)
+	now := time.Now()
+
+	verdict, ok := fetcher.Check(origin.URL+"/private/page?x=1", "Googlebot", time.Hour, now)
+	require.True(t, ok)
+	assert.Equal(t, Verdict{Allowed: false, Group: "*"}, verdict)
+
+	verdict, ok = fetcher.Check(origin.URL+"/public", "Googlebot", time.Hour, now)
+	require.True(t, ok)
+	assert.True(t, verdict.Allowed)
+	assert.Equal(t, int32(1), fetches.Load(), "robots.txt is reused within the TTL")
+
+	_, ok = fetcher.Check(origin.URL+"/public", "Googlebot", time.Hour, now.Add(2*time.Hour))
+	require.True(t, ok, "the expired copy is served while it is refreshed")
+	assert.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, 10*time.Millisecond,
+		"robots.txt is fetched again after the TTL")
+}
+
+func TestFetcherCoalescesRefresh(t *testing.T) {
+	var fetches atomic.Int32
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			// Keep the refresh running while the concurrent checks arrive
+			time.Sleep(100 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	t.Cleanup(origin.Close)
+
+	fetcher := newTestFetcher(t, false)
+	now := time.Now()
+	_, ok := fetcher.Check(origin.URL+"/page", "Googlebot", time.Hour, now)
+	require.True(t, ok)
+
+	expired := now.Add(2 * time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verdict, ok := fetcher.Check(origin.URL+"/private", "Googlebot", time.Hour, expired)
+			assert.True(t, ok)
+			assert.False(t, verdict.Allowed)
+		}()
+	}
+	wg.Wait()
+
+	assert.Eventually(t, func() bool {
+		rules := fetcher.Get(origin.URL, time.Hour, expired)
+		return rules != nil && fetches.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(2), fetches.Load(), "concurrent checks of an expired origin fetch robots.txt once")
+}
+
+func TestFetcherFirstFetchWait(t *testing.T) {
+	release := make(chan struct{})
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	t.Cleanup(origin.Close)
+	t.Cleanup(func() { close(release) })
+
+	fetcher := newTestFetcher(t, false)
+	fetcher.firstFetchWait = 20 * time.Millisecond
+	now := time.Now()
+
+	_, ok := fetcher.Check(origin.URL+"/page", "Googlebot", time.Hour, now)
+	assert.False(t, ok, "a slow first fetch is not waited for")
+
+	release <- struct{}{}
+	assert.Eventually(t, func() bool {
+		verdict, ok := fetcher.Check(origin.URL+"/page", "Googlebot", time.Hour, now)
+		return ok && !verdict.Allowed
+	}, time.Second, 10*time.Millisecond, "the fetch completes in the background")
+}
+
+func TestFetcherStatusHandling(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	t.Cleanup(origin.Close)
+
+	t.Run("missing file allows everything", func(t *testing.T) {
+		status.Store(http.StatusNotFound)
+		verdict, ok := newTestFetcher(t, false).Check(origin.URL+"/page", "Googlebot", time.Hour, time.Now())
+		require.True(t, ok)
+		assert.True(t, verdict.Allowed)
+	})
+
+	t.Run("server error without previous copy is unavailable", func(t *testing.T) {
+		status.Store(http.StatusServiceUnavailable)
+		_, ok := newTestFetcher(t, false).Check(origin.URL+"/page", "Googlebot", time.Hour, time.Now())
+		assert.False(t, ok)
+	})
+
+	t.Run("server error keeps previous copy", func(t *testing.T) {
+		fetcher := newTestFetcher(t, false)
+		now := time.Now()
+
+		status.Store(http.StatusOK)
+		_, ok := fetcher.Check(origin.URL+"/page", "Googlebot", time.Hour, now)
+		require.True(t, ok)
+
+		status.Store(http.StatusInternalServerError)
+		expired := now.Add(2 * time.Hour)
+		require.NotNil(t, fetcher.load(origin.URL, time.Hour, expired))
+		verdict, ok := fetcher.Check(origin.URL+"/page", "Googlebot", time.Hour, expired)
+		require.True(t, ok)
+		assert.False(t, verdict.Allowed)
+	})
+}
+
+func TestFetcherSSRFProtection(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	t.Cleanup(origin.Close)
+
+	// The test server listens on loopback, which the SSRF-safe dialer refuses
+	_, ok := newTestFetcher(t, true).Check(origin.URL+"/page", "Googlebot", time.Hour, time.Now())
+	assert.False(t, ok)
+}
+
+func TestFetcherNilSafe(t *testing.T) {
+	var fetcher *Fetcher
+	_, ok := fetcher.Check("https://example.com/page", "Googlebot", time.Hour, time.Now())
+	assert.False(t, ok)
+}
@@ -0,0 +1,206 @@
+package robots
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// MaxSize is the number of robots.txt bytes parsed (RFC 9309 requires at least 500 KiB)
+const MaxSize = 500 * 1024
+
+// WildcardGroup is the user agent token of the group that applies to any crawler
+const WildcardGroup = "*"
+
+// Verdict values reported in request events
+const (
+	VerdictAllow    = "allow"
+	VerdictDisallow = "disallow"
+)
+
+// Verdict is the result of checking a path against robots.txt
+type Verdict struct {
+	Allowed bool
+	Group   string // User agent token of the applied group ("*" for the wildcard group, empty if no group applies)
+}
+
+// String returns VerdictAllow or VerdictDisallow
+func (v Verdict) String() string {
+	if v.Allowed {
+		return VerdictAllow
+	}
+	return VerdictDisallow
+}
+
+// Rules is a parsed robots.txt file
+type Rules struct {
+	groups []group
+}
+
+type group struct {
+	agents []string
+	rules  []rule
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// Parse parses a robots.txt body following RFC 9309.
+// Unknown lines are ignored and only the first MaxSize bytes are read.
+func Parse(body []byte) *Rules {
+	if len(body) > MaxSize {
+		body = body[:MaxSize]
+	}
+
+	rules := &Rules{}
+	var current *group
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 4096), MaxSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share one group
+			if current == nil || !lastWasAgent {
+				rules.groups = append(rules.groups, group{})
+				current = &rules.groups[len(rules.groups)-1]
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			// Rules before the first user-agent line and empty paths have no effect
+			if current == nil || value == "" {
+				continue
+			}
+			current.rules = append(current.rules, rule{allow: key == "allow", pattern: value})
+		default:
+			// Other records (sitemap, crawl-delay, ...) do not end the user-agent list
+		}
+	}
+
+	return rules
+}
+
+// Check returns the verdict for a URL path (with query) requested by userAgent.
+// The most specific group whose token appears in the user agent applies, falling back
+// to the wildcard group. Within the group the longest matching rule wins and allow
+// wins ties. Paths are allowed if no group or rule applies.
+func (r *Rules) Check(userAgent, path string) Verdict {
+	if path == "" {
+		path = "/"
+	}
+
+	token := r.groupFor(userAgent)
+	if token == "" {
+		return Verdict{Allowed: true}
+	}
+	if path == Path {
+		return Verdict{Allowed: true, Group: token}
+	}
+
+	matchLen := -1
+	allowed := true
+	for _, g := range r.groups {
+		if !g.hasAgent(token) {
+			continue
+		}
+		for _, rl := range g.rules {
+			if !matchPattern(rl.pattern, path) {
+				continue
+			}
+			if len(rl.pattern) > matchLen || (len(rl.pattern) == matchLen && rl.allow) {
+				matchLen = len(rl.pattern)
+				allowed = rl.allow
+			}
+		}
+	}
+
+	return Verdict{Allowed: allowed, Group: token}
+}
+
+// groupFor returns the token of the group that applies to userAgent.
+// Groups with the same token are merged by Check.
+func (r *Rules) groupFor(userAgent string) string {
+	if r == nil {
+		return ""
+	}
+
+	userAgent = strings.ToLower(userAgent)
+	best := ""
+	hasWildcard := false
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent == WildcardGroup {
+				hasWildcard = true
+				continue
+			}
+			if agent != "" && len(agent) > len(best) && strings.Contains(userAgent, agent) {
+				best = agent
+			}
+		}
+	}
+
+	if best == "" && hasWildcard {
+		return WildcardGroup
+	}
+	return best
+}
+
+func (g *group) hasAgent(token string) bool {
+	for _, agent := range g.agents {
+		if agent == token {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern reports whether a robots.txt path pattern matches the start of path.
+// "*" matches any sequence of characters and a trailing "$" anchors the end of the path.
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = strings.TrimSuffix(pattern, "$")
+	}
+
+	// Greedy wildcard matching with backtracking to the last "*"
+	p, s := 0, 0
+	star, mark := -1, 0
+	for s < len(path) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, s
+			p++
+		case p < len(pattern) && pattern[p] == path[s]:
+			p++
+			s++
+		case p == len(pattern) && !anchored:
+			return true
+		case star >= 0:
+			mark++
+			p, s = star+1, mark
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,108 @@
+package robots
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testRobotsTxt = `# Example robots.txt
+User-agent: *
+Disallow: /admin/
+Disallow: /*.pdf$
+Allow: /admin/public
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /private
+Allow: /private/press
+Crawl-delay: 5
+
+User-agent: Googlebot-Image
+Disallow: /
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestRulesCheck(t *testing.T) {
+	rules := Parse([]byte(testRobotsTxt))
+
+	tests := []struct {
+		name      string
+		userAgent string
+		path      string
+		expected  Verdict
+	}{
+		{"wildcard group disallow", "Mozilla/5.0 (compatible; ExampleBot/1.0)", "/admin/users", Verdict{Allowed: false, Group: "*"}},
+		{"longer allow wins", "ExampleBot", "/admin/public/logo.png", Verdict{Allowed: true, Group: "*"}},
+		{"end anchor matches", "ExampleBot", "/docs/guide.pdf", Verdict{Allowed: false, Group: "*"}},
+		{"end anchor does not match query", "ExampleBot", "/docs/guide.pdf?v=2", Verdict{Allowed: true, Group: "*"}},
+		{"named group ignores wildcard rules", "Mozilla/5.0 (compatible; Googlebot/2.1)", "/admin/users", Verdict{Allowed: true, Group: "googlebot"}},
+		{"named group disallow", "Mozilla/5.0 (compatible; Googlebot/2.1)", "/private/docs", Verdict{Allowed: false, Group: "googlebot"}},
+		{"shared group member", "Mozilla/5.0 (compatible; bingbot/2.0)", "/private", Verdict{Allowed: false, Group: "bingbot"}},
+		{"most specific token wins", "Googlebot-Image/1.0", "/photos/cat.jpg", Verdict{Allowed: false, Group: "googlebot-image"}},
+		{"robots.txt is always allowed", "Googlebot-Image/1.0", "/robots.txt", Verdict{Allowed: true, Group: "googlebot-image"}},
+		{"empty path is root", "Googlebot", "", Verdict{Allowed: true, Group: "googlebot"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rules.Check(tt.userAgent, tt.path))
+		})
+	}
+}
+
+func TestRulesCheckWithoutMatchingGroup(t *testing.T) {
+	rules := Parse([]byte("User-agent: Googlebot\nDisallow: /\n"))
+	assert.Equal(t, Verdict{Allowed: true}, rules.Check("Bingbot", "/page"))
+
+	// A missing robots.txt allows everything
+	assert.Equal(t, Verdict{Allowed: true}, (&Rules{}).Check("Googlebot", "/page"))
+}
+
+func TestParseIgnoresInvalidLines(t *testing.T) {
+	rules := Parse([]byte(strings.Join([]string{
+		"Disallow: /before-any-group",
+		"not a record",
+		"USER-AGENT: *",
+		"disallow:   /tmp   # temporary files",
+		"Disallow:",
+	}, "\n")))
+
+	assert.Equal(t, Verdict{Allowed: false, Group: "*"}, rules.Check("AnyBot", "/tmp/file"))
+	assert.Equal(t, Verdict{Allowed: true, Group: "*"}, rules.Check("AnyBot", "/before-any-group"))
+	assert.Equal(t, Verdict{Allowed: true, Group: "*"}, rules.Check("AnyBot", "/page"))
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"/", "/anything", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/Fish.html", false},
+		{"/fish/", "/fish", false},
+		{"/*.php", "/folder/index.php?x=1", true},
+		{"/*.php$", "/index.php", true},
+		{"/*.php$", "/index.php5", false},
+		{"/fish*", "/fishheads", true},
+		{"/a*b*c", "/aXbYc", true},
+		{"/a*b*c", "/aXbY", false},
+		{"/exact$", "/exact", true},
+		{"/exact$", "/exact/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchPattern(tt.pattern, tt.path))
+		})
+	}
+}
+
+func TestVerdictString(t *testing.T) {
+	assert.Equal(t, VerdictAllow, Verdict{Allowed: true}.String())
+	assert.Equal(t, VerdictDisallow, Verdict{}.String())
+}
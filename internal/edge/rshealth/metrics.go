@@ -0,0 +1,68 @@
+package rshealth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds Prometheus metrics for render service health
+type Metrics struct {
+	outcomesTotal *prometheus.CounterVec
+	tripsTotal    *prometheus.CounterVec
+}
+
+// NewMetrics creates and registers render service health metrics with the default registry
+func NewMetrics(namespace string) *Metrics {
+	return NewMetricsWithRegistry(namespace, prometheus.DefaultRegisterer)
+}
+
+// NewMetricsWithRegistry creates and registers render service health metrics with registerer
+func NewMetricsWithRegistry(namespace string, registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		outcomesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "eg",
+				Name:      "rs_outcomes_total",
+				Help:      "Render outcomes recorded for render service health",
+			},
+			[]string{"rs", "outcome"},
+		),
+		tripsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "eg",
+				Name:      "rs_circuit_trips_total",
+				Help:      "Render service circuits opened by this EG",
+			},
+			[]string{"rs"},
+		),
+	}
+
+	registerer.MustRegister(
+		m.outcomesTotal,
+		m.tripsTotal,
+	)
+
+	return m
+}
+
+func (m *Metrics) recordOutcome(serviceID string, outcome Outcome) {
+	if m == nil {
+		return
+	}
+	result := "failure"
+	switch {
+	case outcome.HardTimeout:
+		result = "hard_timeout"
+	case outcome.Success:
+		result = "success"
+	}
+	m.outcomesTotal.WithLabelValues(serviceID, result).Inc()
+}
+
+func (m *Metrics) recordTrip(serviceID string) {
+	if m == nil {
+		return
+	}
+	m.tripsTotal.WithLabelValues(serviceID).Inc()
+}
@@ -0,0 +1,151 @@
+package rshealth
+
+// SelectAndReserveScript atomically selects a render service and reserves an available tab.
+// Services with an open circuit are skipped unless every candidate is ejected, so a
+// cluster-wide problem (e.g., a slow origin) degrades selection instead of stopping renders.
+// Excluded services (render retries) are never selected.
+const SelectAndReserveScript = `
+-- Atomically selects a healthy render service and reserves an available tab
+-- ARGV[1] = request_id
+-- ARGV[2] = strategy ("least_loaded", "most_available", or "healthiest")
+-- ARGV[3] = reservation TTL (seconds, typically 2)
+-- ARGV[4] = now (ms), compared with circuit open_until
+-- ARGV[5] = comma-separated service IDs to exclude (optional, e.g., services that failed this request)
+
+local request_id = ARGV[1]
+local strategy = ARGV[2]
+local reservation_ttl = tonumber(ARGV[3])
+local now = tonumber(ARGV[4]) or 0
+local excluded = {}
+for excluded_id in string.gmatch(ARGV[5] or '', '[^,]+') do
+    excluded[excluded_id] = true
+end
+
+-- 1. Find all render services
+local service_keys = redis.call('KEYS', 'service:render:*')
+if #service_keys == 0 then
+    return {false, 'no_services'}
+end
+
+-- 2. Filter healthy services and collect tab availability info
+local candidates = {}
+local ejected = {}
+for _, service_key in ipairs(service_keys) do
+    local service_data = redis.call('GET', service_key)
+    if service_data then
+        local service = cjson.decode(service_data)
+
+        -- Skip excluded services; only consider services with available capacity (registry already handles staleness via TTL)
+        -- Check: capacity exists, is positive, and has available slots (load < capacity)
+        if not excluded[service.id] and service.capacity and service.capacity > 0 and (service.load or 0) < service.capacity then
+            local service_id = service.id
+            local tabs_key = 'tabs:' .. service_id
+
+            if redis.call('EXISTS', tabs_key) == 1 then
+                local tabs = redis.call('HGETALL', tabs_key)
+                local available_count = 0
+                local first_available = nil
+
+                for i = 1, #tabs, 2 do
+                    local tab_id = tonumber(tabs[i])
+                    local tab_value = tabs[i + 1]
+
+                    if tab_value == '' then
+                        available_count = available_count + 1
+                        if first_available == nil then
+                            first_available = tab_id
+                        end
+                    end
+                end
+
+                if available_count > 0 then
+                    -- Calculate load percentage with nil check
+                    local load = service.load or 0
+                    local load_pct = load / service.capacity
+
+                    -- Recorded health (absent when health tracking is disabled or no outcomes yet)
+                    local health = redis.call('HMGET', '` + KeyPrefix + `' .. service_id, 'open_until', 'score', 'p95_ms')
+                    local open_until = tonumber(health[1]) or 0
+                    local score = tonumber(health[2]) or 1
+                    local p95 = tonumber(health[3]) or 0
+
+                    local candidate = {
+                        service_id = service_id,
+                        service = service,
+                        tabs_key = tabs_key,
+                        available_count = available_count,
+                        first_available = first_available,
+                        load_pct = load_pct,
+                        score = score,
+                        p95 = p95
+                    }
+                    if open_until > now then
+                        table.insert(ejected, candidate)
+                    else
+                        table.insert(candidates, candidate)
+                    end
+                end
+            end
+        end
+    end
+end
+
+-- Fail open when every service with capacity is ejected
+if #candidates == 0 then
+    candidates = ejected
+end
+
+if #candidates == 0 then
+    return {false, 'no_capacity'}
+end
+
+-- 3. Select best service based on strategy
+local selected = candidates[1]
+
+if strategy == 'least_loaded' then
+    for _, candidate in ipairs(candidates) do
+        if candidate.load_pct < selected.load_pct then
+            selected = candidate
+        end
+    end
+elseif strategy == 'most_available' then
+    for _, candidate in ipairs(candidates) do
+        if candidate.available_count > selected.available_count then
+            selected = candidate
+        end
+    end
+elseif strategy == 'healthiest' then
+    -- Weight: health score x free capacity x p95 render time relative to the fastest candidate
+    local fastest = 0
+    for _, candidate in ipairs(candidates) do
+        if candidate.p95 > 0 and (fastest == 0 or candidate.p95 < fastest) then
+            fastest = candidate.p95
+        end
+    end
+    local best = -1
+    for _, candidate in ipairs(candidates) do
+        local speed = 1
+        if fastest > 0 and candidate.p95 > 0 then
+            speed = fastest / candidate.p95
+        end
+        local weight = candidate.score * (1 - candidate.load_pct) * speed
+        if weight > best then
+            best = weight
+            selected = candidate
+        end
+    end
+end
+
+-- 4. Reserve the first available tab
+local tab_id = selected.first_available
+redis.call('HSET', selected.tabs_key, tostring(tab_id), request_id)
+redis.call('EXPIRE', selected.tabs_key, reservation_ttl)
+
+-- 5. Return result: {service_id, tab_id, address, port}
+return {
+    selected.service_id,
+    tostring(tab_id),
+    selected.service.address,
+    tostring(selected.service.port)
+}
+`
@@ -0,0 +1,299 @@
+package rshealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// KeyPrefix is the Redis hash prefix holding health of one render service ({prefix}{service_id})
+const KeyPrefix = "rshealth:"
+
+// renderTimeBuckets are the upper bounds (ms) of the render time histogram used for p95.
+// Render times above the last bound fall into an overflow bucket.
+var renderTimeBuckets = []int64{250, 500, 1000, 2000, 3000, 5000, 8000, 12000, 20000, 30000}
+
+// recordScript decays the stored counters of a service, adds one outcome, recomputes
+// health and opens the circuit when a threshold is crossed. Outcomes arriving while the
+// circuit is open are dropped; counters restart when it opens, so a service is judged on
+// fresh outcomes once it is selectable again.
+//
+// KEYS[1] = rshealth:{service_id}
+// ARGV[1] = now (ms), ARGV[2] = half-life (ms)
+// ARGV[3] = success (0/1), ARGV[4] = hard timeout (0/1), ARGV[5] = render time (ms, -1 if unknown)
+// ARGV[6] = min requests, ARGV[7] = min success rate, ARGV[8] = max hard-timeout rate
+// ARGV[9] = open duration (ms), ARGV[10] = max open duration (ms), ARGV[11] = key TTL (s)
+// ARGV[12..] = render time bucket bounds (ms)
+// Returns {opened (0/1), open duration (ms)}
+const recordScript = `
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local half_life = tonumber(ARGV[2])
+local success = tonumber(ARGV[3])
+local hard_timeout = tonumber(ARGV[4])
+local render_ms = tonumber(ARGV[5])
+local min_requests = tonumber(ARGV[6])
+local min_success_rate = tonumber(ARGV[7])
+local max_hard_timeout_rate = tonumber(ARGV[8])
+local open_ms = tonumber(ARGV[9])
+local max_open_ms = tonumber(ARGV[10])
+local ttl = tonumber(ARGV[11])
+local bounds = {}
+for i = 12, #ARGV do
+    table.insert(bounds, tonumber(ARGV[i]))
+end
+
+local h = {}
+local raw = redis.call('HGETALL', key)
+for i = 1, #raw, 2 do
+    h[raw[i]] = tonumber(raw[i + 1]) or 0
+end
+
+if (h.open_until or 0) > now then
+    return {0, 0}
+end
+
+local decay = 1
+if h.updated then
+    decay = 2 ^ (-(now - h.updated) / half_life)
+end
+
+local total = (h.total or 0) * decay + 1
+local succeeded = (h.success or 0) * decay + success
+local hard = (h.hard_timeout or 0) * decay + hard_timeout
+
+local rt = {}
+local rt_sum = 0
+for i = 0, #bounds do
+    rt[i] = (h['rt_' .. i] or 0) * decay
+end
+if render_ms >= 0 then
+    local idx = #bounds
+    for i, bound in ipairs(bounds) do
+        if render_ms <= bound then
+            idx = i - 1
+            break
+        end
+    end
+    rt[idx] = rt[idx] + 1
+end
+for i = 0, #bounds do
+    rt_sum = rt_sum + rt[i]
+end
+
+-- p95 render time: upper bound of the bucket reaching 95% (overflow reports twice the last bound)
+local p95 = 0
+if rt_sum > 0 then
+    local cumulative = 0
+    for i = 0, #bounds do
+        cumulative = cumulative + rt[i]
+        if cumulative >= 0.95 * rt_sum then
+            p95 = bounds[i + 1] or bounds[#bounds] * 2
+            break
+        end
+    end
+end
+
+local trips = h.trips or 0
+local opened = 0
+local open_for = 0
+if total >= min_requests then
+    if succeeded / total < min_success_rate or hard / total > max_hard_timeout_rate then
+        trips = trips + 1
+        open_for = math.min(open_ms * 2 ^ (trips - 1), max_open_ms)
+        opened = 1
+    else
+        trips = 0
+    end
+end
+
+-- Health score in [0, 1], smoothed towards healthy while outcomes are few
+local prior = 5
+local score = ((succeeded + prior) / (total + prior)) * (1 - hard / (total + prior))
+
+if opened == 1 then
+    -- Half weight once the circuit closes, so the service still receives trial renders
+    redis.call('DEL', key)
+    redis.call('HSET', key, 'open_until', tostring(now + open_for), 'trips', tostring(trips),
+        'updated', tostring(now), 'score', '0.5', 'p95_ms', tostring(p95))
+else
+    local fields = {'total', tostring(total), 'success', tostring(succeeded), 'hard_timeout', tostring(hard),
+        'updated', tostring(now), 'trips', tostring(trips), 'open_until', '0',
+        'score', tostring(score), 'p95_ms', tostring(p95)}
+    for i = 0, #bounds do
+        table.insert(fields, 'rt_' .. i)
+        table.insert(fields, tostring(rt[i]))
+    end
+    redis.call('HSET', key, unpack(fields))
+end
+redis.call('EXPIRE', key, ttl)
+
+return {opened, math.floor(open_for)}
+`
+
+// Outcome is the result of one render on a render service
+type Outcome struct {
+	Success     bool          // Render service returned a render
+	HardTimeout bool          // Render was aborted at the hard timeout
+	RenderTime  time.Duration // Render time reported by the service (0 if unknown)
+}
+
+// OutcomeFromResponse derives the outcome of a CallRenderService call. A service that
+// has not answered by the request deadline counts as a hard timeout. Returns false when
+// the call says nothing about service health: the caller cancelled the request.
+func OutcomeFromResponse(ctx context.Context, resp *types.RenderResponse, err error) (Outcome, bool) {
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled) {
+			return Outcome{}, false
+		}
+		var netErr net.Error
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) ||
+			(errors.As(err, &netErr) && netErr.Timeout()) {
+			return Outcome{HardTimeout: true}, true
+		}
+		return Outcome{}, true
+	}
+	if resp == nil {
+		return Outcome{}, true
+	}
+	return Outcome{
+		Success:     resp.Success,
+		HardTimeout: resp.ErrorType == types.ErrorTypeHardTimeout,
+		RenderTime:  resp.RenderTime,
+	}, true
+}
+
+// Health is the recorded health of a render service
+type Health struct {
+	Score     float64       // Health score in [0, 1]
+	P95       time.Duration // p95 render time
+	Requests  float64       // Decayed number of recorded outcomes
+	OpenUntil time.Time     // Service is ejected from selection until then (zero if closed)
+	Trips     int           // Consecutive circuit trips
+}
+
+// IsOpen reports whether the circuit is open at now
+func (h *Health) IsOpen(now time.Time) bool {
+	return h.OpenUntil.After(now)
+}
+
+// Tracker records render outcomes per render service in Redis. Health is shared by all
+// EGs and read by the service selection script.
+type Tracker struct {
+	redis   *redis.Client
+	cfg     *configtypes.RSHealthConfig
+	metrics *Metrics
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewTracker creates a Tracker. Returns nil when health tracking is disabled; a nil
+// Tracker records nothing.
+func NewTracker(redisClient *redis.Client, cfg *configtypes.RSHealthConfig, metrics *Metrics, logger *zap.Logger) *Tracker {
+	if !cfg.IsEnabled() {
+		return nil
+	}
+	return &Tracker{
+		redis:   redisClient,
+		cfg:     cfg,
+		metrics: metrics,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Record adds an outcome of serviceID and opens its circuit if it is unhealthy
+func (t *Tracker) Record(ctx context.Context, serviceID string, outcome Outcome) {
+	if t == nil || serviceID == "" {
+		return
+	}
+	t.metrics.recordOutcome(serviceID, outcome)
+
+	renderMs := int64(-1)
+	if outcome.RenderTime > 0 {
+		renderMs = outcome.RenderTime.Milliseconds()
+	}
+	ttl := 10 * t.cfg.GetHalfLife()
+	if max := t.cfg.GetMaxOpenDuration() * 2; ttl < max {
+		ttl = max
+	}
+
+	args := []interface{}{
+		t.now().UnixMilli(),
+		t.cfg.GetHalfLife().Milliseconds(),
+		boolToInt(outcome.Success),
+		boolToInt(outcome.HardTimeout),
+		renderMs,
+		t.cfg.GetMinRequests(),
+		t.cfg.GetMinSuccessRate(),
+		t.cfg.GetMaxHardTimeoutRate(),
+		t.cfg.GetOpenDuration().Milliseconds(),
+		t.cfg.GetMaxOpenDuration().Milliseconds(),
+		int64(ttl.Seconds()),
+	}
+	for _, bound := range renderTimeBuckets {
+		args = append(args, bound)
+	}
+
+	result, err := t.redis.Eval(ctx, recordScript, []string{KeyPrefix + serviceID}, args...)
+	if err != nil {
+		t.logger.Warn("Failed to record render service outcome",
+			zap.String("rs", serviceID),
+			zap.Error(err))
+		return
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) < 2 {
+		return
+	}
+	if opened, _ := values[0].(int64); opened == 1 {
+		openMs, _ := values[1].(int64)
+		t.metrics.recordTrip(serviceID)
+		t.logger.Warn("Render service circuit opened, ejecting it from selection",
+			zap.String("rs", serviceID),
+			zap.Duration("open_duration", time.Duration(openMs)*time.Millisecond))
+	}
+}
+
+// Get returns the recorded health of serviceID (nil if nothing was recorded)
+func (t *Tracker) Get(ctx context.Context, serviceID string) (*Health, error) {
+	data, err := t.redis.HGetAll(ctx, KeyPrefix+serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get render service health: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	parse := func(field string) float64 {
+		v, _ := strconv.ParseFloat(data[field], 64)
+		return v
+	}
+	health := &Health{
+		Score:    parse("score"),
+		P95:      time.Duration(parse("p95_ms")) * time.Millisecond,
+		Requests: parse("total"),
+		Trips:    int(parse("trips")),
+	}
+	if openUntil := int64(parse("open_until")); openUntil > 0 {
+		health.OpenUntil = time.UnixMilli(openUntil)
+	}
+	return health, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
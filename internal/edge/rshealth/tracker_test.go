@@ -0,0 +1,248 @@
+package rshealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/rsclient"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func setupTestRedis(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+	return redisClient, mr
+}
+
+func newTestTracker(t *testing.T, redisClient *redis.Client, cfg *configtypes.RSHealthConfig) *Tracker {
+	t.Helper()
+	tracker := NewTracker(redisClient, cfg, NewMetricsWithRegistry("test", prometheus.NewRegistry()), zap.NewNop())
+	require.NotNil(t, tracker)
+	return tracker
+}
+
+// registerService adds a render service with free tabs, as the render service registry does
+func registerService(t *testing.T, mr *miniredis.Miniredis, id string, load, capacity int) {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"id":       id,
+		"address":  "127.0.0.1",
+		"port":     8080,
+		"capacity": capacity,
+		"load":     load,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mr.Set("service:render:"+id, string(data)))
+	for tab := 0; tab < capacity-load; tab++ {
+		mr.HSet("tabs:"+id, strconv.Itoa(tab), "")
+	}
+}
+
+func selectService(t *testing.T, redisClient *redis.Client, strategy string, now time.Time) string {
+	t.Helper()
+	return selectServiceExcluding(t, redisClient, strategy, now, "")
+}
+
+func selectServiceExcluding(t *testing.T, redisClient *redis.Client, strategy string, now time.Time, excluded string) string {
+	t.Helper()
+	result, err := redisClient.Eval(context.Background(), SelectAndReserveScript, []string{}, "req-1", strategy, 2, now.UnixMilli(), excluded)
+	require.NoError(t, err)
+	values := result.([]interface{})
+	serviceID, _ := values[0].(string)
+	return serviceID
+}
+
+func TestOutcomeFromResponse(t *testing.T) {
+	ctx := context.Background()
+
+	outcome, ok := OutcomeFromResponse(ctx, &types.RenderResponse{Success: true, RenderTime: time.Second}, nil)
+	require.True(t, ok)
+	assert.Equal(t, Outcome{Success: true, RenderTime: time.Second}, outcome)
+
+	outcome, ok = OutcomeFromResponse(ctx, &types.RenderResponse{ErrorType: types.ErrorTypeHardTimeout}, nil)
+	require.True(t, ok)
+	assert.True(t, outcome.HardTimeout)
+
+	outcome, ok = OutcomeFromResponse(ctx, nil, errors.New("connection refused"))
+	require.True(t, ok)
+	assert.False(t, outcome.Success)
+
+	// Caller gave up: not the service's fault
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, ok = OutcomeFromResponse(cancelled, nil, context.Canceled)
+	assert.False(t, ok)
+
+	// Service did not answer by the request deadline
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	outcome, ok = OutcomeFromResponse(expired, nil, fmt.Errorf("HTTP request failed: %w", context.DeadlineExceeded))
+	require.True(t, ok)
+	assert.Equal(t, Outcome{HardTimeout: true}, outcome)
+}
+
+func TestTracker_HungServiceOpensCircuit(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	tracker := newTestTracker(t, redisClient, &configtypes.RSHealthConfig{
+		Enabled:      true,
+		MinRequests:  5,
+		OpenDuration: types.Duration(30 * time.Second),
+	})
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+
+	// The service accepts connections but never answers
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hung.Close()
+	defer close(release)
+	client := rsclient.NewRSClient(zap.NewNop())
+
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		resp, err := client.CallRenderService(ctx, hung.URL, &types.RenderRequest{RequestID: "req-1", URL: "https://example.com/"})
+		outcome, ok := OutcomeFromResponse(ctx, resp, err)
+		cancel()
+		require.Error(t, err)
+		require.True(t, ok, "deadline hits are recorded")
+		tracker.Record(context.Background(), "rs-1", outcome)
+	}
+
+	health, err := tracker.Get(context.Background(), "rs-1")
+	require.NoError(t, err)
+	assert.True(t, health.IsOpen(now), "hung service is ejected")
+}
+
+func TestTracker_DisabledIsNil(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	tracker := NewTracker(redisClient, &configtypes.RSHealthConfig{}, nil, zap.NewNop())
+	assert.Nil(t, tracker)
+
+	// A nil tracker records nothing
+	tracker.Record(context.Background(), "rs-1", Outcome{})
+}
+
+func TestTracker_OpensCircuit(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	tracker := newTestTracker(t, redisClient, &configtypes.RSHealthConfig{
+		Enabled:         true,
+		MinRequests:     10,
+		OpenDuration:    types.Duration(30 * time.Second),
+		MaxOpenDuration: types.Duration(time.Minute),
+	})
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 6; i++ {
+		tracker.Record(ctx, "rs-1", Outcome{Success: true, RenderTime: 800 * time.Millisecond})
+	}
+	health, err := tracker.Get(ctx, "rs-1")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, health.Score, 0.001)
+	assert.Equal(t, time.Second, health.P95)
+	assert.False(t, health.IsOpen(now))
+
+	// Hard timeouts push the service over the threshold once min_requests is reached
+	for i := 0; i < 4; i++ {
+		tracker.Record(ctx, "rs-1", Outcome{HardTimeout: true})
+	}
+	health, err = tracker.Get(ctx, "rs-1")
+	require.NoError(t, err)
+	assert.True(t, health.IsOpen(now))
+	assert.Equal(t, now.Add(30*time.Second).UnixMilli(), health.OpenUntil.UnixMilli())
+	assert.Equal(t, 1, health.Trips)
+
+	// Outcomes while open are dropped; counters restart once the circuit closes
+	tracker.Record(ctx, "rs-1", Outcome{})
+	now = now.Add(31 * time.Second)
+	for i := 0; i < 10; i++ {
+		tracker.Record(ctx, "rs-1", Outcome{})
+	}
+	health, err = tracker.Get(ctx, "rs-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, health.Trips)
+	assert.Equal(t, now.Add(time.Minute).UnixMilli(), health.OpenUntil.UnixMilli(), "doubled, capped at max_open_duration")
+}
+
+func TestTracker_Decay(t *testing.T) {
+	redisClient, _ := setupTestRedis(t)
+	tracker := newTestTracker(t, redisClient, &configtypes.RSHealthConfig{
+		Enabled:  true,
+		HalfLife: types.Duration(time.Minute),
+	})
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 8; i++ {
+		tracker.Record(ctx, "rs-1", Outcome{Success: true})
+	}
+	now = now.Add(2 * time.Minute)
+	tracker.Record(ctx, "rs-1", Outcome{Success: true})
+
+	health, err := tracker.Get(ctx, "rs-1")
+	require.NoError(t, err)
+	assert.InDelta(t, 3.0, health.Requests, 0.001, "8 outcomes two half-lives ago weigh 2")
+}
+
+func TestSelectAndReserveScript_Health(t *testing.T) {
+	redisClient, mr := setupTestRedis(t)
+	now := time.Now()
+	registerService(t, mr, "rs-1", 0, 4)
+	registerService(t, mr, "rs-2", 2, 4)
+
+	// Without health data, least_loaded picks the idle service
+	assert.Equal(t, "rs-1", selectService(t, redisClient, types.SelectionStrategyLeastLoaded, now))
+	mr.HSet("tabs:rs-1", "0", "")
+
+	// Open circuit ejects rs-1
+	openUntil := strconv.FormatInt(now.Add(time.Minute).UnixMilli(), 10)
+	mr.HSet(KeyPrefix+"rs-1", "open_until", openUntil)
+	assert.Equal(t, "rs-2", selectService(t, redisClient, types.SelectionStrategyLeastLoaded, now))
+
+	// Fail open when every candidate is ejected
+	mr.HSet(KeyPrefix+"rs-2", "open_until", openUntil)
+	assert.NotEmpty(t, selectService(t, redisClient, types.SelectionStrategyLeastLoaded, now))
+
+	// Healthiest weighs score and latency against load
+	mr.Del(KeyPrefix + "rs-1")
+	mr.Del(KeyPrefix + "rs-2")
+	mr.HSet(KeyPrefix+"rs-1", "score", "0.3", "p95_ms", "8000")
+	mr.HSet(KeyPrefix+"rs-2", "score", "1", "p95_ms", "2000")
+	assert.Equal(t, "rs-2", selectService(t, redisClient, types.SelectionStrategyHealthiest, now))
+}
+
+func TestSelectAndReserveScript_Excluded(t *testing.T) {
+	redisClient, mr := setupTestRedis(t)
+	now := time.Now()
+	registerService(t, mr, "rs-1", 0, 4)
+	registerService(t, mr, "rs-2", 2, 4)
+
+	assert.Equal(t, "rs-2", selectServiceExcluding(t, redisClient, types.SelectionStrategyLeastLoaded, now, "rs-1"))
+
+	// Exclusion is not subject to fail-open
+	assert.Empty(t, selectServiceExcluding(t, redisClient, types.SelectionStrategyLeastLoaded, now, "rs-1,rs-2"))
+}
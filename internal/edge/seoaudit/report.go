@@ -0,0 +1,214 @@
+package seoaudit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+const scanCount = 500
+
+// Finding aggregates the pages of a host that violate one rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity string   `json:"severity"`
+	Pages    int      `json:"pages"` // Affected cache entries (one per URL and dimension)
+	URLs     []string `json:"urls"`  // Sample of affected URLs
+}
+
+// Report is the SEO health of a host's cached pages
+type Report struct {
+	PagesAudited    int       `json:"pages_audited"`
+	PagesWithIssues int       `json:"pages_with_issues"`
+	Findings        []Finding `json:"findings"`
+}
+
+// Auditor builds per-host SEO reports from cache metadata in Redis
+type Auditor struct {
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+	logger       *zap.Logger
+}
+
+// NewAuditor creates a new Auditor
+func NewAuditor(redisClient *redis.Client, keyGenerator *redis.KeyGenerator, logger *zap.Logger) *Auditor {
+	return &Auditor{
+		redis:        redisClient,
+		keyGenerator: keyGenerator,
+		logger:       logger,
+	}
+}
+
+// HostReport scans the host's cache metadata and aggregates page-level and cross-page findings.
+// An empty dimension includes all dimensions. maxURLs limits the sample URLs of each finding.
+func (a *Auditor) HostReport(ctx context.Context, hostID int, dimension string, maxURLs int) (*Report, error) {
+	var entries []*cache.CacheMetadata
+	pattern := a.keyGenerator.HostMetadataScanPattern(hostID)
+
+	var cursor uint64
+	for {
+		keys, next, err := a.redis.Scan(ctx, cursor, pattern, scanCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cache metadata: %w", err)
+		}
+
+		for _, key := range keys {
+			data, err := a.redis.HGetAll(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read cache metadata %s: %w", key, err)
+			}
+			if len(data) == 0 {
+				continue
+			}
+
+			metadata := &cache.CacheMetadata{}
+			if err := metadata.FromHash(data); err != nil {
+				a.logger.Debug("Skipping invalid cache metadata", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			if dimension != "" && metadata.Dimension != dimension {
+				continue
+			}
+			entries = append(entries, metadata)
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return BuildReport(entries, maxURLs), nil
+}
+
+// BuildReport combines the page-level issues stored with each entry with the cross-page rules.
+// Rendered 200 pages are audited. Pages are only compared with pages of the same dimension.
+func BuildReport(entries []*cache.CacheMetadata, maxURLs int) *Report {
+	byURL := make(map[string]map[string]*cache.CacheMetadata)
+	for _, entry := range entries {
+		if byURL[entry.Dimension] == nil {
+			byURL[entry.Dimension] = make(map[string]*cache.CacheMetadata)
+		}
+		byURL[entry.Dimension][entry.URL] = entry
+	}
+
+	issues := make(map[*cache.CacheMetadata][]string)
+	byTitle := make(map[string][]*cache.CacheMetadata)
+	report := &Report{}
+
+	for _, entry := range entries {
+		if !audited(entry) {
+			continue
+		}
+		report.PagesAudited++
+		issues[entry] = append(issues[entry], entry.SEOIssues...)
+
+		// Pages excluded from the index do not compete for their title
+		if entry.Title != "" && entry.IndexStatus != int(types.IndexStatusBlockedByMeta) &&
+			entry.IndexStatus != int(types.IndexStatusNonCanonical) {
+			titleKey := entry.Dimension + "\x00" + entry.Title
+			byTitle[titleKey] = append(byTitle[titleKey], entry)
+		}
+
+		sameDimension := byURL[entry.Dimension]
+		if entry.CanonicalURL != "" && entry.CanonicalURL != entry.URL {
+			if target, ok := sameDimension[entry.CanonicalURL]; ok && target.StatusCode != 200 {
+				issues[entry] = append(issues[entry], RuleCanonicalNon200)
+			}
+		}
+		if !hreflangReciprocal(entry, sameDimension) {
+			issues[entry] = append(issues[entry], RuleHreflangNotReciprocal)
+		}
+	}
+
+	for _, group := range byTitle {
+		if len(group) < 2 {
+			continue
+		}
+		for _, entry := range group {
+			issues[entry] = append(issues[entry], RuleDuplicateTitle)
+		}
+	}
+
+	urlsByRule := make(map[string]map[string]struct{})
+	pagesByRule := make(map[string]int)
+	for entry, entryIssues := range issues {
+		if len(entryIssues) == 0 {
+			continue
+		}
+		report.PagesWithIssues++
+		for _, rule := range entryIssues {
+			pagesByRule[rule]++
+			if urlsByRule[rule] == nil {
+				urlsByRule[rule] = make(map[string]struct{})
+			}
+			urlsByRule[rule][entry.URL] = struct{}{}
+		}
+	}
+
+	report.Findings = make([]Finding, 0, len(pagesByRule))
+	for _, rs := range ruleSeverity {
+		if pagesByRule[rs.rule] == 0 {
+			continue
+		}
+		urls := make([]string, 0, len(urlsByRule[rs.rule]))
+		for u := range urlsByRule[rs.rule] {
+			urls = append(urls, u)
+		}
+		sort.Strings(urls)
+		if maxURLs > 0 && len(urls) > maxURLs {
+			urls = urls[:maxURLs]
+		}
+		report.Findings = append(report.Findings, Finding{
+			Rule:     rs.rule,
+			Severity: rs.severity,
+			Pages:    pagesByRule[rs.rule],
+			URLs:     urls,
+		})
+	}
+	return report
+}
+
+// audited reports whether a cache entry is a rendered page subject to the audit
+func audited(entry *cache.CacheMetadata) bool {
+	return entry.Source == cache.SourceRender && entry.StatusCode == 200
+}
+
+// hreflangReciprocal reports whether every cached 200 alternate of the page links back to it.
+// Alternates that are not cached are not checked.
+func hreflangReciprocal(entry *cache.CacheMetadata, sameDimension map[string]*cache.CacheMetadata) bool {
+	for _, alternate := range hreflang(entry) {
+		if alternate.URL == entry.URL {
+			continue
+		}
+		target, ok := sameDimension[alternate.URL]
+		if !ok || !audited(target) {
+			continue
+		}
+		linksBack := false
+		for _, back := range hreflang(target) {
+			if back.URL == entry.URL {
+				linksBack = true
+				break
+			}
+		}
+		if !linksBack {
+			return false
+		}
+	}
+	return true
+}
+
+// hreflang returns the hreflang alternates from the entry's SEO snapshot
+func hreflang(entry *cache.CacheMetadata) []types.HreflangEntry {
+	if entry.SEO == nil {
+		return nil
+	}
+	return entry.SEO.Hreflang
+}
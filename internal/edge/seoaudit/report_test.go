@@ -0,0 +1,120 @@
+package seoaudit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func renderEntry(url, dimension, title string) *cache.CacheMetadata {
+	return &cache.CacheMetadata{
+		URL:         url,
+		Dimension:   dimension,
+		Source:      cache.SourceRender,
+		StatusCode:  200,
+		Title:       title,
+		IndexStatus: int(types.IndexStatusIndexable),
+	}
+}
+
+func findingsByRule(report *Report) map[string]Finding {
+	findings := make(map[string]Finding, len(report.Findings))
+	for _, f := range report.Findings {
+		findings[f.Rule] = f
+	}
+	return findings
+}
+
+func TestBuildReport(t *testing.T) {
+	home := renderEntry("https://example.com/", "desktop", "Shop")
+	home.SEOIssues = []string{RuleMissingH1}
+	shoes := renderEntry("https://example.com/shoes", "desktop", "Shop")
+	// Same title in another dimension does not collide with desktop pages
+	mobileShoes := renderEntry("https://example.com/shoes", "mobile", "Shoes")
+	// Filtered variant declares the canonical: excluded from duplicate titles
+	filtered := renderEntry("https://example.com/shoes?color=red", "desktop", "Shop")
+	filtered.IndexStatus = int(types.IndexStatusNonCanonical)
+	filtered.CanonicalURL = "https://example.com/shoes"
+
+	oldPage := renderEntry("https://example.com/old", "desktop", "Old")
+	oldPage.CanonicalURL = "https://example.com/moved"
+	moved := &cache.CacheMetadata{URL: "https://example.com/moved", Dimension: "desktop", Source: cache.SourceRender, StatusCode: 301}
+
+	en := renderEntry("https://example.com/en", "desktop", "English")
+	en.SEO = &types.PageSEO{Hreflang: []types.HreflangEntry{{Lang: "en", URL: "https://example.com/en"}, {Lang: "de", URL: "https://example.com/de"}}}
+	de := renderEntry("https://example.com/de", "desktop", "Deutsch")
+	de.SEO = &types.PageSEO{Hreflang: []types.HreflangEntry{{Lang: "de", URL: "https://example.com/de"}}}
+	fr := renderEntry("https://example.com/fr", "desktop", "Français")
+	fr.SEO = &types.PageSEO{Hreflang: []types.HreflangEntry{{Lang: "fr", URL: "https://example.com/fr"}, {Lang: "es", URL: "https://example.com/es"}}}
+
+	report := BuildReport([]*cache.CacheMetadata{home, shoes, mobileShoes, filtered, oldPage, moved, en, de, fr}, 0)
+	assert.Equal(t, 8, report.PagesAudited, "redirects are not audited")
+	assert.Equal(t, 4, report.PagesWithIssues)
+
+	findings := findingsByRule(report)
+	require.Len(t, findings, 4)
+	assert.Equal(t, Finding{Rule: RuleMissingH1, Severity: SeverityWarning, Pages: 1, URLs: []string{"https://example.com/"}}, findings[RuleMissingH1])
+	assert.Equal(t, []string{"https://example.com/", "https://example.com/shoes"}, findings[RuleDuplicateTitle].URLs)
+	assert.Equal(t, 2, findings[RuleDuplicateTitle].Pages)
+	assert.Equal(t, []string{"https://example.com/old"}, findings[RuleCanonicalNon200].URLs)
+	assert.Equal(t, SeverityError, findings[RuleCanonicalNon200].Severity)
+	assert.Equal(t, []string{"https://example.com/en"}, findings[RuleHreflangNotReciprocal].URLs,
+		"alternates that are not cached are not checked")
+
+	assert.Equal(t, RuleCanonicalNon200, report.Findings[0].Rule, "findings are ordered by severity")
+
+	t.Run("sample urls are limited", func(t *testing.T) {
+		report := BuildReport([]*cache.CacheMetadata{home, shoes}, 1)
+		assert.Equal(t, 2, findingsByRule(report)[RuleDuplicateTitle].Pages)
+		assert.Equal(t, []string{"https://example.com/"}, findingsByRule(report)[RuleDuplicateTitle].URLs)
+	})
+}
+
+func TestAuditor_HostReport(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+	keyGenerator := redis.NewKeyGenerator()
+	auditor := NewAuditor(redisClient, keyGenerator, zap.NewNop())
+
+	store := func(hostID, dimensionID int, urlHash string, metadata *cache.CacheMetadata) {
+		cacheKey := &types.CacheKey{HostID: hostID, DimensionID: dimensionID, URLHash: urlHash}
+		now := time.Now().UTC()
+		metadata.Key = cacheKey.String()
+		metadata.HostID = hostID
+		metadata.CreatedAt, metadata.ExpiresAt, metadata.LastAccess = now, now.Add(time.Hour), now
+		require.NoError(t, redisClient.HSet(context.Background(), keyGenerator.GenerateMetadataKey(cacheKey), metadata.ToHash()))
+	}
+
+	thin := renderEntry("https://example.com/thin", "desktop", "Thin")
+	thin.SEOIssues = []string{RuleLowWordCount}
+	store(1, 1, "a", thin)
+	mobileThin := renderEntry("https://example.com/thin", "mobile", "Thin")
+	mobileThin.SEOIssues = []string{RuleLowWordCount}
+	store(1, 2, "a", mobileThin)
+	store(2, 1, "a", renderEntry("https://other.com/", "desktop", ""))
+
+	report, err := auditor.HostReport(context.Background(), 1, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.PagesAudited)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, 2, report.Findings[0].Pages)
+	assert.Equal(t, []string{"https://example.com/thin"}, report.Findings[0].URLs)
+
+	report, err = auditor.HostReport(context.Background(), 1, "mobile", 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.PagesAudited)
+}
@@ -0,0 +1,89 @@
+package seoaudit
+
+import (
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// Page-level rules, evaluated on render from the page's own SEO metadata
+const (
+	RuleMissingTitle     = "missing_title"
+	RuleMissingH1        = "missing_h1"
+	RuleMultipleH1       = "multiple_h1"
+	RuleNoindex          = "noindex"
+	RuleImagesMissingAlt = "images_missing_alt"
+	RuleEmptyRender      = "empty_render"
+	RuleLowWordCount     = "low_word_count"
+)
+
+// Cross-page rules, evaluated over all cached pages of a host
+const (
+	RuleDuplicateTitle        = "duplicate_title"
+	RuleCanonicalNon200       = "canonical_non200"
+	RuleHreflangNotReciprocal = "hreflang_not_reciprocal"
+)
+
+// Severity levels of rules
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ruleSeverity lists all rules in report order
+var ruleSeverity = []struct {
+	rule     string
+	severity string
+}{
+	{RuleEmptyRender, SeverityError},
+	{RuleMissingTitle, SeverityError},
+	{RuleCanonicalNon200, SeverityError},
+	{RuleNoindex, SeverityWarning},
+	{RuleDuplicateTitle, SeverityWarning},
+	{RuleMissingH1, SeverityWarning},
+	{RuleMultipleH1, SeverityWarning},
+	{RuleHreflangNotReciprocal, SeverityWarning},
+	{RuleImagesMissingAlt, SeverityWarning},
+	{RuleLowWordCount, SeverityWarning},
+}
+
+// IsPageRule reports whether rule is evaluated on render and stored with the cache entry
+func IsPageRule(rule string) bool {
+	switch rule {
+	case RuleMissingTitle, RuleMissingH1, RuleMultipleH1, RuleNoindex,
+		RuleImagesMissingAlt, RuleEmptyRender, RuleLowWordCount:
+		return true
+	}
+	return false
+}
+
+// Evaluate runs the page-level rules against a rendered page.
+// Only 200 responses are audited; other status codes return no issues.
+func Evaluate(seo *types.PageSEO, statusCode int, cfg *types.SEOAuditConfig) []string {
+	if seo == nil || statusCode != 200 {
+		return nil
+	}
+
+	var issues []string
+	if seo.WordCount == 0 {
+		issues = append(issues, RuleEmptyRender)
+	}
+	if seo.Title == "" {
+		issues = append(issues, RuleMissingTitle)
+	}
+	if seo.IndexStatus == types.IndexStatusBlockedByMeta {
+		issues = append(issues, RuleNoindex)
+	}
+	switch {
+	case len(seo.H1s) == 0:
+		issues = append(issues, RuleMissingH1)
+	case len(seo.H1s) > 1:
+		issues = append(issues, RuleMultipleH1)
+	}
+	if seo.ImagesWithoutAlt > cfg.GetMaxImagesWithoutAlt() {
+		issues = append(issues, RuleImagesMissingAlt)
+	}
+	// Empty pages are reported once, as empty_render
+	if seo.WordCount > 0 && seo.WordCount < cfg.GetMinWordCount() {
+		issues = append(issues, RuleLowWordCount)
+	}
+	return issues
+}
@@ -0,0 +1,66 @@
+package seoaudit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestEvaluate(t *testing.T) {
+	healthy := types.PageSEO{
+		Title:       "Product",
+		IndexStatus: types.IndexStatusIndexable,
+		H1s:         []string{"Product"},
+		WordCount:   500,
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(seo *types.PageSEO)
+		statusCode int
+		cfg        *types.SEOAuditConfig
+		want       []string
+	}{
+		{"healthy page", func(seo *types.PageSEO) {}, 200, nil, nil},
+		{"non-200 pages are not audited", func(seo *types.PageSEO) { seo.Title = "" }, 404, nil, nil},
+		{"missing title", func(seo *types.PageSEO) { seo.Title = "" }, 200, nil, []string{RuleMissingTitle}},
+		{"missing h1", func(seo *types.PageSEO) { seo.H1s = nil }, 200, nil, []string{RuleMissingH1}},
+		{"multiple h1", func(seo *types.PageSEO) { seo.H1s = []string{"A", "B"} }, 200, nil, []string{RuleMultipleH1}},
+		{"noindex", func(seo *types.PageSEO) { seo.IndexStatus = types.IndexStatusBlockedByMeta }, 200, nil, []string{RuleNoindex}},
+		{"images without alt", func(seo *types.PageSEO) { seo.ImagesWithoutAlt = 1 }, 200, nil, []string{RuleImagesMissingAlt}},
+		{
+			"images without alt within threshold",
+			func(seo *types.PageSEO) { seo.ImagesWithoutAlt = 3 },
+			200, &types.SEOAuditConfig{Enabled: true, MaxImagesWithoutAlt: 3}, nil,
+		},
+		{"low word count", func(seo *types.PageSEO) { seo.WordCount = 99 }, 200, nil, []string{RuleLowWordCount}},
+		{
+			"custom min word count",
+			func(seo *types.PageSEO) { seo.WordCount = 99 },
+			200, &types.SEOAuditConfig{Enabled: true, MinWordCount: 50}, nil,
+		},
+		{
+			"empty render is not also low word count",
+			func(seo *types.PageSEO) { seo.WordCount = 0; seo.Title = ""; seo.H1s = nil },
+			200, nil, []string{RuleEmptyRender, RuleMissingTitle, RuleMissingH1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seo := healthy
+			tt.modify(&seo)
+			assert.Equal(t, tt.want, Evaluate(&seo, tt.statusCode, tt.cfg))
+		})
+	}
+
+	assert.Nil(t, Evaluate(nil, 200, nil))
+}
+
+func TestIsPageRule(t *testing.T) {
+	assert.True(t, IsPageRule(RuleMissingH1))
+	assert.False(t, IsPageRule(RuleDuplicateTitle), "cross-page rules are not stored with entries")
+	assert.False(t, IsPageRule("unknown"))
+}
@@ -1,17 +1,24 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 
+	"github.com/edgecomet/engine/internal/common/config"
+	"github.com/edgecomet/engine/internal/common/configtypes"
 	"github.com/edgecomet/engine/internal/edge/clientip"
 	"github.com/edgecomet/engine/internal/edge/edgectx"
 	"github.com/edgecomet/engine/internal/edge/events"
+	"github.com/edgecomet/engine/internal/edge/llmstxt"
 	"github.com/edgecomet/engine/internal/edge/orchestrator"
+	"github.com/edgecomet/engine/internal/edge/popularity"
+	"github.com/edgecomet/engine/pkg/types"
 )
 
 // requestError represents an error with HTTP status code and metrics category
@@ -161,6 +168,98 @@ func (s *Server) handleStatusAction(renderCtx *edgectx.RenderContext, start time
 	return nil
 }
 
+// isLLMsTxtRequest reports whether the target URL is the host's /llms.txt
+func isLLMsTxtRequest(targetURL string) bool {
+	parsed, err := url.Parse(targetURL)
+	return err == nil && parsed.Path == llmstxt.Path
+}
+
+// handleLLMsTxt serves the llms.txt file generated from the host's cached pages
+func (s *Server) handleLLMsTxt(ctx *fasthttp.RequestCtx, renderCtx *edgectx.RenderContext, start time.Time) error {
+	content, err := s.llmsTxtGenerator.Get(ctx, renderCtx.Host, time.Now())
+	if err != nil {
+		duration := time.Since(start)
+		reqErr := &requestError{
+			statusCode: fasthttp.StatusInternalServerError,
+			message:    "Internal server error",
+			category:   "llms_txt_error",
+		}
+		s.handleRequestError(ctx, renderCtx, err, reqErr, duration)
+		return err
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.Response.Header.Set("Content-Type", llmstxt.ContentType)
+	ctx.Response.Header.Set("X-Render-Source", "llms_txt")
+	ctx.SetBody(content)
+
+	duration := time.Since(start)
+	s.metricsCollector.RecordRequest(renderCtx.Host.Domain, "", "llms_txt", duration)
+
+	renderCtx.Logger.Info("END Served generated llms.txt",
+		zap.Int("bytes_served", len(content)),
+		zap.Duration("duration", duration))
+
+	return nil
+}
+
+// applyRobotsTxt annotates the request with the robots.txt verdict for the requesting bot.
+// Disallowed paths are answered with the host's disallowed_status, unless a URL rule
+// already returns a status. Requests are handled normally if robots.txt is unavailable.
+func (s *Server) applyRobotsTxt(renderCtx *edgectx.RenderContext, targetURL string) {
+	cfg := renderCtx.Host.RobotsTxt
+	if !cfg.IsEnabled() {
+		return
+	}
+
+	verdict, ok := s.robotsFetcher.Check(targetURL, string(renderCtx.HTTPCtx.UserAgent()), cfg.GetTTL(), time.Now())
+	if !ok {
+		return
+	}
+	renderCtx.RobotsVerdict = verdict.String()
+	renderCtx.RobotsGroup = verdict.Group
+
+	if verdict.Allowed || cfg.DisallowedStatus == 0 || renderCtx.ResolvedConfig.Action.IsStatusAction() {
+		return
+	}
+
+	renderCtx.Logger.Info("Path disallowed by robots.txt, returning configured status",
+		zap.String("robots_group", verdict.Group),
+		zap.Int("status_code", cfg.DisallowedStatus))
+	renderCtx.ResolvedConfig.Action = types.ActionStatus
+	renderCtx.ResolvedConfig.Status = config.ResolvedStatusConfig{
+		Code:   cfg.DisallowedStatus,
+		Reason: "Disallowed by robots.txt",
+	}
+}
+
+// applyPopularity records a hit for the request's cache key and scales the resolved
+// cache TTL by the URL's decayed hit score. Returns the score and false if adaptive
+// popularity is disabled or the score could not be recorded (static TTL is kept).
+func (s *Server) applyPopularity(ctx context.Context, renderCtx *edgectx.RenderContext, cfg *configtypes.PopularityConfig) (float64, bool) {
+	if !cfg.IsEnabled() || s.popularityTracker == nil {
+		return 0, false
+	}
+
+	score, err := s.popularityTracker.RecordHit(ctx, renderCtx.CacheKey, cfg.GetHalfLife(), time.Now())
+	if err != nil {
+		renderCtx.Logger.Warn("Failed to record popularity hit, using static TTL",
+			zap.String("cache_key", renderCtx.CacheKey.String()),
+			zap.Error(err))
+		return 0, false
+	}
+
+	baseTTL := renderCtx.ResolvedConfig.Cache.TTL
+	renderCtx.ResolvedConfig.Cache.TTL = popularity.AdjustTTL(cfg, baseTTL, score)
+
+	renderCtx.Logger.Debug("Popularity applied",
+		zap.Float64("score", score),
+		zap.Duration("base_ttl", baseTTL),
+		zap.Duration("adjusted_ttl", renderCtx.ResolvedConfig.Cache.TTL))
+
+	return score, true
+}
+
 // recordResultMetrics records metrics based on render result source
 func (s *Server) recordResultMetrics(renderCtx *edgectx.RenderContext, result *orchestrator.RenderResult, duration time.Duration) string {
 	host := renderCtx.Host
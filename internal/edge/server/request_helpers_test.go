@@ -1,6 +1,8 @@
 package server
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -9,12 +11,15 @@ import (
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 
+	"github.com/edgecomet/engine/internal/common/config"
 	"github.com/edgecomet/engine/internal/common/configtypes"
 	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/bypass"
 	"github.com/edgecomet/engine/internal/edge/device"
 	"github.com/edgecomet/engine/internal/edge/edgectx"
 	"github.com/edgecomet/engine/internal/edge/events"
 	"github.com/edgecomet/engine/internal/edge/metrics"
+	"github.com/edgecomet/engine/internal/edge/robots"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -195,3 +200,74 @@ func TestHandleRequestError(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
 	assert.Contains(t, string(ctx.Response.Body()), "Invalid URL")
 }
+
+func TestIsLLMsTxtRequest(t *testing.T) {
+	assert.True(t, isLLMsTxtRequest("https://test.com/llms.txt"))
+	assert.True(t, isLLMsTxtRequest("https://test.com/llms.txt?v=1"))
+	assert.False(t, isLLMsTxtRequest("https://test.com/docs/llms.txt"))
+	assert.False(t, isLLMsTxtRequest("https://test.com/llms-full.txt"))
+	assert.False(t, isLLMsTxtRequest("https://test.com/"))
+}
+
+func TestApplyRobotsTxt(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n\nUser-agent: Googlebot\nDisallow: /drafts\n"))
+	}))
+	t.Cleanup(origin.Close)
+
+	ssrfProtection := false
+	bypassSvc := bypass.NewBypassService(&config.GlobalBypassConfig{SSRFProtection: &ssrfProtection}, zap.NewNop())
+	server := &Server{robotsFetcher: robots.NewFetcher(bypassSvc, zap.NewNop())}
+
+	newRenderCtx := func(robotsTxt *types.RobotsTxtConfig, action types.URLRuleAction) *edgectx.RenderContext {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetUserAgent("Mozilla/5.0 (compatible; Googlebot/2.1)")
+		host := getTestHost()
+		host.RobotsTxt = robotsTxt
+		renderCtx := edgectx.NewRenderContext("test-req", ctx, zap.NewNop(), 30*time.Second)
+		renderCtx.WithHost(host)
+		renderCtx.ResolvedConfig = &config.ResolvedConfig{Action: action}
+		return renderCtx
+	}
+
+	t.Run("annotates verdict for the bot's group", func(t *testing.T) {
+		renderCtx := newRenderCtx(&types.RobotsTxtConfig{Enabled: true}, types.ActionRender)
+		server.applyRobotsTxt(renderCtx, origin.URL+"/drafts/post")
+
+		assert.Equal(t, robots.VerdictDisallow, renderCtx.RobotsVerdict)
+		assert.Equal(t, "googlebot", renderCtx.RobotsGroup)
+		assert.Equal(t, types.ActionRender, renderCtx.ResolvedConfig.Action, "no disallowed_status keeps the action")
+	})
+
+	t.Run("serves disallowed_status", func(t *testing.T) {
+		renderCtx := newRenderCtx(&types.RobotsTxtConfig{Enabled: true, DisallowedStatus: 403}, types.ActionRender)
+		server.applyRobotsTxt(renderCtx, origin.URL+"/drafts/post")
+
+		assert.Equal(t, types.ActionStatus, renderCtx.ResolvedConfig.Action)
+		assert.Equal(t, 403, renderCtx.ResolvedConfig.Status.Code)
+	})
+
+	t.Run("allowed path keeps action", func(t *testing.T) {
+		renderCtx := newRenderCtx(&types.RobotsTxtConfig{Enabled: true, DisallowedStatus: 403}, types.ActionRender)
+		server.applyRobotsTxt(renderCtx, origin.URL+"/private/page")
+
+		assert.Equal(t, robots.VerdictAllow, renderCtx.RobotsVerdict)
+		assert.Equal(t, types.ActionRender, renderCtx.ResolvedConfig.Action)
+	})
+
+	t.Run("url rule status takes precedence", func(t *testing.T) {
+		renderCtx := newRenderCtx(&types.RobotsTxtConfig{Enabled: true, DisallowedStatus: 403}, types.ActionStatus410)
+		renderCtx.ResolvedConfig.Status = config.ResolvedStatusConfig{Code: 410}
+		server.applyRobotsTxt(renderCtx, origin.URL+"/drafts/post")
+
+		assert.Equal(t, types.ActionStatus410, renderCtx.ResolvedConfig.Action)
+		assert.Equal(t, 410, renderCtx.ResolvedConfig.Status.Code)
+	})
+
+	t.Run("disabled leaves request untouched", func(t *testing.T) {
+		renderCtx := newRenderCtx(nil, types.ActionRender)
+		server.applyRobotsTxt(renderCtx, origin.URL+"/drafts/post")
+
+		assert.Empty(t, renderCtx.RobotsVerdict)
+	})
+}
@@ -21,8 +21,11 @@ import (
 	"github.com/edgecomet/engine/internal/edge/edgectx"
 	"github.com/edgecomet/engine/internal/edge/events"
 	"github.com/edgecomet/engine/internal/edge/hash"
+	"github.com/edgecomet/engine/internal/edge/llmstxt"
 	"github.com/edgecomet/engine/internal/edge/metrics"
 	"github.com/edgecomet/engine/internal/edge/orchestrator"
+	"github.com/edgecomet/engine/internal/edge/popularity"
+	"github.com/edgecomet/engine/internal/edge/robots"
 	"github.com/edgecomet/engine/internal/edge/sharding"
 	"github.com/edgecomet/engine/pkg/types"
 )
@@ -41,6 +44,9 @@ type Server struct {
 	shardingManager    *sharding.Manager
 	metadataStore      *cache.MetadataStore
 	autorecacheClient  *cachedaemon.AutorecacheClient
+	popularityTracker  *popularity.Tracker
+	llmsTxtGenerator   *llmstxt.Generator
+	robotsFetcher      *robots.Fetcher
 
 	// Event logging (nil if disabled)
 	eventEmitter events.EventEmitter
@@ -59,6 +65,7 @@ func NewServer(
 	shardingManager *sharding.Manager,
 	metadataStore *cache.MetadataStore,
 	autorecacheClient *cachedaemon.AutorecacheClient,
+	popularityTracker *popularity.Tracker,
 	eventEmitter events.EventEmitter,
 	instanceID string,
 ) *Server {
@@ -74,11 +81,18 @@ func NewServer(
 		shardingManager:    shardingManager,
 		metadataStore:      metadataStore,
 		autorecacheClient:  autorecacheClient,
+		popularityTracker:  popularityTracker,
+		llmsTxtGenerator:   llmstxt.NewGenerator(redisClient, keyGenerator, logger),
 		eventEmitter:       eventEmitter,
 		instanceID:         instanceID,
 	}
 }
 
+// SetRobotsFetcher enables robots.txt checks for hosts with robots_txt enabled
+func (s *Server) SetRobotsFetcher(fetcher *robots.Fetcher) {
+	s.robotsFetcher = fetcher
+}
+
 func (s *Server) HandleRequest(ctx *fasthttp.RequestCtx) {
 	// Extract custom request ID from header (if provided)
 	customRequestID := string(ctx.Request.Header.Peek("X-Request-ID"))
@@ -169,6 +183,11 @@ func (s *Server) processRenderRequest(ctx *fasthttp.RequestCtx, requestID string
 	extractedIP := clientip.Extract(ctx, clientIPHeaders)
 	renderCtx.WithClientIP(extractedIP)
 
+	// Serve the generated llms.txt instead of rendering the page
+	if host.LLMsTxt.IsEnabled() && isLLMsTxtRequest(targetURL) {
+		return s.handleLLMsTxt(ctx, renderCtx, start)
+	}
+
 	// Detect device dimension (needed for config resolution)
 	dimension, dimensionMatched := s.deviceDetector.DetectDimension(renderCtx)
 
@@ -230,6 +249,12 @@ func (s *Server) processRenderRequest(ctx *fasthttp.RequestCtx, requestID string
 		}
 	}
 
+	// Select HTML or Markdown output for the final dimension
+	renderCtx.OutputFormat = orchestrator.SelectOutputFormat(renderCtx, dimConfig)
+	if resolved.Render.Markdown.AcceptHeader {
+		ctx.Response.Header.Add("Vary", "Accept")
+	}
+
 	// Normalize URL (includes tracking param stripping if enabled)
 	normalizer := hash.NewURLNormalizer()
 	var stripPatterns []config.CompiledStripPattern
@@ -266,6 +291,9 @@ func (s *Server) processRenderRequest(ctx *fasthttp.RequestCtx, requestID string
 	// Store in context
 	renderCtx.WithProcessedURL(normalizeResult.NormalizedURL).WithURLHash(urlHash)
 
+	// Check the requested path against robots.txt (may switch to the configured status action)
+	s.applyRobotsTxt(renderCtx, targetURL)
+
 	// Handle status actions before dimension branching
 	if resolved.Action.IsStatusAction() {
 		return s.handleStatusAction(renderCtx, start)
@@ -296,6 +324,9 @@ func (s *Server) processRenderRequest(ctx *fasthttp.RequestCtx, requestID string
 
 	renderCtx.WithCacheKey(cacheKey).WithLockKey(lockKey)
 
+	// Track URL popularity and adapt cache TTL (no-op unless popularity is enabled)
+	popularityScore, popularityApplied := s.applyPopularity(ctx, renderCtx, globalConfig.Popularity)
+
 	// Process render request through orchestrator (handles cache, rendering, fallback)
 	result, err := s.renderOrchestrator.ProcessRenderRequest(renderCtx)
 	if err != nil {
@@ -323,8 +354,11 @@ func (s *Server) processRenderRequest(ctx *fasthttp.RequestCtx, requestID string
 				// Non-fatal error, continue
 			}
 
-			// Schedule autorecache
+			// Schedule autorecache (popular pages are recached sooner)
 			interval := renderCtx.ResolvedConfig.BothitRecache.Interval
+			if popularityApplied {
+				interval = popularity.AdjustRecacheInterval(globalConfig.Popularity, interval, popularityScore)
+			}
 			scheduledAt := now.Add(interval)
 			if err := s.autorecacheClient.ScheduleAutorecache(ctx, renderCtx.Host.ID, renderCtx.TargetURL, renderCtx.CacheKey.DimensionID, scheduledAt); err != nil {
 				renderCtx.Logger.Error("Failed to schedule autorecache",
@@ -332,7 +366,8 @@ func (s *Server) processRenderRequest(ctx *fasthttp.RequestCtx, requestID string
 				// Non-fatal error, continue serving the response
 			} else {
 				renderCtx.Logger.Debug("Bot detected on cache hit, autorecache scheduled",
-					zap.String("user_agent", userAgent))
+					zap.String("user_agent", userAgent),
+					zap.Duration("interval", interval))
 			}
 		}
 	}
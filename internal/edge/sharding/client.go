@@ -1,14 +1,19 @@
 package sharding
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 
+	"github.com/edgecomet/engine/internal/edge/internal_server"
 	"github.com/edgecomet/engine/pkg/types"
 )
 
@@ -25,16 +30,52 @@ type PullResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// PushRequest represents a request to push cache to another EG
+// PushRequest represents a request to push cache to another EG.
+// Content is streamed from SourcePath (the local, already compressed cache file).
 type PushRequest struct {
 	HostID      int       `json:"host_id"`
 	DimensionID int       `json:"dimension_id"`
 	URLHash     string    `json:"url_hash"`
-	Content     []byte    `json:"content"`
+	SourcePath  string    `json:"-"` // Absolute path of the local cache file to stream
 	CreatedAt   time.Time `json:"created_at"`
 	ExpiresAt   time.Time `json:"expires_at"`
 	RequestID   string    `json:"request_id"` // Origin request ID for tracing
 	FilePath    string    `json:"file_path"`  // Relative file path (includes compression extension)
+
+	// Filled by prepare from SourcePath
+	Size     int64  `json:"-"`
+	Checksum string `json:"-"`
+}
+
+// prepare computes size and checksum of the source file once per request
+func (r *PushRequest) prepare() error {
+	if r.Checksum != "" {
+		return nil
+	}
+	size, checksum, err := fileChecksum(r.SourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to read cache file: %w", err)
+	}
+	r.Size = size
+	r.Checksum = checksum
+	return nil
+}
+
+// shardMetadata builds the transfer header for the request
+func (r *PushRequest) shardMetadata() ShardMetadata {
+	return ShardMetadata{
+		CacheKey: types.CacheKey{
+			HostID:      r.HostID,
+			DimensionID: r.DimensionID,
+			URLHash:     r.URLHash,
+		},
+		CreatedAt: r.CreatedAt,
+		ExpiresAt: r.ExpiresAt,
+		RequestID: r.RequestID,
+		FilePath:  r.FilePath, // Includes compression extension
+		Size:      r.Size,
+		Checksum:  r.Checksum,
+	}
 }
 
 // PushResponse represents the response from a push request
@@ -42,9 +83,21 @@ type PushResponse struct {
 	Success bool `json:"success"`
 }
 
+// PushBatchResponse lists per-entry results of a batch push, in request order
+type PushBatchResponse struct {
+	Results []PushBatchResult `json:"results"`
+}
+
+// PushBatchResult is the outcome of a single batch entry
+type PushBatchResult struct {
+	CacheKey string `json:"cache_key"`
+	Error    string `json:"error,omitempty"`
+}
+
 // StatusResponse represents the response from a status request
 type StatusResponse struct {
 	EgID                 string   `json:"eg_id"`
+	Zone                 string   `json:"zone,omitempty"`
 	ShardingEnabled      bool     `json:"sharding_enabled"`
 	ReplicationFactor    int      `json:"replication_factor"`
 	DistributionStrategy string   `json:"distribution_strategy"`
@@ -57,18 +110,22 @@ type StatusResponse struct {
 
 // ShardMetadata represents metadata transferred in X-Shard-Metadata header
 type ShardMetadata struct {
-	CacheKey  types.CacheKey `json:"k"`  // k: cache key (host_id, dimension_id, url_hash)
-	CreatedAt time.Time      `json:"c"`  // c: created timestamp
-	ExpiresAt time.Time      `json:"e"`  // e: expires timestamp
-	RequestID string         `json:"r"`  // r: request ID for tracing
-	EgID      string         `json:"eg"` // eg: edge gateway ID
-	FilePath  string         `json:"fp"` // fp: relative file path (includes compression extension)
+	CacheKey  types.CacheKey `json:"k"`            // k: cache key (host_id, dimension_id, url_hash)
+	CreatedAt time.Time      `json:"c"`            // c: created timestamp
+	ExpiresAt time.Time      `json:"e"`            // e: expires timestamp
+	RequestID string         `json:"r"`            // r: request ID for tracing
+	EgID      string         `json:"eg"`           // eg: edge gateway ID
+	FilePath  string         `json:"fp"`           // fp: relative file path (includes compression extension)
+	Size      int64          `json:"sz,omitempty"` // sz: content size in bytes (as stored on disk)
+	Checksum  string         `json:"cs,omitempty"` // cs: xxhash64 of content (hex); empty from older EGs
 }
 
 // Client handles inter-EG HTTP communication
 type Client interface {
 	Pull(ctx context.Context, targetEgID string, req *PullRequest) (*PullResponse, error)
+	PullToFile(ctx context.Context, targetEgID string, req *PullRequest, absolutePath string) (int64, error)
 	Push(ctx context.Context, targetEgID string, req *PushRequest) error
+	PushBatch(ctx context.Context, targetEgID string, reqs []*PushRequest) ([]error, error)
 	Status(ctx context.Context, targetEgID string) (*StatusResponse, error)
 }
 
@@ -83,7 +140,8 @@ type FastHTTPClient struct {
 }
 
 // NewFastHTTPClient creates a new FastHTTP-based inter-EG client
-func NewFastHTTPClient(registry Registry, authKey string, protocol string, timeout time.Duration, logger *zap.Logger) *FastHTTPClient {
+// tlsConfig is used for https connections and may carry a client certificate for mTLS.
+func NewFastHTTPClient(registry Registry, authKey string, protocol string, tlsConfig *tls.Config, timeout time.Duration, logger *zap.Logger) *FastHTTPClient {
 	return &FastHTTPClient{
 		registry: registry,
 		authKey:  authKey,
@@ -92,15 +150,66 @@ func NewFastHTTPClient(registry Registry, authKey string, protocol string, timeo
 		httpClient: &fasthttp.Client{
 			ReadTimeout:  timeout,
 			WriteTimeout: timeout,
+			TLSConfig:    tlsConfig,
 
-			MaxIdleConnDuration: 500 * time.Millisecond,
+			// Keep peer connections warm: pushes and pulls hit the same few EGs repeatedly
+			MaxIdleConnDuration: interEgIdleConnDuration,
+			MaxConnsPerHost:     interEgMaxConnsPerHost,
 		},
 		logger: logger,
 	}
 }
 
-// Pull retrieves cache content from another EG using streaming
+// Pull retrieves cache content from another EG into memory (proxy-only serving).
+// Content is verified against the sender's checksum.
 func (c *FastHTTPClient) Pull(ctx context.Context, targetEgID string, req *PullRequest) (*PullResponse, error) {
+	var content []byte
+	metadata, err := c.pull(ctx, targetEgID, req, func(body io.Reader, metadata *ShardMetadata) (int64, error) {
+		var err error
+		content, err = io.ReadAll(body)
+		if err != nil {
+			return 0, err
+		}
+		if metadata.Checksum != "" {
+			if _, checksum, _ := readerChecksum(bytes.NewReader(content)); checksum != metadata.Checksum {
+				return 0, fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, metadata.Checksum, checksum)
+			}
+		}
+		return int64(len(content)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PullResponse{
+		Content:   content,
+		CreatedAt: metadata.CreatedAt,
+	}, nil
+}
+
+// PullToFile streams cache content from another EG to absolutePath without buffering
+// it in memory. The file is written atomically after size and checksum verification.
+func (c *FastHTTPClient) PullToFile(ctx context.Context, targetEgID string, req *PullRequest, absolutePath string) (int64, error) {
+	var written int64
+	_, err := c.pull(ctx, targetEgID, req, func(body io.Reader, metadata *ShardMetadata) (int64, error) {
+		size := int64(-1)
+		if metadata.Checksum != "" {
+			size = metadata.Size
+		}
+		var err error
+		written, err = receiveToFile(body, absolutePath, size, metadata.Checksum)
+		return written, err
+	})
+	return written, err
+}
+
+// pull performs a streamed pull request and hands the body to consume
+func (c *FastHTTPClient) pull(
+	ctx context.Context,
+	targetEgID string,
+	req *PullRequest,
+	consume func(body io.Reader, metadata *ShardMetadata) (int64, error),
+) (*ShardMetadata, error) {
 	// Get target EG address from registry
 	address, err := c.registry.GetEGAddress(ctx, targetEgID)
 	if err != nil {
@@ -120,13 +229,14 @@ func (c *FastHTTPClient) Pull(ctx context.Context, targetEgID string, req *PullR
 	httpReq.SetRequestURI(url)
 	httpReq.Header.SetMethod("GET")
 	httpReq.Header.Set("X-Internal-Auth", c.authKey)
+	httpReq.SetTimeout(c.timeout)
 
-	// Create FastHTTP response
+	// Stream the response body instead of buffering the whole file
 	httpResp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(httpResp)
+	httpResp.StreamBody = true
 
-	// Execute request with timeout
-	if err := c.httpClient.DoTimeout(httpReq, httpResp, c.timeout); err != nil {
+	if err := c.httpClient.Do(httpReq, httpResp); err != nil {
 		c.logger.Warn("PULL failed",
 			zap.String("peer", url),
 			zap.String("cache_key", cacheKey),
@@ -155,23 +265,24 @@ func (c *FastHTTPClient) Pull(ctx context.Context, targetEgID string, req *PullR
 		}
 	}
 
-	// Read HTML content from body (zero-copy)
-	htmlContent := httpResp.Body()
+	bytesReceived, err := consume(responseBody(httpResp), &metadata)
+	if err != nil {
+		c.logger.Warn("PULL transfer failed",
+			zap.String("peer", url),
+			zap.String("cache_key", cacheKey),
+			zap.Error(err))
+		return nil, err
+	}
 
 	c.logger.Info("PULL completed",
 		zap.String("peer", url),
 		zap.String("cache_key", cacheKey),
-		zap.Int("bytes", len(htmlContent)))
-
-	pullResp := &PullResponse{
-		Content:   htmlContent,
-		CreatedAt: metadata.CreatedAt,
-	}
+		zap.Int64("bytes", bytesReceived))
 
-	return pullResp, nil
+	return &metadata, nil
 }
 
-// Push sends cache content to another EG using streaming
+// Push streams the local cache file to another EG
 func (c *FastHTTPClient) Push(ctx context.Context, targetEgID string, req *PushRequest) error {
 	// Get target EG address from registry
 	address, err := c.registry.GetEGAddress(ctx, targetEgID)
@@ -179,29 +290,25 @@ func (c *FastHTTPClient) Push(ctx context.Context, targetEgID string, req *PushR
 		return fmt.Errorf("failed to get EG address: %w", err)
 	}
 
+	if err := req.prepare(); err != nil {
+		return err
+	}
+
 	// Build URL
 	url := fmt.Sprintf("%s://%s/internal/cache/push", c.protocol, address)
 
-	// Create metadata for header with CacheKey struct (JSON serialized)
-	metadata := ShardMetadata{
-		CacheKey: types.CacheKey{
-			HostID:      req.HostID,
-			DimensionID: req.DimensionID,
-			URLHash:     req.URLHash,
-		},
-		CreatedAt: req.CreatedAt,
-		ExpiresAt: req.ExpiresAt,
-		RequestID: req.RequestID,
-		EgID:      "",
-		FilePath:  req.FilePath, // Includes compression extension
-	}
-
-	// Marshal metadata to JSON for header
+	// Marshal metadata (with size and checksum) to JSON for header
+	metadata := req.shardMetadata()
 	metadataJSON, err := json.Marshal(metadata)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
+	file, err := os.Open(req.SourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to open cache file: %w", err)
+	}
+
 	// Create FastHTTP request
 	httpReq := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(httpReq)
@@ -211,16 +318,17 @@ func (c *FastHTTPClient) Push(ctx context.Context, targetEgID string, req *PushR
 	httpReq.Header.Set("X-Internal-Auth", c.authKey)
 	httpReq.Header.SetContentType("application/octet-stream")
 	httpReq.Header.Set("X-Shard-Metadata", string(metadataJSON))
+	httpReq.SetTimeout(c.timeout)
 
-	// Set body directly (zero-copy)
-	httpReq.SetBody(req.Content)
+	// Stream file body; fasthttp closes the file once sent
+	httpReq.SetBodyStream(file, int(req.Size))
 
 	// Create FastHTTP response
 	httpResp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(httpResp)
 
 	// Execute request with timeout
-	if err := c.httpClient.DoTimeout(httpReq, httpResp, c.timeout); err != nil {
+	if err := c.httpClient.Do(httpReq, httpResp); err != nil {
 		c.logger.Error("PUSH failed",
 			zap.String("peer", url),
 			zap.String("cache_key", metadata.CacheKey.String()),
@@ -241,12 +349,103 @@ func (c *FastHTTPClient) Push(ctx context.Context, targetEgID string, req *PushR
 	c.logger.Info("PUSH completed",
 		zap.String("peer", url),
 		zap.String("cache_key", metadata.CacheKey.String()),
-		zap.Int("bytes", len(req.Content)),
+		zap.Int64("bytes", req.Size),
 		zap.String("request_id", req.RequestID))
 
 	return nil
 }
 
+// PushBatch streams many cache files to one EG in a single request.
+// Returns per-entry errors (same order as reqs) and a transport error that applies to all entries.
+func (c *FastHTTPClient) PushBatch(ctx context.Context, targetEgID string, reqs []*PushRequest) ([]error, error) {
+	results := make([]error, len(reqs))
+	if len(reqs) == 0 {
+		return results, nil
+	}
+
+	address, err := c.registry.GetEGAddress(ctx, targetEgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EG address: %w", err)
+	}
+	url := fmt.Sprintf("%s://%s%s", c.protocol, address, internal_server.PathCachePushBatch)
+
+	// Build frame stream: entries whose file cannot be read fail locally and are skipped
+	body := &multiFileReader{}
+	readers := make([]io.Reader, 0, 2*len(reqs))
+	sent := make([]int, 0, len(reqs)) // indexes of reqs included in the stream
+	var totalSize int64
+
+	for i, req := range reqs {
+		if err := req.prepare(); err != nil {
+			results[i] = err
+			continue
+		}
+		metadata := req.shardMetadata()
+		header, err := encodeFrameHeader(&metadata)
+		if err != nil {
+			results[i] = fmt.Errorf("failed to encode frame header: %w", err)
+			continue
+		}
+		file, err := os.Open(req.SourcePath)
+		if err != nil {
+			results[i] = fmt.Errorf("failed to open cache file: %w", err)
+			continue
+		}
+		body.files = append(body.files, file)
+		readers = append(readers, bytes.NewReader(header), io.LimitReader(file, req.Size))
+		totalSize += int64(len(header)) + req.Size
+		sent = append(sent, i)
+	}
+
+	if len(sent) == 0 {
+		return results, nil
+	}
+	body.Reader = io.MultiReader(readers...)
+
+	httpReq := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(httpReq)
+
+	httpReq.SetRequestURI(url)
+	httpReq.Header.SetMethod("POST")
+	httpReq.Header.Set("X-Internal-Auth", c.authKey)
+	httpReq.Header.SetContentType("application/octet-stream")
+	httpReq.SetTimeout(c.timeout)
+	httpReq.SetBodyStream(body, int(totalSize))
+
+	httpResp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(httpResp)
+
+	if err := c.httpClient.Do(httpReq, httpResp); err != nil {
+		body.Close()
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if statusCode := httpResp.StatusCode(); statusCode != fasthttp.StatusOK {
+		return nil, fmt.Errorf("push batch failed with status %d: %s", statusCode, httpResp.Body())
+	}
+
+	var batchResp PushBatchResponse
+	if err := json.Unmarshal(httpResp.Body(), &batchResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal push batch response: %w", err)
+	}
+	if len(batchResp.Results) != len(sent) {
+		return nil, fmt.Errorf("push batch returned %d results for %d entries", len(batchResp.Results), len(sent))
+	}
+
+	for j, i := range sent {
+		if batchResp.Results[j].Error != "" {
+			results[i] = fmt.Errorf("push rejected: %s", batchResp.Results[j].Error)
+		}
+	}
+
+	c.logger.Info("PUSH batch completed",
+		zap.String("peer", url),
+		zap.Int("entries", len(sent)),
+		zap.Int64("bytes", totalSize))
+
+	return results, nil
+}
+
 // Status retrieves status information from another EG
 func (c *FastHTTPClient) Status(ctx context.Context, targetEgID string) (*StatusResponse, error) {
 	// Get target EG address from registry
@@ -293,6 +492,14 @@ func (c *FastHTTPClient) Status(ctx context.Context, targetEgID string) (*Status
 // PushParallel pushes cache to multiple EGs in parallel
 func (c *FastHTTPClient) PushParallel(ctx context.Context, targetEgIDs []string, req *PushRequest) map[string]error {
 	results := make(map[string]error)
+
+	// Checksum the file once instead of per target
+	if err := req.prepare(); err != nil {
+		for _, egID := range targetEgIDs {
+			results[egID] = err
+		}
+		return results
+	}
 	resultsCh := make(chan struct {
 		egID string
 		err  error
@@ -3,6 +3,7 @@ package sharding
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/rand"
 	"sort"
 	"strings"
@@ -35,7 +36,7 @@ func NewHashModuloDistributor(registry Registry, logger *zap.Logger) *HashModulo
 // Algorithm:
 // 1. Get healthy EGs and sort alphabetically (deterministic ordering)
 // 2. Compute primary index: XXHash64(cacheKey) % numEGs
-// 3. Select N consecutive EGs starting from primary (with wrap-around)
+// 3. Select N consecutive EGs starting from primary (with wrap-around), one per zone first
 // 4. Ensure rendering EG is included in target list
 func (d *HashModuloDistributor) ComputeTargets(ctx context.Context, cacheKey string, renderingEgID string, replicationFactor int) ([]string, error) {
 	if replicationFactor <= 0 {
@@ -57,41 +58,17 @@ func (d *HashModuloDistributor) ComputeTargets(ctx context.Context, cacheKey str
 	for i, eg := range healthyEGs {
 		egIDs[i] = eg.EgID
 	}
-
-	// If replication factor exceeds cluster size, cap it
-	actualReplication := replicationFactor
-	if actualReplication > len(egIDs) {
-		actualReplication = len(egIDs)
-	}
+	zones := egZones(healthyEGs)
 
 	// Compute hash and primary index
 	hashValue := xxhash.Sum64String(cacheKey)
 	primaryIndex := int(hashValue % uint64(len(egIDs)))
 
-	// Select N consecutive EGs with wrap-around
-	targets := make([]string, 0, actualReplication)
-	for i := 0; i < actualReplication; i++ {
-		targetIndex := (primaryIndex + i) % len(egIDs)
-		targets = append(targets, egIDs[targetIndex])
-	}
-
-	// Ensure rendering EG is in target list
-	renderingIncluded := false
-	for _, target := range targets {
-		if target == renderingEgID {
-			renderingIncluded = true
-			break
-		}
-	}
+	// Select N EGs walking consecutively from primary (with wrap-around), spread across zones
+	targets := spreadAcrossZones(rotateSlice(egIDs, primaryIndex), zones, replicationFactor)
 
-	if !renderingIncluded {
-		// Replace first target with rendering EG
-		if len(targets) > 0 {
-			targets[0] = renderingEgID
-		} else {
-			targets = []string{renderingEgID}
-		}
-	}
+	// Ensure rendering EG is in target list (replaces first target unless a same-zone target exists)
+	targets = includeRenderingEG(targets, renderingEgID, zones, 0)
 
 	d.logger.Debug("Computed distribution targets",
 		zap.String("cache_key", cacheKey),
@@ -126,22 +103,12 @@ func (d *HashModuloDistributor) ComputeHashTargets(ctx context.Context, cacheKey
 		egIDs[i] = eg.EgID
 	}
 
-	// If replication factor exceeds cluster size, cap it
-	actualReplication := replicationFactor
-	if actualReplication > len(egIDs) {
-		actualReplication = len(egIDs)
-	}
-
 	// Compute hash and primary index
 	hashValue := xxhash.Sum64String(cacheKey)
 	primaryIndex := int(hashValue % uint64(len(egIDs)))
 
-	// Select N consecutive EGs with wrap-around (NO rendering EG override)
-	targets := make([]string, 0, actualReplication)
-	for i := 0; i < actualReplication; i++ {
-		targetIndex := (primaryIndex + i) % len(egIDs)
-		targets = append(targets, egIDs[targetIndex])
-	}
+	// Select N EGs walking consecutively from primary, spread across zones (NO rendering EG override)
+	targets := spreadAcrossZones(rotateSlice(egIDs, primaryIndex), egZones(healthyEGs), replicationFactor)
 
 	d.logger.Debug("Computed hash-based targets (no rendering override)",
 		zap.String("cache_key", cacheKey),
@@ -152,6 +119,195 @@ func (d *HashModuloDistributor) ComputeHashTargets(ctx context.Context, cacheKey
 	return targets, nil
 }
 
+// RendezvousDistributor implements weighted rendezvous (highest random weight) hashing.
+// Every EG gets an independent score per cache key and the N highest scores win,
+// so membership changes move only the keys owned by the added or removed EG (~1/N).
+type RendezvousDistributor struct {
+	registry Registry
+	logger   *zap.Logger
+}
+
+// NewRendezvousDistributor creates a new rendezvous distributor
+func NewRendezvousDistributor(registry Registry, logger *zap.Logger) *RendezvousDistributor {
+	return &RendezvousDistributor{
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// ComputeTargets computes target EGs using weighted rendezvous hashing
+// Algorithm:
+// 1. Get healthy EGs with their announced weights
+// 2. Rank EGs by weighted score for the cache key
+// 3. Select top N EGs, one per zone first
+// 4. Ensure rendering EG is included (replaces a same-zone or the lowest-ranked target so the primary stays stable)
+func (d *RendezvousDistributor) ComputeTargets(ctx context.Context, cacheKey string, renderingEgID string, replicationFactor int) ([]string, error) {
+	if replicationFactor <= 0 {
+		return []string{renderingEgID}, nil
+	}
+
+	healthyEGs, err := d.registry.GetHealthyEGs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get healthy EGs: %w", err)
+	}
+
+	if len(healthyEGs) == 0 {
+		return []string{renderingEgID}, nil
+	}
+
+	zones := egZones(healthyEGs)
+	targets := spreadAcrossZones(rendezvousRank(cacheKey, healthyEGs), zones, replicationFactor)
+	targets = includeRenderingEG(targets, renderingEgID, zones, len(targets)-1)
+
+	d.logger.Debug("Computed rendezvous distribution targets",
+		zap.String("cache_key", cacheKey),
+		zap.String("rendering_eg", renderingEgID),
+		zap.Int("replication_factor", replicationFactor),
+		zap.Int("cluster_size", len(healthyEGs)),
+		zap.Strings("targets", targets))
+
+	return targets, nil
+}
+
+// ComputeHashTargets computes target EGs using ONLY rendezvous ranking (no rendering EG override)
+func (d *RendezvousDistributor) ComputeHashTargets(ctx context.Context, cacheKey string, replicationFactor int) ([]string, error) {
+	if replicationFactor <= 0 {
+		return []string{}, nil
+	}
+
+	healthyEGs, err := d.registry.GetHealthyEGs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get healthy EGs: %w", err)
+	}
+
+	if len(healthyEGs) == 0 {
+		return []string{}, nil
+	}
+
+	targets := spreadAcrossZones(rendezvousRank(cacheKey, healthyEGs), egZones(healthyEGs), replicationFactor)
+
+	d.logger.Debug("Computed rendezvous targets (no rendering override)",
+		zap.String("cache_key", cacheKey),
+		zap.Int("replication_factor", replicationFactor),
+		zap.Int("cluster_size", len(healthyEGs)),
+		zap.Strings("targets", targets))
+
+	return targets, nil
+}
+
+// rendezvousRank returns all EG IDs ordered by weighted score for cacheKey, highest first.
+// Score is -weight/ln(u) with u uniform in (0,1) derived from xxhash(cacheKey, egID),
+// which gives each EG a share of keys proportional to its weight.
+func rendezvousRank(cacheKey string, egs []EGInfo) []string {
+	type scored struct {
+		egID  string
+		score float64
+	}
+
+	ranked := make([]scored, len(egs))
+	for i, eg := range egs {
+		weight := eg.Weight
+		if weight <= 0 {
+			weight = 1.0
+		}
+		hash := xxhash.Sum64String(cacheKey + "|" + eg.EgID)
+		// Top 53 bits mapped into the open interval (0,1)
+		u := (float64(hash>>11) + 0.5) / (1 << 53)
+		ranked[i] = scored{egID: eg.EgID, score: -weight / math.Log(u)}
+	}
+
+	// Ties are practically impossible; break them by ID for determinism
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].egID < ranked[j].egID
+	})
+
+	egIDs := make([]string, len(ranked))
+	for i, r := range ranked {
+		egIDs[i] = r.egID
+	}
+	return egIDs
+}
+
+// egZones maps EG IDs to their announced zones (EGs without a zone are omitted)
+func egZones(egs []EGInfo) map[string]string {
+	zones := make(map[string]string)
+	for _, eg := range egs {
+		if eg.Zone != "" {
+			zones[eg.EgID] = eg.Zone
+		}
+	}
+	return zones
+}
+
+// spreadAcrossZones picks n EG IDs from ranked (preferred first). The first pass takes
+// at most one EG per zone so replicas land in different zones; remaining slots are
+// filled in ranked order. EGs without a zone share one group, so clusters without
+// zone labels get the first n ranked EGs unchanged.
+func spreadAcrossZones(ranked []string, zones map[string]string, n int) []string {
+	if n > len(ranked) {
+		n = len(ranked)
+	}
+	if len(zones) == 0 {
+		return append([]string{}, ranked[:n]...)
+	}
+
+	targets := make([]string, 0, n)
+	picked := make(map[string]bool, n)
+	usedZones := make(map[string]bool)
+
+	for _, egID := range ranked {
+		if len(targets) == n {
+			break
+		}
+		zone := zones[egID]
+		if usedZones[zone] {
+			continue
+		}
+		usedZones[zone] = true
+		picked[egID] = true
+		targets = append(targets, egID)
+	}
+
+	for _, egID := range ranked {
+		if len(targets) == n {
+			break
+		}
+		if !picked[egID] {
+			targets = append(targets, egID)
+		}
+	}
+
+	return targets
+}
+
+// includeRenderingEG ensures the rendering EG is a target. It replaces a target in the
+// rendering EG's zone to keep the zone spread, otherwise the target at replaceIndex.
+func includeRenderingEG(targets []string, renderingEgID string, zones map[string]string, replaceIndex int) []string {
+	if len(targets) == 0 {
+		return []string{renderingEgID}
+	}
+	for _, target := range targets {
+		if target == renderingEgID {
+			return targets
+		}
+	}
+
+	if zone := zones[renderingEgID]; zone != "" {
+		for i := len(targets) - 1; i >= 0; i-- {
+			if zones[targets[i]] == zone {
+				targets[i] = renderingEgID
+				return targets
+			}
+		}
+	}
+
+	targets[replaceIndex] = renderingEgID
+	return targets
+}
+
 // RandomDistributor implements random distribution
 type RandomDistributor struct {
 	registry Registry
@@ -169,7 +325,7 @@ func NewRandomDistributor(registry Registry, logger *zap.Logger) *RandomDistribu
 // ComputeTargets computes target EGs using random selection
 // Algorithm:
 // 1. Get healthy EGs
-// 2. Randomly select N EGs
+// 2. Randomly select N EGs, one per zone first
 // 3. Ensure rendering EG is included
 func (d *RandomDistributor) ComputeTargets(ctx context.Context, cacheKey string, renderingEgID string, replicationFactor int) ([]string, error) {
 	if replicationFactor <= 0 {
@@ -191,12 +347,7 @@ func (d *RandomDistributor) ComputeTargets(ctx context.Context, cacheKey string,
 	for i, eg := range healthyEGs {
 		egIDs[i] = eg.EgID
 	}
-
-	// If replication factor exceeds cluster size, use all EGs
-	actualReplication := replicationFactor
-	if actualReplication > len(egIDs) {
-		actualReplication = len(egIDs)
-	}
+	zones := egZones(healthyEGs)
 
 	// Randomly shuffle EG IDs
 	shuffled := make([]string, len(egIDs))
@@ -205,26 +356,11 @@ func (d *RandomDistributor) ComputeTargets(ctx context.Context, cacheKey string,
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
-	// Select first N
-	targets := shuffled[:actualReplication]
+	// Select first N, spread across zones
+	targets := spreadAcrossZones(shuffled, zones, replicationFactor)
 
 	// Ensure rendering EG is in target list
-	renderingIncluded := false
-	for _, target := range targets {
-		if target == renderingEgID {
-			renderingIncluded = true
-			break
-		}
-	}
-
-	if !renderingIncluded {
-		// Replace first target with rendering EG
-		if len(targets) > 0 {
-			targets[0] = renderingEgID
-		} else {
-			targets = []string{renderingEgID}
-		}
-	}
+	targets = includeRenderingEG(targets, renderingEgID, zones, 0)
 
 	d.logger.Debug("Computed random distribution targets",
 		zap.String("cache_key", cacheKey),
@@ -258,12 +394,6 @@ func (d *RandomDistributor) ComputeHashTargets(ctx context.Context, cacheKey str
 		egIDs[i] = eg.EgID
 	}
 
-	// If replication factor exceeds cluster size, use all EGs
-	actualReplication := replicationFactor
-	if actualReplication > len(egIDs) {
-		actualReplication = len(egIDs)
-	}
-
 	// Randomly shuffle EG IDs
 	shuffled := make([]string, len(egIDs))
 	copy(shuffled, egIDs)
@@ -271,8 +401,8 @@ func (d *RandomDistributor) ComputeHashTargets(ctx context.Context, cacheKey str
 		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
 	})
 
-	// Select first N (NO rendering EG override)
-	targets := shuffled[:actualReplication]
+	// Select first N, spread across zones (NO rendering EG override)
+	targets := spreadAcrossZones(shuffled, egZones(healthyEGs), replicationFactor)
 
 	d.logger.Debug("Computed random targets (no rendering override)",
 		zap.String("cache_key", cacheKey),
@@ -323,6 +453,8 @@ func DistributorFactory(strategy string, registry Registry, logger *zap.Logger)
 	switch strategy {
 	case "hash_modulo":
 		return NewHashModuloDistributor(registry, logger), nil
+	case "rendezvous":
+		return NewRendezvousDistributor(registry, logger), nil
 	case "random":
 		return NewRandomDistributor(registry, logger), nil
 	case "primary_only":
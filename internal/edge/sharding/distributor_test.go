@@ -0,0 +1,294 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// staticRegistry is a Registry returning a fixed EG list
+type staticRegistry struct {
+	egs []EGInfo
+}
+
+func (r *staticRegistry) Register(ctx context.Context, egID string, address string) error { return nil }
+func (r *staticRegistry) Deregister(ctx context.Context, egID string) error               { return nil }
+func (r *staticRegistry) Heartbeat(ctx context.Context) error                             { return nil }
+func (r *staticRegistry) GetHealthyEGs(ctx context.Context) ([]EGInfo, error)             { return r.egs, nil }
+func (r *staticRegistry) GetEGAddress(ctx context.Context, egID string) (string, error) {
+	return "", nil
+}
+func (r *staticRegistry) GetClusterMembers(ctx context.Context) ([]string, error) {
+	ids := make([]string, len(r.egs))
+	for i, eg := range r.egs {
+		ids[i] = eg.EgID
+	}
+	return ids, nil
+}
+
+func makeEGs(n int) []EGInfo {
+	egs := make([]EGInfo, n)
+	for i := range egs {
+		egs[i] = EGInfo{EgID: fmt.Sprintf("eg-%02d", i+1)}
+	}
+	return egs
+}
+
+const movementKeys = 20000
+
+// primaryAssignments returns the primary target of every test key
+func primaryAssignments(t *testing.T, d Distributor) []string {
+	t.Helper()
+	primaries := make([]string, movementKeys)
+	for i := range primaries {
+		targets, err := d.ComputeHashTargets(t.Context(), fmt.Sprintf("cache:1:1:%d", i), 1)
+		require.NoError(t, err)
+		require.Len(t, targets, 1)
+		primaries[i] = targets[0]
+	}
+	return primaries
+}
+
+func movedFraction(before, after []string) float64 {
+	moved := 0
+	for i := range before {
+		if before[i] != after[i] {
+			moved++
+		}
+	}
+	return float64(moved) / float64(len(before))
+}
+
+func TestDistributorFactory(t *testing.T) {
+	registry := &staticRegistry{}
+	logger := zap.NewNop()
+
+	tests := []struct {
+		strategy string
+		expected any
+	}{
+		{"", &HashModuloDistributor{}},
+		{"hash_modulo", &HashModuloDistributor{}},
+		{"rendezvous", &RendezvousDistributor{}},
+		{"random", &RandomDistributor{}},
+		{"primary_only", &PrimaryOnlyDistributor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			d, err := DistributorFactory(tt.strategy, registry, logger)
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, d)
+		})
+	}
+
+	_, err := DistributorFactory("ring", registry, logger)
+	assert.Error(t, err)
+}
+
+func TestRendezvousDistributor_ComputeTargets(t *testing.T) {
+	registry := &staticRegistry{egs: makeEGs(5)}
+	d := NewRendezvousDistributor(registry, zap.NewNop())
+
+	t.Run("deterministic and distinct", func(t *testing.T) {
+		first, err := d.ComputeHashTargets(t.Context(), "cache:1:1:abc", 3)
+		require.NoError(t, err)
+		second, err := d.ComputeHashTargets(t.Context(), "cache:1:1:abc", 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Len(t, first, 3)
+		seen := make(map[string]bool)
+		for _, egID := range first {
+			assert.False(t, seen[egID], "duplicate target %s", egID)
+			seen[egID] = true
+		}
+	})
+
+	t.Run("replication capped at cluster size", func(t *testing.T) {
+		targets, err := d.ComputeHashTargets(t.Context(), "cache:1:1:abc", 10)
+		require.NoError(t, err)
+		assert.Len(t, targets, 5)
+	})
+
+	t.Run("rendering EG replaces lowest-ranked target", func(t *testing.T) {
+		hashTargets, err := d.ComputeHashTargets(t.Context(), "cache:1:1:abc", 2)
+		require.NoError(t, err)
+
+		outsider := ""
+		for _, eg := range registry.egs {
+			if eg.EgID != hashTargets[0] && eg.EgID != hashTargets[1] {
+				outsider = eg.EgID
+				break
+			}
+		}
+
+		targets, err := d.ComputeTargets(t.Context(), "cache:1:1:abc", outsider, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{hashTargets[0], outsider}, targets)
+	})
+
+	t.Run("empty cluster", func(t *testing.T) {
+		empty := NewRendezvousDistributor(&staticRegistry{}, zap.NewNop())
+
+		targets, err := empty.ComputeTargets(t.Context(), "cache:1:1:abc", "eg-01", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"eg-01"}, targets)
+
+		targets, err = empty.ComputeHashTargets(t.Context(), "cache:1:1:abc", 2)
+		require.NoError(t, err)
+		assert.Empty(t, targets)
+	})
+}
+
+func TestDistributor_KeyMovementOnMembershipChange(t *testing.T) {
+	const clusterSize = 10
+
+	tests := []struct {
+		name        string
+		strategy    string
+		maxOnAdd    float64 // Ideal: 1/(N+1)
+		maxOnRemove float64 // Ideal: 1/N
+		minOnAdd    float64
+	}{
+		{name: "rendezvous moves about 1/N", strategy: "rendezvous", maxOnAdd: 0.11, maxOnRemove: 0.12},
+		{name: "hash_modulo remaps most keys", strategy: "hash_modulo", minOnAdd: 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := &staticRegistry{egs: makeEGs(clusterSize)}
+			d, err := DistributorFactory(tt.strategy, registry, zap.NewNop())
+			require.NoError(t, err)
+
+			base := primaryAssignments(t, d)
+
+			registry.egs = makeEGs(clusterSize + 1)
+			added := primaryAssignments(t, d)
+			addMoved := movedFraction(base, added)
+
+			registry.egs = makeEGs(clusterSize)[1:]
+			removed := primaryAssignments(t, d)
+			removeMoved := movedFraction(base, removed)
+
+			t.Logf("%s: %.1f%% keys moved on add, %.1f%% on remove", tt.strategy, addMoved*100, removeMoved*100)
+
+			if tt.minOnAdd > 0 {
+				assert.Greater(t, addMoved, tt.minOnAdd)
+				return
+			}
+
+			assert.Less(t, addMoved, tt.maxOnAdd)
+			assert.Less(t, removeMoved, tt.maxOnRemove)
+
+			// Only keys of the new EG move on add, only keys of the removed EG move on remove
+			for i := range base {
+				if base[i] != added[i] {
+					assert.Equal(t, "eg-11", added[i])
+				}
+				if base[i] != removed[i] {
+					assert.Equal(t, "eg-01", base[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRendezvousDistributor_Weights(t *testing.T) {
+	egs := makeEGs(4)
+	egs[0].Weight = 2.0
+	egs[3].Weight = 0.5
+
+	d := NewRendezvousDistributor(&staticRegistry{egs: egs}, zap.NewNop())
+
+	counts := make(map[string]int)
+	for _, primary := range primaryAssignments(t, d) {
+		counts[primary]++
+	}
+
+	// Total weight 4.5: expected shares 44.4%, 22.2%, 22.2%, 11.1%
+	totalWeight := 4.5
+	expected := map[string]float64{"eg-01": 2.0, "eg-02": 1.0, "eg-03": 1.0, "eg-04": 0.5}
+	for egID, weight := range expected {
+		share := float64(counts[egID]) / movementKeys
+		assert.InDelta(t, weight/totalWeight, share, 0.02, "share of %s", egID)
+	}
+}
+
+func makeZonedEGs(n int, zones ...string) []EGInfo {
+	egs := makeEGs(n)
+	for i := range egs {
+		egs[i].Zone = zones[i%len(zones)]
+	}
+	return egs
+}
+
+func TestSpreadAcrossZones(t *testing.T) {
+	ranked := []string{"a1", "a2", "b1", "c1", "b2"}
+	zones := map[string]string{"a1": "a", "a2": "a", "b1": "b", "b2": "b", "c1": "c"}
+
+	tests := []struct {
+		name     string
+		zones    map[string]string
+		n        int
+		expected []string
+	}{
+		{"no zones keeps rank order", nil, 3, []string{"a1", "a2", "b1"}},
+		{"one per zone first", zones, 3, []string{"a1", "b1", "c1"}},
+		{"fills by rank after all zones used", zones, 4, []string{"a1", "b1", "c1", "a2"}},
+		{"capped at cluster size", zones, 10, []string{"a1", "b1", "c1", "a2", "b2"}},
+		{"unlabeled EGs share a group", map[string]string{"a1": "a"}, 3, []string{"a1", "a2", "b1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, spreadAcrossZones(ranked, tt.zones, tt.n))
+		})
+	}
+}
+
+func TestIncludeRenderingEG(t *testing.T) {
+	zones := map[string]string{"a1": "a", "a2": "a", "b1": "b", "c1": "c"}
+
+	assert.Equal(t, []string{"a1", "b1"}, includeRenderingEG([]string{"a1", "b1"}, "b1", zones, 0))
+	assert.Equal(t, []string{"a2", "b1"}, includeRenderingEG([]string{"a1", "b1"}, "a2", zones, 1))
+	assert.Equal(t, []string{"a1", "c1"}, includeRenderingEG([]string{"a1", "b1"}, "c1", zones, 1))
+	assert.Equal(t, []string{"x"}, includeRenderingEG(nil, "x", zones, 0))
+}
+
+func TestDistributor_ZoneAwarePlacement(t *testing.T) {
+	for _, strategy := range []string{"hash_modulo", "rendezvous", "random"} {
+		t.Run(strategy, func(t *testing.T) {
+			egs := makeZonedEGs(9, "zone-a", "zone-b", "zone-c")
+			zones := egZones(egs)
+			d, err := DistributorFactory(strategy, &staticRegistry{egs: egs}, zap.NewNop())
+			require.NoError(t, err)
+
+			for i := 0; i < 1000; i++ {
+				cacheKey := fmt.Sprintf("cache:1:1:%d", i)
+
+				targets, err := d.ComputeHashTargets(t.Context(), cacheKey, 3)
+				require.NoError(t, err)
+				seen := make(map[string]bool)
+				for _, egID := range targets {
+					seen[zones[egID]] = true
+				}
+				assert.Len(t, seen, 3, "targets %v of %s not spread across zones", targets, cacheKey)
+
+				// Rendering EG replaces the target in its own zone
+				targets, err = d.ComputeTargets(t.Context(), cacheKey, "eg-01", 3)
+				require.NoError(t, err)
+				assert.Contains(t, targets, "eg-01")
+				seen = make(map[string]bool)
+				for _, egID := range targets {
+					seen[zones[egID]] = true
+				}
+				assert.Len(t, seen, 3, "targets %v of %s not spread across zones", targets, cacheKey)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package sharding
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/valyala/fasthttp"
@@ -18,6 +21,7 @@ import (
 func (m *Manager) RegisterEndpoints(server *internal_server.InternalServer) {
 	server.RegisterHandler("GET", internal_server.PathCachePull, m.handlePull)
 	server.RegisterHandler("POST", internal_server.PathCachePush, m.handlePush)
+	server.RegisterHandler("POST", internal_server.PathCachePushBatch, m.handlePushBatch)
 	server.RegisterHandler("GET", internal_server.PathCacheStatus, m.handleStatus)
 }
 
@@ -64,7 +68,7 @@ func (m *Manager) handlePull(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	fileInfo, err := os.Stat(filePath)
+	file, err := os.Open(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			m.logger.Debug("Cache file not found at path",
@@ -72,7 +76,7 @@ func (m *Manager) handlePull(ctx *fasthttp.RequestCtx) {
 				zap.String("path", filePath))
 			httputil.JSONError(ctx, "cache not found", fasthttp.StatusNotFound)
 		} else {
-			m.logger.Error("Failed to stat cache file",
+			m.logger.Error("Failed to open cache file",
 				zap.String("cache_key", cacheKey.String()),
 				zap.String("path", filePath),
 				zap.Error(err))
@@ -81,6 +85,22 @@ func (m *Manager) handlePull(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	// Checksum the open file, then stream the same file handle so a concurrent
+	// rename cannot change content between checksum and send
+	size, checksum, err := readerChecksum(file)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		m.logger.Error("Failed to read cache file",
+			zap.String("cache_key", cacheKey.String()),
+			zap.String("path", filePath),
+			zap.Error(err))
+		httputil.JSONError(ctx, "internal server error", fasthttp.StatusInternalServerError)
+		return
+	}
+
 	shardMetadata := ShardMetadata{
 		CacheKey:  *cacheKey,
 		CreatedAt: metadata.CreatedAt,
@@ -88,10 +108,13 @@ func (m *Manager) handlePull(ctx *fasthttp.RequestCtx) {
 		RequestID: metadata.RequestID,
 		EgID:      m.egID,
 		FilePath:  metadata.FilePath, // Includes compression extension
+		Size:      size,
+		Checksum:  checksum,
 	}
 
 	metadataJSON, err := json.Marshal(shardMetadata)
 	if err != nil {
+		file.Close()
 		m.logger.Error("Failed to marshal metadata",
 			zap.String("cache_key", cacheKey.String()),
 			zap.Error(err))
@@ -99,17 +122,18 @@ func (m *Manager) handlePull(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	// Content is sent as stored on disk (already compressed); fasthttp closes the file
 	ctx.Response.Header.Set("X-Shard-Metadata", string(metadataJSON))
-	ctx.Response.Header.SetContentType("text/html; charset=utf-8")
-	ctx.SendFile(filePath)
+	ctx.Response.Header.SetContentType("application/octet-stream")
+	ctx.SetBodyStream(file, int(size))
 
 	m.logger.Info("PULL served",
 		zap.String("cache_key", cacheKey.String()),
 		zap.String("to", ctx.RemoteIP().String()),
-		zap.Int64("bytes", fileInfo.Size()))
+		zap.Int64("bytes", size))
 }
 
-// handlePush handles cache push requests from other EGs using streaming
+// handlePush handles cache push requests from other EGs, streaming the body to disk
 func (m *Manager) handlePush(ctx *fasthttp.RequestCtx) {
 	metadataJSON := ctx.Request.Header.Peek("X-Shard-Metadata")
 	if len(metadataJSON) == 0 {
@@ -126,6 +150,83 @@ func (m *Manager) handlePush(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	// Older EGs send no checksum and no size: accept the body as-is
+	size := int64(-1)
+	if metadata.Checksum != "" {
+		size = metadata.Size
+	}
+
+	bytesWritten, status, err := m.storePushed(&metadata, requestBody(ctx), size)
+	if err != nil {
+		httputil.JSONError(ctx, err.Error(), status)
+		return
+	}
+
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+	ctx.Response.Header.SetContentLength(0)
+
+	m.logger.Info("PUSH received",
+		zap.String("cache_key", metadata.CacheKey.String()),
+		zap.String("request_id", metadata.RequestID),
+		zap.String("from", ctx.RemoteIP().String()),
+		zap.Int64("bytes", bytesWritten))
+}
+
+// handlePushBatch handles batched cache pushes: a stream of framed entries (see transfer.go).
+// Each entry is verified and stored independently; results are returned in order.
+func (m *Manager) handlePushBatch(ctx *fasthttp.RequestCtx) {
+	reader := bufio.NewReader(requestBody(ctx))
+	resp := PushBatchResponse{Results: []PushBatchResult{}}
+	var totalBytes int64
+
+	for {
+		metadata, err := readFrameHeader(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Stream is no longer aligned to frames; reject the remainder
+			m.logger.Warn("Invalid push batch frame",
+				zap.Int("entries_received", len(resp.Results)),
+				zap.Error(err))
+			m.metrics.RecordError("push_batch_invalid")
+			httputil.JSONError(ctx, "invalid batch frame", fasthttp.StatusBadRequest)
+			return
+		}
+
+		result := PushBatchResult{CacheKey: metadata.CacheKey.String()}
+		written, _, err := m.storePushed(metadata, reader, metadata.Size)
+		if err != nil {
+			result.Error = err.Error()
+			if errors.Is(err, ErrShortTransfer) {
+				// Truncated body means the batch ended mid-frame
+				httputil.JSONError(ctx, "truncated batch", fasthttp.StatusBadRequest)
+				return
+			}
+		}
+		totalBytes += written
+		resp.Results = append(resp.Results, result)
+	}
+
+	respBody, err := json.Marshal(resp)
+	if err != nil {
+		httputil.JSONError(ctx, "internal server error", fasthttp.StatusInternalServerError)
+		return
+	}
+
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+	ctx.Response.Header.SetContentType("application/json")
+	ctx.Response.SetBody(respBody)
+
+	m.logger.Info("PUSH batch received",
+		zap.String("from", ctx.RemoteIP().String()),
+		zap.Int("entries", len(resp.Results)),
+		zap.Int64("bytes", totalBytes))
+}
+
+// storePushed writes pushed content to its cache path, verifying size (when >= 0) and checksum.
+// Returns the bytes written and the HTTP status to report on error.
+func (m *Manager) storePushed(metadata *ShardMetadata, body io.Reader, size int64) (int64, int, error) {
 	cacheKey := &metadata.CacheKey
 
 	// Use FilePath from metadata if provided (includes compression extension)
@@ -136,53 +237,33 @@ func (m *Manager) handlePush(ctx *fasthttp.RequestCtx) {
 	}
 	absolutePath, err := m.cacheService.GetAbsoluteFilePath(filePath)
 	if err != nil {
+		if size >= 0 {
+			_, _ = io.CopyN(io.Discard, body, size) // Keep batch frames aligned
+		}
 		m.logger.Warn("Invalid cache file path in push metadata",
 			zap.String("file_path", filePath),
 			zap.Error(err))
-		httputil.JSONError(ctx, "invalid file path", fasthttp.StatusBadRequest)
-		return
-	}
-
-	parentDir := filepath.Dir(absolutePath)
-	if err := os.MkdirAll(parentDir, 0755); err != nil {
-		m.logger.Error("Failed to create cache directory",
-			zap.String("dir", parentDir),
-			zap.Error(err))
-		httputil.JSONError(ctx, "failed to create directory", fasthttp.StatusInternalServerError)
-		return
+		return 0, fasthttp.StatusBadRequest, fmt.Errorf("invalid file path")
 	}
 
-	tempPath := absolutePath + ".tmp"
-	htmlContent := ctx.Request.Body()
-
-	if err := os.WriteFile(tempPath, htmlContent, 0644); err != nil {
-		m.logger.Error("Failed to write temp file",
-			zap.String("path", tempPath),
-			zap.Error(err))
-		httputil.JSONError(ctx, "failed to write temp file", fasthttp.StatusInternalServerError)
-		return
-	}
-
-	bytesWritten := int64(len(htmlContent))
-
-	if err := os.Rename(tempPath, absolutePath); err != nil {
-		os.Remove(tempPath)
-		m.logger.Error("Failed to rename temp file",
-			zap.String("temp", tempPath),
-			zap.String("final", absolutePath),
+	written, err := receiveToFile(body, absolutePath, size, metadata.Checksum)
+	if err != nil {
+		m.logger.Error("Failed to store pushed cache",
+			zap.String("cache_key", cacheKey.String()),
+			zap.String("path", absolutePath),
 			zap.Error(err))
-		httputil.JSONError(ctx, "failed to finalize file", fasthttp.StatusInternalServerError)
-		return
+		if errors.Is(err, ErrChecksumMismatch) {
+			m.metrics.RecordError("push_checksum_mismatch")
+			return written, fasthttp.StatusUnprocessableEntity, err
+		}
+		if errors.Is(err, ErrShortTransfer) {
+			return written, fasthttp.StatusBadRequest, err
+		}
+		return written, fasthttp.StatusInternalServerError, fmt.Errorf("failed to store cache file")
 	}
 
-	ctx.Response.SetStatusCode(fasthttp.StatusOK)
-	ctx.Response.Header.SetContentLength(0)
-
-	m.logger.Info("PUSH received",
-		zap.String("cache_key", cacheKey.String()),
-		zap.String("request_id", metadata.RequestID),
-		zap.String("from", ctx.RemoteIP().String()),
-		zap.Int64("bytes", bytesWritten))
+	m.metrics.RecordBytesTransferred("push", "received", int(written))
+	return written, fasthttp.StatusOK, nil
 }
 
 // handleStatus handles status information requests
@@ -209,8 +290,14 @@ func (m *Manager) handleStatus(ctx *fasthttp.RequestCtx) {
 		strategy = m.config.DistributionStrategy
 	}
 
+	zone := ""
+	if m.config != nil {
+		zone = m.config.Zone
+	}
+
 	resp := StatusResponse{
 		EgID:                 m.egID,
+		Zone:                 zone,
 		ShardingEnabled:      m.IsEnabled(),
 		ReplicationFactor:    replicationFactor,
 		DistributionStrategy: strategy,
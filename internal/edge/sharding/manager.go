@@ -2,6 +2,8 @@ package sharding
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,7 +16,9 @@ import (
 )
 
 const (
-	interEgTimeout = 3 * time.Second // Timeout for inter-EG operations (pull/push/status)
+	interEgTimeout          = 3 * time.Second  // Timeout for inter-EG operations (pull/push/status)
+	interEgIdleConnDuration = 30 * time.Second // Keep-alive for idle peer connections
+	interEgMaxConnsPerHost  = 64               // Concurrent connections per peer EG
 )
 
 // Manager coordinates all sharding operations
@@ -28,6 +32,7 @@ type Manager struct {
 	metrics         *Metrics
 	cacheService    *cache.CacheService
 	redisClient     *redis.Client
+	rebalancer      *Rebalancer
 	logger          *zap.Logger
 	startTime       time.Time
 
@@ -40,6 +45,7 @@ func NewManager(
 	config *types.CacheShardingConfig,
 	egID string,
 	internalAuthKey string,
+	internalTLS *tls.Config,
 	redisClient *redis.Client,
 	cacheService *cache.CacheService,
 	metricsNamespace string,
@@ -78,6 +84,11 @@ func NewManager(
 		}, nil
 	}
 
+	if config.Weight != nil {
+		registry.SetWeight(*config.Weight)
+	}
+	registry.SetZone(config.Zone)
+
 	// Create distributor based on strategy
 	strategy := config.DistributionStrategy
 	if strategy == "" {
@@ -88,13 +99,17 @@ func NewManager(
 		return nil, fmt.Errorf("failed to create distributor: %w", err)
 	}
 
-	// Create client (uses HTTP for inter-EG communication)
-	client := NewFastHTTPClient(registry, internalAuthKey, "http", interEgTimeout, logger)
+	// Create client (HTTP for inter-EG communication, HTTPS when internal TLS is enabled)
+	protocol := "http"
+	if internalTLS != nil {
+		protocol = "https"
+	}
+	client := NewFastHTTPClient(registry, internalAuthKey, protocol, internalTLS, interEgTimeout, logger)
 
 	// Create metrics
 	metrics := NewMetrics(metricsNamespace)
 
-	return &Manager{
+	m := &Manager{
 		config:          config,
 		egID:            egID,
 		internalAuthKey: internalAuthKey,
@@ -106,7 +121,15 @@ func NewManager(
 		redisClient:     redisClient,
 		logger:          logger,
 		startTime:       time.Now().UTC(),
-	}, nil
+	}
+
+	// Create rebalancer (started with the manager); needs deterministic placement
+	if config.Rebalance != nil && config.Rebalance.Enabled && (strategy == "hash_modulo" || strategy == "rendezvous") {
+		m.rebalancer = NewRebalancer(config.Rebalance, egID, m.GetReplicationFactor(),
+			registry, distributor, client, cacheService, redisClient, metrics, logger)
+	}
+
+	return m, nil
 }
 
 // Start initializes the sharding system (cluster registration and heartbeat)
@@ -135,6 +158,10 @@ func (m *Manager) Start(ctx context.Context, internalAddr string) error {
 		m.metrics.UpdateClusterSize(len(healthyEGs))
 	}
 
+	if m.rebalancer != nil {
+		m.rebalancer.Start()
+	}
+
 	return nil
 }
 
@@ -147,6 +174,10 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 
 	m.logger.Info("Shutting down sharding system")
 
+	if m.rebalancer != nil {
+		m.rebalancer.Shutdown()
+	}
+
 	// Stop heartbeat
 	if m.heartbeatCancel != nil {
 		m.heartbeatCancel()
@@ -202,8 +233,8 @@ func (m *Manager) IsTargetForCache(ctx context.Context, cacheKey string) (bool,
 	return false, nil
 }
 
-// PushToTargets pushes cache to target EGs
-func (m *Manager) PushToTargets(ctx context.Context, cacheKey *types.CacheKey, content []byte, metadata *cache.CacheMetadata, targetEgIDs []string, requestID string) ([]string, error) {
+// PushToTargets streams the locally stored cache file to target EGs
+func (m *Manager) PushToTargets(ctx context.Context, cacheKey *types.CacheKey, metadata *cache.CacheMetadata, targetEgIDs []string, requestID string) ([]string, error) {
 	// Start with self - cache is already stored locally
 	successfulEgIDs := []string{m.egID}
 
@@ -219,11 +250,16 @@ func (m *Manager) PushToTargets(ctx context.Context, cacheKey *types.CacheKey, c
 		return successfulEgIDs, nil // No remote targets, only self
 	}
 
+	sourcePath, err := m.cacheService.GetAbsoluteFilePath(metadata.FilePath)
+	if err != nil {
+		return successfulEgIDs, fmt.Errorf("invalid cache file path: %w", err)
+	}
+
 	req := &PushRequest{
 		HostID:      cacheKey.HostID,
 		DimensionID: cacheKey.DimensionID,
 		URLHash:     cacheKey.URLHash,
-		Content:     content,
+		SourcePath:  sourcePath,
 		CreatedAt:   metadata.CreatedAt,
 		ExpiresAt:   metadata.ExpiresAt,
 		RequestID:   requestID,
@@ -240,7 +276,7 @@ func (m *Manager) PushToTargets(ctx context.Context, cacheKey *types.CacheKey, c
 			successCount++
 			successfulEgIDs = append(successfulEgIDs, egID)
 			m.metrics.RecordPushRequest(egID, true, 0) // Duration tracked by client
-			m.metrics.RecordBytesTransferred("push", "sent", len(content))
+			m.metrics.RecordBytesTransferred("push", "sent", int(req.Size))
 		} else {
 			m.logger.Warn("Failed to push cache to target EG",
 				zap.String("target_eg", egID),
@@ -270,38 +306,84 @@ func (m *Manager) PushToTargets(ctx context.Context, cacheKey *types.CacheKey, c
 	return successfulEgIDs, nil
 }
 
-// PullFromRemote attempts to pull cache from remote EGs
-// Uses hash-based peer selection to distribute load across replicas
+// PullFromRemote attempts to pull cache from remote EGs into memory
+// Uses hash-based peer selection to distribute load across replicas, preferring same-zone replicas
 func (m *Manager) PullFromRemote(ctx context.Context, cacheKey *types.CacheKey, egIDs []string) ([]byte, error) {
+	var content []byte
+	err := m.pullFromPeers(ctx, cacheKey, egIDs, func(egID string, req *PullRequest) (int64, error) {
+		resp, err := m.client.Pull(ctx, egID, req)
+		if err != nil {
+			return 0, err
+		}
+		content = resp.Content
+		return int64(len(content)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// PullFromRemoteToFile streams cache from remote EGs directly to absolutePath.
+// Content is stored as received (already compressed) and never buffered in memory.
+func (m *Manager) PullFromRemoteToFile(ctx context.Context, cacheKey *types.CacheKey, egIDs []string, absolutePath string) (int64, error) {
+	var size int64
+	err := m.pullFromPeers(ctx, cacheKey, egIDs, func(egID string, req *PullRequest) (int64, error) {
+		var err error
+		size, err = m.client.PullToFile(ctx, egID, req, absolutePath)
+		return size, err
+	})
+	return size, err
+}
+
+// pullFromPeers tries peers in load-spreading order until pull succeeds
+func (m *Manager) pullFromPeers(
+	ctx context.Context,
+	cacheKey *types.CacheKey,
+	egIDs []string,
+	pull func(egID string, req *PullRequest) (int64, error),
+) error {
+	if len(egIDs) == 0 {
+		return fmt.Errorf("no EGs to pull from")
+	}
+
 	// Hash-based peer selection for load distribution
 	// Different cache keys will rotate the peer list differently, spreading load
 	hashValue := xxhash.Sum64String(cacheKey.String())
 	selectedIndex := int(hashValue % uint64(len(egIDs)))
 	orderedPeers := rotateSlice(egIDs, selectedIndex)
 
+	// Try same-zone replicas first (cheaper and faster than cross-zone transfer)
+	if m.config.Zone != "" {
+		healthyEGs, err := m.registry.GetHealthyEGs(ctx)
+		if err == nil {
+			orderedPeers = preferZone(orderedPeers, egZones(healthyEGs), m.config.Zone)
+		}
+	}
+
+	req := &PullRequest{
+		HostID:      cacheKey.HostID,
+		DimensionID: cacheKey.DimensionID,
+		URLHash:     cacheKey.URLHash,
+	}
+
 	for _, egID := range orderedPeers {
 		if egID == m.egID {
 			continue // Skip self
 		}
 
 		start := time.Now()
-		req := &PullRequest{
-			HostID:      cacheKey.HostID,
-			DimensionID: cacheKey.DimensionID,
-			URLHash:     cacheKey.URLHash,
-		}
-
-		resp, err := m.client.Pull(ctx, egID, req)
+		size, err := pull(egID, req)
 		duration := time.Since(start).Seconds()
 
 		if err == nil {
 			m.metrics.RecordPullRequest(egID, true, duration)
-			m.metrics.RecordBytesTransferred("pull", "received", len(resp.Content))
+			m.metrics.RecordBytesTransferred("pull", "received", int(size))
 			m.logger.Info("Successfully pulled cache from remote EG",
 				zap.String("source_eg", egID),
 				zap.String("cache_key", cacheKey.String()),
-				zap.Int("content_size", len(resp.Content)))
-			return resp.Content, nil
+				zap.Int64("content_size", size))
+			return nil
 		}
 
 		m.logger.Warn("Failed to pull cache from EG, trying next",
@@ -309,10 +391,14 @@ func (m *Manager) PullFromRemote(ctx context.Context, cacheKey *types.CacheKey,
 			zap.String("cache_key", cacheKey.String()),
 			zap.Error(err))
 		m.metrics.RecordPullRequest(egID, false, duration)
-		m.metrics.RecordError("pull_failed")
+		if errors.Is(err, ErrChecksumMismatch) {
+			m.metrics.RecordError("pull_checksum_mismatch")
+		} else {
+			m.metrics.RecordError("pull_failed")
+		}
 	}
 
-	return nil, fmt.Errorf("failed to pull cache from all EGs")
+	return fmt.Errorf("failed to pull cache from all EGs")
 }
 
 // GetMetrics returns the metrics instance
@@ -356,3 +442,19 @@ func (m *Manager) GetHealthyEGs(ctx context.Context) ([]EGInfo, error) {
 	}
 	return m.registry.GetHealthyEGs(ctx)
 }
+
+// preferZone moves peers in the given zone to the front, keeping relative order otherwise
+func preferZone(peers []string, zones map[string]string, zone string) []string {
+	ordered := make([]string, 0, len(peers))
+	for _, egID := range peers {
+		if zones[egID] == zone {
+			ordered = append(ordered, egID)
+		}
+	}
+	for _, egID := range peers {
+		if zones[egID] != zone {
+			ordered = append(ordered, egID)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,15 @@
+package sharding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferZone(t *testing.T) {
+	zones := map[string]string{"eg-1": "a", "eg-2": "b", "eg-3": "a", "eg-4": "c"}
+	peers := []string{"eg-2", "eg-3", "eg-4", "eg-1", "eg-5"}
+
+	assert.Equal(t, []string{"eg-3", "eg-1", "eg-2", "eg-4", "eg-5"}, preferZone(peers, zones, "a"))
+	assert.Equal(t, peers, preferZone(peers, zones, "zone-x"))
+}
@@ -15,6 +15,10 @@ type Metrics struct {
 	ErrorsTotal           *prometheus.CounterVec
 	PushFailuresTotal     *prometheus.CounterVec
 	LocalCacheEntries     prometheus.Gauge
+
+	RebalanceEntriesTotal *prometheus.CounterVec
+	RebalanceMisplaced    prometheus.Gauge
+	RebalanceDuration     prometheus.Histogram
 }
 
 // NewMetrics creates and registers Prometheus metrics for sharding
@@ -98,6 +102,35 @@ func NewMetrics(namespace string) *Metrics {
 				Help:      "Number of cache entries stored locally on this EG",
 			},
 		),
+
+		RebalanceEntriesTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: "eg_sharding",
+				Name:      "rebalance_entries_total",
+				Help:      "Cache entries processed by the rebalancer by result (repaired, dropped, failed)",
+			},
+			[]string{"result"},
+		),
+
+		RebalanceMisplaced: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: "eg_sharding",
+				Name:      "rebalance_misplaced_entries",
+				Help:      "Local cache entries found under-replicated or on non-target EGs in the last rebalance run",
+			},
+		),
+
+		RebalanceDuration: promauto.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: "eg_sharding",
+				Name:      "rebalance_duration_seconds",
+				Help:      "Duration of rebalance runs in seconds",
+				Buckets:   []float64{1, 10, 60, 300, 900, 3600},
+			},
+		),
 	}
 }
 
@@ -146,3 +179,14 @@ func (m *Metrics) UpdateClusterSize(size int) {
 func (m *Metrics) UpdateLocalCacheEntries(count int) {
 	m.LocalCacheEntries.Set(float64(count))
 }
+
+// RecordRebalanceEntry records a cache entry processed by the rebalancer
+func (m *Metrics) RecordRebalanceEntry(result string) {
+	m.RebalanceEntriesTotal.WithLabelValues(result).Inc()
+}
+
+// RecordRebalanceRun records the outcome of a rebalance run
+func (m *Metrics) RecordRebalanceRun(misplaced int, duration float64) {
+	m.RebalanceMisplaced.Set(float64(misplaced))
+	m.RebalanceDuration.Observe(duration)
+}
@@ -0,0 +1,543 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+const (
+	defaultRebalanceInterval  = 10 * time.Minute
+	defaultRebalanceRateLimit = 20
+	defaultRebalanceMinAge    = 1 * time.Minute
+
+	rebalanceScanCount = 500
+	rebalanceRequestID = "rebalance"
+
+	// Repairs are pushed to each target in batches of small entries
+	rebalanceBatchSize  = 32
+	rebalanceBatchBytes = 8 * 1024 * 1024
+)
+
+// Rebalance results recorded in metrics
+const (
+	rebalanceResultRepaired = "repaired"
+	rebalanceResultDropped  = "dropped"
+	rebalanceResultFailed   = "failed"
+)
+
+// luaCompareAndSetEgIDs updates eg_ids only if the metadata still exists and eg_ids
+// is unchanged since it was read, so a concurrent render or push is never overwritten.
+// KEYS[1] = metadata key, ARGV[1] = expected eg_ids, ARGV[2] = new eg_ids
+// Returns 1 when updated, 0 otherwise
+const luaCompareAndSetEgIDs = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local current = redis.call('HGET', KEYS[1], 'eg_ids')
+if current == false then
+	current = ''
+end
+if current ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'eg_ids', ARGV[2])
+return 1
+`
+
+// pushClient is the subset of FastHTTPClient used by the rebalancer
+type pushClient interface {
+	PushBatch(ctx context.Context, targetEgID string, reqs []*PushRequest) ([]error, error)
+}
+
+// pendingRepair is an under-replicated entry waiting for its batch to be pushed
+type pendingRepair struct {
+	metaKey      string
+	meta         cache.CacheMetadata
+	targets      []string
+	liveHolders  []string
+	missing      []string
+	selfIsTarget bool
+	req          *PushRequest
+	pushed       []string
+}
+
+// repairBatch accumulates pending repairs until it is large enough to flush
+type repairBatch struct {
+	entries []*pendingRepair
+	bytes   int64
+}
+
+func (b *repairBatch) full() bool {
+	return len(b.entries) >= rebalanceBatchSize || b.bytes >= rebalanceBatchBytes
+}
+
+// rebalanceStats summarizes a single rebalance run
+type rebalanceStats struct {
+	scanned   int
+	misplaced int
+	repaired  int
+	dropped   int
+	failed    int
+}
+
+// Rebalancer is an anti-entropy worker that walks cache metadata held by this EG,
+// pushes copies to distribution targets that are missing them and optionally drops
+// local copies this EG no longer owns. Only the first live holder of an entry
+// repairs it, so EGs do not push the same entry concurrently.
+type Rebalancer struct {
+	egID              string
+	replicationFactor int
+	interval          time.Duration
+	rateLimit         int
+	minAge            time.Duration
+	dropUnowned       bool
+
+	registry     Registry
+	distributor  Distributor
+	client       pushClient
+	cacheService *cache.CacheService
+	redis        *redis.Client
+	keyGenerator *redis.KeyGenerator
+	metrics      *Metrics
+	logger       *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRebalancer creates a rebalancer, applying defaults for unset config values
+func NewRebalancer(
+	config *types.CacheShardingRebalanceConfig,
+	egID string,
+	replicationFactor int,
+	registry Registry,
+	distributor Distributor,
+	client pushClient,
+	cacheService *cache.CacheService,
+	redisClient *redis.Client,
+	metrics *Metrics,
+	logger *zap.Logger,
+) *Rebalancer {
+	interval := config.Interval.ToDuration()
+	if interval <= 0 {
+		interval = defaultRebalanceInterval
+	}
+	rateLimit := config.RateLimit
+	if rateLimit <= 0 {
+		rateLimit = defaultRebalanceRateLimit
+	}
+	minAge := config.MinAge.ToDuration()
+	if minAge <= 0 {
+		minAge = defaultRebalanceMinAge
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Rebalancer{
+		egID:              egID,
+		replicationFactor: replicationFactor,
+		interval:          interval,
+		rateLimit:         rateLimit,
+		minAge:            minAge,
+		dropUnowned:       config.DropUnowned,
+		registry:          registry,
+		distributor:       distributor,
+		client:            client,
+		cacheService:      cacheService,
+		redis:             redisClient,
+		keyGenerator:      redis.NewKeyGenerator(),
+		metrics:           metrics,
+		logger:            logger,
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+}
+
+// Start runs rebalance passes every interval until Shutdown
+func (r *Rebalancer) Start() {
+	r.logger.Info("Shard rebalancer starting",
+		zap.Duration("interval", r.interval),
+		zap.Int("rate_limit", r.rateLimit),
+		zap.Bool("drop_unowned", r.dropUnowned))
+
+	ticker := time.NewTicker(r.interval)
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := r.runOnce(r.ctx); err != nil && r.ctx.Err() == nil {
+					r.logger.Error("Shard rebalance run failed", zap.Error(err))
+				}
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the rebalancer and waits for the current run to exit
+func (r *Rebalancer) Shutdown() {
+	r.cancel()
+	r.wg.Wait()
+	r.logger.Info("Shard rebalancer stopped")
+}
+
+// runOnce scans all cache metadata and repairs entries held by this EG
+func (r *Rebalancer) runOnce(ctx context.Context) (rebalanceStats, error) {
+	var stats rebalanceStats
+	start := time.Now()
+
+	healthyEGs, err := r.registry.GetHealthyEGs(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get healthy EGs: %w", err)
+	}
+	healthy := make(map[string]bool, len(healthyEGs))
+	for _, eg := range healthyEGs {
+		healthy[eg.EgID] = true
+	}
+
+	limiter := time.NewTicker(time.Second / time.Duration(r.rateLimit))
+	defer limiter.Stop()
+	throttle := func() error {
+		select {
+		case <-limiter.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	batch := &repairBatch{}
+	var cursor uint64
+	for {
+		keys, next, err := r.redis.Scan(ctx, cursor, r.keyGenerator.MetadataScanPattern(), rebalanceScanCount)
+		if err != nil {
+			return stats, err
+		}
+
+		for _, metaKey := range keys {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+			stats.scanned++
+			r.rebalanceEntry(ctx, metaKey, healthy, throttle, batch, &stats)
+			if batch.full() {
+				r.flushRepairs(ctx, batch, &stats)
+			}
+		}
+
+		// Flush per SCAN page so repairs do not wait for the whole keyspace walk
+		r.flushRepairs(ctx, batch, &stats)
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	duration := time.Since(start)
+	r.metrics.RecordRebalanceRun(stats.misplaced, duration.Seconds())
+
+	r.logger.Info("Shard rebalance run completed",
+		zap.Int("scanned", stats.scanned),
+		zap.Int("misplaced", stats.misplaced),
+		zap.Int("repaired", stats.repaired),
+		zap.Int("dropped", stats.dropped),
+		zap.Int("failed", stats.failed),
+		zap.Int("cluster_size", len(healthyEGs)),
+		zap.Duration("duration", duration))
+
+	return stats, nil
+}
+
+// rebalanceEntry checks one metadata entry. Repairs this EG is responsible for are
+// queued in batch; drop-only changes are applied immediately.
+func (r *Rebalancer) rebalanceEntry(
+	ctx context.Context,
+	metaKey string,
+	healthy map[string]bool,
+	throttle func() error,
+	batch *repairBatch,
+	stats *rebalanceStats,
+) {
+	data, err := r.redis.HGetAll(ctx, metaKey)
+	if err != nil || len(data) == 0 {
+		return
+	}
+
+	var meta cache.CacheMetadata
+	if err := meta.FromHash(data); err != nil {
+		return
+	}
+
+	// Only entries with a local file copy; redirects and deleted markers are metadata-only
+	if !meta.HasEgID(r.egID) || meta.FilePath == "" || meta.IsDeleted() || meta.IsExpired() {
+		return
+	}
+	if time.Since(meta.CreatedAt) < r.minAge {
+		return
+	}
+
+	targets, err := r.distributor.ComputeHashTargets(ctx, meta.Key, r.replicationFactor)
+	if err != nil || len(targets) == 0 {
+		return
+	}
+
+	liveHolders := make([]string, 0, len(meta.EgIDs))
+	for _, egID := range meta.EgIDs {
+		if healthy[egID] {
+			liveHolders = append(liveHolders, egID)
+		}
+	}
+	sort.Strings(liveHolders)
+
+	missing := missingEgIDs(targets, liveHolders)
+	selfIsTarget := containsEgID(targets, r.egID)
+	if len(missing) == 0 && selfIsTarget {
+		return
+	}
+	stats.misplaced++
+
+	if len(missing) > 0 && len(liveHolders) > 0 && liveHolders[0] == r.egID {
+		if throttle() != nil {
+			return
+		}
+		req, err := r.pushRequest(&meta)
+		if err != nil {
+			r.logger.Warn("Failed to prepare cache entry for repair",
+				zap.String("cache_key", meta.Key),
+				zap.Error(err))
+			stats.failed++
+			r.metrics.RecordRebalanceEntry(rebalanceResultFailed)
+			return
+		}
+		batch.entries = append(batch.entries, &pendingRepair{
+			metaKey:      metaKey,
+			meta:         meta,
+			targets:      targets,
+			liveHolders:  liveHolders,
+			missing:      missing,
+			selfIsTarget: selfIsTarget,
+			req:          req,
+		})
+		batch.bytes += meta.DiskSize
+		return
+	}
+
+	// Not responsible for a repair: only dropping an unowned copy is left
+	if !r.dropUnowned || selfIsTarget || len(missingEgIDs(targets, liveHolders)) > 0 {
+		return // Another holder will handle it
+	}
+	if throttle() != nil {
+		return
+	}
+	r.finishEntry(ctx, metaKey, &meta, targets, liveHolders, false, true, stats)
+}
+
+// flushRepairs pushes queued repairs, one batch request per target EG, and records the results
+func (r *Rebalancer) flushRepairs(ctx context.Context, batch *repairBatch, stats *rebalanceStats) {
+	if len(batch.entries) == 0 {
+		return
+	}
+	entries := batch.entries
+	batch.entries = nil
+	batch.bytes = 0
+
+	// Group by target so each EG receives a single request
+	byTarget := make(map[string][]*pendingRepair)
+	for _, entry := range entries {
+		for _, egID := range entry.missing {
+			byTarget[egID] = append(byTarget[egID], entry)
+		}
+	}
+
+	for egID, group := range byTarget {
+		reqs := make([]*PushRequest, len(group))
+		for i, entry := range group {
+			reqs[i] = entry.req
+		}
+
+		pushCtx, cancel := context.WithTimeout(ctx, interEgTimeout)
+		results, err := r.client.PushBatch(pushCtx, egID, reqs)
+		cancel()
+
+		if err != nil {
+			r.logger.Warn("Failed to push repair batch",
+				zap.String("target_eg", egID),
+				zap.Int("entries", len(group)),
+				zap.Error(err))
+			r.metrics.RecordPushRequest(egID, false, 0)
+			r.metrics.RecordError("rebalance_push_failed")
+			continue
+		}
+
+		r.metrics.RecordPushRequest(egID, true, 0)
+		for i, entry := range group {
+			if results[i] != nil {
+				r.metrics.RecordError("rebalance_push_failed")
+				continue
+			}
+			entry.pushed = append(entry.pushed, egID)
+			r.metrics.RecordBytesTransferred("rebalance", "sent", int(entry.req.Size))
+		}
+	}
+
+	for _, entry := range entries {
+		if len(entry.pushed) == 0 {
+			r.logger.Warn("Failed to repair under-replicated cache entry",
+				zap.String("cache_key", entry.meta.Key),
+				zap.Strings("missing", entry.missing))
+			stats.failed++
+			r.metrics.RecordRebalanceEntry(rebalanceResultFailed)
+			continue
+		}
+
+		newEgIDs := append(append([]string{}, entry.liveHolders...), entry.pushed...)
+		drop := r.dropUnowned && !entry.selfIsTarget && len(missingEgIDs(entry.targets, newEgIDs)) == 0
+		r.finishEntry(ctx, entry.metaKey, &entry.meta, entry.targets, newEgIDs, true, drop, stats)
+	}
+}
+
+// finishEntry records the new holder list (compare-and-set) and drops the local copy if requested
+func (r *Rebalancer) finishEntry(
+	ctx context.Context,
+	metaKey string,
+	meta *cache.CacheMetadata,
+	targets []string,
+	newEgIDs []string,
+	repaired bool,
+	drop bool,
+	stats *rebalanceStats,
+) {
+	if drop {
+		newEgIDs = removeEgID(newEgIDs, r.egID)
+	}
+
+	updated, err := r.redis.Eval(ctx, luaCompareAndSetEgIDs, []string{metaKey},
+		EGIDsToString(meta.EgIDs), EGIDsToString(newEgIDs))
+	if err != nil {
+		stats.failed++
+		r.metrics.RecordRebalanceEntry(rebalanceResultFailed)
+		return
+	}
+	if n, _ := updated.(int64); n != 1 {
+		// Entry changed concurrently (re-render or another push), next run re-evaluates it
+		return
+	}
+
+	// Cached copies of the metadata (hot caches) must not keep routing pulls to EGs that dropped the file
+	if cacheKey, err := types.ParseCacheKey(meta.Key); err == nil {
+		updatedMeta := *meta
+		updatedMeta.EgIDs = newEgIDs
+		r.cacheService.NotifyMetadataChange(ctx, cacheKey, &updatedMeta)
+	}
+
+	if repaired {
+		stats.repaired++
+		r.metrics.RecordRebalanceEntry(rebalanceResultRepaired)
+	}
+
+	if drop {
+		if err := r.deleteLocalFile(meta.FilePath); err != nil {
+			r.logger.Warn("Failed to delete unowned cache file",
+				zap.String("cache_key", meta.Key),
+				zap.String("file_path", meta.FilePath),
+				zap.Error(err))
+		}
+		stats.dropped++
+		r.metrics.RecordRebalanceEntry(rebalanceResultDropped)
+	}
+
+	r.logger.Debug("Rebalanced cache entry",
+		zap.String("cache_key", meta.Key),
+		zap.Strings("targets", targets),
+		zap.Strings("eg_ids", newEgIDs),
+		zap.Bool("repaired", repaired),
+		zap.Bool("dropped", drop))
+}
+
+// pushRequest builds a streaming push request for the local cache file of meta
+func (r *Rebalancer) pushRequest(meta *cache.CacheMetadata) (*PushRequest, error) {
+	cacheKey, err := types.ParseCacheKey(meta.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	absolutePath, err := r.cacheService.GetAbsoluteFilePath(meta.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &PushRequest{
+		HostID:      cacheKey.HostID,
+		DimensionID: cacheKey.DimensionID,
+		URLHash:     cacheKey.URLHash,
+		SourcePath:  absolutePath,
+		CreatedAt:   meta.CreatedAt,
+		ExpiresAt:   meta.ExpiresAt,
+		RequestID:   rebalanceRequestID,
+		FilePath:    meta.FilePath,
+	}
+	if err := req.prepare(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// deleteLocalFile removes a cache file this EG no longer owns
+func (r *Rebalancer) deleteLocalFile(filePath string) error {
+	absolutePath, err := r.cacheService.GetAbsoluteFilePath(filePath)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(absolutePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// missingEgIDs returns targets not present in holders
+func missingEgIDs(targets, holders []string) []string {
+	var missing []string
+	for _, target := range targets {
+		if !containsEgID(holders, target) {
+			missing = append(missing, target)
+		}
+	}
+	return missing
+}
+
+func containsEgID(egIDs []string, egID string) bool {
+	for _, id := range egIDs {
+		if id == egID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeEgID(egIDs []string, egID string) []string {
+	result := make([]string, 0, len(egIDs))
+	for _, id := range egIDs {
+		if id != egID {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,310 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/redis"
+	"github.com/edgecomet/engine/internal/edge/cache"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+// Registered once: promauto panics on duplicate registration
+var testMetrics = NewMetrics("sharding_test")
+
+// fakePushClient records pushes and fails for EGs listed in failing
+type fakePushClient struct {
+	mu      sync.Mutex
+	pushed  map[string][]string // egID -> cache keys
+	failing map[string]bool
+	batches int
+}
+
+func (c *fakePushClient) PushBatch(ctx context.Context, targetEgID string, reqs []*PushRequest) ([]error, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failing[targetEgID] {
+		return nil, fmt.Errorf("connection refused")
+	}
+	if c.pushed == nil {
+		c.pushed = make(map[string][]string)
+	}
+	c.batches++
+	for _, req := range reqs {
+		key := types.CacheKey{HostID: req.HostID, DimensionID: req.DimensionID, URLHash: req.URLHash}
+		c.pushed[targetEgID] = append(c.pushed[targetEgID], key.String())
+	}
+	return make([]error, len(reqs)), nil
+}
+
+type rebalanceFixture struct {
+	registry     *staticRegistry
+	distributor  Distributor
+	client       *fakePushClient
+	cacheService *cache.CacheService
+	metadata     *cache.MetadataStore
+	redis        *redis.Client
+	cacheDir     string
+}
+
+func setupRebalanceFixture(t *testing.T, clusterSize int) *rebalanceFixture {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	redisClient, err := redis.NewClient(&configtypes.RedisConfig{Addr: mr.Addr()}, zap.NewNop())
+	require.NoError(t, err)
+
+	cacheDir := t.TempDir()
+	metadataStore := cache.NewMetadataStore(redisClient, redis.NewKeyGenerator(), cacheDir, zap.NewNop())
+	registry := &staticRegistry{egs: makeEGs(clusterSize)}
+
+	return &rebalanceFixture{
+		registry:     registry,
+		distributor:  NewRendezvousDistributor(registry, zap.NewNop()),
+		client:       &fakePushClient{},
+		cacheService: cache.NewCacheService(metadataStore, cache.NewFilesystemCache(zap.NewNop()), zap.NewNop()),
+		metadata:     metadataStore,
+		redis:        redisClient,
+		cacheDir:     cacheDir,
+	}
+}
+
+func (f *rebalanceFixture) newRebalancer(egID string, dropUnowned bool) *Rebalancer {
+	cfg := &types.CacheShardingRebalanceConfig{Enabled: true, RateLimit: 1000, DropUnowned: dropUnowned}
+	return NewRebalancer(cfg, egID, 2, f.registry, f.distributor, f.client, f.cacheService, f.redis, testMetrics, zap.NewNop())
+}
+
+// storeEntry writes cache metadata held by egIDs and a local file for it
+func (f *rebalanceFixture) storeEntry(t *testing.T, urlHash string, egIDs []string, createdAt time.Time) *types.CacheKey {
+	t.Helper()
+
+	cacheKey := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: urlHash}
+	filePath := filepath.Join("1", urlHash+".html")
+	absolutePath := filepath.Join(f.cacheDir, filePath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(absolutePath), 0755))
+	require.NoError(t, os.WriteFile(absolutePath, []byte("<html>"+urlHash+"</html>"), 0644))
+
+	meta := &cache.CacheMetadata{
+		Key:        cacheKey.String(),
+		URL:        "https://example.com/" + urlHash,
+		FilePath:   filePath,
+		HostID:     1,
+		CreatedAt:  createdAt,
+		ExpiresAt:  createdAt.Add(24 * time.Hour),
+		LastAccess: createdAt,
+		Source:     cache.SourceRender,
+		StatusCode: 200,
+		EgIDs:      egIDs,
+	}
+	require.NoError(t, f.metadata.StoreMetadata(context.Background(), meta, cacheKey, 0))
+	return cacheKey
+}
+
+func (f *rebalanceFixture) egIDs(t *testing.T, cacheKey *types.CacheKey) []string {
+	t.Helper()
+	meta, err := f.metadata.GetCacheEntry(context.Background(), cacheKey)
+	require.NoError(t, err)
+	require.NotNil(t, meta)
+	return meta.EgIDs
+}
+
+func (f *rebalanceFixture) targets(t *testing.T, cacheKey *types.CacheKey) []string {
+	t.Helper()
+	targets, err := f.distributor.ComputeHashTargets(context.Background(), cacheKey.String(), 2)
+	require.NoError(t, err)
+	return targets
+}
+
+func (f *rebalanceFixture) fileExists(cacheKey *types.CacheKey) bool {
+	_, err := os.Stat(filepath.Join(f.cacheDir, "1", cacheKey.URLHash+".html"))
+	return err == nil
+}
+
+func TestRebalancer_RepairsUnderReplicatedEntry(t *testing.T) {
+	f := setupRebalanceFixture(t, 4)
+	created := time.Now().UTC().Add(-time.Hour)
+
+	probe := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "repair"}
+	targets := f.targets(t, probe)
+
+	// Second replica lived on an EG that left the cluster
+	cacheKey := f.storeEntry(t, "repair", []string{targets[0], "eg-gone"}, created)
+
+	stats, err := f.newRebalancer(targets[0], false).runOnce(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, stats.misplaced)
+	assert.Equal(t, 1, stats.repaired)
+	assert.Equal(t, []string{cacheKey.String()}, f.client.pushed[targets[1]])
+	assert.ElementsMatch(t, targets, f.egIDs(t, cacheKey))
+	assert.True(t, f.fileExists(cacheKey))
+}
+
+func TestRebalancer_OnlyFirstLiveHolderRepairs(t *testing.T) {
+	f := setupRebalanceFixture(t, 4)
+	created := time.Now().UTC().Add(-time.Hour)
+
+	probe := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "coord"}
+	targets := f.targets(t, probe)
+
+	// Two live holders, neither the second target: only the alphabetically first one pushes
+	var holders []string
+	for _, eg := range f.registry.egs {
+		if eg.EgID != targets[1] && len(holders) < 2 {
+			holders = append(holders, eg.EgID)
+		}
+	}
+	cacheKey := f.storeEntry(t, "coord", holders, created)
+
+	stats, err := f.newRebalancer(holders[1], false).runOnce(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.misplaced)
+	assert.Equal(t, 0, stats.repaired)
+	assert.Empty(t, f.client.pushed)
+
+	stats, err = f.newRebalancer(holders[0], false).runOnce(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.repaired)
+	assert.Contains(t, f.egIDs(t, cacheKey), targets[1])
+}
+
+func TestRebalancer_DropsUnownedCopy(t *testing.T) {
+	f := setupRebalanceFixture(t, 4)
+	created := time.Now().UTC().Add(-time.Hour)
+
+	probe := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "drop"}
+	targets := f.targets(t, probe)
+
+	outsider := ""
+	for _, eg := range f.registry.egs {
+		if eg.EgID != targets[0] && eg.EgID != targets[1] {
+			outsider = eg.EgID
+			break
+		}
+	}
+
+	t.Run("kept without drop_unowned", func(t *testing.T) {
+		cacheKey := f.storeEntry(t, "drop", append([]string{outsider}, targets...), created)
+
+		stats, err := f.newRebalancer(outsider, false).runOnce(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.misplaced)
+		assert.Equal(t, 0, stats.dropped)
+		assert.Contains(t, f.egIDs(t, cacheKey), outsider)
+		assert.True(t, f.fileExists(cacheKey))
+	})
+
+	t.Run("dropped once all targets hold a copy", func(t *testing.T) {
+		cacheKey := f.storeEntry(t, "drop", append([]string{outsider}, targets...), created)
+
+		stats, err := f.newRebalancer(outsider, true).runOnce(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.dropped)
+		assert.ElementsMatch(t, targets, f.egIDs(t, cacheKey))
+		assert.False(t, f.fileExists(cacheKey))
+	})
+
+	t.Run("sole holder pushes before dropping", func(t *testing.T) {
+		cacheKey := f.storeEntry(t, "drop", []string{outsider}, created)
+
+		stats, err := f.newRebalancer(outsider, true).runOnce(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.repaired)
+		assert.Equal(t, 1, stats.dropped)
+		assert.ElementsMatch(t, targets, f.egIDs(t, cacheKey))
+		assert.False(t, f.fileExists(cacheKey))
+	})
+}
+
+func TestRebalancer_PushFailureKeepsMetadata(t *testing.T) {
+	f := setupRebalanceFixture(t, 4)
+	created := time.Now().UTC().Add(-time.Hour)
+
+	probe := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "fail"}
+	targets := f.targets(t, probe)
+	f.client.failing = map[string]bool{targets[1]: true}
+
+	cacheKey := f.storeEntry(t, "fail", []string{targets[0]}, created)
+
+	stats, err := f.newRebalancer(targets[0], true).runOnce(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.failed)
+	assert.Equal(t, []string{targets[0]}, f.egIDs(t, cacheKey))
+}
+
+func TestRebalancer_SkipsEntries(t *testing.T) {
+	f := setupRebalanceFixture(t, 4)
+
+	probe := &types.CacheKey{HostID: 1, DimensionID: 1, URLHash: "fresh"}
+	targets := f.targets(t, probe)
+
+	// Created within min_age: render push may still be in flight
+	fresh := f.storeEntry(t, "fresh", []string{targets[0]}, time.Now().UTC())
+
+	// Not held by this EG
+	other := f.storeEntry(t, "other", []string{"eg-gone"}, time.Now().UTC().Add(-time.Hour))
+
+	stats, err := f.newRebalancer(targets[0], true).runOnce(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.scanned)
+	assert.Equal(t, 0, stats.misplaced)
+	assert.Empty(t, f.client.pushed)
+	assert.Equal(t, []string{targets[0]}, f.egIDs(t, fresh))
+	assert.Equal(t, []string{"eg-gone"}, f.egIDs(t, other))
+}
+
+func TestLuaCompareAndSetEgIDs(t *testing.T) {
+	f := setupRebalanceFixture(t, 2)
+	cacheKey := f.storeEntry(t, "cas", []string{"eg-01"}, time.Now().UTC())
+	metaKey := redis.NewKeyGenerator().GenerateMetadataKey(cacheKey)
+
+	result, err := f.redis.Eval(t.Context(), luaCompareAndSetEgIDs, []string{metaKey}, "eg-02", "eg-01,eg-02")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result)
+
+	result, err = f.redis.Eval(t.Context(), luaCompareAndSetEgIDs, []string{metaKey}, "eg-01", "eg-01,eg-02")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result)
+	assert.Equal(t, "eg-01,eg-02", strings.Join(f.egIDs(t, cacheKey), ","))
+
+	result, err = f.redis.Eval(t.Context(), luaCompareAndSetEgIDs, []string{"meta:cache:1:1:missing"}, "", "eg-01")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result)
+}
+
+func TestRebalancer_BatchesRepairsPerTarget(t *testing.T) {
+	f := setupRebalanceFixture(t, 2)
+	created := time.Now().UTC().Add(-time.Hour)
+
+	// With two EGs and replication 2, every entry held by eg-01 only is missing eg-02
+	var keys []*types.CacheKey
+	for i := 0; i < 5; i++ {
+		keys = append(keys, f.storeEntry(t, fmt.Sprintf("batch%d", i), []string{"eg-01"}, created))
+	}
+
+	stats, err := f.newRebalancer("eg-01", false).runOnce(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, stats.repaired)
+	assert.Equal(t, 1, f.client.batches)
+	assert.Len(t, f.client.pushed["eg-02"], 5)
+	for _, cacheKey := range keys {
+		assert.ElementsMatch(t, []string{"eg-01", "eg-02"}, f.egIDs(t, cacheKey))
+	}
+}
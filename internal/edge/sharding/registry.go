@@ -44,6 +44,8 @@ type RedisRegistry struct {
 	// Set once during Register(), read-only during Heartbeat()
 	egID    string
 	address string
+	weight  float64
+	zone    string
 }
 
 // NewRedisRegistry creates a new Redis-based registry
@@ -57,6 +59,18 @@ func NewRedisRegistry(redisClient *redis.Client, logger *zap.Logger) *RedisRegis
 	}
 }
 
+// SetWeight sets the distribution weight announced with every heartbeat
+// Must be called before Register()
+func (r *RedisRegistry) SetWeight(weight float64) {
+	r.weight = weight
+}
+
+// SetZone sets the availability zone announced with every heartbeat
+// Must be called before Register()
+func (r *RedisRegistry) SetZone(zone string) {
+	r.zone = zone
+}
+
 // Register registers an EG instance in the registry
 // Checks for duplicate eg_id, then delegates to Heartbeat() for actual registration
 func (r *RedisRegistry) Register(ctx context.Context, egID string, address string) error {
@@ -139,6 +153,8 @@ func (r *RedisRegistry) Heartbeat(ctx context.Context) error {
 		Address:         address,
 		LastHeartbeat:   time.Now().UTC(),
 		ShardingEnabled: true,
+		Weight:          r.weight,
+		Zone:            r.zone,
 	}
 
 	data, err := json.Marshal(info)
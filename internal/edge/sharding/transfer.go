@@ -0,0 +1,180 @@
+package sharding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// maxFrameHeaderSize bounds the JSON metadata of a single batch frame
+	maxFrameHeaderSize = 64 * 1024
+)
+
+// ErrChecksumMismatch is returned when transferred content does not match the sender's checksum
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrShortTransfer is returned when the stream ends before the announced size was received
+var ErrShortTransfer = errors.New("transfer truncated")
+
+// fileChecksum returns the size and xxhash64 checksum (hex) of a file, streaming its content
+func fileChecksum(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	return readerChecksum(f)
+}
+
+func readerChecksum(r io.Reader) (int64, string, error) {
+	digest := xxhash.New()
+	n, err := io.Copy(digest, r)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, formatChecksum(digest.Sum64()), nil
+}
+
+func formatChecksum(sum uint64) string {
+	return strconv.FormatUint(sum, 16)
+}
+
+// receiveToFile streams r into absolutePath through a temp file and renames it into place
+// once size (when >= 0) and checksum (when set) match. Exactly size bytes are consumed from r,
+// even on failure, so the next batch frame stays aligned.
+func receiveToFile(r io.Reader, absolutePath string, size int64, checksum string) (int64, error) {
+	if size >= 0 {
+		limited := io.LimitReader(r, size)
+		defer func() { _, _ = io.Copy(io.Discard, limited) }() // Drain remaining frame bytes
+		r = limited
+	}
+
+	if err := os.MkdirAll(filepath.Dir(absolutePath), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(absolutePath), filepath.Base(absolutePath)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tempPath := tmp.Name()
+
+	digest := xxhash.New()
+	written, err := io.Copy(io.MultiWriter(tmp, digest), r)
+	closeErr := tmp.Close()
+
+	switch {
+	case err != nil:
+		err = fmt.Errorf("failed to write temp file: %w", err)
+	case closeErr != nil:
+		err = fmt.Errorf("failed to close temp file: %w", closeErr)
+	case size >= 0 && written != size:
+		err = fmt.Errorf("%w: received %d of %d bytes", ErrShortTransfer, written, size)
+	case checksum != "" && formatChecksum(digest.Sum64()) != checksum:
+		err = fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, checksum, formatChecksum(digest.Sum64()))
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return written, err
+	}
+
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		os.Remove(tempPath)
+		return written, fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err := os.Rename(tempPath, absolutePath); err != nil {
+		os.Remove(tempPath)
+		return written, fmt.Errorf("failed to finalize file: %w", err)
+	}
+
+	return written, nil
+}
+
+// requestBody returns the streamed request body, or the buffered body when the
+// server does not stream requests
+func requestBody(ctx *fasthttp.RequestCtx) io.Reader {
+	if stream := ctx.RequestBodyStream(); stream != nil {
+		return stream
+	}
+	return bytes.NewReader(ctx.Request.Body())
+}
+
+// responseBody returns the streamed response body, or the buffered body for small responses
+func responseBody(resp *fasthttp.Response) io.Reader {
+	if stream := resp.BodyStream(); stream != nil {
+		return stream
+	}
+	return bytes.NewReader(resp.Body())
+}
+
+// Batch push wire format: a sequence of frames, each a 4-byte big-endian header length,
+// the ShardMetadata JSON header, then exactly header.Size bytes of cache file content.
+
+// encodeFrameHeader returns the length-prefixed JSON header of a batch frame
+func encodeFrameHeader(metadata *ShardMetadata) ([]byte, error) {
+	headerJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, 4+len(headerJSON))
+	binary.BigEndian.PutUint32(frame, uint32(len(headerJSON)))
+	copy(frame[4:], headerJSON)
+	return frame, nil
+}
+
+// readFrameHeader reads the next frame header. Returns io.EOF at a clean end of stream.
+func readFrameHeader(r *bufio.Reader) (*ShardMetadata, error) {
+	var lengthBuf [4]byte
+	if _, err := io.ReadFull(r, lengthBuf[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read frame length: %w", err)
+	}
+
+	length := binary.BigEndian.Uint32(lengthBuf[:])
+	if length == 0 || length > maxFrameHeaderSize {
+		return nil, fmt.Errorf("invalid frame header length %d", length)
+	}
+
+	headerJSON := make([]byte, length)
+	if _, err := io.ReadFull(r, headerJSON); err != nil {
+		return nil, fmt.Errorf("failed to read frame header: %w", err)
+	}
+
+	var metadata ShardMetadata
+	if err := json.Unmarshal(headerJSON, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid frame header: %w", err)
+	}
+	if metadata.Size < 0 {
+		return nil, fmt.Errorf("invalid frame size %d", metadata.Size)
+	}
+	return &metadata, nil
+}
+
+// multiFileReader streams frame headers and open cache files in sequence and closes
+// the files when fasthttp is done with the request body
+type multiFileReader struct {
+	io.Reader
+	files []*os.File
+}
+
+func (r *multiFileReader) Close() error {
+	for _, f := range r.files {
+		f.Close()
+	}
+	r.files = nil
+	return nil
+}
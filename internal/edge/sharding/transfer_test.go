@@ -0,0 +1,239 @@
+package sharding
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/edge/internal_server"
+	"github.com/edgecomet/engine/pkg/types"
+)
+
+func TestReceiveToFile(t *testing.T) {
+	content := []byte("<html>compressed bytes</html>")
+	_, checksum, err := readerChecksum(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	t.Run("verified write", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "a", "page.html")
+		n, err := receiveToFile(bytes.NewReader(content), path, int64(len(content)), checksum)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), n)
+
+		stored, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, content, stored)
+	})
+
+	t.Run("checksum mismatch leaves no file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "page.html")
+		_, err := receiveToFile(bytes.NewReader(content), path, int64(len(content)), "deadbeef")
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("short transfer", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "page.html")
+		_, err := receiveToFile(bytes.NewReader(content[:5]), path, int64(len(content)), checksum)
+		assert.ErrorIs(t, err, ErrShortTransfer)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("consumes exactly size bytes", func(t *testing.T) {
+		r := bytes.NewReader(append(append([]byte{}, content...), "next"...))
+		_, err := receiveToFile(r, filepath.Join(t.TempDir(), "page.html"), int64(len(content)), "deadbeef")
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+		assert.Equal(t, 4, r.Len())
+	})
+}
+
+func TestFrameHeader_RoundTrip(t *testing.T) {
+	var stream bytes.Buffer
+	for _, hash := range []string{"a", "b"} {
+		header, err := encodeFrameHeader(&ShardMetadata{
+			CacheKey: types.CacheKey{HostID: 1, DimensionID: 2, URLHash: hash},
+			FilePath: "1/" + hash + ".html",
+			Size:     3,
+			Checksum: "abc",
+		})
+		require.NoError(t, err)
+		stream.Write(header)
+		stream.WriteString("xyz")
+	}
+
+	r := bufio.NewReader(&stream)
+	for _, hash := range []string{"a", "b"} {
+		metadata, err := readFrameHeader(r)
+		require.NoError(t, err)
+		assert.Equal(t, hash, metadata.CacheKey.URLHash)
+		assert.Equal(t, int64(3), metadata.Size)
+		_, err = r.Discard(int(metadata.Size))
+		require.NoError(t, err)
+	}
+
+	_, err := readFrameHeader(r)
+	assert.Equal(t, io.EOF, err)
+}
+
+// addressRegistry resolves every EG to a single test server address
+type addressRegistry struct {
+	staticRegistry
+	address string
+}
+
+func (r *addressRegistry) GetEGAddress(ctx context.Context, egID string) (string, error) {
+	return r.address, nil
+}
+
+// setupTransferPeer starts a peer EG backed by a temp cache dir and returns a client for it
+func setupTransferPeer(t *testing.T) (*FastHTTPClient, *rebalanceFixture) {
+	t.Helper()
+
+	f := setupRebalanceFixture(t, 1)
+	m := &Manager{
+		egID:         "eg-peer",
+		cacheService: f.cacheService,
+		metrics:      testMetrics,
+		logger:       zap.NewNop(),
+	}
+
+	handlers := map[string]fasthttp.RequestHandler{
+		internal_server.PathCachePull:      m.handlePull,
+		internal_server.PathCachePush:      m.handlePush,
+		internal_server.PathCachePushBatch: m.handlePushBatch,
+	}
+	server := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			if handler, ok := handlers[string(ctx.Path())]; ok {
+				handler(ctx)
+				return
+			}
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		},
+		StreamRequestBody: true,
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	registry := &addressRegistry{address: ln.Addr().String()}
+	return NewFastHTTPClient(registry, "", "http", nil, 5*time.Second, zap.NewNop()), f
+}
+
+func writeSource(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, content, 0644))
+	return path
+}
+
+func TestClient_PushStreaming(t *testing.T) {
+	client, f := setupTransferPeer(t)
+	cacheDir := f.cacheDir
+	content := bytes.Repeat([]byte("<p>large page</p>"), 64*1024)
+
+	req := &PushRequest{
+		HostID:      1,
+		DimensionID: 1,
+		URLHash:     "big",
+		SourcePath:  writeSource(t, "big.html.br", content),
+		ExpiresAt:   time.Now().Add(time.Hour),
+		FilePath:    "1/big.html.br",
+	}
+	require.NoError(t, client.Push(t.Context(), "eg-peer", req))
+
+	stored, err := os.ReadFile(filepath.Join(cacheDir, "1", "big.html.br"))
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+	assert.NotEmpty(t, req.Checksum)
+}
+
+func TestClient_PullToFile(t *testing.T) {
+	client, f := setupTransferPeer(t)
+	cacheKey := f.storeEntry(t, "pulled", []string{"eg-peer"}, time.Now().UTC())
+
+	dest := filepath.Join(t.TempDir(), "pulled.html")
+	n, err := client.PullToFile(t.Context(), "eg-peer", &PullRequest{
+		HostID:      cacheKey.HostID,
+		DimensionID: cacheKey.DimensionID,
+		URLHash:     cacheKey.URLHash,
+	}, dest)
+	require.NoError(t, err)
+
+	stored, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "<html>pulled</html>", string(stored))
+	assert.Equal(t, int64(len(stored)), n)
+
+	resp, err := client.Pull(t.Context(), "eg-peer", &PullRequest{HostID: 1, DimensionID: 1, URLHash: "pulled"})
+	require.NoError(t, err)
+	assert.Equal(t, stored, resp.Content)
+}
+
+func TestClient_PushBatch(t *testing.T) {
+	client, f := setupTransferPeer(t)
+	cacheDir := f.cacheDir
+
+	var reqs []*PushRequest
+	for _, hash := range []string{"one", "two", "three"} {
+		reqs = append(reqs, &PushRequest{
+			HostID:      1,
+			DimensionID: 1,
+			URLHash:     hash,
+			SourcePath:  writeSource(t, hash+".html", []byte("<html>"+hash+"</html>")),
+			FilePath:    "1/" + hash + ".html",
+		})
+	}
+	// Unreadable source fails locally without breaking the batch
+	reqs = append(reqs, &PushRequest{HostID: 1, DimensionID: 1, URLHash: "gone", SourcePath: "/nonexistent", FilePath: "1/gone.html"})
+
+	results, err := client.PushBatch(t.Context(), "eg-peer", reqs)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	for i, hash := range []string{"one", "two", "three"} {
+		assert.NoError(t, results[i])
+		stored, err := os.ReadFile(filepath.Join(cacheDir, "1", hash+".html"))
+		require.NoError(t, err)
+		assert.Equal(t, "<html>"+hash+"</html>", string(stored))
+	}
+	assert.Error(t, results[3])
+}
+
+func TestHandlePush_RejectsChecksumMismatch(t *testing.T) {
+	client, f := setupTransferPeer(t)
+	cacheDir := f.cacheDir
+
+	req := &PushRequest{
+		HostID:      1,
+		DimensionID: 1,
+		URLHash:     "bad",
+		SourcePath:  writeSource(t, "bad.html", []byte("<html>bad</html>")),
+		FilePath:    "1/bad.html",
+	}
+	require.NoError(t, req.prepare())
+	req.Checksum = "0"
+
+	err := client.Push(t.Context(), "eg-peer", req)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "422"))
+	assert.NoFileExists(t, filepath.Join(cacheDir, "1", "bad.html"))
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/edgecomet/engine/internal/common/configtypes"
+	"github.com/edgecomet/engine/internal/common/htmlprocessor"
 	"github.com/edgecomet/engine/internal/common/yamlutil"
 	"github.com/edgecomet/engine/pkg/pattern"
 	"github.com/edgecomet/engine/pkg/types"
@@ -24,6 +25,12 @@ var validDimensionActions = map[types.URLRuleAction]bool{
 	types.ActionBlock:  true,
 }
 
+// validOutputFormats contains the valid values for a dimension's format field
+var validOutputFormats = map[string]bool{
+	types.OutputFormatHTML:     true,
+	types.OutputFormatMarkdown: true,
+}
+
 // validResourceTypes contains all valid Chrome DevTools Protocol resource types
 var validResourceTypes = map[string]bool{
 	"Document":           true,
@@ -342,7 +349,7 @@ func loadAndValidateMainConfig(path string, collector *ErrorCollector) (*configt
 	validateMetricsConfig(&cfg, filepath.Base(path), lineTracker, collector)
 
 	// Validate internal server configuration
-	validateInternalConfig(&cfg, filepath.Base(path), lineTracker, collector)
+	validateInternalConfig(&cfg, filepath.Dir(path), filepath.Base(path), lineTracker, collector)
 
 	// Validate render configuration
 	validateRenderConfig(&cfg, filepath.Base(path), lineTracker, collector)
@@ -377,6 +384,21 @@ func loadAndValidateMainConfig(path string, collector *ErrorCollector) (*configt
 	// Validate event logging
 	validateEventLoggingConfig(&cfg, filepath.Base(path), collector)
 
+	// Validate change detection
+	validateChangeDetectionConfig(&cfg, filepath.Base(path), collector)
+
+	// Validate popularity configuration
+	validatePopularityConfig(&cfg, filepath.Base(path), collector)
+
+	// Validate hot cache configuration
+	validateHotCacheConfig(&cfg, filepath.Base(path), collector)
+
+	// Validate SEO history configuration
+	validateSEOHistoryConfig(&cfg, filepath.Base(path), collector)
+
+	// Validate link graph configuration
+	validateLinkGraphConfig(&cfg, filepath.Base(path), collector)
+
 	// Validate TLS configuration
 	validateTLSConfig(&cfg, filepath.Dir(path), filepath.Base(path), collector)
 
@@ -677,6 +699,9 @@ func validateRenderConfig(cfg *configtypes.EgConfig, filename string, lt *LineTr
 		}
 	}
 
+	// Validate HTML transforms
+	validateHTMLTransforms(cfg.Render.Transforms, "render.transforms", filename, collector)
+
 	// Validate global dimensions
 	validateGlobalDimensions(cfg, filename, collector)
 
@@ -712,6 +737,10 @@ func validateGlobalDimensions(cfg *configtypes.EgConfig, filename string, collec
 			collector.Add(filename, 0, "dimensions: dimension '%s' has invalid action '%s' (must be 'render', 'bypass', or 'block')",
 				dimensionName, dimension.Action)
 		}
+		if dimension.Format != "" && !validOutputFormats[dimension.Format] {
+			collector.Add(filename, 0, "dimensions: dimension '%s' has invalid format '%s' (must be 'html' or 'markdown')",
+				dimensionName, dimension.Format)
+		}
 
 		// Bypass dimension constraints
 		if isBypassDimension {
@@ -830,9 +859,74 @@ func validateRegistryConfig(cfg *configtypes.EgConfig, filename string, lt *Line
 	// Validate selection_strategy
 	if cfg.Registry.SelectionStrategy != "" {
 		strategy := cfg.Registry.SelectionStrategy
-		if strategy != types.SelectionStrategyLeastLoaded && strategy != types.SelectionStrategyMostAvailable {
-			collector.Add(filename, 0, "invalid registry.selection_strategy '%s', must be '%s' or '%s'",
-				strategy, types.SelectionStrategyLeastLoaded, types.SelectionStrategyMostAvailable)
+		switch strategy {
+		case types.SelectionStrategyLeastLoaded, types.SelectionStrategyMostAvailable:
+		case types.SelectionStrategyHealthiest:
+			if !cfg.Registry.Health.IsEnabled() {
+				collector.Add(filename, 0, "registry.selection_strategy '%s' requires registry.health.enabled", strategy)
+			}
+		default:
+			collector.Add(filename, 0, "invalid registry.selection_strategy '%s', must be '%s', '%s' or '%s'",
+				strategy, types.SelectionStrategyLeastLoaded, types.SelectionStrategyMostAvailable, types.SelectionStrategyHealthiest)
+		}
+	}
+
+	validateRSHealthConfig(cfg.Registry.Health, filename, collector)
+	validateRSRetryConfig(cfg.Registry.Retry, filename, collector)
+}
+
+// validateRSHealthConfig validates render service health tracking configuration
+func validateRSHealthConfig(h *configtypes.RSHealthConfig, filename string, collector *ErrorCollector) {
+	if h == nil {
+		return
+	}
+
+	if h.HalfLife < 0 || h.OpenDuration < 0 || h.MaxOpenDuration < 0 {
+		collector.Add(filename, 0, "registry.health.half_life, open_duration and max_open_duration must be positive")
+	} else if h.GetOpenDuration() > h.GetMaxOpenDuration() {
+		collector.Add(filename, 0, "registry.health.open_duration (%v) must be <= max_open_duration (%v)",
+			h.GetOpenDuration(), h.GetMaxOpenDuration())
+	}
+	if h.MinRequests < 0 {
+		collector.Add(filename, 0, "registry.health.min_requests must be positive, got %d", h.MinRequests)
+	}
+	if h.MinSuccessRate < 0 || h.MinSuccessRate > 1 {
+		collector.Add(filename, 0, "registry.health.min_success_rate must be between 0 and 1, got %v", h.MinSuccessRate)
+	}
+	if h.MaxHardTimeoutRate < 0 || h.MaxHardTimeoutRate > 1 {
+		collector.Add(filename, 0, "registry.health.max_hard_timeout_rate must be between 0 and 1, got %v", h.MaxHardTimeoutRate)
+	}
+}
+
+// retryableErrorTypes are the render service error types a render retry can be keyed on
+var retryableErrorTypes = map[string]bool{
+	types.ErrorTypeHardTimeout:          true,
+	types.ErrorTypeChromeCrash:          true,
+	types.ErrorTypeChromeRestartFailed:  true,
+	types.ErrorTypePoolUnavailable:      true,
+	types.ErrorTypeConnectionFailed:     true,
+	types.ErrorTypeSoftTimeout:          true,
+	types.ErrorTypeNavigationFailed:     true,
+	types.ErrorTypeNetworkError:         true,
+	types.ErrorTypeHTMLExtractionFailed: true,
+	types.ErrorTypeStatusCaptureFailed:  true,
+}
+
+// validateRSRetryConfig validates render retry configuration
+func validateRSRetryConfig(r *configtypes.RSRetryConfig, filename string, collector *ErrorCollector) {
+	if r == nil {
+		return
+	}
+
+	if r.MaxAttempts < 0 || r.MaxAttempts > 5 {
+		collector.Add(filename, 0, "registry.retry.max_attempts must be between 1 and 5, got %d", r.MaxAttempts)
+	}
+	if r.MinRemaining < 0 {
+		collector.Add(filename, 0, "registry.retry.min_remaining must be positive")
+	}
+	for _, errorType := range r.ErrorTypes {
+		if !retryableErrorTypes[errorType] {
+			collector.Add(filename, 0, "registry.retry.error_types: '%s' is not a retryable render error type", errorType)
 		}
 	}
 }
@@ -936,7 +1030,7 @@ func validateMetricsConfig(cfg *configtypes.EgConfig, filename string, lt *LineT
 	}
 }
 
-func validateInternalConfig(cfg *configtypes.EgConfig, filename string, lt *LineTracker, collector *ErrorCollector) {
+func validateInternalConfig(cfg *configtypes.EgConfig, configDir string, filename string, lt *LineTracker, collector *ErrorCollector) {
 	lineNum := 0
 
 	// internal.listen is required
@@ -953,12 +1047,42 @@ func validateInternalConfig(cfg *configtypes.EgConfig, filename string, lt *Line
 		}
 	}
 
-	// internal.auth_key is required
+	// internal.auth_key is required unless scoped credentials replace it
 	if cfg.Internal.AuthKey == "" {
-		collector.Add(filename, lineNum, "internal.auth_key is required")
+		if len(cfg.Internal.Credentials) == 0 {
+			collector.Add(filename, lineNum, "internal.auth_key is required (or configure internal.credentials)")
+		}
 	} else if len(cfg.Internal.AuthKey) < 16 {
 		collector.AddWarning(filename, lineNum, "internal.auth_key is short (%d chars), recommend 32+ characters for security", len(cfg.Internal.AuthKey))
 	}
+
+	// Peers need a key to call each other unless they authenticate with client certificates
+	shardingEnabled := cfg.CacheSharding != nil && cfg.CacheSharding.Enabled != nil && *cfg.CacheSharding.Enabled
+	if shardingEnabled && cfg.Internal.GetClientKey() == "" && cfg.Internal.TLS.CAFile == "" {
+		collector.Add(filename, lineNum, "internal.client_key is required when cache_sharding is enabled without internal.auth_key or mutual TLS")
+	}
+
+	if err := cfg.Internal.TLS.Validate("internal.tls"); err != nil {
+		collector.Add(filename, lineNum, "%v", err)
+	} else if cfg.Internal.TLS.Enabled {
+		files := []struct{ name, path string }{
+			{"cert_file", cfg.Internal.TLS.CertFile},
+			{"key_file", cfg.Internal.TLS.KeyFile},
+			{"ca_file", cfg.Internal.TLS.CAFile},
+		}
+		for _, file := range files {
+			if file.path == "" {
+				continue
+			}
+			if _, err := resolvePath(file.path, configDir); err != nil {
+				collector.Add(filename, lineNum, "internal.tls.%s not found: %s", file.name, file.path)
+			}
+		}
+	}
+
+	if err := configtypes.ValidateInternalCredentials("internal.credentials", cfg.Internal.Credentials, &cfg.Internal.TLS); err != nil {
+		collector.Add(filename, lineNum, "%v", err)
+	}
 }
 
 // validateTimeoutRanges validates timeout configuration and warns about dangerously low or high values
@@ -1034,11 +1158,36 @@ func validateCacheShardingConfig(cfg *configtypes.EgConfig, filename string, lt
 	if cs.DistributionStrategy != "" {
 		validStrategies := map[string]bool{
 			"hash_modulo":  true,
+			"rendezvous":   true,
 			"random":       true,
 			"primary_only": true,
 		}
 		if !validStrategies[cs.DistributionStrategy] {
-			collector.Add(filename, lineNum, "invalid cache_sharding.distribution_strategy: %q (must be: hash_modulo, random, primary_only)", cs.DistributionStrategy)
+			collector.Add(filename, lineNum, "invalid cache_sharding.distribution_strategy: %q (must be: hash_modulo, rendezvous, random, primary_only)", cs.DistributionStrategy)
+		}
+	}
+
+	// Weight only affects rendezvous distribution
+	if cs.Weight != nil {
+		if *cs.Weight <= 0 {
+			collector.Add(filename, lineNum, "cache_sharding.weight must be > 0, got %v", *cs.Weight)
+		} else if cs.DistributionStrategy != "rendezvous" {
+			collector.AddWarning(filename, lineNum, "cache_sharding.weight is ignored unless distribution_strategy is rendezvous")
+		}
+	}
+
+	if rb := cs.Rebalance; rb != nil && rb.Enabled {
+		if rb.Interval != 0 && rb.Interval.ToDuration() < time.Minute {
+			collector.Add(filename, lineNum, "cache_sharding.rebalance.interval must be at least 1m, got %s", rb.Interval.ToDuration())
+		}
+		if rb.RateLimit < 0 {
+			collector.Add(filename, lineNum, "cache_sharding.rebalance.rate_limit must be >= 0, got %d", rb.RateLimit)
+		}
+		if rb.MinAge < 0 {
+			collector.Add(filename, lineNum, "cache_sharding.rebalance.min_age must be >= 0, got %s", rb.MinAge.ToDuration())
+		}
+		if cs.DistributionStrategy == "random" || cs.DistributionStrategy == "primary_only" {
+			collector.AddWarning(filename, lineNum, "cache_sharding.rebalance is ignored with distribution_strategy %q", cs.DistributionStrategy)
 		}
 	}
 }
@@ -1125,6 +1274,112 @@ func validateEventLoggingConfig(cfg *configtypes.EgConfig, filename string, coll
 	}
 }
 
+// validateChangeDetectionConfig validates recache change detection configuration
+func validateChangeDetectionConfig(cfg *configtypes.EgConfig, filename string, collector *ErrorCollector) {
+	if cfg.ChangeDetection == nil {
+		return
+	}
+
+	threshold := cfg.ChangeDetection.SimilarityThreshold
+	if threshold < 0 || threshold > 1 {
+		collector.Add(filename, 0, "change_detection.similarity_threshold must be between 0 and 1, got %v", threshold)
+	}
+}
+
+// validatePopularityConfig validates adaptive popularity configuration
+func validatePopularityConfig(cfg *configtypes.EgConfig, filename string, collector *ErrorCollector) {
+	p := cfg.Popularity
+	if p == nil {
+		return
+	}
+
+	if p.HalfLife < 0 {
+		collector.Add(filename, 0, "popularity.half_life must be positive, got %v", time.Duration(p.HalfLife))
+	}
+	if p.HotScore < 0 {
+		collector.Add(filename, 0, "popularity.hot_score must be positive, got %v", p.HotScore)
+	}
+	if p.HotFactor < 0 || p.ColdFactor < 0 {
+		collector.Add(filename, 0, "popularity.hot_factor and cold_factor must be positive")
+	} else if p.GetHotFactor() > p.GetColdFactor() {
+		collector.Add(filename, 0, "popularity.hot_factor (%v) must be <= cold_factor (%v)", p.GetHotFactor(), p.GetColdFactor())
+	}
+
+	if p.MinTTL < 0 || p.MaxTTL < 0 {
+		collector.Add(filename, 0, "popularity.min_ttl and max_ttl must be positive")
+	} else if p.GetMinTTL() > p.GetMaxTTL() {
+		collector.Add(filename, 0, "popularity.min_ttl (%v) must be <= max_ttl (%v)", p.GetMinTTL(), p.GetMaxTTL())
+	}
+
+	// Same range as bothit_recache.interval
+	minInterval, maxInterval := p.GetMinRecacheInterval(), p.GetMaxRecacheInterval()
+	if minInterval < 30*time.Minute {
+		collector.Add(filename, 0, "popularity.min_recache_interval must be >= 30m, got %v", minInterval)
+	}
+	if maxInterval > 24*time.Hour {
+		collector.Add(filename, 0, "popularity.max_recache_interval must be <= 24h, got %v", maxInterval)
+	}
+	if minInterval > maxInterval {
+		collector.Add(filename, 0, "popularity.min_recache_interval (%v) must be <= max_recache_interval (%v)", minInterval, maxInterval)
+	}
+}
+
+// validateHotCacheConfig validates in-process hot cache configuration
+func validateHotCacheConfig(cfg *configtypes.EgConfig, filename string, collector *ErrorCollector) {
+	h := cfg.HotCache
+	if h == nil {
+		return
+	}
+
+	if h.MaxEntries < 0 {
+		collector.Add(filename, 0, "hot_cache.max_entries must be positive, got %d", h.MaxEntries)
+	}
+	if h.MaxSize < 0 || h.MaxBodySize < 0 {
+		collector.Add(filename, 0, "hot_cache.max_size and max_body_size must be positive")
+	} else if h.GetMaxBodySizeBytes() > h.GetMaxSizeBytes() {
+		collector.Add(filename, 0, "hot_cache.max_body_size (%dMB) must be <= max_size (%dMB)",
+			h.GetMaxBodySizeBytes()>>20, h.GetMaxSizeBytes()>>20)
+	}
+	if h.MaxAge < 0 {
+		collector.Add(filename, 0, "hot_cache.max_age must be positive, got %v", time.Duration(h.MaxAge))
+	}
+}
+
+// validateSEOHistoryConfig validates PageSEO history configuration
+func validateSEOHistoryConfig(cfg *configtypes.EgConfig, filename string, collector *ErrorCollector) {
+	h := cfg.SEOHistory
+	if h == nil {
+		return
+	}
+
+	if h.MaxVersions < 0 {
+		collector.Add(filename, 0, "seo_history.max_versions must be positive, got %d", h.MaxVersions)
+	}
+	if h.Retention < 0 {
+		collector.Add(filename, 0, "seo_history.retention must be positive, got %v", time.Duration(h.Retention))
+	}
+}
+
+// validateLinkGraphConfig validates internal link graph configuration
+func validateLinkGraphConfig(cfg *configtypes.EgConfig, filename string, collector *ErrorCollector) {
+	l := cfg.LinkGraph
+	if l == nil {
+		return
+	}
+
+	if l.Retention < 0 {
+		collector.Add(filename, 0, "link_graph.retention must be positive, got %v", time.Duration(l.Retention))
+	}
+	if l.Discover != nil {
+		if l.Discover.MaxDepth < 0 {
+			collector.Add(filename, 0, "link_graph.discover.max_depth must be positive, got %d", l.Discover.MaxDepth)
+		}
+		if l.Discover.Enabled && !l.Enabled {
+			collector.Add(filename, 0, "link_graph.discover requires link_graph.enabled")
+		}
+	}
+}
+
 // validateStorageConfig validates storage configuration
 func validateStorageConfig(cfg *configtypes.EgConfig, hostsConfig *configtypes.HostsConfig, filename string, collector *ErrorCollector) {
 	basePath := strings.TrimSpace(cfg.Storage.BasePath)
@@ -1328,6 +1583,94 @@ func validateHostBlockedResourceTypes(hostIndex int, host *types.Host, filename
 	}
 }
 
+// validateHostLLMsTxt validates generated llms.txt configuration at host level
+func validateHostLLMsTxt(hostIndex int, host *types.Host, filename string, collector *ErrorCollector) {
+	if host.LLMsTxt == nil {
+		return
+	}
+	if host.LLMsTxt.MaxPages < 0 {
+		collector.Add(filename, 0, "host[%d] (%s): llms_txt.max_pages must be non-negative, got %d",
+			hostIndex, host.Domain, host.LLMsTxt.MaxPages)
+	}
+	if host.LLMsTxt.TTL != nil && *host.LLMsTxt.TTL < 0 {
+		collector.Add(filename, 0, "host[%d] (%s): llms_txt.ttl must be non-negative, got %v",
+			hostIndex, host.Domain, time.Duration(*host.LLMsTxt.TTL))
+	}
+}
+
+// validateHostSEOAudit validates SEO audit thresholds at host level
+func validateHostSEOAudit(hostIndex int, host *types.Host, filename string, collector *ErrorCollector) {
+	if host.SEOAudit == nil {
+		return
+	}
+	if host.SEOAudit.MinWordCount < 0 {
+		collector.Add(filename, 0, "host[%d] (%s): seo_audit.min_word_count must be non-negative, got %d",
+			hostIndex, host.Domain, host.SEOAudit.MinWordCount)
+	}
+	if host.SEOAudit.MaxImagesWithoutAlt < 0 {
+		collector.Add(filename, 0, "host[%d] (%s): seo_audit.max_images_without_alt must be non-negative, got %d",
+			hostIndex, host.Domain, host.SEOAudit.MaxImagesWithoutAlt)
+	}
+}
+
+// validateHostRobotsTxt validates robots.txt configuration at host level
+func validateHostRobotsTxt(hostIndex int, host *types.Host, filename string, collector *ErrorCollector) {
+	if host.RobotsTxt == nil {
+		return
+	}
+	if host.RobotsTxt.TTL != nil && *host.RobotsTxt.TTL < 0 {
+		collector.Add(filename, 0, "host[%d] (%s): robots_txt.ttl must be non-negative, got %v",
+			hostIndex, host.Domain, time.Duration(*host.RobotsTxt.TTL))
+	}
+	if host.RobotsTxt.UserAgent != "" && strings.ContainsAny(host.RobotsTxt.UserAgent, " \t") {
+		collector.Add(filename, 0, "host[%d] (%s): robots_txt.user_agent must be a single product token, got '%s'",
+			hostIndex, host.Domain, host.RobotsTxt.UserAgent)
+	}
+	if status := host.RobotsTxt.DisallowedStatus; status != 0 && (status < 400 || status > 599) {
+		collector.Add(filename, 0, "host[%d] (%s): robots_txt.disallowed_status must be a 4xx or 5xx status code, got %d",
+			hostIndex, host.Domain, status)
+	}
+}
+
+// validateHTMLTransforms validates an HTML post-processing pipeline (types, required fields, selectors)
+func validateHTMLTransforms(transforms []types.HTMLTransform, contextPrefix string, filename string, collector *ErrorCollector) {
+	for i, t := range transforms {
+		if err := htmlprocessor.ValidateTransform(t); err != nil {
+			collector.Add(filename, 0, "%s[%d]: %v", contextPrefix, i, err)
+		}
+	}
+}
+
+// validateRenderQuality validates render quality gates (non-negative limits, selectors, forbidden text)
+func validateRenderQuality(quality *types.RenderQualityConfig, contextPrefix string, filename string, collector *ErrorCollector) {
+	if quality == nil {
+		return
+	}
+
+	if quality.MinWordCount < 0 {
+		collector.Add(filename, 0, "%s.min_word_count must be non-negative, got %d", contextPrefix, quality.MinWordCount)
+	}
+	if quality.MaxConsoleErrors != nil && *quality.MaxConsoleErrors < 0 {
+		collector.Add(filename, 0, "%s.max_console_errors must be non-negative, got %d", contextPrefix, *quality.MaxConsoleErrors)
+	}
+	if quality.MaxAttempts < 0 {
+		collector.Add(filename, 0, "%s.max_attempts must be positive, got %d", contextPrefix, quality.MaxAttempts)
+	}
+	if quality.RetryBackoff < 0 {
+		collector.Add(filename, 0, "%s.retry_backoff must be positive, got %v", contextPrefix, time.Duration(quality.RetryBackoff))
+	}
+	for i, selector := range quality.RequiredSelectors {
+		if _, err := htmlprocessor.CompileSelector(selector); err != nil {
+			collector.Add(filename, 0, "%s.required_selectors[%d]: %v", contextPrefix, i, err)
+		}
+	}
+	for i, text := range quality.ForbiddenText {
+		if strings.TrimSpace(text) == "" {
+			collector.Add(filename, 0, "%s.forbidden_text[%d] must not be empty", contextPrefix, i)
+		}
+	}
+}
+
 // CompiledStripPattern represents a compiled pattern for parameter stripping
 type CompiledStripPattern struct {
 	Original    string
@@ -1635,6 +1978,17 @@ func validateHosts(hosts *configtypes.HostsConfig, filename string, hasGlobalDim
 
 		// Validate blocked resource types
 		validateHostBlockedResourceTypes(i, host, filename, collector)
+
+		// Validate HTML transforms
+		validateHTMLTransforms(host.Render.Transforms, fmt.Sprintf("host[%d] (%s): render.transforms", i, host.Domain), filename, collector)
+
+		// Validate render quality gates
+		validateRenderQuality(host.Render.Quality, fmt.Sprintf("host[%d] (%s): render.quality", i, host.Domain), filename, collector)
+
+		// Validate llms_txt
+		validateHostLLMsTxt(i, host, filename, collector)
+		validateHostSEOAudit(i, host, filename, collector)
+		validateHostRobotsTxt(i, host, filename, collector)
 	}
 }
 
@@ -1657,6 +2011,10 @@ func validateDimensions(hostIndex int, host *types.Host, filename string, hasGlo
 			collector.Add(filename, 0, "host[%d] (%s): dimension '%s' has invalid action '%s' (must be 'render', 'bypass', or 'block')",
 				hostIndex, host.Domain, dimensionName, dimension.Action)
 		}
+		if dimension.Format != "" && !validOutputFormats[dimension.Format] {
+			collector.Add(filename, 0, "host[%d] (%s): dimension '%s' has invalid format '%s' (must be 'html' or 'markdown')",
+				hostIndex, host.Domain, dimensionName, dimension.Format)
+		}
 
 		// Bypass dimension constraints
 		if isBypassDimension {
@@ -1910,6 +2268,12 @@ func validateRuleActionConfig(hostIndex, ruleIndex int, rule *types.URLRule, hos
 						hostIndex, host.Domain, ruleIndex, rt)
 				}
 			}
+			// Validate HTML transforms
+			validateHTMLTransforms(rule.Render.Transforms,
+				fmt.Sprintf("host[%d] (%s): url_rules[%d]: render.transforms", hostIndex, host.Domain, ruleIndex), filename, collector)
+			// Validate render quality gates
+			validateRenderQuality(rule.Render.Quality,
+				fmt.Sprintf("host[%d] (%s): url_rules[%d]: render.quality", hostIndex, host.Domain, ruleIndex), filename, collector)
 		}
 
 	case types.ActionBypass:
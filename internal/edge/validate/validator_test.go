@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -3752,6 +3753,379 @@ hosts:
 	}
 }
 
+func TestValidateChangeDetectionConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		wantErr   bool
+	}{
+		{name: "zero uses default", threshold: 0},
+		{name: "valid threshold", threshold: 0.95},
+		{name: "one is valid", threshold: 1},
+		{name: "negative fails", threshold: -0.1, wantErr: true},
+		{name: "above one fails", threshold: 1.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			cfg := &configtypes.EgConfig{
+				ChangeDetection: &configtypes.ChangeDetectionConfig{SimilarityThreshold: tt.threshold},
+			}
+			validateChangeDetectionConfig(cfg, "edge-gateway.yaml", collector)
+
+			assert.Equal(t, tt.wantErr, collector.HasErrors(), "errors: %v", collector.Errors())
+			if tt.wantErr {
+				assert.Contains(t, collector.Errors()[0].Message, "change_detection.similarity_threshold")
+			}
+		})
+	}
+}
+
+func TestValidatePopularityConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *configtypes.PopularityConfig
+		errContains string
+	}{
+		{name: "nil config is valid"},
+		{name: "defaults are valid", config: &configtypes.PopularityConfig{Enabled: true}},
+		{
+			name:        "negative half life",
+			config:      &configtypes.PopularityConfig{HalfLife: types.Duration(-time.Hour)},
+			errContains: "popularity.half_life",
+		},
+		{
+			name:        "hot factor above cold factor",
+			config:      &configtypes.PopularityConfig{HotFactor: 2, ColdFactor: 1},
+			errContains: "popularity.hot_factor",
+		},
+		{
+			name:        "min ttl above max ttl",
+			config:      &configtypes.PopularityConfig{MinTTL: types.Duration(48 * time.Hour), MaxTTL: types.Duration(24 * time.Hour)},
+			errContains: "popularity.min_ttl",
+		},
+		{
+			name:        "recache interval below 30m",
+			config:      &configtypes.PopularityConfig{MinRecacheInterval: types.Duration(10 * time.Minute)},
+			errContains: "popularity.min_recache_interval",
+		},
+		{
+			name:        "recache interval above 24h",
+			config:      &configtypes.PopularityConfig{MaxRecacheInterval: types.Duration(48 * time.Hour)},
+			errContains: "popularity.max_recache_interval",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validatePopularityConfig(&configtypes.EgConfig{Popularity: tt.config}, "edge-gateway.yaml", collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
+func TestValidateHotCacheConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *configtypes.HotCacheConfig
+		errContains string
+	}{
+		{name: "nil config is valid"},
+		{name: "defaults are valid", config: &configtypes.HotCacheConfig{Enabled: true}},
+		{
+			name:        "negative max entries",
+			config:      &configtypes.HotCacheConfig{MaxEntries: -1},
+			errContains: "hot_cache.max_entries",
+		},
+		{
+			name:        "body size above max size",
+			config:      &configtypes.HotCacheConfig{MaxSize: 4, MaxBodySize: 8},
+			errContains: "hot_cache.max_body_size",
+		},
+		{
+			name:        "negative max age",
+			config:      &configtypes.HotCacheConfig{MaxAge: types.Duration(-time.Minute)},
+			errContains: "hot_cache.max_age",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validateHotCacheConfig(&configtypes.EgConfig{HotCache: tt.config}, "edge-gateway.yaml", collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
+func TestValidateSEOHistoryConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *configtypes.SEOHistoryConfig
+		errContains string
+	}{
+		{name: "nil config is valid"},
+		{name: "defaults are valid", config: &configtypes.SEOHistoryConfig{Enabled: true}},
+		{
+			name:        "negative max versions",
+			config:      &configtypes.SEOHistoryConfig{MaxVersions: -1},
+			errContains: "seo_history.max_versions",
+		},
+		{
+			name:        "negative retention",
+			config:      &configtypes.SEOHistoryConfig{Retention: types.Duration(-time.Hour)},
+			errContains: "seo_history.retention",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validateSEOHistoryConfig(&configtypes.EgConfig{SEOHistory: tt.config}, "edge-gateway.yaml", collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
+func TestValidateLinkGraphConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *configtypes.LinkGraphConfig
+		errContains string
+	}{
+		{name: "nil config is valid"},
+		{
+			name:   "discover with defaults is valid",
+			config: &configtypes.LinkGraphConfig{Enabled: true, Discover: &configtypes.LinkDiscoveryConfig{Enabled: true}},
+		},
+		{
+			name:        "negative retention",
+			config:      &configtypes.LinkGraphConfig{Enabled: true, Retention: types.Duration(-time.Hour)},
+			errContains: "link_graph.retention",
+		},
+		{
+			name:        "negative max depth",
+			config:      &configtypes.LinkGraphConfig{Enabled: true, Discover: &configtypes.LinkDiscoveryConfig{MaxDepth: -1}},
+			errContains: "link_graph.discover.max_depth",
+		},
+		{
+			name:        "discover without link graph",
+			config:      &configtypes.LinkGraphConfig{Discover: &configtypes.LinkDiscoveryConfig{Enabled: true}},
+			errContains: "requires link_graph.enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validateLinkGraphConfig(&configtypes.EgConfig{LinkGraph: tt.config}, "edge-gateway.yaml", collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
+func TestValidateRegistryConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      configtypes.EdgeRegistryConfig
+		errContains string
+	}{
+		{name: "default strategy", config: configtypes.EdgeRegistryConfig{SelectionStrategy: types.SelectionStrategyLeastLoaded}},
+		{
+			name: "healthiest with health enabled",
+			config: configtypes.EdgeRegistryConfig{
+				SelectionStrategy: types.SelectionStrategyHealthiest,
+				Health:            &configtypes.RSHealthConfig{Enabled: true},
+			},
+		},
+		{
+			name:        "unknown strategy",
+			config:      configtypes.EdgeRegistryConfig{SelectionStrategy: "random"},
+			errContains: "invalid registry.selection_strategy",
+		},
+		{
+			name:        "healthiest without health",
+			config:      configtypes.EdgeRegistryConfig{SelectionStrategy: types.SelectionStrategyHealthiest},
+			errContains: "requires registry.health.enabled",
+		},
+		{
+			name: "success rate above 1",
+			config: configtypes.EdgeRegistryConfig{
+				Health: &configtypes.RSHealthConfig{Enabled: true, MinSuccessRate: 1.5},
+			},
+			errContains: "registry.health.min_success_rate",
+		},
+		{
+			name: "open duration above max",
+			config: configtypes.EdgeRegistryConfig{
+				Health: &configtypes.RSHealthConfig{
+					Enabled:         true,
+					OpenDuration:    types.Duration(10 * time.Minute),
+					MaxOpenDuration: types.Duration(time.Minute),
+				},
+			},
+			errContains: "registry.health.open_duration",
+		},
+		{
+			name: "retry with defaults",
+			config: configtypes.EdgeRegistryConfig{
+				Retry: &configtypes.RSRetryConfig{Enabled: true},
+			},
+		},
+		{
+			name: "retry max attempts above limit",
+			config: configtypes.EdgeRegistryConfig{
+				Retry: &configtypes.RSRetryConfig{Enabled: true, MaxAttempts: 10},
+			},
+			errContains: "registry.retry.max_attempts",
+		},
+		{
+			name: "retry on origin error",
+			config: configtypes.EdgeRegistryConfig{
+				Retry: &configtypes.RSRetryConfig{Enabled: true, ErrorTypes: []string{types.ErrorTypeOrigin5xx}},
+			},
+			errContains: "not a retryable render error type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validateRegistryConfig(&configtypes.EgConfig{Registry: tt.config}, "edge-gateway.yaml", nil, collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
+func TestValidateHTMLTransforms(t *testing.T) {
+	tests := []struct {
+		name        string
+		transforms  []types.HTMLTransform
+		errContains string
+	}{
+		{
+			name: "valid pipeline",
+			transforms: []types.HTMLTransform{
+				{Type: types.HTMLTransformRemove, Selector: "#cookie-banner, .chat-widget"},
+				{Type: types.HTMLTransformInjectHead, Selector: `link[rel="canonical"]`, HTML: `<link rel="canonical" href="{url}">`},
+				{Type: types.HTMLTransformMinify},
+			},
+		},
+		{
+			name:        "unknown type",
+			transforms:  []types.HTMLTransform{{Type: "strip"}},
+			errContains: "render.transforms[0]: unknown transform type 'strip'",
+		},
+		{
+			name: "invalid selector",
+			transforms: []types.HTMLTransform{
+				{Type: types.HTMLTransformMinify},
+				{Type: types.HTMLTransformRemove, Selector: "div:first-child"},
+			},
+			errContains: "render.transforms[1]: invalid selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validateHTMLTransforms(tt.transforms, "render.transforms", "edge-gateway.yaml", collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
+func TestValidateRenderQuality(t *testing.T) {
+	negative := -1
+	tests := []struct {
+		name        string
+		quality     *types.RenderQualityConfig
+		errContains string
+	}{
+		{name: "nil config is valid"},
+		{
+			name: "valid gates",
+			quality: &types.RenderQualityConfig{
+				MinWordCount:      50,
+				RequiredSelectors: []string{"#app main", "h1"},
+				ForbiddenText:     []string{"Something went wrong"},
+			},
+		},
+		{
+			name:        "negative min word count",
+			quality:     &types.RenderQualityConfig{MinWordCount: -5},
+			errContains: "render.quality.min_word_count",
+		},
+		{
+			name:        "negative console errors",
+			quality:     &types.RenderQualityConfig{MaxConsoleErrors: &negative},
+			errContains: "render.quality.max_console_errors",
+		},
+		{
+			name:        "invalid selector",
+			quality:     &types.RenderQualityConfig{RequiredSelectors: []string{"#app", "li:nth-child(2)"}},
+			errContains: "render.quality.required_selectors[1]: invalid selector",
+		},
+		{
+			name:        "empty forbidden text",
+			quality:     &types.RenderQualityConfig{ForbiddenText: []string{" "}},
+			errContains: "render.quality.forbidden_text[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewErrorCollector()
+			validateRenderQuality(tt.quality, "render.quality", "hosts.yaml", collector)
+
+			if tt.errContains == "" {
+				assert.False(t, collector.HasErrors(), "errors: %v", collector.Errors())
+				return
+			}
+			require.True(t, collector.HasErrors())
+			assert.Contains(t, collector.Errors()[0].Message, tt.errContains)
+		})
+	}
+}
+
 func TestValidateClientIPConfig(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -3935,6 +4309,29 @@ func TestValidateConfiguration_DimensionAction(t *testing.T) {
 			wantErr:       true,
 			expectedError: "has invalid action 'unknown'",
 		},
+		{
+			name: "valid format markdown",
+			dimensions: `      desktop:
+        id: 1
+        width: 1920
+        height: 1080
+        render_ua: "Mozilla/5.0"
+        format: "markdown"
+        match_ua:
+          - "*GPTBot*"`,
+			wantErr: false,
+		},
+		{
+			name: "invalid format unknown",
+			dimensions: `      desktop:
+        id: 1
+        width: 1920
+        height: 1080
+        render_ua: "Mozilla/5.0"
+        format: "pdf"`,
+			wantErr:       true,
+			expectedError: "has invalid format 'pdf'",
+		},
 		{
 			name: "bypass dimension with wrong id",
 			dimensions: `      desktop:
@@ -4092,6 +4489,203 @@ func TestValidateConfiguration_DimensionAction(t *testing.T) {
 	}
 }
 
+func TestValidateConfiguration_LLMsTxt(t *testing.T) {
+	dimensions := `      desktop:
+        id: 1
+        width: 1920
+        height: 1080
+        render_ua: "Mozilla/5.0"`
+
+	tests := []struct {
+		name          string
+		llmsTxt       string
+		wantErr       bool
+		expectedError string
+	}{
+		{
+			name: "valid llms_txt",
+			llmsTxt: `    llms_txt:
+      enabled: true
+      title: "Example"
+      max_pages: 100
+      ttl: 30m`,
+			wantErr: false,
+		},
+		{
+			name: "negative max_pages",
+			llmsTxt: `    llms_txt:
+      enabled: true
+      max_pages: -1`,
+			wantErr:       true,
+			expectedError: "llms_txt.max_pages must be non-negative",
+		},
+		{
+			name: "negative ttl",
+			llmsTxt: `    llms_txt:
+      enabled: true
+      ttl: -5m`,
+			wantErr:       true,
+			expectedError: "llms_txt.ttl must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeValidationTestConfig(t, dimensions, tt.llmsTxt)
+			result, err := ValidateConfiguration(configPath)
+			require.NoError(t, err)
+
+			if tt.wantErr {
+				assert.False(t, result.Valid, "Expected configuration to be invalid")
+				found := false
+				for _, e := range result.Errors {
+					if strings.Contains(e.Message, tt.expectedError) {
+						found = true
+						break
+					}
+				}
+				assert.True(t, found, "Expected error containing '%s', got errors: %v", tt.expectedError, result.Errors)
+			} else {
+				assert.True(t, result.Valid, "Expected configuration to be valid, got errors: %v", result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfiguration_SEOAudit(t *testing.T) {
+	dimensions := `      desktop:
+        id: 1
+        width: 1920
+        height: 1080
+        render_ua: "Mozilla/5.0"`
+
+	tests := []struct {
+		name          string
+		seoAudit      string
+		wantErr       bool
+		expectedError string
+	}{
+		{
+			name: "valid seo_audit",
+			seoAudit: `    seo_audit:
+      enabled: true
+      min_word_count: 200
+      max_images_without_alt: 2`,
+			wantErr: false,
+		},
+		{
+			name: "negative min_word_count",
+			seoAudit: `    seo_audit:
+      enabled: true
+      min_word_count: -1`,
+			wantErr:       true,
+			expectedError: "seo_audit.min_word_count must be non-negative",
+		},
+		{
+			name: "negative max_images_without_alt",
+			seoAudit: `    seo_audit:
+      enabled: true
+      max_images_without_alt: -3`,
+			wantErr:       true,
+			expectedError: "seo_audit.max_images_without_alt must be non-negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeValidationTestConfig(t, dimensions, tt.seoAudit)
+			result, err := ValidateConfiguration(configPath)
+			require.NoError(t, err)
+
+			if tt.wantErr {
+				assert.False(t, result.Valid, "Expected configuration to be invalid")
+				found := false
+				for _, e := range result.Errors {
+					if strings.Contains(e.Message, tt.expectedError) {
+						found = true
+						break
+					}
+				}
+				assert.True(t, found, "Expected error containing '%s', got errors: %v", tt.expectedError, result.Errors)
+			} else {
+				assert.True(t, result.Valid, "Expected configuration to be valid, got errors: %v", result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateConfiguration_RobotsTxt(t *testing.T) {
+	dimensions := `      desktop:
+        id: 1
+        width: 1920
+        height: 1080
+        render_ua: "Mozilla/5.0"`
+
+	tests := []struct {
+		name          string
+		robotsTxt     string
+		wantErr       bool
+		expectedError string
+	}{
+		{
+			name: "valid robots_txt",
+			robotsTxt: `    robots_txt:
+      enabled: true
+      ttl: 30m
+      skip_disallowed: true
+      user_agent: "Googlebot"
+      disallowed_status: 403`,
+			wantErr: false,
+		},
+		{
+			name: "negative ttl",
+			robotsTxt: `    robots_txt:
+      enabled: true
+      ttl: -5m`,
+			wantErr:       true,
+			expectedError: "robots_txt.ttl must be non-negative",
+		},
+		{
+			name: "user agent with spaces",
+			robotsTxt: `    robots_txt:
+      enabled: true
+      user_agent: "Mozilla/5.0 (compatible; Googlebot/2.1)"`,
+			wantErr:       true,
+			expectedError: "robots_txt.user_agent must be a single product token",
+		},
+		{
+			name: "redirect disallowed_status",
+			robotsTxt: `    robots_txt:
+      enabled: true
+      disallowed_status: 301`,
+			wantErr:       true,
+			expectedError: "robots_txt.disallowed_status must be a 4xx or 5xx status code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := writeValidationTestConfig(t, dimensions, tt.robotsTxt)
+			result, err := ValidateConfiguration(configPath)
+			require.NoError(t, err)
+
+			if tt.wantErr {
+				assert.False(t, result.Valid, "Expected configuration to be invalid")
+				found := false
+				for _, e := range result.Errors {
+					if strings.Contains(e.Message, tt.expectedError) {
+						found = true
+						break
+					}
+				}
+				assert.True(t, found, "Expected error containing '%s', got errors: %v", tt.expectedError, result.Errors)
+			} else {
+				assert.True(t, result.Valid, "Expected configuration to be valid, got errors: %v", result.Errors)
+			}
+		})
+	}
+}
+
 func TestValidateConfiguration_UnmatchedDimensionWithNewActions(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -19,6 +19,9 @@ type Config struct {
 	// Restart policies
 	RestartAfterCount int           // Restart after N renders
 	RestartAfterTime  time.Duration // Restart after duration
+
+	// ResourceCache is shared by all instances to serve subresources across renders (nil = disabled)
+	ResourceCache *ResourceCache
 }
 
 // NewConfigFromYAML creates a Config from ChromeYAMLConfig
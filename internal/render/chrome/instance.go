@@ -15,13 +15,14 @@ import (
 func NewChromeInstance(id int, serviceID string, config *Config, logger *zap.Logger) (*ChromeInstance, error) {
 	now := time.Now().UTC()
 	instance := &ChromeInstance{
-		ID:           id,
-		serviceID:    serviceID,
-		createdAt:    now,
-		logger:       logger,
-		status:       int32(ChromeStatusIdle),
-		requestsDone: 0,
-		lastUsedNano: now.UnixNano(),
+		ID:            id,
+		serviceID:     serviceID,
+		createdAt:     now,
+		logger:        logger,
+		resourceCache: config.ResourceCache,
+		status:        int32(ChromeStatusIdle),
+		requestsDone:  0,
+		lastUsedNano:  now.UnixNano(),
 	}
 
 	if err := instance.createBrowser(config); err != nil {
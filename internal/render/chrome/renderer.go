@@ -2,6 +2,7 @@ package chrome
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -275,6 +276,9 @@ func (ci *ChromeInstance) buildTasks(req *types.RenderRequest, resp *types.Rende
 	// Track active fetch handler goroutines
 	var fetchHandlerCount int64
 
+	// Headers sent for cacheable requests awaiting their response stage, by fetch request ID
+	var resourceRequestHeaders sync.Map
+
 	return chromedp.Tasks{
 		// Set up event listeners FIRST - before any CDP commands
 		chromedp.ActionFunc(func(ctx context.Context) error {
@@ -295,6 +299,16 @@ func (ci *ChromeInstance) buildTasks(req *types.RenderRequest, resp *types.Rende
 						c := chromedp.FromContext(cmdCtx)
 						ctxExecutor := cdp.WithExecutor(cmdCtx, c.Target)
 
+						// Response stage: only cacheable requests continued by serveCachedResource get here
+						if event.ResponseStatusCode != 0 || event.ResponseErrorReason != "" {
+							requestHeaders := event.Request.Headers
+							if sent, ok := resourceRequestHeaders.LoadAndDelete(event.RequestID); ok {
+								requestHeaders = sent.(network.Headers)
+							}
+							ci.storeResourceResponse(ctxExecutor, event, requestHeaders, req.RequestID)
+							return
+						}
+
 						// Check if request should be blocked by URL pattern or resource type
 						blockedByURL := blocklist.IsBlocked(event.Request.URL)
 						blockedByResourceType := blocklist.IsResourceTypeBlocked(string(event.ResourceType))
@@ -330,6 +344,8 @@ func (ci *ChromeInstance) buildTasks(req *types.RenderRequest, resp *types.Rende
 									zap.String("url", event.Request.URL),
 									zap.Error(err))
 							}
+						} else if ci.resourceCache.Eligible(event.Request, event.ResourceType, targetOrigin, req.Headers) {
+							ci.serveCachedResource(ctxExecutor, event, req, targetOrigin, &resourceRequestHeaders)
 						} else if len(req.Headers) > 0 && isSameHost(event.Request.URL, targetOrigin) {
 							// Same-origin request - inject client headers
 							headers := mergeRequestHeaders(event.Request.Headers, req.Headers)
@@ -798,6 +814,87 @@ func enableLifeCycle() chromedp.ActionFunc {
 	}
 }
 
+// serveCachedResource fulfills a paused request from the resource cache. On a miss the
+// request continues (with client headers for same-origin requests) and its response is
+// intercepted so storeResourceResponse can cache it. Variants are keyed by the headers
+// actually sent, which are kept in sentHeaders until the response stage.
+func (ci *ChromeInstance) serveCachedResource(ctx context.Context, event *fetch.EventRequestPaused,
+	req *types.RenderRequest, targetOrigin string, sentHeaders *sync.Map) {
+	requestHeaders, continueHeaders := outgoingRequestHeaders(event.Request, targetOrigin, req.Headers)
+
+	if resource := ci.resourceCache.Get(event.Request.URL, requestHeaders); resource != nil {
+		err := fetch.FulfillRequest(event.RequestID, int64(resource.status)).
+			WithResponseHeaders(resource.headers).
+			WithBody(base64.StdEncoding.EncodeToString(resource.body)).
+			Do(ctx)
+		if err == nil {
+			return
+		}
+		ci.logger.Warn("Failed to fulfill request from resource cache, continuing instead",
+			zap.String("request_id", req.RequestID),
+			zap.Int("instance_id", ci.ID),
+			zap.String("url", event.Request.URL),
+			zap.Error(err))
+	}
+
+	continueReq := fetch.ContinueRequest(event.RequestID).WithInterceptResponse(true)
+	if continueHeaders != nil {
+		continueReq = continueReq.WithHeaders(continueHeaders)
+	}
+	sentHeaders.Store(event.RequestID, requestHeaders)
+	if err := continueReq.Do(ctx); err != nil {
+		sentHeaders.Delete(event.RequestID)
+		ci.logger.Warn("Failed to continue cacheable request, failing instead",
+			zap.String("request_id", req.RequestID),
+			zap.Int("instance_id", ci.ID),
+			zap.String("url", event.Request.URL),
+			zap.Error(err))
+		fetch.FailRequest(event.RequestID, network.ErrorReasonAborted).Do(ctx)
+	}
+}
+
+// outgoingRequestHeaders returns the headers a request is sent with: Chrome's headers,
+// merged with the injected client headers for same-origin requests. The merged header
+// entries are also returned for ContinueRequest (nil when nothing is injected).
+func outgoingRequestHeaders(request *network.Request, targetOrigin string, injected map[string][]string) (network.Headers, []*fetch.HeaderEntry) {
+	if len(injected) == 0 || !isSameHost(request.URL, targetOrigin) {
+		return request.Headers, nil
+	}
+	entries := mergeRequestHeaders(request.Headers, injected)
+	headers := make(network.Headers, len(entries))
+	for _, entry := range entries {
+		headers[entry.Name] = entry.Value
+	}
+	return headers, entries
+}
+
+// storeResourceResponse stores an intercepted subresource response in the resource cache
+// under the headers its request was sent with, and lets the original response through unchanged
+func (ci *ChromeInstance) storeResourceResponse(ctx context.Context, event *fetch.EventRequestPaused,
+	requestHeaders network.Headers, requestID string) {
+	statusCode := int(event.ResponseStatusCode)
+	if event.ResponseErrorReason == "" && ci.resourceCache.IsStorable(statusCode, event.ResponseHeaders) {
+		body, err := fetch.GetResponseBody(event.RequestID).Do(ctx)
+		if err != nil {
+			ci.logger.Debug("Failed to read response body for resource cache",
+				zap.String("request_id", requestID),
+				zap.String("url", event.Request.URL),
+				zap.Error(err))
+		} else {
+			ci.resourceCache.Store(event.Request.URL, requestHeaders, statusCode, event.ResponseHeaders, body)
+		}
+	}
+
+	if err := fetch.ContinueRequest(event.RequestID).Do(ctx); err != nil {
+		ci.logger.Warn("Failed to continue intercepted response, failing instead",
+			zap.String("request_id", requestID),
+			zap.Int("instance_id", ci.ID),
+			zap.String("url", event.Request.URL),
+			zap.Error(err))
+		fetch.FailRequest(event.RequestID, network.ErrorReasonAborted).Do(ctx)
+	}
+}
+
 // isSameHost checks if requestURL has the same scheme and host as targetOrigin.
 // This is a strict check: scheme, host, and port must match exactly.
 // Note: This differs from urlutil.IsSameOrigin which allows subdomains.
@@ -0,0 +1,447 @@
+package chrome
+
+import (
+	"container/list"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"go.uber.org/zap"
+
+	"github.com/edgecomet/engine/internal/render/metrics"
+)
+
+// resourceHeadersDropped are response headers not replayed from the resource cache.
+// Bodies are stored decoded, so encoding and length headers no longer apply.
+var resourceHeadersDropped = map[string]bool{
+	"content-encoding":  true,
+	"content-length":    true,
+	"transfer-encoding": true,
+	"connection":        true,
+	"keep-alive":        true,
+	"set-cookie":        true,
+	"age":               true,
+}
+
+// cachedResource is a stored subresource response
+type cachedResource struct {
+	url       string
+	variant   string // Request values of the response's Vary headers
+	status    int
+	headers   []*fetch.HeaderEntry
+	body      []byte
+	expiresAt time.Time
+}
+
+// resourceVariants holds the stored responses of one URL
+type resourceVariants struct {
+	vary     []string // Lowercase request header names the responses vary on
+	elements map[string]*list.Element
+}
+
+// ResourceCache is a size-bounded in-memory cache of subresource responses (scripts,
+// stylesheets, fonts, ...) shared by all Chrome instances, so repeated renders of pages
+// on the same site do not download the same assets again. Only resources of the rendered
+// page's origin or of allowed domains are cached, and responses are stored only when
+// Cache-Control or Expires makes them explicitly fresh.
+type ResourceCache struct {
+	maxBytes       int64
+	maxEntryBytes  int64
+	allowedDomains []string
+	resourceTypes  map[string]bool
+
+	mu    sync.Mutex
+	urls  map[string]*resourceVariants
+	lru   *list.List // Front = most recently used
+	bytes int64      // Total bytes of held bodies
+
+	metrics *metrics.MetricsCollector
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewResourceCache creates a resource cache holding at most maxBytes of response bodies.
+// metricsCollector may be nil.
+func NewResourceCache(maxBytes, maxEntryBytes int64, allowedDomains, resourceTypes []string,
+	metricsCollector *metrics.MetricsCollector, logger *zap.Logger) *ResourceCache {
+	domains := make([]string, 0, len(allowedDomains))
+	for _, domain := range allowedDomains {
+		domains = append(domains, strings.ToLower(strings.TrimSuffix(domain, ".")))
+	}
+	typeSet := make(map[string]bool, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		typeSet[resourceType] = true
+	}
+
+	return &ResourceCache{
+		maxBytes:       maxBytes,
+		maxEntryBytes:  maxEntryBytes,
+		allowedDomains: domains,
+		resourceTypes:  typeSet,
+		urls:           make(map[string]*resourceVariants),
+		lru:            list.New(),
+		metrics:        metricsCollector,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// Eligible reports whether a paused request may be served from or stored in the cache.
+// Only GET requests of a cached resource type without credentials qualify, and only
+// when they target the rendered page's origin or an allowed domain. injected are the
+// client headers added to same-origin requests (nil-safe).
+func (rc *ResourceCache) Eligible(request *network.Request, resourceType network.ResourceType,
+	targetOrigin string, injected map[string][]string) bool {
+	if rc == nil || request == nil {
+		return false
+	}
+	if request.Method != http.MethodGet || !rc.resourceTypes[string(resourceType)] {
+		return false
+	}
+	if _, ok := requestHeader(request.Headers, "Authorization"); ok {
+		return false
+	}
+
+	if isSameHost(request.URL, targetOrigin) {
+		// Credentials forwarded from the client make the response user-specific
+		for name := range injected {
+			if strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Cookie") {
+				return false
+			}
+		}
+		return true
+	}
+
+	parsed, err := url.Parse(request.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	return rc.isAllowedDomain(strings.ToLower(parsed.Hostname()))
+}
+
+// Get returns the fresh stored response for a request, or nil on a miss.
+// Requests with Cache-Control: no-cache or no-store always miss.
+func (rc *ResourceCache) Get(requestURL string, requestHeaders network.Headers) *cachedResource {
+	if rc == nil {
+		return nil
+	}
+
+	directives := parseCacheControl(headerValue(requestHeaders, "Cache-Control"))
+	if _, ok := directives["no-cache"]; ok {
+		rc.recordMiss()
+		return nil
+	}
+	if _, ok := directives["no-store"]; ok {
+		rc.recordMiss()
+		return nil
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	variants, ok := rc.urls[requestURL]
+	if !ok {
+		rc.recordMiss()
+		return nil
+	}
+	elem, ok := variants.elements[variantKey(variants.vary, requestHeaders)]
+	if !ok {
+		rc.recordMiss()
+		return nil
+	}
+
+	resource := elem.Value.(*cachedResource)
+	if !rc.now().Before(resource.expiresAt) {
+		rc.removeLocked(elem)
+		rc.updateUsageLocked()
+		rc.recordMiss()
+		return nil
+	}
+
+	rc.lru.MoveToFront(elem)
+	if rc.metrics != nil {
+		rc.metrics.RecordResourceCacheHit(len(resource.body))
+	}
+	return resource
+}
+
+// IsStorable reports whether a response can be stored, before its body is fetched
+func (rc *ResourceCache) IsStorable(statusCode int, responseHeaders []*fetch.HeaderEntry) bool {
+	if rc == nil {
+		return false
+	}
+	_, ok := responseFreshness(statusCode, toHTTPHeader(responseHeaders), rc.now())
+	return ok
+}
+
+// Store caches a response for a request. Returns false if the response is not cacheable:
+// not a 200, not explicitly fresh, private, setting cookies, varying on "*", or too large.
+func (rc *ResourceCache) Store(requestURL string, requestHeaders network.Headers, statusCode int,
+	responseHeaders []*fetch.HeaderEntry, body []byte) bool {
+	if rc == nil || int64(len(body)) > rc.maxEntryBytes || int64(len(body)) > rc.maxBytes {
+		return false
+	}
+
+	if _, ok := parseCacheControl(headerValue(requestHeaders, "Cache-Control"))["no-store"]; ok {
+		return false
+	}
+
+	header := toHTTPHeader(responseHeaders)
+	now := rc.now()
+	lifetime, ok := responseFreshness(statusCode, header, now)
+	if !ok {
+		return false
+	}
+	vary, ok := parseVary(header)
+	if !ok {
+		return false
+	}
+
+	stored := make([]*fetch.HeaderEntry, 0, len(responseHeaders))
+	for _, h := range responseHeaders {
+		if !resourceHeadersDropped[strings.ToLower(h.Name)] {
+			stored = append(stored, &fetch.HeaderEntry{Name: h.Name, Value: h.Value})
+		}
+	}
+
+	resource := &cachedResource{
+		url:       requestURL,
+		variant:   variantKey(vary, requestHeaders),
+		status:    statusCode,
+		headers:   stored,
+		body:      body,
+		expiresAt: now.Add(lifetime),
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	variants, ok := rc.urls[requestURL]
+	if ok && !slices.Equal(variants.vary, vary) {
+		// The URL now varies on different headers; earlier variants are unreachable
+		for _, elem := range variants.elements {
+			rc.removeLocked(elem)
+		}
+		ok = false
+	}
+	if !ok {
+		variants = &resourceVariants{vary: vary, elements: make(map[string]*list.Element)}
+		rc.urls[requestURL] = variants
+	}
+	if elem, exists := variants.elements[resource.variant]; exists {
+		rc.removeLocked(elem)
+	}
+
+	for rc.bytes+int64(len(body)) > rc.maxBytes {
+		oldest := rc.lru.Back()
+		if oldest == nil {
+			break
+		}
+		rc.removeLocked(oldest)
+		if rc.metrics != nil {
+			rc.metrics.RecordResourceCacheEviction()
+		}
+	}
+
+	// Eviction may have dropped the variants of this URL
+	if _, exists := rc.urls[requestURL]; !exists {
+		rc.urls[requestURL] = variants
+	}
+	variants.elements[resource.variant] = rc.lru.PushFront(resource)
+	rc.bytes += int64(len(body))
+	rc.updateUsageLocked()
+
+	rc.logger.Debug("Stored resource in cache",
+		zap.String("url", requestURL),
+		zap.Int("size", len(body)),
+		zap.Duration("ttl", lifetime))
+	return true
+}
+
+// Stats returns the number of stored responses and their total body size
+func (rc *ResourceCache) Stats() (entries int, bytes int64) {
+	if rc == nil {
+		return 0, 0
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.lru.Len(), rc.bytes
+}
+
+// removeLocked drops a stored response. Caller must hold rc.mu.
+func (rc *ResourceCache) removeLocked(elem *list.Element) {
+	resource := rc.lru.Remove(elem).(*cachedResource)
+	rc.bytes -= int64(len(resource.body))
+
+	if variants, ok := rc.urls[resource.url]; ok {
+		if current, ok := variants.elements[resource.variant]; ok && current == elem {
+			delete(variants.elements, resource.variant)
+		}
+		if len(variants.elements) == 0 {
+			delete(rc.urls, resource.url)
+		}
+	}
+}
+
+// updateUsageLocked publishes cache usage metrics. Caller must hold rc.mu.
+func (rc *ResourceCache) updateUsageLocked() {
+	if rc.metrics != nil {
+		rc.metrics.UpdateResourceCacheUsage(rc.bytes, rc.lru.Len())
+	}
+}
+
+func (rc *ResourceCache) recordMiss() {
+	if rc.metrics != nil {
+		rc.metrics.RecordResourceCacheMiss()
+	}
+}
+
+// isAllowedDomain reports whether host is an allowed domain or one of its subdomains
+func (rc *ResourceCache) isAllowedDomain(host string) bool {
+	for _, domain := range rc.allowedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseFreshness returns how long a response stays fresh. Returns false for responses
+// that must not be shared across renders or have no explicit freshness lifetime.
+// s-maxage takes precedence over max-age, which takes precedence over Expires.
+func responseFreshness(statusCode int, header http.Header, now time.Time) (time.Duration, bool) {
+	if statusCode != http.StatusOK || header.Get("Set-Cookie") != "" {
+		return 0, false
+	}
+
+	directives := parseCacheControl(strings.Join(header.Values("Cache-Control"), ","))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := directives[directive]; ok {
+			return 0, false
+		}
+	}
+
+	var lifetime time.Duration
+	if seconds, ok := parseDeltaSeconds(directives, "s-maxage"); ok {
+		lifetime = seconds
+	} else if seconds, ok := parseDeltaSeconds(directives, "max-age"); ok {
+		lifetime = seconds
+	} else if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0, false
+		}
+		date := now
+		if parsed, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = parsed
+		}
+		lifetime = expiresAt.Sub(date)
+	} else {
+		return 0, false
+	}
+
+	if age, err := strconv.ParseInt(strings.TrimSpace(header.Get("Age")), 10, 64); err == nil && age > 0 {
+		lifetime -= time.Duration(age) * time.Second
+	}
+	if lifetime <= 0 {
+		return 0, false
+	}
+	return lifetime, true
+}
+
+// parseCacheControl parses Cache-Control directives into lowercase names and unquoted values
+func parseCacheControl(value string) map[string]string {
+	if value == "" {
+		return nil
+	}
+	directives := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+	return directives
+}
+
+func parseDeltaSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		// Invalid values make the response stale
+		return 0, true
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// parseVary returns the sorted lowercase request header names a response varies on.
+// Accept-Encoding is ignored because bodies are stored decoded. Returns false for "Vary: *".
+func parseVary(header http.Header) ([]string, bool) {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			switch name {
+			case "":
+			case "*":
+				return nil, false
+			case "accept-encoding":
+			default:
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	slices.Sort(names)
+	return names, true
+}
+
+// variantKey joins the request values of the vary headers
+func variantKey(vary []string, requestHeaders network.Headers) string {
+	if len(vary) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, name := range vary {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(headerValue(requestHeaders, name))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// requestHeader looks up a CDP request header case-insensitively
+func requestHeader(headers network.Headers, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			str, _ := value.(string)
+			return str, true
+		}
+	}
+	return "", false
+}
+
+func headerValue(headers network.Headers, name string) string {
+	value, _ := requestHeader(headers, name)
+	return value
+}
+
+func toHTTPHeader(entries []*fetch.HeaderEntry) http.Header {
+	header := make(http.Header, len(entries))
+	for _, entry := range entries {
+		header.Add(entry.Name, entry.Value)
+	}
+	return header
+}
@@ -0,0 +1,210 @@
+package chrome
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestResourceCache(maxBytes, maxEntryBytes int64, now *time.Time) *ResourceCache {
+	rc := NewResourceCache(maxBytes, maxEntryBytes, []string{"cdn.example.net"},
+		[]string{"Script", "Stylesheet", "Font"}, nil, zap.NewNop())
+	rc.now = func() time.Time { return *now }
+	return rc
+}
+
+func cacheHeaders(pairs ...string) []*fetch.HeaderEntry {
+	headers := make([]*fetch.HeaderEntry, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		headers = append(headers, &fetch.HeaderEntry{Name: pairs[i], Value: pairs[i+1]})
+	}
+	return headers
+}
+
+func TestResourceCacheEligible(t *testing.T) {
+	rc := newTestResourceCache(1<<20, 1<<20, &time.Time{})
+	const origin = "https://example.com"
+
+	tests := []struct {
+		name         string
+		request      *network.Request
+		resourceType network.ResourceType
+		injected     map[string][]string
+		expected     bool
+	}{
+		{"same-origin script", &network.Request{Method: "GET", URL: "https://example.com/app.js"}, network.ResourceTypeScript, nil, true},
+		{"allowed domain", &network.Request{Method: "GET", URL: "https://cdn.example.net/app.css"}, network.ResourceTypeStylesheet, nil, true},
+		{"allowed subdomain", &network.Request{Method: "GET", URL: "https://fonts.cdn.example.net/a.woff2"}, network.ResourceTypeFont, nil, true},
+		{"other domain", &network.Request{Method: "GET", URL: "https://tracker.example.org/t.js"}, network.ResourceTypeScript, nil, false},
+		{"suffix is not a subdomain", &network.Request{Method: "GET", URL: "https://evilcdn.example.net/app.js"}, network.ResourceTypeScript, nil, false},
+		{"other scheme on same host", &network.Request{Method: "GET", URL: "http://example.com/app.js"}, network.ResourceTypeScript, nil, false},
+		{"resource type not cached", &network.Request{Method: "GET", URL: "https://example.com/logo.png"}, network.ResourceTypeImage, nil, false},
+		{"post request", &network.Request{Method: "POST", URL: "https://example.com/app.js"}, network.ResourceTypeScript, nil, false},
+		{"authorization header", &network.Request{Method: "GET", URL: "https://example.com/app.js", Headers: network.Headers{"authorization": "Bearer x"}}, network.ResourceTypeScript, nil, false},
+		{"injected cookie", &network.Request{Method: "GET", URL: "https://example.com/app.js"}, network.ResourceTypeScript, map[string][]string{"Cookie": {"session=1"}}, false},
+		{"injected cookie not sent cross-origin", &network.Request{Method: "GET", URL: "https://cdn.example.net/app.js"}, network.ResourceTypeScript, map[string][]string{"Cookie": {"session=1"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rc.Eligible(tt.request, tt.resourceType, origin, tt.injected))
+		})
+	}
+
+	var disabled *ResourceCache
+	assert.False(t, disabled.Eligible(tests[0].request, network.ResourceTypeScript, origin, nil))
+	assert.Nil(t, disabled.Get("https://example.com/app.js", nil))
+}
+
+func TestResourceCacheFreshness(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	date := now.Format(http.TimeFormat)
+
+	tests := []struct {
+		name     string
+		status   int
+		headers  []*fetch.HeaderEntry
+		expected time.Duration
+		stored   bool
+	}{
+		{"max-age", 200, cacheHeaders("Cache-Control", "public, max-age=600"), 10 * time.Minute, true},
+		{"s-maxage wins", 200, cacheHeaders("Cache-Control", "max-age=600, s-maxage=60"), time.Minute, true},
+		{"age is subtracted", 200, cacheHeaders("Cache-Control", "max-age=600", "Age", "60"), 9 * time.Minute, true},
+		{"expires", 200, cacheHeaders("Date", date, "Expires", now.Add(time.Hour).Format(http.TimeFormat)), time.Hour, true},
+		{"no explicit freshness", 200, cacheHeaders("Last-Modified", date), 0, false},
+		{"no-store", 200, cacheHeaders("Cache-Control", "no-store, max-age=600"), 0, false},
+		{"no-cache", 200, cacheHeaders("Cache-Control", "no-cache, max-age=600"), 0, false},
+		{"private", 200, cacheHeaders("Cache-Control", "private, max-age=600"), 0, false},
+		{"set-cookie", 200, cacheHeaders("Cache-Control", "max-age=600", "Set-Cookie", "a=b"), 0, false},
+		{"expired", 200, cacheHeaders("Cache-Control", "max-age=60", "Age", "120"), 0, false},
+		{"invalid max-age", 200, cacheHeaders("Cache-Control", "max-age=soon", "Expires", now.Add(time.Hour).Format(http.TimeFormat)), 0, false},
+		{"not found", 404, cacheHeaders("Cache-Control", "max-age=600"), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lifetime, ok := responseFreshness(tt.status, toHTTPHeader(tt.headers), now)
+			assert.Equal(t, tt.stored, ok)
+			assert.Equal(t, tt.expected, lifetime)
+		})
+	}
+}
+
+func TestResourceCacheStoreAndGet(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rc := newTestResourceCache(1<<20, 1<<20, &now)
+	const scriptURL = "https://example.com/app.js"
+
+	stored := rc.Store(scriptURL, nil, 200, cacheHeaders(
+		"Content-Type", "application/javascript",
+		"Content-Encoding", "gzip",
+		"Content-Length", "10",
+		"Cache-Control", "max-age=60",
+	), []byte("console.log(1)"))
+	require.True(t, stored)
+
+	resource := rc.Get(scriptURL, nil)
+	require.NotNil(t, resource)
+	assert.Equal(t, 200, resource.status)
+	assert.Equal(t, []byte("console.log(1)"), resource.body)
+	assert.Equal(t, cacheHeaders("Content-Type", "application/javascript", "Cache-Control", "max-age=60"), resource.headers,
+		"encoding and length headers do not apply to the decoded body")
+
+	assert.Nil(t, rc.Get(scriptURL, network.Headers{"Cache-Control": "no-cache"}), "request no-cache bypasses the cache")
+	assert.Nil(t, rc.Get(scriptURL+"?v=2", nil))
+
+	now = now.Add(time.Minute)
+	assert.Nil(t, rc.Get(scriptURL, nil), "expired responses miss")
+	entries, bytes := rc.Stats()
+	assert.Equal(t, 0, entries)
+	assert.Equal(t, int64(0), bytes)
+
+	assert.False(t, rc.Store(scriptURL, network.Headers{"cache-control": "no-store"}, 200,
+		cacheHeaders("Cache-Control", "max-age=60"), []byte("x")), "request no-store is not cached")
+}
+
+func TestResourceCacheVary(t *testing.T) {
+	now := time.Now()
+	rc := newTestResourceCache(1<<20, 1<<20, &now)
+	const fontURL = "https://cdn.example.net/font.woff2"
+	headers := cacheHeaders("Cache-Control", "max-age=600", "Vary", "Origin, Accept-Encoding")
+
+	require.True(t, rc.Store(fontURL, network.Headers{"Origin": "https://a.example.com"}, 200, headers, []byte("a")))
+	require.True(t, rc.Store(fontURL, network.Headers{"Origin": "https://b.example.com"}, 200, headers, []byte("b")))
+
+	resource := rc.Get(fontURL, network.Headers{"origin": "https://a.example.com", "Accept-Encoding": "br"})
+	require.NotNil(t, resource)
+	assert.Equal(t, []byte("a"), resource.body)
+	resource = rc.Get(fontURL, network.Headers{"Origin": "https://b.example.com"})
+	require.NotNil(t, resource)
+	assert.Equal(t, []byte("b"), resource.body)
+	assert.Nil(t, rc.Get(fontURL, network.Headers{"Origin": "https://c.example.com"}))
+
+	// A different Vary drops the variants stored under the previous one
+	require.True(t, rc.Store(fontURL, nil, 200, cacheHeaders("Cache-Control", "max-age=600"), []byte("c")))
+	entries, _ := rc.Stats()
+	assert.Equal(t, 1, entries)
+
+	assert.False(t, rc.Store(fontURL, nil, 200, cacheHeaders("Cache-Control", "max-age=600", "Vary", "*"), []byte("d")))
+}
+
+func TestResourceCacheVaryOnInjectedHeaders(t *testing.T) {
+	now := time.Now()
+	rc := newTestResourceCache(1<<20, 1<<20, &now)
+	const origin = "https://example.com"
+	request := &network.Request{Method: "GET", URL: "https://example.com/i18n.js",
+		Headers: network.Headers{"Accept-Language": "en-US", "User-Agent": "Chrome"}}
+	responseHeaders := cacheHeaders("Cache-Control", "max-age=600", "Vary", "Accept-Language")
+
+	// Same-origin requests are sent, and so keyed, with the client headers of the render
+	german, entries := outgoingRequestHeaders(request, origin, map[string][]string{"Accept-Language": {"de-DE"}})
+	require.NotNil(t, entries)
+	assert.Equal(t, "de-DE", german["Accept-Language"])
+	assert.Equal(t, "Chrome", german["User-Agent"])
+	require.True(t, rc.Store(request.URL, german, 200, responseHeaders, []byte("de")))
+
+	french, _ := outgoingRequestHeaders(request, origin, map[string][]string{"Accept-Language": {"fr-FR"}})
+	assert.Nil(t, rc.Get(request.URL, french), "other client language misses")
+	unmodified, entries := outgoingRequestHeaders(request, origin, nil)
+	assert.Nil(t, entries)
+	assert.Nil(t, rc.Get(request.URL, unmodified), "Chrome's own language misses")
+
+	resource := rc.Get(request.URL, german)
+	require.NotNil(t, resource)
+	assert.Equal(t, []byte("de"), resource.body)
+
+	// Cross-origin requests are sent with Chrome's headers only
+	cdnRequest := &network.Request{Method: "GET", URL: "https://cdn.example.net/i18n.js", Headers: request.Headers}
+	cdnHeaders, entries := outgoingRequestHeaders(cdnRequest, origin, map[string][]string{"Accept-Language": {"de-DE"}})
+	assert.Nil(t, entries)
+	assert.Equal(t, "en-US", cdnHeaders["Accept-Language"])
+}
+
+func TestResourceCacheEviction(t *testing.T) {
+	now := time.Now()
+	rc := newTestResourceCache(10, 6, &now)
+	headers := cacheHeaders("Cache-Control", "max-age=600")
+
+	require.True(t, rc.Store("https://example.com/a.js", nil, 200, headers, []byte("aaaa")))
+	require.True(t, rc.Store("https://example.com/b.js", nil, 200, headers, []byte("bbbb")))
+	require.NotNil(t, rc.Get("https://example.com/a.js", nil)) // a.js is now most recently used
+
+	require.True(t, rc.Store("https://example.com/c.js", nil, 200, headers, []byte("cccc")))
+	assert.Nil(t, rc.Get("https://example.com/b.js", nil), "least recently used entry is evicted")
+	assert.NotNil(t, rc.Get("https://example.com/a.js", nil))
+	assert.NotNil(t, rc.Get("https://example.com/c.js", nil))
+
+	entries, bytes := rc.Stats()
+	assert.Equal(t, 2, entries)
+	assert.Equal(t, int64(8), bytes)
+
+	assert.False(t, rc.Store("https://example.com/big.js", nil, 200, headers, []byte(strings.Repeat("x", 7))),
+		"responses larger than the entry limit are not cached")
+}
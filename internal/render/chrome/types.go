@@ -51,6 +51,7 @@ type ChromeInstance struct {
 	createdAt       time.Time          // Immutable after creation
 	logger          *zap.Logger        // Immutable
 	browserVersion  string             // Immutable after creation (e.g., "Chrome/120.0.6099.109")
+	resourceCache   *ResourceCache     // Shared subresource cache (nil = disabled, immutable)
 
 	// Mutable fields - protected by atomic operations
 	status           int32 // ChromeStatus as int32
@@ -95,6 +95,27 @@ func (mc *MetricsCollector) RecordInternalError() {
 	mc.prometheus.RecordError("internal")
 }
 
+// RecordResourceCacheHit records a subresource served from the resource cache
+func (mc *MetricsCollector) RecordResourceCacheHit(bytes int) {
+	mc.prometheus.RecordResourceCacheLookup("hit")
+	mc.prometheus.RecordResourceCacheBytesServed(float64(bytes))
+}
+
+// RecordResourceCacheMiss records a cacheable subresource fetched from origin
+func (mc *MetricsCollector) RecordResourceCacheMiss() {
+	mc.prometheus.RecordResourceCacheLookup("miss")
+}
+
+// RecordResourceCacheEviction records a resource evicted from the resource cache
+func (mc *MetricsCollector) RecordResourceCacheEviction() {
+	mc.prometheus.RecordResourceCacheEviction()
+}
+
+// UpdateResourceCacheUsage updates the resource cache size and entry count
+func (mc *MetricsCollector) UpdateResourceCacheUsage(bytes int64, entries int) {
+	mc.prometheus.UpdateResourceCacheUsage(float64(bytes), float64(entries))
+}
+
 // ServeHTTP serves Prometheus metrics via HTTP
 func (mc *MetricsCollector) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	mc.prometheus.ServeHTTP(ctx)
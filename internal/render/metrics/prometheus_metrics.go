@@ -28,6 +28,13 @@ type PrometheusMetrics struct {
 	// Error metrics
 	errorsTotal *prometheus.CounterVec
 
+	// Resource cache metrics
+	resourceCacheRequests    *prometheus.CounterVec
+	resourceCacheBytesServed prometheus.Counter
+	resourceCacheEvictions   prometheus.Counter
+	resourceCacheSize        prometheus.Gauge
+	resourceCacheEntries     prometheus.Gauge
+
 	logger      *zap.Logger
 	httpHandler func(*fasthttp.RequestCtx)
 }
@@ -105,6 +112,42 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 		Help:      "Total errors by type",
 	}, []string{"type"}) // type: validation, render, timeout, internal
 
+	// Resource cache metrics
+	pm.resourceCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rs",
+		Name:      "resource_cache_requests_total",
+		Help:      "Cacheable subresource requests by cache result",
+	}, []string{"result"}) // result: hit, miss
+
+	pm.resourceCacheBytesServed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rs",
+		Name:      "resource_cache_bytes_served_total",
+		Help:      "Response body bytes served from the resource cache instead of origin",
+	})
+
+	pm.resourceCacheEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "rs",
+		Name:      "resource_cache_evictions_total",
+		Help:      "Resources evicted from the resource cache to stay within its size limit",
+	})
+
+	pm.resourceCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "rs",
+		Name:      "resource_cache_size_bytes",
+		Help:      "Response body bytes held in the resource cache",
+	})
+
+	pm.resourceCacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "rs",
+		Name:      "resource_cache_entries",
+		Help:      "Responses held in the resource cache",
+	})
+
 	// Register all metrics
 	registerer.MustRegister(
 		pm.chromePoolSize,
@@ -115,6 +158,11 @@ func NewPrometheusMetricsWithRegistry(namespace string, registerer prometheus.Re
 		pm.queueRejections,
 		pm.httpRequests,
 		pm.errorsTotal,
+		pm.resourceCacheRequests,
+		pm.resourceCacheBytesServed,
+		pm.resourceCacheEvictions,
+		pm.resourceCacheSize,
+		pm.resourceCacheEntries,
 	)
 
 	// Create HTTP handler
@@ -168,6 +216,27 @@ func (pm *PrometheusMetrics) RecordError(errorType string) {
 	pm.errorsTotal.WithLabelValues(errorType).Inc()
 }
 
+// RecordResourceCacheLookup records a resource cache lookup result
+func (pm *PrometheusMetrics) RecordResourceCacheLookup(result string) {
+	pm.resourceCacheRequests.WithLabelValues(result).Inc()
+}
+
+// RecordResourceCacheBytesServed records body bytes served from the resource cache
+func (pm *PrometheusMetrics) RecordResourceCacheBytesServed(bytes float64) {
+	pm.resourceCacheBytesServed.Add(bytes)
+}
+
+// RecordResourceCacheEviction records a resource cache eviction
+func (pm *PrometheusMetrics) RecordResourceCacheEviction() {
+	pm.resourceCacheEvictions.Inc()
+}
+
+// UpdateResourceCacheUsage updates the resource cache size and entry count
+func (pm *PrometheusMetrics) UpdateResourceCacheUsage(bytes, entries float64) {
+	pm.resourceCacheSize.Set(bytes)
+	pm.resourceCacheEntries.Set(entries)
+}
+
 // ServeHTTP serves Prometheus metrics via HTTP
 func (pm *PrometheusMetrics) ServeHTTP(ctx *fasthttp.RequestCtx) {
 	pm.httpHandler(ctx)
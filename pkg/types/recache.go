@@ -50,3 +50,28 @@ type InvalidateAllAPIData struct {
 	DimensionIDsCount  int `json:"dimension_ids_count"`
 	EntriesInvalidated int `json:"entries_invalidated"`
 }
+
+// Origin webhook event types
+const (
+	WebhookEventPublish   = "publish"   // Content published: high-priority recache
+	WebhookEventUpdate    = "update"    // Content changed: high-priority recache
+	WebhookEventUnpublish = "unpublish" // Content hidden: serve 404 until deleted_ttl
+	WebhookEventDelete    = "delete"    // Content removed: serve 410 until deleted_ttl
+)
+
+// WebhookRequest is the request body for POST /webhooks/origin
+type WebhookRequest struct {
+	Event string   `json:"event"` // publish, update, unpublish or delete
+	URLs  []string `json:"urls"`  // Absolute URLs; host is resolved from the domain
+	Tags  []string `json:"tags"`  // Tags mapped to cached URLs by webhooks.tags
+}
+
+// WebhookAPIData is the data payload for POST /webhooks/origin response
+type WebhookAPIData struct {
+	Event           string   `json:"event"`
+	URLsCount       int      `json:"urls_count"`             // URLs resolved from urls and tags
+	EntriesEnqueued int      `json:"entries_enqueued"`       // High-priority recache entries added
+	EntriesDeleted  int      `json:"entries_deleted"`        // Deleted-content markers written
+	UnknownURLs     []string `json:"unknown_urls,omitempty"` // URLs whose domain matches no host
+	UnknownTags     []string `json:"unknown_tags,omitempty"` // Tags not present in webhooks.tags
+}
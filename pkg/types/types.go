@@ -30,6 +30,111 @@ type ClientIPConfig struct {
 	Headers []string `yaml:"headers,omitempty" json:"headers,omitempty"`
 }
 
+// llms.txt defaults
+const (
+	DefaultLLMsTxtMaxPages = 500
+	DefaultLLMsTxtTTL      = time.Hour
+)
+
+// LLMsTxtConfig controls the /llms.txt file generated from cached page titles and descriptions
+type LLMsTxtConfig struct {
+	Enabled     bool      `yaml:"enabled" json:"enabled"`
+	Title       string    `yaml:"title,omitempty" json:"title,omitempty"`             // H1 of the file (default: host domain)
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"` // Summary blockquote under the title
+	MaxPages    int       `yaml:"max_pages,omitempty" json:"max_pages,omitempty"`     // Maximum listed pages (default: 500)
+	TTL         *Duration `yaml:"ttl,omitempty" json:"ttl,omitempty"`                 // How long a generated file is reused (default: 1h)
+}
+
+// IsEnabled returns true if llms.txt generation is enabled (nil-safe)
+func (c *LLMsTxtConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetMaxPages returns the maximum number of listed pages
+func (c *LLMsTxtConfig) GetMaxPages() int {
+	if c == nil || c.MaxPages <= 0 {
+		return DefaultLLMsTxtMaxPages
+	}
+	return c.MaxPages
+}
+
+// GetTTL returns how long a generated file is reused
+func (c *LLMsTxtConfig) GetTTL() time.Duration {
+	if c == nil || c.TTL == nil || *c.TTL <= 0 {
+		return DefaultLLMsTxtTTL
+	}
+	return time.Duration(*c.TTL)
+}
+
+// robots.txt defaults
+const (
+	DefaultRobotsTxtTTL       = time.Hour
+	DefaultRobotsTxtUserAgent = "*"
+)
+
+// RobotsTxtConfig controls how the host's robots.txt is applied to rendering and serving
+type RobotsTxtConfig struct {
+	Enabled          bool      `yaml:"enabled" json:"enabled"`
+	TTL              *Duration `yaml:"ttl,omitempty" json:"ttl,omitempty"`                             // How long a fetched robots.txt is reused (default: 1h)
+	SkipDisallowed   bool      `yaml:"skip_disallowed,omitempty" json:"skip_disallowed,omitempty"`     // Skip pre-render and recache of disallowed URLs
+	UserAgent        string    `yaml:"user_agent,omitempty" json:"user_agent,omitempty"`               // Crawler checked by skip_disallowed (default: *)
+	DisallowedStatus int       `yaml:"disallowed_status,omitempty" json:"disallowed_status,omitempty"` // Status served for paths disallowed to the requesting bot (default: serve normally)
+}
+
+// IsEnabled returns true if robots.txt checks are enabled (nil-safe)
+func (c *RobotsTxtConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetTTL returns how long a fetched robots.txt is reused
+func (c *RobotsTxtConfig) GetTTL() time.Duration {
+	if c == nil || c.TTL == nil || *c.TTL <= 0 {
+		return DefaultRobotsTxtTTL
+	}
+	return time.Duration(*c.TTL)
+}
+
+// GetUserAgent returns the crawler whose rules decide which URLs are pre-rendered
+func (c *RobotsTxtConfig) GetUserAgent() string {
+	if c == nil || c.UserAgent == "" {
+		return DefaultRobotsTxtUserAgent
+	}
+	return c.UserAgent
+}
+
+// SEO audit defaults
+const (
+	DefaultSEOAuditMinWordCount = 100
+)
+
+// SEOAuditConfig controls the SEO rules evaluated on rendered pages
+type SEOAuditConfig struct {
+	Enabled             bool `yaml:"enabled" json:"enabled"`
+	MinWordCount        int  `yaml:"min_word_count,omitempty" json:"min_word_count,omitempty"`                 // Pages with fewer words are flagged (default: 100)
+	MaxImagesWithoutAlt int  `yaml:"max_images_without_alt,omitempty" json:"max_images_without_alt,omitempty"` // Images without alt allowed per page (default: 0)
+}
+
+// IsEnabled returns true if the SEO audit is enabled (nil-safe)
+func (c *SEOAuditConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetMinWordCount returns the word count below which a page is flagged
+func (c *SEOAuditConfig) GetMinWordCount() int {
+	if c == nil || c.MinWordCount <= 0 {
+		return DefaultSEOAuditMinWordCount
+	}
+	return c.MinWordCount
+}
+
+// GetMaxImagesWithoutAlt returns the number of images without alt text allowed per page
+func (c *SEOAuditConfig) GetMaxImagesWithoutAlt() int {
+	if c == nil || c.MaxImagesWithoutAlt < 0 {
+		return 0
+	}
+	return c.MaxImagesWithoutAlt
+}
+
 // Host represents a domain configuration
 type Host struct {
 	ID                 int                          `yaml:"id" json:"id"`
@@ -47,6 +152,9 @@ type Host struct {
 	Headers            *HeadersConfig               `yaml:"headers,omitempty" json:"headers,omitempty"`                 // Host-level headers override
 	ClientIP           *ClientIPConfig              `yaml:"client_ip,omitempty" json:"client_ip,omitempty"`             // Host-level client IP override
 	URLRules           []URLRule                    `yaml:"url_rules,omitempty" json:"url_rules,omitempty"`             // URL pattern rules
+	LLMsTxt            *LLMsTxtConfig               `yaml:"llms_txt,omitempty" json:"llms_txt,omitempty"`               // Generated /llms.txt (optional)
+	SEOAudit           *SEOAuditConfig              `yaml:"seo_audit,omitempty" json:"seo_audit,omitempty"`             // SEO rules evaluated on render (optional)
+	RobotsTxt          *RobotsTxtConfig             `yaml:"robots_txt,omitempty" json:"robots_txt,omitempty"`           // robots.txt checks (optional)
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling for Host.
@@ -215,12 +323,92 @@ const CompressionMinSize = 1024
 
 // RenderConfig defines rendering behavior
 type RenderConfig struct {
-	Timeout              Duration           `yaml:"timeout" json:"timeout"`
-	Events               RenderEvents       `yaml:"events" json:"events"`
-	Cache                *RenderCacheConfig `yaml:"cache,omitempty" json:"cache,omitempty"`                                   // Cache configuration for render action
-	BlockedResourceTypes []string           `yaml:"blocked_resource_types,omitempty" json:"blocked_resource_types,omitempty"` // Resource types to block during rendering
-	BlockedPatterns      []string           `yaml:"blocked_patterns,omitempty" json:"blocked_patterns,omitempty"`             // URL patterns to block (domains/paths)
-	StripScripts         *bool              `yaml:"strip_scripts,omitempty" json:"strip_scripts,omitempty"`
+	Timeout              Duration             `yaml:"timeout" json:"timeout"`
+	Events               RenderEvents         `yaml:"events" json:"events"`
+	Cache                *RenderCacheConfig   `yaml:"cache,omitempty" json:"cache,omitempty"`                                   // Cache configuration for render action
+	BlockedResourceTypes []string             `yaml:"blocked_resource_types,omitempty" json:"blocked_resource_types,omitempty"` // Resource types to block during rendering
+	BlockedPatterns      []string             `yaml:"blocked_patterns,omitempty" json:"blocked_patterns,omitempty"`             // URL patterns to block (domains/paths)
+	StripScripts         *bool                `yaml:"strip_scripts,omitempty" json:"strip_scripts,omitempty"`
+	Transforms           []HTMLTransform      `yaml:"transforms,omitempty" json:"transforms,omitempty"` // HTML post-processing applied to rendered pages
+	Markdown             *MarkdownConfig      `yaml:"markdown,omitempty" json:"markdown,omitempty"`     // Markdown output variant
+	Quality              *RenderQualityConfig `yaml:"quality,omitempty" json:"quality,omitempty"`       // Quality gates for rendered pages
+}
+
+// Output formats served for rendered pages
+const (
+	OutputFormatHTML     = "html"     // Rendered HTML (default)
+	OutputFormatMarkdown = "markdown" // Main content converted to Markdown
+)
+
+// MarkdownContentType is the Content-Type of Markdown responses
+const MarkdownContentType = "text/markdown; charset=utf-8"
+
+// MarkdownConfig controls the Markdown variant of rendered pages
+type MarkdownConfig struct {
+	AcceptHeader  *bool `yaml:"accept_header,omitempty" json:"accept_header,omitempty"`   // Serve Markdown when the client prefers text/markdown (default: false)
+	JSONLDSummary *bool `yaml:"jsonld_summary,omitempty" json:"jsonld_summary,omitempty"` // Append a summary of JSON-LD structured data (default: true)
+}
+
+// Render quality defaults
+const (
+	DefaultRenderQualityMaxAttempts  = 2
+	DefaultRenderQualityRetryBackoff = time.Second
+)
+
+// RenderQualityConfig defines checks a rendered 200 page must pass before it is cached.
+// A failing render (skeleton page, error banner, crashed app) is retried with backoff
+// and never replaces an existing cache entry.
+type RenderQualityConfig struct {
+	MinWordCount      int      `yaml:"min_word_count,omitempty" json:"min_word_count,omitempty"`         // Minimum words of body content (0 = not checked)
+	RequiredSelectors []string `yaml:"required_selectors,omitempty" json:"required_selectors,omitempty"` // CSS selectors that must each match an element
+	ForbiddenText     []string `yaml:"forbidden_text,omitempty" json:"forbidden_text,omitempty"`         // Visible text that must not appear (case-insensitive)
+	MaxConsoleErrors  *int     `yaml:"max_console_errors,omitempty" json:"max_console_errors,omitempty"` // Maximum JavaScript console errors (nil = not checked)
+	MaxAttempts       int      `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"`             // Render attempts including the first, default 2
+	RetryBackoff      Duration `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`           // Wait before the first retry, doubled for each further retry, default 1s
+}
+
+// GetMaxAttempts returns the render attempts including the first or the default
+func (c *RenderQualityConfig) GetMaxAttempts() int {
+	if c == nil || c.MaxAttempts == 0 {
+		return DefaultRenderQualityMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// GetRetryBackoff returns the wait before the given retry (1 for the first retry)
+func (c *RenderQualityConfig) GetRetryBackoff(retry int) time.Duration {
+	backoff := DefaultRenderQualityRetryBackoff
+	if c != nil && c.RetryBackoff != 0 {
+		backoff = time.Duration(c.RetryBackoff)
+	}
+	for i := 1; i < retry; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
+
+// HTML transform types
+const (
+	HTMLTransformRemove          = "remove"           // Remove elements matching selector
+	HTMLTransformSetAttribute    = "set_attribute"    // Set attribute to value on elements matching selector
+	HTMLTransformRemoveAttribute = "remove_attribute" // Remove attribute from elements matching selector
+	HTMLTransformInjectHead      = "inject_head"      // Append html to <head>, replacing elements matching selector
+	HTMLTransformRewriteURLs     = "rewrite_urls"     // Replace URL prefix from with to in URL attributes
+	HTMLTransformMinify          = "minify"           // Remove comments and collapse whitespace
+)
+
+// HTMLTransformURLPlaceholder is replaced by the page URL in transform values and snippets
+const HTMLTransformURLPlaceholder = "{url}"
+
+// HTMLTransform is one step of the ordered HTML post-processing pipeline applied to rendered pages
+type HTMLTransform struct {
+	Type      string `yaml:"type" json:"type"`
+	Selector  string `yaml:"selector,omitempty" json:"selector,omitempty"`   // CSS selector (remove, set_attribute, remove_attribute; optional for inject_head and rewrite_urls)
+	Attribute string `yaml:"attribute,omitempty" json:"attribute,omitempty"` // Attribute name (set_attribute, remove_attribute)
+	Value     string `yaml:"value,omitempty" json:"value,omitempty"`         // Attribute value (set_attribute), supports {url}
+	HTML      string `yaml:"html,omitempty" json:"html,omitempty"`           // Snippet appended to <head> (inject_head), supports {url}
+	From      string `yaml:"from,omitempty" json:"from,omitempty"`           // URL prefix to replace (rewrite_urls)
+	To        string `yaml:"to,omitempty" json:"to,omitempty"`               // Replacement URL prefix (rewrite_urls)
 }
 
 // Dimension defines viewport configuration
@@ -231,6 +419,7 @@ type Dimension struct {
 	RenderUA string        `yaml:"render_ua" json:"render_ua"`
 	MatchUA  []string      `yaml:"match_ua" json:"match_ua"`
 	Action   URLRuleAction `yaml:"action,omitempty" json:"action,omitempty"`
+	Format   string        `yaml:"format,omitempty" json:"format,omitempty"` // Output format: html (default) or markdown
 
 	// CompiledPatterns stores pre-compiled user agent patterns
 	CompiledPatterns []*pattern.Pattern `yaml:"-" json:"-"`
@@ -244,6 +433,14 @@ func (d Dimension) EffectiveAction() URLRuleAction {
 	return d.Action
 }
 
+// EffectiveFormat returns the dimension's output format, defaulting to OutputFormatHTML
+func (d Dimension) EffectiveFormat() string {
+	if d.Format == "" {
+		return OutputFormatHTML
+	}
+	return d.Format
+}
+
 // CompileMatchUAPatterns pre-compiles patterns for user agent matching
 // Uses unified pattern package for consistent behavior:
 // - No prefix: exact match (case-sensitive)
@@ -300,6 +497,8 @@ type EGInfo struct {
 	Address         string    `json:"address"`
 	LastHeartbeat   time.Time `json:"last_heartbeat"`
 	ShardingEnabled bool      `json:"sharding_enabled"`
+	Weight          float64   `json:"weight,omitempty"` // Distribution weight (0 = default 1.0)
+	Zone            string    `json:"zone,omitempty"`   // Availability zone / rack label for replica placement
 }
 
 // ParseCacheKey parses a cache key string in format "cache:host_id:dimension_id:url_hash"
@@ -367,6 +566,7 @@ const (
 	ErrorTypeChromeCrash         = "chrome_crash"
 	ErrorTypeChromeRestartFailed = "chrome_restart_failed"
 	ErrorTypePoolUnavailable     = "pool_unavailable"
+	ErrorTypeConnectionFailed    = "connection_failed" // EG could not reach the render service
 )
 
 // Error type constants - Render errors
@@ -389,6 +589,7 @@ const (
 const (
 	ErrorTypeEmptyResponse    = "empty_response"
 	ErrorTypeResponseTooLarge = "response_too_large"
+	ErrorTypeRenderQuality    = "render_quality" // Rendered page failed the host's quality gates
 )
 
 // RenderResponse represents a render result (unified for RS→EG and Chrome→RS)
@@ -505,6 +706,7 @@ const (
 const (
 	SelectionStrategyLeastLoaded   = "least_loaded"   // Select service with lowest load percentage
 	SelectionStrategyMostAvailable = "most_available" // Select service with most available tabs
+	SelectionStrategyHealthiest    = "healthiest"     // Select service with best health score weighed by load
 )
 
 // IndexStatus represents the indexability status of a rendered page
@@ -543,14 +745,36 @@ const (
 	MaxHeadingLength         = 500
 	MaxHeadingsPerLevel      = 5
 	MaxExternalDomains       = 20
+	MaxInternalLinks         = 500
 )
 
 // PageSEO extraction limits - performance
 const (
 	MaxJSONLDSize           = 1024 * 1024 // 1MB per JSON-LD block
 	MaxJSONLDRecursionDepth = 10          // Prevent stack overflow
+	MaxStructuredDataIssues = 20          // Issues kept per page
 )
 
+// Structured data formats detected on a page
+const (
+	StructuredDataFormatJSONLD    = "json-ld"
+	StructuredDataFormatMicrodata = "microdata"
+	StructuredDataFormatRDFa      = "rdfa"
+)
+
+// Structured data issue kinds
+const (
+	StructuredDataIssueParseError      = "parse_error"      // JSON-LD block is not valid JSON
+	StructuredDataIssueMissingProperty = "missing_property" // Item lacks a required property
+)
+
+// StructuredDataIssue is a problem found in a page's JSON-LD structured data
+type StructuredDataIssue struct {
+	Issue    string `json:"issue"`              // StructuredDataIssue* constant
+	Type     string `json:"type,omitempty"`     // schema.org type of the item (empty for parse errors)
+	Property string `json:"property,omitempty"` // Missing property, alternatives joined by "|"
+}
+
 // Chrome resource type constants for PageMetrics.BytesByType map keys
 const (
 	ResourceTypeDocument       = "Document"
@@ -603,6 +827,7 @@ type PageSEO struct {
 	LinksNofollowInternal int            `json:"links_nofollow_internal,omitempty"`
 	LinksNofollowExternal int            `json:"links_nofollow_external,omitempty"`
 	ExternalDomains       map[string]int `json:"external_domains,omitempty"`
+	InternalLinks         []string       `json:"internal_links,omitempty"` // Distinct followed internal link targets (first 500)
 
 	// Images analysis
 	ImagesTotal      int `json:"images_total,omitempty"`
@@ -620,7 +845,9 @@ type PageSEO struct {
 	HreflangSelf string          `json:"hreflang_self,omitempty"`
 
 	// Structured data
-	StructuredDataTypes []string `json:"structured_data_types,omitempty"`
+	StructuredDataTypes   []string              `json:"structured_data_types,omitempty"`
+	StructuredDataFormats []string              `json:"structured_data_formats,omitempty"` // Formats present: json-ld, microdata, rdfa
+	StructuredDataIssues  []StructuredDataIssue `json:"structured_data_issues,omitempty"`  // JSON-LD parse errors and missing required properties
 }
 
 // Duration wraps time.Duration with extended YAML parsing support for days and weeks
@@ -88,6 +88,9 @@ type RenderRuleConfig struct {
 	BlockedPatterns      []string             `yaml:"blocked_patterns,omitempty" json:"blocked_patterns,omitempty"`             // Override blocked URL patterns
 	BlockedResourceTypes []string             `yaml:"blocked_resource_types,omitempty" json:"blocked_resource_types,omitempty"` // Override blocked resource types
 	StripScripts         *bool                `yaml:"strip_scripts,omitempty" json:"strip_scripts,omitempty"`
+	Transforms           []HTMLTransform      `yaml:"transforms,omitempty" json:"transforms,omitempty"` // Override HTML post-processing
+	Markdown             *MarkdownConfig      `yaml:"markdown,omitempty" json:"markdown,omitempty"`     // Override Markdown variant settings
+	Quality              *RenderQualityConfig `yaml:"quality,omitempty" json:"quality,omitempty"`       // Override quality gates (replaces host gates)
 }
 
 // BypassRuleConfig defines bypass overrides for URL patterns
@@ -211,18 +214,32 @@ func (c *BothitRecacheConfig) CompileMatchUAPatterns() error {
 // CacheShardingConfig defines cache sharding configuration for multi-EG deployments
 // Can be specified at global and host levels (not URL pattern level)
 type CacheShardingConfig struct {
-	Enabled              *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"`                             // Enable/disable sharding (pointer for override detection)
-	ReplicationFactor    *int   `yaml:"replication_factor,omitempty" json:"replication_factor,omitempty"`       // Number of EG instances to replicate cache to (pointer for override detection)
-	DistributionStrategy string `yaml:"distribution_strategy,omitempty" json:"distribution_strategy,omitempty"` // hash_modulo | random | primary_only
-	PushOnRender         *bool  `yaml:"push_on_render,omitempty" json:"push_on_render,omitempty"`               // Push to replicas after render (pointer for override detection)
-	ReplicateOnPull      *bool  `yaml:"replicate_on_pull,omitempty" json:"replicate_on_pull,omitempty"`         // Store pulled cache locally (pointer for override detection)
+	Enabled              *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"`                             // Enable/disable sharding (pointer for override detection)
+	ReplicationFactor    *int     `yaml:"replication_factor,omitempty" json:"replication_factor,omitempty"`       // Number of EG instances to replicate cache to (pointer for override detection)
+	DistributionStrategy string   `yaml:"distribution_strategy,omitempty" json:"distribution_strategy,omitempty"` // hash_modulo | rendezvous | random | primary_only
+	PushOnRender         *bool    `yaml:"push_on_render,omitempty" json:"push_on_render,omitempty"`               // Push to replicas after render (pointer for override detection)
+	ReplicateOnPull      *bool    `yaml:"replicate_on_pull,omitempty" json:"replicate_on_pull,omitempty"`         // Store pulled cache locally (pointer for override detection)
+	Weight               *float64 `yaml:"weight,omitempty" json:"weight,omitempty"`                               // Relative share of keys stored by this EG (rendezvous only, global level)
+	Zone                 string   `yaml:"zone,omitempty" json:"zone,omitempty"`                                   // Availability zone / rack of this EG (global level)
+
+	Rebalance *CacheShardingRebalanceConfig `yaml:"rebalance,omitempty" json:"rebalance,omitempty"` // Background replica repair (global level only)
+}
+
+// CacheShardingRebalanceConfig configures the anti-entropy worker that repairs
+// under-replicated and misplaced cache entries after cluster membership changes
+type CacheShardingRebalanceConfig struct {
+	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	Interval    Duration `yaml:"interval,omitempty" json:"interval,omitempty"`         // Time between metadata scans (default 10m)
+	RateLimit   int      `yaml:"rate_limit,omitempty" json:"rate_limit,omitempty"`     // Max entries repaired or dropped per second (default 20)
+	MinAge      Duration `yaml:"min_age,omitempty" json:"min_age,omitempty"`           // Skip entries created more recently, render pushes may be in flight (default 1m)
+	DropUnowned bool     `yaml:"drop_unowned,omitempty" json:"drop_unowned,omitempty"` // Delete local copies this EG is no longer a target for
 }
 
 // CacheShardingBehaviorConfig contains behavioral sharding settings that can be overridden per host/pattern
 type CacheShardingBehaviorConfig struct {
 	Enabled              *bool  `yaml:"enabled,omitempty" json:"enabled,omitempty"`                             // Enable/disable sharding (pointer for override detection)
 	ReplicationFactor    *int   `yaml:"replication_factor,omitempty" json:"replication_factor,omitempty"`       // Number of EG instances to replicate cache to (pointer for override detection)
-	DistributionStrategy string `yaml:"distribution_strategy,omitempty" json:"distribution_strategy,omitempty"` // hash_modulo | random | primary_only
+	DistributionStrategy string `yaml:"distribution_strategy,omitempty" json:"distribution_strategy,omitempty"` // hash_modulo | rendezvous | random | primary_only
 	PushOnRender         *bool  `yaml:"push_on_render,omitempty" json:"push_on_render,omitempty"`               // Push to replicas after render (pointer for override detection)
 	ReplicateOnPull      *bool  `yaml:"replicate_on_pull,omitempty" json:"replicate_on_pull,omitempty"`         // Store pulled cache locally (pointer for override detection)
 }